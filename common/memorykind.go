@@ -257,6 +257,9 @@ const (
 	MemoryKindOrderedMapEntryList
 	MemoryKindOrderedMapEntry
 
+	// Values (added after the other kinds, so the values of existing kinds don't change)
+	MemoryKindStringSplitIteratorValue
+
 	// Placeholder kind to allow consistent indexing
 	// this should always be the last kind
 	MemoryKindLast
@@ -155,6 +155,7 @@ var (
 	PublishedValueMemoryUsage                   = NewConstantMemoryUsage(MemoryKindPublishedValue)
 	StorageCapabilityControllerValueMemoryUsage = NewConstantMemoryUsage(MemoryKindStorageCapabilityControllerValue)
 	AccountCapabilityControllerValueMemoryUsage = NewConstantMemoryUsage(MemoryKindAccountCapabilityControllerValue)
+	StringSplitIteratorValueMemoryUsage         = NewConstantMemoryUsage(MemoryKindStringSplitIteratorValue)
 
 	// Static Types
 
@@ -262,6 +263,7 @@ var (
 	StorageCapabilityControllerValueStringMemoryUsage = NewRawStringMemoryUsage(len("StorageCapabilityController(borrowType: , capabilityID: , target: )"))
 	AccountCapabilityControllerValueStringMemoryUsage = NewRawStringMemoryUsage(len("AccountCapabilityController(borrowType: , capabilityID: )"))
 	PublishedValueStringMemoryUsage                   = NewRawStringMemoryUsage(len("PublishedValue<>()"))
+	StringSplitIteratorValueStringMemoryUsage         = NewRawStringMemoryUsage(len("StringSplitIterator()"))
 	AuthStringMemoryUsage                             = NewRawStringMemoryUsage(len("auth() "))
 
 	// Static types string representations
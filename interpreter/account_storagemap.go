@@ -19,7 +19,14 @@
 package interpreter
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
 	goerrors "errors"
+	"math"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/onflow/atree"
 
@@ -30,6 +37,9 @@ import (
 // AccountStorageMap stores domain storage maps in an account.
 type AccountStorageMap struct {
 	orderedMap *atree.OrderedMap
+	// modificationCount is incremented on each modification of the account storage map,
+	// and is used by iterators to detect modifications during iteration.
+	modificationCount uint64
 }
 
 // NewAccountStorageMap creates account storage map.
@@ -99,6 +109,44 @@ func (s *AccountStorageMap) GetDomain(
 	domain common.StorageDomain,
 	createIfNotExists bool,
 ) *DomainStorageMap {
+	domainStorageMap, _ := s.getDomain(
+		gauge,
+		storageMutationTracker,
+		domain,
+		createIfNotExists,
+	)
+	return domainStorageMap
+}
+
+// GetOrCreateDomain returns domain storage map for the given domain,
+// like GetDomain with createIfNotExists set to true.
+// Returns true if the domain storage map did not exist and was created.
+func (s *AccountStorageMap) GetOrCreateDomain(
+	gauge common.MemoryGauge,
+	storageMutationTracker StorageMutationTracker,
+	domain common.StorageDomain,
+) (
+	domainStorageMap *DomainStorageMap,
+	created bool,
+) {
+	const createIfNotExists = true
+	return s.getDomain(
+		gauge,
+		storageMutationTracker,
+		domain,
+		createIfNotExists,
+	)
+}
+
+func (s *AccountStorageMap) getDomain(
+	gauge common.MemoryGauge,
+	storageMutationTracker StorageMutationTracker,
+	domain common.StorageDomain,
+	createIfNotExists bool,
+) (
+	domainStorageMap *DomainStorageMap,
+	created bool,
+) {
 	key := Uint64StorageMapKey(domain)
 
 	storedValue, err := s.orderedMap.Get(
@@ -112,17 +160,17 @@ func (s *AccountStorageMap) GetDomain(
 			// Create domain storage map if needed.
 
 			if createIfNotExists {
-				return s.NewDomain(gauge, storageMutationTracker, domain)
+				return s.NewDomain(gauge, storageMutationTracker, domain), true
 			}
 
-			return nil
+			return nil, false
 		}
 
 		panic(errors.NewExternalError(err))
 	}
 
 	// Create domain storage map from raw atree value.
-	return NewDomainStorageMapWithAtreeValue(storedValue)
+	return NewDomainStorageMapWithAtreeValue(storedValue), false
 }
 
 // NewDomain creates new domain storage map and inserts it to AccountStorageMap with given domain as key.
@@ -146,6 +194,9 @@ func (s *AccountStorageMap) NewDomain(
 	if err != nil {
 		panic(errors.NewExternalError(err))
 	}
+
+	s.modificationCount++
+
 	if existingStorable != nil {
 		panic(errors.NewUnexpectedError(
 			"account %x domain %s should not exist",
@@ -157,6 +208,21 @@ func (s *AccountStorageMap) NewDomain(
 	return domainStorageMap
 }
 
+// NewDomainIfAbsent creates new domain storage map and inserts it to AccountStorageMap with given domain as key,
+// unless the domain already exists, in which case the existing domain storage map is returned unchanged.
+// Returns true if the domain storage map was created.
+// Unlike NewDomain, it never replaces an existing domain storage map, see GetOrCreateDomain.
+func (s *AccountStorageMap) NewDomainIfAbsent(
+	gauge common.MemoryGauge,
+	storageMutationTracker StorageMutationTracker,
+	domain common.StorageDomain,
+) (
+	domainStorageMap *DomainStorageMap,
+	created bool,
+) {
+	return s.GetOrCreateDomain(gauge, storageMutationTracker, domain)
+}
+
 // WriteDomain sets or removes domain storage map in account storage map.
 // If the given storage map is nil, domain is removed.
 // If the given storage map is non-nil, domain is added/updated.
@@ -172,6 +238,56 @@ func (s *AccountStorageMap) WriteDomain(
 	return s.setDomain(context, domain, domainStorageMap)
 }
 
+// WriteDomainStrict is like WriteDomain, but doesn't overwrite a non-empty domain storage map.
+// If the given storage map is non-nil and the domain already stores a non-empty domain storage map,
+// a DomainAlreadyExistsError is returned, and the existing domain storage map remains unchanged.
+func (s *AccountStorageMap) WriteDomainStrict(
+	context ValueTransferContext,
+	domain common.StorageDomain,
+	domainStorageMap *DomainStorageMap,
+) (existed bool, err error) {
+	if domainStorageMap != nil {
+		existingDomainStorageMap := s.GetDomain(context, context, domain, false)
+		if existingDomainStorageMap != nil && existingDomainStorageMap.Count() > 0 {
+			return true, DomainAlreadyExistsError{
+				Address: common.Address(s.orderedMap.Address()),
+				Domain:  domain,
+			}
+		}
+	}
+
+	return s.WriteDomain(context, domain, domainStorageMap), nil
+}
+
+// WriteDomains sets or removes the given domain storage maps in account storage map,
+// in deterministic (ascending) domain order.
+// A nil domain storage map removes the domain, like WriteDomain.
+// Returns for each given domain if a domain storage map previously existed.
+func (s *AccountStorageMap) WriteDomains(
+	inter *Interpreter,
+	domainStorageMaps map[common.StorageDomain]*DomainStorageMap,
+) map[common.StorageDomain]bool {
+
+	domains := make([]common.StorageDomain, 0, len(domainStorageMaps))
+
+	// NOTE: map range is safe, as domains are sorted below
+	for domain := range domainStorageMaps { //nolint:maprange
+		domains = append(domains, domain)
+	}
+
+	sort.Slice(domains, func(i, j int) bool {
+		return domains[i] < domains[j]
+	})
+
+	existed := make(map[common.StorageDomain]bool, len(domains))
+
+	for _, domain := range domains {
+		existed[domain] = s.WriteDomain(inter, domain, domainStorageMaps[domain])
+	}
+
+	return existed
+}
+
 // setDomain sets domain storage map in the account storage map and returns true if domain previously existed.
 // If the given domain already stores a domain storage map, it is overwritten.
 func (s *AccountStorageMap) setDomain(
@@ -193,6 +309,8 @@ func (s *AccountStorageMap) setDomain(
 		panic(errors.NewExternalError(err))
 	}
 
+	s.modificationCount++
+
 	existed = existingValueStorable != nil
 	if existed {
 		// Create domain storage map from overwritten storable
@@ -234,6 +352,8 @@ func (s *AccountStorageMap) removeDomain(context ValueTransferContext, domain co
 		panic(errors.NewExternalError(err))
 	}
 
+	s.modificationCount++
+
 	// Key
 
 	// NOTE: Key is just an atree.Value (Uint64AtreeValue), not an interpreter.Value,
@@ -260,14 +380,117 @@ func (s *AccountStorageMap) removeDomain(context ValueTransferContext, domain co
 	return
 }
 
+// Clear removes all domain storage maps (and their slabs) in account storage map.
+// The account storage map itself remains valid and empty.
+func (s *AccountStorageMap) Clear(inter *Interpreter) {
+	inter.RecordStorageMutation()
+
+	s.modificationCount++
+
+	storage := s.orderedMap.Storage
+
+	err := s.orderedMap.PopIterate(func(keyStorable atree.Storable, valueStorable atree.Storable) {
+		// Key
+
+		// NOTE: Key is just an atree.Value (Uint64AtreeValue), not an interpreter.Value,
+		// so do not need (can) convert and not need to deep remove
+		RemoveReferencedSlab(inter, keyStorable)
+
+		// Value
+
+		// Create domain storage map from removed storable
+		domainStorageMap := newDomainStorageMapWithAtreeStorable(storage, valueStorable)
+
+		// Deep remove elements in domain storage map
+		domainStorageMap.DeepRemove(inter, false) // domain storage map is an element of account storage map because it is from PopIterate() callback.
+
+		// Remove domain storage map slab
+		RemoveReferencedSlab(inter, valueStorable)
+	})
+	if err != nil {
+		panic(errors.NewExternalError(err))
+	}
+
+	inter.MaybeValidateAtreeValue(s.orderedMap)
+	inter.MaybeValidateAtreeStorage()
+}
+
 func (s *AccountStorageMap) SlabID() atree.SlabID {
 	return s.orderedMap.SlabID()
 }
 
+// SlabIDs returns the root slab ID of the account storage map,
+// followed by the root slab IDs of all domain storage maps which are not inlined.
+// Inlined domain storage maps are stored in the slabs of the account storage map, so they have no own slab.
+//
+// Note that atree only considers the account storage map root slab a root slab of the storage,
+// as the domain storage map root slabs are referenced by the account storage map.
+func (s *AccountStorageMap) SlabIDs() []atree.SlabID {
+	slabIDs := []atree.SlabID{s.SlabID()}
+
+	iterator := s.Iterator()
+
+	for {
+		domain, domainStorageMap := iterator.Next()
+		if domain == common.StorageDomainUnknown {
+			break
+		}
+
+		if domainStorageMap.Inlined() {
+			continue
+		}
+
+		slabIDs = append(slabIDs, domainStorageMap.SlabID())
+	}
+
+	return slabIDs
+}
+
 func (s *AccountStorageMap) Count() uint64 {
 	return s.orderedMap.Count()
 }
 
+// CountAllValues returns the total number of values stored in all domains of the account storage map.
+// Only the domain storage maps are loaded, the stored values are not.
+func (s *AccountStorageMap) CountAllValues() uint64 {
+	var count uint64
+
+	iterator := s.Iterator()
+
+	for {
+		_, domainStorageMap := iterator.Next()
+		if domainStorageMap == nil {
+			break
+		}
+
+		count += domainStorageMap.Count()
+	}
+
+	return count
+}
+
+// DomainSizes returns the total size of all slabs of each domain storage map and its values.
+// Slabs are never shared between domains, so each slab is attributed to exactly one domain.
+// Inlined domain storage maps are attributed the size of their inlined data.
+// The size of the account storage map itself, excluding inlined domain storage maps, is not included.
+// The size of each retrieved slab is metered using the given memory gauge, see DomainStorageMap.ByteSize.
+func (s *AccountStorageMap) DomainSizes(gauge common.MemoryGauge) map[common.StorageDomain]uint64 {
+	sizes := make(map[common.StorageDomain]uint64, s.Count())
+
+	iterator := s.Iterator()
+
+	for {
+		domain, domainStorageMap := iterator.Next()
+		if domain == common.StorageDomainUnknown {
+			break
+		}
+
+		sizes[domain] = domainStorageMap.ByteSize(gauge)
+	}
+
+	return sizes
+}
+
 // Domains returns a set of domains in account storage map
 func (s *AccountStorageMap) Domains() map[common.StorageDomain]struct{} {
 	domains := make(map[common.StorageDomain]struct{})
@@ -291,8 +514,595 @@ func (s *AccountStorageMap) Domains() map[common.StorageDomain]struct{} {
 	return domains
 }
 
+// DomainsSorted returns the domains in account storage map in canonical order,
+// i.e. sorted by their identifiers.
+// Unlike the order of Iterator, the order only depends on the domains,
+// not on the layout of the underlying slabs.
+func (s *AccountStorageMap) DomainsSorted() []common.StorageDomain {
+	existingDomains := s.Domains()
+
+	domains := make([]common.StorageDomain, 0, len(existingDomains))
+	for domain := range existingDomains { //nolint:maprange
+		domains = append(domains, domain)
+	}
+
+	slices.SortFunc(domains, func(a, b common.StorageDomain) int {
+		return strings.Compare(a.Identifier(), b.Identifier())
+	})
+
+	return domains
+}
+
+// UnsavedChangesStorage is a slab storage which tracks unsaved changes,
+// and which provides access to the committed state, i.e. the state without unsaved changes.
+type UnsavedChangesStorage interface {
+	atree.SlabStorage
+	// HasUnsavedChanges returns true if there are unsaved changes to the slabs of the given address.
+	HasUnsavedChanges(address atree.Address) bool
+	// HasUnsavedSlab returns true if the slab with the given ID has unsaved changes,
+	// i.e. if it was stored or removed since the last commit.
+	HasUnsavedSlab(id atree.SlabID) bool
+	// CommittedSlabStorage returns a read-only slab storage for the committed state.
+	CommittedSlabStorage() atree.SlabStorage
+}
+
+// ModifiedDomains returns the set of domains which have unsaved changes,
+// i.e. domains which were created, removed, or whose domain storage map or stored values were modified
+// since the last commit.
+//
+// Accounts without unsaved changes are detected using atree's tracking of unsaved slabs, without loading any domain.
+// For accounts with unsaved changes, the slabs of each domain storage map which is stored in separate slabs
+// are checked for unsaved changes. Such domains are reported even if their modifications were reverted.
+// Inlined domain storage maps share their slab with the account storage map,
+// so their content is compared with the committed state instead.
+//
+// The slab storage of the account storage map must implement UnsavedChangesStorage.
+func (s *AccountStorageMap) ModifiedDomains(gauge common.MemoryGauge) map[common.StorageDomain]struct{} {
+	storage, ok := s.orderedMap.Storage.(UnsavedChangesStorage)
+	if !ok {
+		panic(errors.NewUnexpectedError("slab storage does not track unsaved changes"))
+	}
+
+	modifiedDomains := make(map[common.StorageDomain]struct{})
+
+	address := s.orderedMap.Address()
+
+	if !storage.HasUnsavedChanges(address) {
+		return modifiedDomains
+	}
+
+	committedStorage := storage.CommittedSlabStorage()
+
+	var committedAccountStorageMap *AccountStorageMap
+
+	_, committed, err := committedStorage.Retrieve(s.SlabID())
+	if err != nil {
+		panic(errors.NewExternalError(err))
+	}
+	if committed {
+		committedAccountStorageMap = NewAccountStorageMapWithRootID(committedStorage, s.SlabID())
+	}
+
+	hasher := func(data []byte) []byte {
+		digest := sha256.Sum256(data)
+		return digest[:]
+	}
+
+	// Domains which exist now, and which were created, or which have modified content
+
+	iterator := s.Iterator()
+
+	for {
+		domain, domainStorageMap := iterator.Next()
+		if domain == common.StorageDomainUnknown {
+			break
+		}
+
+		if committedAccountStorageMap == nil {
+			modifiedDomains[domain] = struct{}{}
+			continue
+		}
+
+		if !domainStorageMap.Inlined() {
+			rootSlabID := domainStorageMap.orderedMap.SlabID()
+			if storage.HasUnsavedSlab(rootSlabID) ||
+				referencesUnsavedSlab(storage, retrieveSlab(storage, rootSlabID)) {
+
+				modifiedDomains[domain] = struct{}{}
+			}
+			continue
+		}
+
+		committedDomainStorageMap := committedAccountStorageMap.GetDomain(gauge, nil, domain, false)
+		if committedDomainStorageMap == nil {
+			modifiedDomains[domain] = struct{}{}
+			continue
+		}
+
+		hash := atreeValueContentHash(
+			storage,
+			address,
+			domainStorageMap.orderedMap,
+			hasher,
+		)
+		committedHash := atreeValueContentHash(
+			committedStorage,
+			address,
+			committedDomainStorageMap.orderedMap,
+			hasher,
+		)
+		if !bytes.Equal(hash, committedHash) {
+			modifiedDomains[domain] = struct{}{}
+		}
+	}
+
+	// Domains which were removed
+
+	if committedAccountStorageMap != nil {
+		for domain := range committedAccountStorageMap.Domains() { //nolint:maprange
+			if !s.DomainExists(domain) {
+				modifiedDomains[domain] = struct{}{}
+			}
+		}
+	}
+
+	return modifiedDomains
+}
+
+// referencesUnsavedSlab returns true if any slab referenced by the given storable,
+// directly or indirectly through inlined storables, has unsaved changes.
+func referencesUnsavedSlab(storage UnsavedChangesStorage, storable atree.Storable) bool {
+	for _, child := range storable.ChildStorables() {
+		if slabIDStorable, ok := child.(atree.SlabIDStorable); ok {
+			slabID := atree.SlabID(slabIDStorable)
+			if storage.HasUnsavedSlab(slabID) {
+				return true
+			}
+			child = retrieveSlab(storage, slabID)
+		}
+
+		if referencesUnsavedSlab(storage, child) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DeepCopy creates a new account storage map in the given address,
+// and transfers every value of every domain to the new address.
+// The account storage map and its values remain unchanged.
+func (s *AccountStorageMap) DeepCopy(
+	inter *Interpreter,
+	newAddress atree.Address,
+) (*AccountStorageMap, error) {
+
+	// Copying to the same address is not supported,
+	// because transferring a resource within the same address doesn't copy it.
+	if newAddress == s.orderedMap.Address() {
+		return nil, errors.NewDefaultUserError(
+			"cannot copy account storage map of account %x to the same account",
+			newAddress,
+		)
+	}
+
+	newAccountStorageMap := NewAccountStorageMap(inter, s.orderedMap.Storage, newAddress)
+
+	iterator := s.Iterator()
+
+	for {
+		domain, domainStorageMap := iterator.Next()
+		if domain == common.StorageDomainUnknown {
+			break
+		}
+
+		newDomainStorageMap := newAccountStorageMap.NewDomain(inter, inter, domain)
+
+		domainIterator := domainStorageMap.Iterator(inter)
+
+		for {
+			key, value := domainIterator.Next()
+			if key == nil {
+				break
+			}
+
+			storageMapKey, err := convertAtreeValueToStorageMapKey(key)
+			if err != nil {
+				return nil, err
+			}
+
+			value = value.Transfer(
+				inter,
+				EmptyLocationRange,
+				newAddress,
+				false,
+				nil,
+				nil,
+				false, // value is an element of domain storage map because it is returned from iterator.
+			)
+
+			newDomainStorageMap.WriteValue(inter, storageMapKey, value)
+		}
+	}
+
+	return newAccountStorageMap, nil
+}
+
+// ConflictResolution specifies how AccountStorageMap.Merge handles
+// a key which exists in both account storage maps.
+type ConflictResolution uint8
+
+const (
+	// ConflictResolutionKeepExisting keeps the existing value.
+	ConflictResolutionKeepExisting ConflictResolution = iota
+	// ConflictResolutionOverwrite overwrites the existing value.
+	// Existing resource-kinded values are never overwritten,
+	// a ResourceLossError is returned instead.
+	ConflictResolutionOverwrite
+	// ConflictResolutionError returns a StorageMergeConflictError.
+	ConflictResolutionError
+)
+
+// Merge copies every domain of the other account storage map into this account storage map,
+// and transfers every value to the address of this account storage map.
+// Keys which exist in both account storage maps are handled according to onConflict.
+// Conflicts are checked before any value is written,
+// so this account storage map remains unchanged if an error is returned.
+// The other account storage map and its values remain unchanged.
+func (s *AccountStorageMap) Merge(
+	inter *Interpreter,
+	other *AccountStorageMap,
+	onConflict ConflictResolution,
+) error {
+	address := s.orderedMap.Address()
+
+	// Merging from the same address is not supported,
+	// because transferring a resource within the same address doesn't copy it.
+	if other.orderedMap.Address() == address {
+		return errors.NewDefaultUserError(
+			"cannot merge account storage map of account %x into the same account",
+			address,
+		)
+	}
+
+	if onConflict != ConflictResolutionKeepExisting {
+		err := s.checkMergeConflicts(inter, other, onConflict)
+		if err != nil {
+			return err
+		}
+	}
+
+	iterator := other.Iterator()
+
+	for {
+		domain, otherDomainStorageMap := iterator.Next()
+		if domain == common.StorageDomainUnknown {
+			break
+		}
+
+		domainStorageMap := s.GetDomain(inter, inter, domain, true)
+
+		domainIterator := otherDomainStorageMap.Iterator(inter)
+
+		for {
+			key, value := domainIterator.Next()
+			if key == nil {
+				break
+			}
+
+			storageMapKey, err := convertAtreeValueToStorageMapKey(key)
+			if err != nil {
+				return err
+			}
+
+			if onConflict == ConflictResolutionKeepExisting &&
+				domainStorageMap.ValueExists(storageMapKey) {
+
+				continue
+			}
+
+			value = value.Transfer(
+				inter,
+				EmptyLocationRange,
+				address,
+				false,
+				nil,
+				nil,
+				false, // value is an element of domain storage map because it is returned from iterator.
+			)
+
+			domainStorageMap.WriteValue(inter, storageMapKey, value)
+		}
+	}
+
+	return nil
+}
+
+// checkMergeConflicts returns an error if merging the other account storage map
+// with the given conflict resolution would fail or lose a resource.
+func (s *AccountStorageMap) checkMergeConflicts(
+	inter *Interpreter,
+	other *AccountStorageMap,
+	onConflict ConflictResolution,
+) error {
+	iterator := other.Iterator()
+
+	for {
+		domain, otherDomainStorageMap := iterator.Next()
+		if domain == common.StorageDomainUnknown {
+			break
+		}
+
+		domainStorageMap := s.GetDomain(inter, inter, domain, false)
+		if domainStorageMap == nil {
+			continue
+		}
+
+		domainIterator := otherDomainStorageMap.Iterator(inter)
+
+		for {
+			key := domainIterator.NextKey()
+			if key == nil {
+				break
+			}
+
+			storageMapKey, err := convertAtreeValueToStorageMapKey(key)
+			if err != nil {
+				return err
+			}
+
+			existingValue := domainStorageMap.ReadValue(inter, storageMapKey)
+			if existingValue == nil {
+				continue
+			}
+
+			switch onConflict {
+			case ConflictResolutionError:
+				return StorageMergeConflictError{
+					Address: common.Address(s.orderedMap.Address()),
+					Domain:  domain,
+					Key:     storageMapKey,
+				}
+
+			case ConflictResolutionOverwrite:
+				if existingValue.IsResourceKinded(inter) {
+					return ResourceLossError{
+						LocationRange: EmptyLocationRange,
+					}
+				}
+
+			default:
+				panic(errors.NewUnreachableError())
+			}
+		}
+	}
+
+	return nil
+}
+
+// AccountStorageMapsEqual returns true if the given account storage maps have the same domains,
+// and the domain storage maps of each domain have the same keys and equal values.
+// Values are compared using EquatableValue.Equal, values which are not equatable are considered unequal.
+// Two nil account storage maps are equal, a nil account storage map is not equal to a non-nil one.
+func AccountStorageMapsEqual(context ValueComparisonContext, a, b *AccountStorageMap) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	if a.Count() != b.Count() {
+		return false
+	}
+
+	iterator := a.Iterator()
+
+	for {
+		domain, domainStorageMap := iterator.Next()
+		if domain == common.StorageDomainUnknown {
+			return true
+		}
+
+		otherDomainStorageMap := b.GetDomain(context, nil, domain, false)
+		if otherDomainStorageMap == nil {
+			return false
+		}
+
+		if !domainStorageMapsEqual(context, domainStorageMap, otherDomainStorageMap) {
+			return false
+		}
+	}
+}
+
+func domainStorageMapsEqual(context ValueComparisonContext, a, b *DomainStorageMap) bool {
+	if a.Count() != b.Count() {
+		return false
+	}
+
+	iterator := a.Iterator(context)
+
+	for {
+		key, value := iterator.Next()
+		if key == nil {
+			return true
+		}
+
+		storageMapKey, err := convertAtreeValueToStorageMapKey(key)
+		if err != nil {
+			panic(err)
+		}
+
+		otherValue := b.ReadValue(context, storageMapKey)
+		if otherValue == nil {
+			return false
+		}
+
+		equatableValue, ok := value.(EquatableValue)
+		if !ok || !equatableValue.Equal(context, EmptyLocationRange, otherValue) {
+			return false
+		}
+	}
+}
+
+// ContentHash returns a deterministic digest of the content of the account storage map,
+// computed by folding the digests of all domains, keys, and values using the given hash function.
+//
+// The digest only depends on the logical content of the account storage map.
+// It is independent of how the content is laid out in slabs, which slabs are loaded or cached,
+// and of the iteration order of the underlying atree maps (which depends on the seed of each map).
+func (s *AccountStorageMap) ContentHash(hasher func([]byte) []byte) []byte {
+	return atreeValueContentHash(
+		s.orderedMap.Storage,
+		s.orderedMap.Address(),
+		s.orderedMap,
+		hasher,
+	)
+}
+
+const (
+	contentHashTagLeaf byte = iota
+	contentHashTagSome
+	contentHashTagArray
+	contentHashTagMap
+)
+
+// atreeValueContentHash returns the digest of the given atree value.
+//
+//   - Arrays are folded in order.
+//   - Maps are folded in the byte order of the digests of their entries,
+//     as the iteration order of atree maps depends on the seed of the map.
+//   - Other values are folded using their inlined encoding.
+func atreeValueContentHash(
+	storage atree.SlabStorage,
+	address atree.Address,
+	value atree.Value,
+	hasher func([]byte) []byte,
+) []byte {
+	switch value := value.(type) {
+	case *SomeValue:
+		data := []byte{contentHashTagSome}
+		data = appendContentHash(data, atreeValueContentHash(storage, address, value.value, hasher))
+		return hasher(data)
+
+	case *ArrayValue:
+		return atreeValueContentHash(storage, address, value.array, hasher)
+
+	case *DictionaryValue:
+		return atreeValueContentHash(storage, address, value.dictionary, hasher)
+
+	case *CompositeValue:
+		return atreeValueContentHash(storage, address, value.dictionary, hasher)
+
+	case *atree.Array:
+		data := []byte{contentHashTagArray}
+		data = appendContentHash(data, encodeAtreeTypeInfo(value.Type()))
+
+		iterator, err := value.ReadOnlyIterator()
+		if err != nil {
+			panic(errors.NewExternalError(err))
+		}
+
+		for {
+			element, err := iterator.Next()
+			if err != nil {
+				panic(errors.NewExternalError(err))
+			}
+			if element == nil {
+				break
+			}
+
+			data = appendContentHash(data, atreeValueContentHash(storage, address, element, hasher))
+		}
+
+		return hasher(data)
+
+	case *atree.OrderedMap:
+		entries := make([][]byte, 0, value.Count())
+
+		iterator, err := value.ReadOnlyIterator()
+		if err != nil {
+			panic(errors.NewExternalError(err))
+		}
+
+		for {
+			k, v, err := iterator.Next()
+			if err != nil {
+				panic(errors.NewExternalError(err))
+			}
+			if k == nil || v == nil {
+				break
+			}
+
+			var entry []byte
+			entry = appendContentHash(entry, atreeValueContentHash(storage, address, k, hasher))
+			entry = appendContentHash(entry, atreeValueContentHash(storage, address, v, hasher))
+			entries = append(entries, hasher(entry))
+		}
+
+		slices.SortFunc(entries, bytes.Compare)
+
+		data := []byte{contentHashTagMap}
+		data = appendContentHash(data, encodeAtreeTypeInfo(value.Type()))
+		for _, entry := range entries {
+			data = appendContentHash(data, entry)
+		}
+
+		return hasher(data)
+
+	default:
+		// Use the largest max inline size, so the value is always encoded inline,
+		// and no separate slab is stored.
+		storable, err := value.Storable(storage, address, math.MaxUint64)
+		if err != nil {
+			panic(errors.NewExternalError(err))
+		}
+
+		var buf bytes.Buffer
+		buf.WriteByte(contentHashTagLeaf)
+
+		enc := atree.NewEncoder(&buf, CBOREncMode)
+
+		err = storable.Encode(enc)
+		if err != nil {
+			panic(errors.NewExternalError(err))
+		}
+
+		err = enc.CBOR.Flush()
+		if err != nil {
+			panic(errors.NewExternalError(err))
+		}
+
+		return hasher(buf.Bytes())
+	}
+}
+
+// appendContentHash appends the given digest to the given data, prefixed with its length.
+func appendContentHash(data []byte, digest []byte) []byte {
+	data = binary.BigEndian.AppendUint64(data, uint64(len(digest)))
+	return append(data, digest...)
+}
+
+func encodeAtreeTypeInfo(typeInfo atree.TypeInfo) []byte {
+	var buf bytes.Buffer
+
+	enc := CBOREncMode.NewStreamEncoder(&buf)
+
+	err := typeInfo.Encode(enc)
+	if err != nil {
+		panic(errors.NewExternalError(err))
+	}
+
+	err = enc.Flush()
+	if err != nil {
+		panic(errors.NewExternalError(err))
+	}
+
+	return buf.Bytes()
+}
+
 // Iterator returns a mutable iterator (AccountStorageMapIterator),
 // which allows iterating over the domain and domain storage map.
+// The iteration order is deterministic for a given slab layout,
+// but it is not guaranteed to be the same for account storage maps with the same domains,
+// e.g. if they were built in a different order. Use IteratorSorted for a canonical order.
 func (s *AccountStorageMap) Iterator() *AccountStorageMapIterator {
 	mapIterator, err := s.orderedMap.Iterator(
 		StorageMapKeyAtreeValueComparator,
@@ -303,20 +1113,134 @@ func (s *AccountStorageMap) Iterator() *AccountStorageMapIterator {
 	}
 
 	return &AccountStorageMapIterator{
-		mapIterator: mapIterator,
-		storage:     s.orderedMap.Storage,
+		mapIterator:       mapIterator,
+		storage:           s.orderedMap.Storage,
+		accountStorageMap: s,
+		modificationCount: s.modificationCount,
 	}
 }
 
+// IteratorForDomains returns an iterator (AccountStorageMapOrderedIterator),
+// which allows iterating over the given domains and their domain storage maps, in ascending order.
+// Domains which don't exist are skipped, and duplicate domains are only iterated once.
+// Only the given domains are looked up, the domain storage maps of other domains are not loaded.
+func (s *AccountStorageMap) IteratorForDomains(domains []common.StorageDomain) *AccountStorageMapOrderedIterator {
+	sortedDomains := slices.Clone(domains)
+	slices.Sort(sortedDomains)
+	sortedDomains = slices.Compact(sortedDomains)
+
+	existingDomains := sortedDomains[:0]
+	for _, domain := range sortedDomains {
+		if !s.DomainExists(domain) {
+			continue
+		}
+		existingDomains = append(existingDomains, domain)
+	}
+
+	return &AccountStorageMapOrderedIterator{
+		accountStorageMap: s,
+		domains:           existingDomains,
+		modificationCount: s.modificationCount,
+	}
+}
+
+// IteratorInOrder returns an iterator (AccountStorageMapOrderedIterator),
+// which allows iterating over the domains and domain storage maps in the given order.
+// Domains which don't exist are skipped, and duplicate domains are only iterated once.
+// Domains which exist but are not in the given order are iterated afterward, in ascending order.
+func (s *AccountStorageMap) IteratorInOrder(order []common.StorageDomain) *AccountStorageMapOrderedIterator {
+	existingDomains := s.Domains()
+
+	domains := make([]common.StorageDomain, 0, len(existingDomains))
+
+	for _, domain := range order {
+		if _, ok := existingDomains[domain]; !ok {
+			continue
+		}
+		// Remove domain from existing domains, so it is only iterated once,
+		// and only unexpected domains remain.
+		delete(existingDomains, domain)
+
+		domains = append(domains, domain)
+	}
+
+	unexpectedDomains := make([]common.StorageDomain, 0, len(existingDomains))
+	for domain := range existingDomains { //nolint:maprange
+		unexpectedDomains = append(unexpectedDomains, domain)
+	}
+	slices.Sort(unexpectedDomains)
+
+	return &AccountStorageMapOrderedIterator{
+		accountStorageMap: s,
+		domains:           append(domains, unexpectedDomains...),
+		modificationCount: s.modificationCount,
+	}
+}
+
+// IteratorSorted returns an iterator (AccountStorageMapOrderedIterator),
+// which allows iterating over the domains and domain storage maps in canonical order, see DomainsSorted.
+// Account storage maps with the same domains are always iterated in the same order.
+func (s *AccountStorageMap) IteratorSorted() *AccountStorageMapOrderedIterator {
+	return &AccountStorageMapOrderedIterator{
+		accountStorageMap: s,
+		domains:           s.DomainsSorted(),
+		modificationCount: s.modificationCount,
+	}
+}
+
+// AccountStorageMapOrderedIterator is an iterator over AccountStorageMap,
+// which iterates over domains in a given order.
+// The iterator panics with a ConcurrentModificationError
+// if the account storage map is modified during iteration.
+type AccountStorageMapOrderedIterator struct {
+	accountStorageMap *AccountStorageMap
+	domains           []common.StorageDomain
+	index             int
+	modificationCount uint64
+}
+
+// Next returns the next domain and domain storage map.
+// If there is no more domain, (common.StorageDomainUnknown, nil) is returned.
+func (i *AccountStorageMapOrderedIterator) Next() (common.StorageDomain, *DomainStorageMap) {
+	i.accountStorageMap.checkModification(i.modificationCount)
+
+	if i.index >= len(i.domains) {
+		return common.StorageDomainUnknown, nil
+	}
+
+	domain := i.domains[i.index]
+	i.index++
+
+	domainStorageMap := i.accountStorageMap.GetDomain(nil, nil, domain, false)
+	if domainStorageMap == nil {
+		panic(errors.NewUnexpectedError("domain %s doesn't exist", domain.Identifier()))
+	}
+
+	return domain, domainStorageMap
+}
+
 // AccountStorageMapIterator is an iterator over AccountStorageMap.
+// The iterator panics with a ConcurrentModificationError
+// if the account storage map is modified during iteration.
 type AccountStorageMapIterator struct {
-	mapIterator atree.MapIterator
-	storage     atree.SlabStorage
+	mapIterator       atree.MapIterator
+	storage           atree.SlabStorage
+	accountStorageMap *AccountStorageMap
+	modificationCount uint64
+}
+
+// checkModification panics if the account storage map was modified since the iterator was created.
+func (s *AccountStorageMap) checkModification(modificationCount uint64) {
+	if s.modificationCount != modificationCount {
+		panic(ConcurrentModificationError{})
+	}
 }
 
 // Next returns the next domain and domain storage map.
 // If there is no more domain, (common.StorageDomainUnknown, nil) is returned.
 func (i *AccountStorageMapIterator) Next() (common.StorageDomain, *DomainStorageMap) {
+	i.accountStorageMap.checkModification(i.modificationCount)
+
 	k, v, err := i.mapIterator.Next()
 	if err != nil {
 		panic(errors.NewExternalError(err))
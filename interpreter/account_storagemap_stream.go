@@ -0,0 +1,635 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"io"
+	"math"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/onflow/atree"
+
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/errors"
+)
+
+// accountStorageMapStreamVersion is the version of the account storage map stream format.
+const accountStorageMapStreamVersion = 1
+
+// Account storage map stream value tags.
+// Every value in the stream is encoded as a CBOR array,
+// where the first element is one of these tags.
+const (
+	// accountStorageMapStreamTagLeaf is followed by the inlined encoding of the value:
+	// [tag, storable]
+	accountStorageMapStreamTagLeaf uint64 = iota
+	// accountStorageMapStreamTagSome is followed by the inner value:
+	// [tag, value]
+	accountStorageMapStreamTagSome
+	// accountStorageMapStreamTagArray is followed by the type info and the elements:
+	// [tag, type info, [value, ...]]
+	accountStorageMapStreamTagArray
+	// accountStorageMapStreamTagMap is followed by the type info and the entries:
+	// [tag, type info, [[key, value], ...]]
+	accountStorageMapStreamTagMap
+)
+
+// accountStorageMapStreamFlushThreshold is the approximate size of the encoded data,
+// after which the encoded data of a container is flushed to the writer.
+const accountStorageMapStreamFlushThreshold = 64 * 1024
+
+// EncodeTo writes the content of the account storage map to the given writer,
+// as a self-describing CBOR stream:
+//
+//	[version, [[domain, [[key, value], ...]], ...]]
+//
+// Values are encoded independently of how they are laid out in slabs,
+// i.e. values which are stored in separate slabs are encoded inline.
+// The stream can be decoded using DecodeAccountStorageMap.
+//
+// The encoded data is written to the writer after each domain storage map entry,
+// and within large containers, so the encoded data of large values doesn't accumulate in memory.
+func (s *AccountStorageMap) EncodeTo(w io.Writer) error {
+	e := &accountStorageMapStreamEncoder{
+		enc:     atree.NewEncoder(w, CBOREncMode),
+		storage: s.orderedMap.Storage,
+		address: s.orderedMap.Address(),
+	}
+
+	err := e.enc.CBOR.EncodeArrayHead(2)
+	if err != nil {
+		return err
+	}
+
+	err = e.enc.CBOR.EncodeUint64(accountStorageMapStreamVersion)
+	if err != nil {
+		return err
+	}
+
+	err = e.enc.CBOR.EncodeArrayHead(s.Count())
+	if err != nil {
+		return err
+	}
+
+	iterator := s.Iterator()
+
+	for {
+		domain, domainStorageMap := iterator.Next()
+		if domain == common.StorageDomainUnknown {
+			break
+		}
+
+		err = e.enc.CBOR.EncodeArrayHead(2)
+		if err != nil {
+			return err
+		}
+
+		err = e.enc.CBOR.EncodeUint64(uint64(domain))
+		if err != nil {
+			return err
+		}
+
+		err = e.enc.CBOR.EncodeArrayHead(domainStorageMap.Count())
+		if err != nil {
+			return err
+		}
+
+		domainIterator, err := domainStorageMap.orderedMap.ReadOnlyIterator()
+		if err != nil {
+			return errors.NewExternalError(err)
+		}
+
+		for {
+			key, value, err := domainIterator.Next()
+			if err != nil {
+				return errors.NewExternalError(err)
+			}
+			if key == nil || value == nil {
+				break
+			}
+
+			err = e.enc.CBOR.EncodeArrayHead(2)
+			if err != nil {
+				return err
+			}
+
+			err = e.encodeLeaf(key)
+			if err != nil {
+				return err
+			}
+
+			err = e.encodeValue(value)
+			if err != nil {
+				return err
+			}
+
+			err = e.flush()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return e.flush()
+}
+
+// accountStorageMapStreamEncoder encodes values of an account storage map stream, see AccountStorageMap.EncodeTo.
+type accountStorageMapStreamEncoder struct {
+	enc     *atree.Encoder
+	storage atree.SlabStorage
+	address atree.Address
+	// bufferedSize is the approximate size of the encoded data which was not flushed yet.
+	bufferedSize uint64
+}
+
+// flush writes the encoded data to the writer.
+func (e *accountStorageMapStreamEncoder) flush() error {
+	e.bufferedSize = 0
+	return e.enc.CBOR.Flush()
+}
+
+// flushIfNeeded writes the encoded data to the writer,
+// if its size exceeds accountStorageMapStreamFlushThreshold.
+func (e *accountStorageMapStreamEncoder) flushIfNeeded() error {
+	if e.bufferedSize < accountStorageMapStreamFlushThreshold {
+		return nil
+	}
+	return e.flush()
+}
+
+// encodeValue encodes the given atree value.
+// Containers are encoded element by element, other values are encoded inline.
+func (e *accountStorageMapStreamEncoder) encodeValue(value atree.Value) error {
+	switch value := value.(type) {
+	case *SomeValue:
+		err := e.enc.CBOR.EncodeArrayHead(2)
+		if err != nil {
+			return err
+		}
+
+		err = e.enc.CBOR.EncodeUint64(accountStorageMapStreamTagSome)
+		if err != nil {
+			return err
+		}
+
+		return e.encodeValue(value.value)
+
+	case *ArrayValue:
+		return e.encodeValue(value.array)
+
+	case *DictionaryValue:
+		return e.encodeValue(value.dictionary)
+
+	case *CompositeValue:
+		return e.encodeValue(value.dictionary)
+
+	case *atree.Array:
+		err := e.enc.CBOR.EncodeArrayHead(3)
+		if err != nil {
+			return err
+		}
+
+		err = e.enc.CBOR.EncodeUint64(accountStorageMapStreamTagArray)
+		if err != nil {
+			return err
+		}
+
+		err = value.Type().Encode(e.enc.CBOR)
+		if err != nil {
+			return err
+		}
+
+		err = e.enc.CBOR.EncodeArrayHead(value.Count())
+		if err != nil {
+			return err
+		}
+
+		iterator, err := value.ReadOnlyIterator()
+		if err != nil {
+			return errors.NewExternalError(err)
+		}
+
+		for {
+			element, err := iterator.Next()
+			if err != nil {
+				return errors.NewExternalError(err)
+			}
+			if element == nil {
+				break
+			}
+
+			err = e.encodeValue(element)
+			if err != nil {
+				return err
+			}
+
+			err = e.flushIfNeeded()
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	case *atree.OrderedMap:
+		err := e.enc.CBOR.EncodeArrayHead(3)
+		if err != nil {
+			return err
+		}
+
+		err = e.enc.CBOR.EncodeUint64(accountStorageMapStreamTagMap)
+		if err != nil {
+			return err
+		}
+
+		err = value.Type().Encode(e.enc.CBOR)
+		if err != nil {
+			return err
+		}
+
+		err = e.enc.CBOR.EncodeArrayHead(value.Count())
+		if err != nil {
+			return err
+		}
+
+		iterator, err := value.ReadOnlyIterator()
+		if err != nil {
+			return errors.NewExternalError(err)
+		}
+
+		for {
+			k, v, err := iterator.Next()
+			if err != nil {
+				return errors.NewExternalError(err)
+			}
+			if k == nil || v == nil {
+				break
+			}
+
+			err = e.enc.CBOR.EncodeArrayHead(2)
+			if err != nil {
+				return err
+			}
+
+			err = e.encodeValue(k)
+			if err != nil {
+				return err
+			}
+
+			err = e.encodeValue(v)
+			if err != nil {
+				return err
+			}
+
+			err = e.flushIfNeeded()
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+
+	default:
+		err := e.enc.CBOR.EncodeArrayHead(2)
+		if err != nil {
+			return err
+		}
+
+		err = e.enc.CBOR.EncodeUint64(accountStorageMapStreamTagLeaf)
+		if err != nil {
+			return err
+		}
+
+		return e.encodeLeaf(value)
+	}
+}
+
+// encodeLeaf encodes the given value inline.
+func (e *accountStorageMapStreamEncoder) encodeLeaf(value atree.Value) error {
+	// Use the largest max inline size, so the value is always encoded inline,
+	// and no separate slab is stored.
+	storable, err := value.Storable(e.storage, e.address, math.MaxUint64)
+	if err != nil {
+		return errors.NewExternalError(err)
+	}
+
+	err = storable.Encode(e.enc)
+	if err != nil {
+		return err
+	}
+
+	e.bufferedSize += uint64(storable.ByteSize())
+
+	return nil
+}
+
+// DecodeAccountStorageMap creates a new account storage map in the given address,
+// from a stream written by AccountStorageMap.EncodeTo.
+func DecodeAccountStorageMap(
+	r io.Reader,
+	inter *Interpreter,
+	storage atree.SlabStorage,
+	address atree.Address,
+) (*AccountStorageMap, error) {
+
+	dec := CBORDecMode.NewStreamDecoder(r)
+
+	size, err := dec.DecodeArrayHead()
+	if err != nil {
+		return nil, err
+	}
+	if size != 2 {
+		return nil, errors.NewUnexpectedError(
+			"invalid account storage map stream: expected 2 elements, got %d",
+			size,
+		)
+	}
+
+	version, err := dec.DecodeUint64()
+	if err != nil {
+		return nil, err
+	}
+	if version != accountStorageMapStreamVersion {
+		return nil, errors.NewUnexpectedError(
+			"invalid account storage map stream: unsupported version %d",
+			version,
+		)
+	}
+
+	domainCount, err := dec.DecodeArrayHead()
+	if err != nil {
+		return nil, err
+	}
+
+	accountStorageMap := NewAccountStorageMap(inter, storage, address)
+
+	for i := uint64(0); i < domainCount; i++ {
+		size, err := dec.DecodeArrayHead()
+		if err != nil {
+			return nil, err
+		}
+		if size != 2 {
+			return nil, errors.NewUnexpectedError(
+				"invalid account storage map stream: expected 2 domain elements, got %d",
+				size,
+			)
+		}
+
+		rawDomain, err := dec.DecodeUint64()
+		if err != nil {
+			return nil, err
+		}
+
+		domain, err := common.StorageDomainFromUint64(rawDomain)
+		if err != nil {
+			return nil, err
+		}
+
+		if accountStorageMap.DomainExists(domain) {
+			return nil, errors.NewUnexpectedError(
+				"invalid account storage map stream: duplicate domain %s",
+				domain.Identifier(),
+			)
+		}
+
+		domainStorageMap := accountStorageMap.NewDomain(inter, inter, domain)
+
+		entryCount, err := dec.DecodeArrayHead()
+		if err != nil {
+			return nil, err
+		}
+
+		for j := uint64(0); j < entryCount; j++ {
+			size, err := dec.DecodeArrayHead()
+			if err != nil {
+				return nil, err
+			}
+			if size != 2 {
+				return nil, errors.NewUnexpectedError(
+					"invalid account storage map stream: expected 2 entry elements, got %d",
+					size,
+				)
+			}
+
+			key, err := decodeAccountStorageMapStreamLeaf(dec, inter, storage)
+			if err != nil {
+				return nil, err
+			}
+
+			storageMapKey, err := convertAtreeValueToStorageMapKey(key)
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := decodeAccountStorageMapStreamValue(dec, inter, storage, address)
+			if err != nil {
+				return nil, err
+			}
+
+			if domainStorageMap.SetValue(inter, storageMapKey, value) {
+				return nil, errors.NewUnexpectedError(
+					"invalid account storage map stream: duplicate key %s in domain %s",
+					storageMapKey,
+					domain.Identifier(),
+				)
+			}
+		}
+	}
+
+	return accountStorageMap, nil
+}
+
+// decodeAccountStorageMapStreamValue decodes a value written by accountStorageMapStreamEncoder.encodeValue,
+// and stores it in the given address.
+func decodeAccountStorageMapStreamValue(
+	dec *cbor.StreamDecoder,
+	inter *Interpreter,
+	storage atree.SlabStorage,
+	address atree.Address,
+) (atree.Value, error) {
+
+	size, err := dec.DecodeArrayHead()
+	if err != nil {
+		return nil, err
+	}
+	if size < 2 {
+		return nil, errors.NewUnexpectedError(
+			"invalid account storage map stream: expected at least 2 value elements, got %d",
+			size,
+		)
+	}
+
+	tag, err := dec.DecodeUint64()
+	if err != nil {
+		return nil, err
+	}
+
+	expectedSize := uint64(2)
+	if tag == accountStorageMapStreamTagArray || tag == accountStorageMapStreamTagMap {
+		expectedSize = 3
+	}
+	if size != expectedSize {
+		return nil, errors.NewUnexpectedError(
+			"invalid account storage map stream: expected %d value elements, got %d",
+			expectedSize,
+			size,
+		)
+	}
+
+	switch tag {
+	case accountStorageMapStreamTagLeaf:
+		return decodeAccountStorageMapStreamLeaf(dec, inter, storage)
+
+	case accountStorageMapStreamTagSome:
+		innerValue, err := decodeAccountStorageMapStreamValue(dec, inter, storage, address)
+		if err != nil {
+			return nil, err
+		}
+
+		return NewSomeValueNonCopying(
+			inter,
+			MustConvertStoredValue(inter, innerValue),
+		), nil
+
+	case accountStorageMapStreamTagArray:
+		typeInfo, err := DecodeTypeInfo(dec, inter)
+		if err != nil {
+			return nil, err
+		}
+
+		array, err := atree.NewArray(storage, address, typeInfo)
+		if err != nil {
+			return nil, errors.NewExternalError(err)
+		}
+
+		count, err := dec.DecodeArrayHead()
+		if err != nil {
+			return nil, err
+		}
+
+		for i := uint64(0); i < count; i++ {
+			element, err := decodeAccountStorageMapStreamValue(dec, inter, storage, address)
+			if err != nil {
+				return nil, err
+			}
+
+			err = array.Append(element)
+			if err != nil {
+				return nil, errors.NewExternalError(err)
+			}
+		}
+
+		return array, nil
+
+	case accountStorageMapStreamTagMap:
+		typeInfo, err := DecodeTypeInfo(dec, inter)
+		if err != nil {
+			return nil, err
+		}
+
+		orderedMap, err := atree.NewMap(
+			storage,
+			address,
+			atree.NewDefaultDigesterBuilder(),
+			typeInfo,
+		)
+		if err != nil {
+			return nil, errors.NewExternalError(err)
+		}
+
+		var comparator atree.ValueComparator
+		var hashInputProvider atree.HashInputProvider
+		if typeInfo.IsComposite() {
+			// Composite fields are keyed by field name
+			comparator = StringAtreeValueComparator
+			hashInputProvider = StringAtreeValueHashInput
+		} else {
+			comparator = newValueComparator(inter, EmptyLocationRange)
+			hashInputProvider = newHashInputProvider(inter, EmptyLocationRange)
+		}
+
+		count, err := dec.DecodeArrayHead()
+		if err != nil {
+			return nil, err
+		}
+
+		for i := uint64(0); i < count; i++ {
+			size, err := dec.DecodeArrayHead()
+			if err != nil {
+				return nil, err
+			}
+			if size != 2 {
+				return nil, errors.NewUnexpectedError(
+					"invalid account storage map stream: expected 2 entry elements, got %d",
+					size,
+				)
+			}
+
+			key, err := decodeAccountStorageMapStreamValue(dec, inter, storage, address)
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := decodeAccountStorageMapStreamValue(dec, inter, storage, address)
+			if err != nil {
+				return nil, err
+			}
+
+			existingStorable, err := orderedMap.Set(comparator, hashInputProvider, key, value)
+			if err != nil {
+				return nil, errors.NewExternalError(err)
+			}
+			if existingStorable != nil {
+				return nil, errors.NewUnexpectedError(
+					"invalid account storage map stream: duplicate map key",
+				)
+			}
+		}
+
+		return orderedMap, nil
+
+	default:
+		return nil, errors.NewUnexpectedError(
+			"invalid account storage map stream: unknown value tag %d",
+			tag,
+		)
+	}
+}
+
+// decodeAccountStorageMapStreamLeaf decodes a value written by accountStorageMapStreamEncoder.encodeLeaf.
+func decodeAccountStorageMapStreamLeaf(
+	dec *cbor.StreamDecoder,
+	inter *Interpreter,
+	storage atree.SlabStorage,
+) (atree.Value, error) {
+
+	storable, err := DecodeStorable(dec, atree.SlabIDUndefined, nil, inter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Leaves are always encoded inline, never as a reference to a separate slab.
+	if _, ok := storable.(atree.SlabIDStorable); ok {
+		return nil, errors.NewUnexpectedError(
+			"invalid account storage map stream: unexpected slab ID",
+		)
+	}
+
+	return storable.StoredValue(storage)
+}
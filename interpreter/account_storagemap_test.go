@@ -19,9 +19,12 @@
 package interpreter_test
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"math/rand"
 	goruntime "runtime"
 	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"testing"
@@ -31,6 +34,7 @@ import (
 	"github.com/onflow/cadence/common"
 	"github.com/onflow/cadence/interpreter"
 	"github.com/onflow/cadence/runtime"
+	. "github.com/onflow/cadence/test_utils/common_utils"
 	. "github.com/onflow/cadence/test_utils/interpreter_utils"
 	. "github.com/onflow/cadence/test_utils/runtime_utils"
 
@@ -186,6 +190,152 @@ func TestAccountStorageMapGetDomain(t *testing.T) {
 	})
 }
 
+func TestAccountStorageMapGetOrCreateDomain(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	random := rand.New(rand.NewSource(42))
+
+	ledger := NewTestLedger(nil, nil)
+	storage := runtime.NewStorage(
+		ledger,
+		nil,
+		runtime.StorageConfig{},
+	)
+
+	// Turn off AtreeStorageValidationEnabled and explicitly check atree storage health at the end of test.
+	// This is because AccountStorageMap isn't created through runtime.Storage, so there isn't any
+	// account register to match AccountStorageMap root slab.
+	const atreeValueValidationEnabled = true
+	const atreeStorageValidationEnabled = false
+	inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+		t,
+		storage,
+		atreeValueValidationEnabled,
+		atreeStorageValidationEnabled,
+	)
+
+	existingDomains := []common.StorageDomain{common.PathDomainStorage.StorageDomain()}
+
+	const count = 10
+	accountStorageMap, accountValues := createAccountStorageMap(storage, inter, address, existingDomains, count, random)
+
+	for _, domain := range common.AllStorageDomains {
+		existed := slices.Contains(existingDomains, domain)
+
+		// First call creates the domain storage map, if it doesn't exist
+
+		domainStorageMap, created := accountStorageMap.GetOrCreateDomain(nil, inter, domain)
+		require.NotNil(t, domainStorageMap)
+		require.Equal(t, !existed, created)
+
+		if existed {
+			checkDomainStorageMapData(t, inter, domainStorageMap, accountValues[domain])
+		} else {
+			require.Equal(t, uint64(0), domainStorageMap.Count())
+			accountValues[domain] = make(domainStorageMapValues)
+		}
+
+		// Subsequent call gets the existing domain storage map
+
+		domainStorageMap, created = accountStorageMap.GetOrCreateDomain(nil, inter, domain)
+		require.NotNil(t, domainStorageMap)
+		require.False(t, created)
+	}
+
+	require.Equal(t, uint64(len(common.AllStorageDomains)), accountStorageMap.Count())
+
+	checkAccountStorageMapData(t, inter, accountStorageMap, accountValues)
+
+	CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
+}
+
+func TestAccountStorageMapNewDomainIfAbsent(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	newStorageAndInterpreter := func(t *testing.T) (*runtime.Storage, *interpreter.Interpreter) {
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off AtreeStorageValidationEnabled and explicitly check atree storage health at the end of test.
+		// This is because AccountStorageMap isn't created through runtime.Storage, so there isn't any
+		// account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
+		return storage, inter
+	}
+
+	t.Run("absent", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		storage, inter := newStorageAndInterpreter(t)
+
+		existingDomains := []common.StorageDomain{common.PathDomainStorage.StorageDomain()}
+
+		const count = 10
+		accountStorageMap, accountValues := createAccountStorageMap(storage, inter, address, existingDomains, count, random)
+
+		domain := common.PathDomainPublic.StorageDomain()
+
+		domainStorageMap, created := accountStorageMap.NewDomainIfAbsent(nil, inter, domain)
+		require.NotNil(t, domainStorageMap)
+		require.True(t, created)
+		require.Equal(t, uint64(0), domainStorageMap.Count())
+
+		accountValues[domain] = make(domainStorageMapValues)
+
+		require.Equal(t, uint64(2), accountStorageMap.Count())
+
+		checkAccountStorageMapData(t, inter, accountStorageMap, accountValues)
+
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
+	})
+
+	t.Run("present", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		storage, inter := newStorageAndInterpreter(t)
+
+		domain := common.PathDomainStorage.StorageDomain()
+		existingDomains := []common.StorageDomain{domain}
+
+		const count = 10
+		accountStorageMap, accountValues := createAccountStorageMap(storage, inter, address, existingDomains, count, random)
+
+		domainStorageMap, created := accountStorageMap.NewDomainIfAbsent(nil, inter, domain)
+		require.NotNil(t, domainStorageMap)
+		require.False(t, created)
+
+		// The existing domain storage map is returned, not replaced
+
+		checkDomainStorageMapData(t, inter, domainStorageMap, accountValues[domain])
+
+		require.Equal(t, uint64(1), accountStorageMap.Count())
+
+		checkAccountStorageMapData(t, inter, accountStorageMap, accountValues)
+
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
+	})
+}
+
 func TestAccountStorageMapCreateDomain(t *testing.T) {
 	t.Parallel()
 
@@ -405,14 +555,12 @@ func TestAccountStorageMapSetAndUpdateDomain(t *testing.T) {
 	})
 }
 
-func TestAccountStorageMapRemoveDomain(t *testing.T) {
+func TestAccountStorageMapWriteDomainStrict(t *testing.T) {
 	t.Parallel()
 
 	address := common.MustBytesToAddress([]byte{0x1})
 
-	t.Run("empty", func(t *testing.T) {
-		t.Parallel()
-
+	newStorageAndInterpreter := func(t *testing.T) (*runtime.Storage, *interpreter.Interpreter) {
 		ledger := NewTestLedger(nil, nil)
 		storage := runtime.NewStorage(
 			ledger,
@@ -432,34 +580,162 @@ func TestAccountStorageMapRemoveDomain(t *testing.T) {
 			atreeStorageValidationEnabled,
 		)
 
-		accountValues := make(accountStorageMapValues)
+		return storage, inter
+	}
+
+	domain := common.PathDomainStorage.StorageDomain()
+
+	t.Run("new domain", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		storage, inter := newStorageAndInterpreter(t)
 
 		accountStorageMap := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
-		require.NotNil(t, accountStorageMap)
-		require.Equal(t, uint64(0), accountStorageMap.Count())
 
-		accountStorageMapRootSlabID := accountStorageMap.SlabID()
+		const count = 10
+		domainStorageMap := interpreter.NewDomainStorageMap(nil, storage, atree.Address(address))
+		domainValues := writeRandomValuesToDomainStorageMap(inter, domainStorageMap, count, random)
 
-		for _, domain := range common.AllStorageDomains {
-			existed := accountStorageMap.WriteDomain(inter, domain, nil)
-			require.False(t, existed)
-		}
+		existed, err := accountStorageMap.WriteDomainStrict(inter, domain, domainStorageMap)
+		require.NoError(t, err)
+		require.False(t, existed)
 
-		checkAccountStorageMapData(t, inter, accountStorageMap, accountValues)
+		checkAccountStorageMapData(t, inter, accountStorageMap, accountStorageMapValues{domain: domainValues})
 
-		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMapRootSlabID})
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
+	})
 
-		err := storage.PersistentSlabStorage.FastCommit(goruntime.NumCPU())
+	t.Run("overwrite empty domain", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		storage, inter := newStorageAndInterpreter(t)
+
+		const count = 0
+		accountStorageMap, _ := createAccountStorageMap(storage, inter, address, []common.StorageDomain{domain}, count, random)
+
+		const newCount = 10
+		domainStorageMap := interpreter.NewDomainStorageMap(nil, storage, atree.Address(address))
+		domainValues := writeRandomValuesToDomainStorageMap(inter, domainStorageMap, newCount, random)
+
+		existed, err := accountStorageMap.WriteDomainStrict(inter, domain, domainStorageMap)
 		require.NoError(t, err)
+		require.True(t, existed)
 
-		checkAccountStorageMapDataWithRawData(t, ledger.StoredValues, ledger.StorageIndices, accountStorageMapRootSlabID, accountValues)
+		checkAccountStorageMapData(t, inter, accountStorageMap, accountStorageMapValues{domain: domainValues})
+
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
 	})
 
-	t.Run("non-empty", func(t *testing.T) {
+	t.Run("overwrite non-empty domain", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		storage, inter := newStorageAndInterpreter(t)
+
+		const count = 10
+		accountStorageMap, accountValues := createAccountStorageMap(storage, inter, address, []common.StorageDomain{domain}, count, random)
+
+		domainStorageMap := interpreter.NewDomainStorageMap(nil, storage, atree.Address(address))
+
+		existed, err := accountStorageMap.WriteDomainStrict(inter, domain, domainStorageMap)
+		require.Equal(
+			t,
+			interpreter.DomainAlreadyExistsError{
+				Address: address,
+				Domain:  domain,
+			},
+			err,
+		)
+		require.True(t, existed)
+
+		// Existing domain is intact
+		checkAccountStorageMapData(t, inter, accountStorageMap, accountValues)
+
+		// Rejected domain storage map is not referenced, remove it explicitly
+		domainStorageMap.DeepRemove(inter, true)
+		err = storage.Remove(domainStorageMap.SlabID())
+		require.NoError(t, err)
+
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
+	})
+
+	t.Run("remove non-empty domain", func(t *testing.T) {
 		t.Parallel()
 
 		random := rand.New(rand.NewSource(42))
 
+		storage, inter := newStorageAndInterpreter(t)
+
+		const count = 10
+		accountStorageMap, _ := createAccountStorageMap(storage, inter, address, []common.StorageDomain{domain}, count, random)
+
+		existed, err := accountStorageMap.WriteDomainStrict(inter, domain, nil)
+		require.NoError(t, err)
+		require.True(t, existed)
+
+		checkAccountStorageMapData(t, inter, accountStorageMap, accountStorageMapValues{})
+
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
+	})
+}
+
+func TestAccountStorageMapWriteDomains(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	existingDomains := []common.StorageDomain{
+		common.PathDomainStorage.StorageDomain(),
+		common.PathDomainPublic.StorageDomain(),
+	}
+
+	// newDomainStorageMaps returns domain storage maps to write:
+	// new and existing domains are set, existing and non-existent domains are removed.
+	newDomainStorageMaps := func(
+		storage atree.SlabStorage,
+		inter *interpreter.Interpreter,
+		random *rand.Rand,
+	) (map[common.StorageDomain]*interpreter.DomainStorageMap, map[common.StorageDomain]domainStorageMapValues) {
+		const count = 10
+
+		domainStorageMaps := map[common.StorageDomain]*interpreter.DomainStorageMap{}
+		domainValues := map[common.StorageDomain]domainStorageMapValues{}
+
+		for _, domain := range []common.StorageDomain{
+			common.StorageDomainInbox,
+			common.PathDomainStorage.StorageDomain(),
+		} {
+			domainStorageMap := interpreter.NewDomainStorageMap(nil, storage, atree.Address(address))
+			domainStorageMaps[domain] = domainStorageMap
+			domainValues[domain] = writeRandomValuesToDomainStorageMap(inter, domainStorageMap, count, random)
+		}
+
+		domainStorageMaps[common.PathDomainPublic.StorageDomain()] = nil
+		domainStorageMaps[common.StorageDomainContract] = nil
+
+		return domainStorageMaps, domainValues
+	}
+
+	type result struct {
+		existed       map[common.StorageDomain]bool
+		accountValues accountStorageMapValues
+	}
+
+	write := func(
+		t *testing.T,
+		writeDomains func(
+			inter *interpreter.Interpreter,
+			accountStorageMap *interpreter.AccountStorageMap,
+			domainStorageMaps map[common.StorageDomain]*interpreter.DomainStorageMap,
+		) map[common.StorageDomain]bool,
+	) result {
+		random := rand.New(rand.NewSource(42))
+
 		ledger := NewTestLedger(nil, nil)
 		storage := runtime.NewStorage(
 			ledger,
@@ -479,33 +755,76 @@ func TestAccountStorageMapRemoveDomain(t *testing.T) {
 			atreeStorageValidationEnabled,
 		)
 
-		existingDomains := []common.StorageDomain{common.PathDomainStorage.StorageDomain()}
-
 		const count = 10
 		accountStorageMap, accountValues := createAccountStorageMap(storage, inter, address, existingDomains, count, random)
 
-		accountStorageMapRootSlabID := accountStorageMap.SlabID()
-
-		for _, domain := range common.AllStorageDomains {
+		domainStorageMaps, domainValues := newDomainStorageMaps(storage, inter, random)
 
-			existed := accountStorageMap.WriteDomain(inter, domain, nil)
-			require.Equal(t, slices.Contains(existingDomains, domain), existed)
+		existed := writeDomains(inter, accountStorageMap, domainStorageMaps)
 
-			delete(accountValues, domain)
+		for domain, domainStorageMap := range domainStorageMaps {
+			if domainStorageMap == nil {
+				delete(accountValues, domain)
+			} else {
+				accountValues[domain] = domainValues[domain]
+			}
 		}
 
 		checkAccountStorageMapData(t, inter, accountStorageMap, accountValues)
 
-		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMapRootSlabID})
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
 
-		err := storage.PersistentSlabStorage.FastCommit(goruntime.NumCPU())
-		require.NoError(t, err)
+		return result{
+			existed:       existed,
+			accountValues: accountValues,
+		}
+	}
 
-		checkAccountStorageMapDataWithRawData(t, ledger.StoredValues, ledger.StorageIndices, accountStorageMapRootSlabID, accountValues)
-	})
+	bulkResult := write(
+		t,
+		func(
+			inter *interpreter.Interpreter,
+			accountStorageMap *interpreter.AccountStorageMap,
+			domainStorageMaps map[common.StorageDomain]*interpreter.DomainStorageMap,
+		) map[common.StorageDomain]bool {
+			return accountStorageMap.WriteDomains(inter, domainStorageMaps)
+		},
+	)
+
+	sequentialResult := write(
+		t,
+		func(
+			inter *interpreter.Interpreter,
+			accountStorageMap *interpreter.AccountStorageMap,
+			domainStorageMaps map[common.StorageDomain]*interpreter.DomainStorageMap,
+		) map[common.StorageDomain]bool {
+			existed := map[common.StorageDomain]bool{}
+			for _, domain := range common.AllStorageDomains {
+				domainStorageMap, ok := domainStorageMaps[domain]
+				if !ok {
+					continue
+				}
+				existed[domain] = accountStorageMap.WriteDomain(inter, domain, domainStorageMap)
+			}
+			return existed
+		},
+	)
+
+	require.Equal(
+		t,
+		map[common.StorageDomain]bool{
+			common.PathDomainStorage.StorageDomain(): true,
+			common.PathDomainPublic.StorageDomain():  true,
+			common.StorageDomainInbox:                false,
+			common.StorageDomainContract:             false,
+		},
+		bulkResult.existed,
+	)
+	require.Equal(t, sequentialResult.existed, bulkResult.existed)
+	require.Equal(t, sequentialResult.accountValues, bulkResult.accountValues)
 }
 
-func TestAccountStorageMapIterator(t *testing.T) {
+func TestAccountStorageMapRemoveDomain(t *testing.T) {
 	t.Parallel()
 
 	address := common.MustBytesToAddress([]byte{0x1})
@@ -538,18 +857,21 @@ func TestAccountStorageMapIterator(t *testing.T) {
 		require.NotNil(t, accountStorageMap)
 		require.Equal(t, uint64(0), accountStorageMap.Count())
 
-		iterator := accountStorageMap.Iterator()
+		accountStorageMapRootSlabID := accountStorageMap.SlabID()
 
-		// Test calling Next() twice on empty account storage map.
-		for range 2 {
-			domain, domainStorageMap := iterator.Next()
-			require.Empty(t, domain)
-			require.Nil(t, domainStorageMap)
+		for _, domain := range common.AllStorageDomains {
+			existed := accountStorageMap.WriteDomain(inter, domain, nil)
+			require.False(t, existed)
 		}
 
 		checkAccountStorageMapData(t, inter, accountStorageMap, accountValues)
 
-		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMapRootSlabID})
+
+		err := storage.PersistentSlabStorage.FastCommit(goruntime.NumCPU())
+		require.NoError(t, err)
+
+		checkAccountStorageMapDataWithRawData(t, ledger.StoredValues, ledger.StorageIndices, accountStorageMapRootSlabID, accountValues)
 	})
 
 	t.Run("non-empty", func(t *testing.T) {
@@ -576,45 +898,33 @@ func TestAccountStorageMapIterator(t *testing.T) {
 			atreeStorageValidationEnabled,
 		)
 
-		existingDomains := []common.StorageDomain{
-			common.PathDomainStorage.StorageDomain(),
-			common.PathDomainPublic.StorageDomain(),
-		}
+		existingDomains := []common.StorageDomain{common.PathDomainStorage.StorageDomain()}
 
 		const count = 10
 		accountStorageMap, accountValues := createAccountStorageMap(storage, inter, address, existingDomains, count, random)
 
-		iterator := accountStorageMap.Iterator()
-
-		domainCount := 0
-		for {
-			domain, domainStorageMap := iterator.Next()
-			if domain == common.StorageDomainUnknown {
-				break
-			}
+		accountStorageMapRootSlabID := accountStorageMap.SlabID()
 
-			domainCount++
+		for _, domain := range common.AllStorageDomains {
 
-			require.True(t, slices.Contains(existingDomains, domain))
-			require.NotNil(t, domainStorageMap)
+			existed := accountStorageMap.WriteDomain(inter, domain, nil)
+			require.Equal(t, slices.Contains(existingDomains, domain), existed)
 
-			checkDomainStorageMapData(t, inter, domainStorageMap, accountValues[domain])
+			delete(accountValues, domain)
 		}
 
-		// Test calling Next() after iterator reaches the end.
-		domain, domainStorageMap := iterator.Next()
-		require.Equal(t, common.StorageDomainUnknown, domain)
-		require.Nil(t, domainStorageMap)
+		checkAccountStorageMapData(t, inter, accountStorageMap, accountValues)
 
-		require.Equal(t, len(existingDomains), domainCount)
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMapRootSlabID})
 
-		checkAccountStorageMapData(t, inter, accountStorageMap, accountValues)
+		err := storage.PersistentSlabStorage.FastCommit(goruntime.NumCPU())
+		require.NoError(t, err)
 
-		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
+		checkAccountStorageMapDataWithRawData(t, ledger.StoredValues, ledger.StorageIndices, accountStorageMapRootSlabID, accountValues)
 	})
 }
 
-func TestAccountStorageMapDomains(t *testing.T) {
+func TestAccountStorageMapClear(t *testing.T) {
 	t.Parallel()
 
 	address := common.MustBytesToAddress([]byte{0x1})
@@ -629,12 +939,24 @@ func TestAccountStorageMapDomains(t *testing.T) {
 			runtime.StorageConfig{},
 		)
 
+		// Turn off AtreeStorageValidationEnabled and explicitly check atree storage health at the end of test.
+		// This is because AccountStorageMap isn't created through runtime.Storage, so there isn't any
+		// account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
 		accountStorageMap := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
 		require.NotNil(t, accountStorageMap)
 		require.Equal(t, uint64(0), accountStorageMap.Count())
 
-		domains := accountStorageMap.Domains()
-		require.Equal(t, 0, len(domains))
+		accountStorageMap.Clear(inter)
+		require.Equal(t, uint64(0), accountStorageMap.Count())
 
 		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
 	})
@@ -651,33 +973,2477 @@ func TestAccountStorageMapDomains(t *testing.T) {
 			runtime.StorageConfig{},
 		)
 
-		// Turn off automatic AtreeStorageValidationEnabled and explicitly check atree storage health directly.
-		// This is because AccountStorageMap isn't created through storage, so there isn't any account register to match AccountStorageMap root slab.
+		// Turn off AtreeStorageValidationEnabled and explicitly check atree storage health at the end of test.
+		// This is because AccountStorageMap isn't created through runtime.Storage, so there isn't any
+		// account register to match AccountStorageMap root slab.
 		const atreeValueValidationEnabled = true
 		const atreeStorageValidationEnabled = false
-		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, atreeValueValidationEnabled, atreeStorageValidationEnabled)
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
 
 		existingDomains := []common.StorageDomain{
 			common.PathDomainStorage.StorageDomain(),
 			common.PathDomainPublic.StorageDomain(),
-			common.PathDomainPrivate.StorageDomain(),
+			common.StorageDomainContract,
 		}
 
-		const count = 10
+		// Use enough values so that domain storage maps are not inlined.
+		const count = 100
 		accountStorageMap, _ := createAccountStorageMap(storage, inter, address, existingDomains, count, random)
 
-		domains := accountStorageMap.Domains()
-		require.Equal(t, len(existingDomains), len(domains))
+		accountStorageMap.Clear(inter)
+		require.Equal(t, uint64(0), accountStorageMap.Count())
 
 		for _, domain := range existingDomains {
-			_, exist := domains[domain]
-			require.True(t, exist)
+			require.False(t, accountStorageMap.DomainExists(domain))
+		}
+
+		checkAccountStorageMapData(t, inter, accountStorageMap, accountStorageMapValues{})
+
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
+	})
+}
+
+func TestAccountStorageMapIterator(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off AtreeStorageValidationEnabled and explicitly check atree storage health at the end of test.
+		// This is because AccountStorageMap isn't created through runtime.Storage, so there isn't any
+		// account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
+		accountValues := make(accountStorageMapValues)
+
+		accountStorageMap := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
+		require.NotNil(t, accountStorageMap)
+		require.Equal(t, uint64(0), accountStorageMap.Count())
+
+		iterator := accountStorageMap.Iterator()
+
+		// Test calling Next() twice on empty account storage map.
+		for range 2 {
+			domain, domainStorageMap := iterator.Next()
+			require.Empty(t, domain)
+			require.Nil(t, domainStorageMap)
+		}
+
+		checkAccountStorageMapData(t, inter, accountStorageMap, accountValues)
+
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
+	})
+
+	t.Run("non-empty", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off AtreeStorageValidationEnabled and explicitly check atree storage health at the end of test.
+		// This is because AccountStorageMap isn't created through runtime.Storage, so there isn't any
+		// account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
+		existingDomains := []common.StorageDomain{
+			common.PathDomainStorage.StorageDomain(),
+			common.PathDomainPublic.StorageDomain(),
+		}
+
+		const count = 10
+		accountStorageMap, accountValues := createAccountStorageMap(storage, inter, address, existingDomains, count, random)
+
+		iterator := accountStorageMap.Iterator()
+
+		domainCount := 0
+		for {
+			domain, domainStorageMap := iterator.Next()
+			if domain == common.StorageDomainUnknown {
+				break
+			}
+
+			domainCount++
+
+			require.True(t, slices.Contains(existingDomains, domain))
+			require.NotNil(t, domainStorageMap)
+
+			checkDomainStorageMapData(t, inter, domainStorageMap, accountValues[domain])
 		}
 
+		// Test calling Next() after iterator reaches the end.
+		domain, domainStorageMap := iterator.Next()
+		require.Equal(t, common.StorageDomainUnknown, domain)
+		require.Nil(t, domainStorageMap)
+
+		require.Equal(t, len(existingDomains), domainCount)
+
+		checkAccountStorageMapData(t, inter, accountStorageMap, accountValues)
+
 		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
 	})
 }
 
+func TestAccountStorageMapIteratorForDomains(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	random := rand.New(rand.NewSource(42))
+
+	ledger := NewTestLedger(nil, nil)
+	storage := runtime.NewStorage(
+		ledger,
+		nil,
+		runtime.StorageConfig{},
+	)
+
+	// Turn off AtreeStorageValidationEnabled and explicitly check atree storage health at the end of test.
+	// This is because AccountStorageMap isn't created through runtime.Storage, so there isn't any
+	// account register to match AccountStorageMap root slab.
+	const atreeValueValidationEnabled = true
+	const atreeStorageValidationEnabled = false
+	inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+		t,
+		storage,
+		atreeValueValidationEnabled,
+		atreeStorageValidationEnabled,
+	)
+
+	existingDomains := []common.StorageDomain{
+		common.PathDomainStorage.StorageDomain(),
+		common.PathDomainPublic.StorageDomain(),
+		common.StorageDomainContract,
+		common.StorageDomainInbox,
+	}
+
+	const count = 10
+	accountStorageMap, accountValues := createAccountStorageMap(storage, inter, address, existingDomains, count, random)
+
+	testCases := []struct {
+		name    string
+		domains []common.StorageDomain
+	}{
+		{
+			name:    "none",
+			domains: nil,
+		},
+		{
+			name: "single",
+			domains: []common.StorageDomain{
+				common.PathDomainPublic.StorageDomain(),
+			},
+		},
+		{
+			name: "multiple, reversed",
+			domains: []common.StorageDomain{
+				common.StorageDomainInbox,
+				common.PathDomainStorage.StorageDomain(),
+			},
+		},
+		{
+			name: "non-existent",
+			domains: []common.StorageDomain{
+				common.PathDomainPrivate.StorageDomain(),
+				common.StorageDomainContract,
+			},
+		},
+		{
+			name: "duplicate",
+			domains: []common.StorageDomain{
+				common.StorageDomainContract,
+				common.PathDomainStorage.StorageDomain(),
+				common.StorageDomainContract,
+			},
+		},
+		{
+			name:    "all",
+			domains: common.AllStorageDomains,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			// Existing domains, in ascending order
+			var expectedDomains []common.StorageDomain
+			for _, domain := range common.AllStorageDomains {
+				if slices.Contains(tc.domains, domain) &&
+					slices.Contains(existingDomains, domain) {
+
+					expectedDomains = append(expectedDomains, domain)
+				}
+			}
+			slices.Sort(expectedDomains)
+
+			var domains []common.StorageDomain
+			iterator := accountStorageMap.IteratorForDomains(tc.domains)
+			for {
+				domain, domainStorageMap := iterator.Next()
+				if domain == common.StorageDomainUnknown {
+					break
+				}
+				domains = append(domains, domain)
+
+				checkDomainStorageMapData(t, inter, domainStorageMap, accountValues[domain])
+			}
+
+			require.Equal(t, expectedDomains, domains)
+
+			// Test calling Next() after iterator reaches the end.
+			domain, domainStorageMap := iterator.Next()
+			require.Equal(t, common.StorageDomainUnknown, domain)
+			require.Nil(t, domainStorageMap)
+		})
+	}
+
+	t.Run("only given domains are loaded", func(t *testing.T) {
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
+		// Write enough values so the domain storage maps are not inlined
+		const count = 100
+		accountStorageMap, _ := createAccountStorageMap(storage, inter, address, existingDomains, count, random)
+
+		err := storage.PersistentSlabStorage.FastCommit(goruntime.NumCPU())
+		require.NoError(t, err)
+
+		iterateAndCountReads := func(domains []common.StorageDomain) int {
+			var reads int
+
+			reloadedStorage := runtime.NewStorage(
+				NewTestLedgerWithData(
+					func(_, _, _ []byte) {
+						reads++
+					},
+					nil,
+					ledger.StoredValues,
+					ledger.StorageIndices,
+				),
+				nil,
+				runtime.StorageConfig{},
+			)
+
+			reloadedAccountStorageMap := interpreter.NewAccountStorageMapWithRootID(
+				reloadedStorage,
+				accountStorageMap.SlabID(),
+			)
+
+			iterator := reloadedAccountStorageMap.IteratorForDomains(domains)
+			for {
+				domain, _ := iterator.Next()
+				if domain == common.StorageDomainUnknown {
+					break
+				}
+			}
+
+			return reads
+		}
+
+		require.Less(t,
+			iterateAndCountReads(existingDomains[:1]),
+			iterateAndCountReads(existingDomains),
+		)
+	})
+
+	CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
+}
+
+func TestAccountStorageMapIteratorInOrder(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	random := rand.New(rand.NewSource(42))
+
+	ledger := NewTestLedger(nil, nil)
+	storage := runtime.NewStorage(
+		ledger,
+		nil,
+		runtime.StorageConfig{},
+	)
+
+	// Turn off AtreeStorageValidationEnabled and explicitly check atree storage health at the end of test.
+	// This is because AccountStorageMap isn't created through runtime.Storage, so there isn't any
+	// account register to match AccountStorageMap root slab.
+	const atreeValueValidationEnabled = true
+	const atreeStorageValidationEnabled = false
+	inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+		t,
+		storage,
+		atreeValueValidationEnabled,
+		atreeStorageValidationEnabled,
+	)
+
+	existingDomains := []common.StorageDomain{
+		common.PathDomainStorage.StorageDomain(),
+		common.PathDomainPublic.StorageDomain(),
+		common.StorageDomainContract,
+		common.StorageDomainInbox,
+	}
+
+	const count = 10
+	accountStorageMap, accountValues := createAccountStorageMap(storage, inter, address, existingDomains, count, random)
+
+	testCases := []struct {
+		name            string
+		order           []common.StorageDomain
+		expectedDomains []common.StorageDomain
+	}{
+		{
+			name:  "none",
+			order: nil,
+			// Unexpected domains are iterated in ascending order
+			expectedDomains: []common.StorageDomain{
+				common.StorageDomainPathStorage,
+				common.StorageDomainPathPublic,
+				common.StorageDomainContract,
+				common.StorageDomainInbox,
+			},
+		},
+		{
+			name: "all, reversed",
+			order: []common.StorageDomain{
+				common.StorageDomainInbox,
+				common.StorageDomainContract,
+				common.StorageDomainPathPublic,
+				common.StorageDomainPathStorage,
+			},
+			expectedDomains: []common.StorageDomain{
+				common.StorageDomainInbox,
+				common.StorageDomainContract,
+				common.StorageDomainPathPublic,
+				common.StorageDomainPathStorage,
+			},
+		},
+		{
+			name: "partial",
+			order: []common.StorageDomain{
+				common.StorageDomainContract,
+				common.StorageDomainPathPublic,
+			},
+			expectedDomains: []common.StorageDomain{
+				common.StorageDomainContract,
+				common.StorageDomainPathPublic,
+				common.StorageDomainPathStorage,
+				common.StorageDomainInbox,
+			},
+		},
+		{
+			name: "non-existent and duplicate",
+			order: []common.StorageDomain{
+				common.StorageDomainPathPrivate,
+				common.StorageDomainInbox,
+				common.StorageDomainAccountCapability,
+				common.StorageDomainInbox,
+			},
+			expectedDomains: []common.StorageDomain{
+				common.StorageDomainInbox,
+				common.StorageDomainPathStorage,
+				common.StorageDomainPathPublic,
+				common.StorageDomainContract,
+			},
+		},
+		{
+			name:  "all storage domains",
+			order: common.AllStorageDomains,
+			expectedDomains: []common.StorageDomain{
+				common.StorageDomainPathStorage,
+				common.StorageDomainPathPublic,
+				common.StorageDomainContract,
+				common.StorageDomainInbox,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+
+			var domains []common.StorageDomain
+			iterator := accountStorageMap.IteratorInOrder(tc.order)
+			for {
+				domain, domainStorageMap := iterator.Next()
+				if domain == common.StorageDomainUnknown {
+					break
+				}
+				domains = append(domains, domain)
+
+				checkDomainStorageMapData(t, inter, domainStorageMap, accountValues[domain])
+			}
+
+			require.Equal(t, tc.expectedDomains, domains)
+
+			// Test calling Next() after iterator reaches the end.
+			domain, domainStorageMap := iterator.Next()
+			require.Equal(t, common.StorageDomainUnknown, domain)
+			require.Nil(t, domainStorageMap)
+		})
+	}
+
+	CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
+}
+
+func TestAccountStorageMapIteratorSorted(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	// newAccountStorageMap builds an account storage map with the given domains,
+	// inserted in the given order, in a separate storage
+	newAccountStorageMap := func(
+		t *testing.T,
+		domains []common.StorageDomain,
+		count int,
+		seed int64,
+	) (
+		*runtime.Storage,
+		*interpreter.Interpreter,
+		*interpreter.AccountStorageMap,
+		accountStorageMapValues,
+	) {
+		random := rand.New(rand.NewSource(seed))
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off AtreeStorageValidationEnabled and explicitly check atree storage health at the end of test.
+		// This is because AccountStorageMap isn't created through runtime.Storage, so there isn't any
+		// account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
+		accountStorageMap, accountValues := createAccountStorageMap(storage, inter, address, domains, count, random)
+
+		return storage, inter, accountStorageMap, accountValues
+	}
+
+	domains := common.AllStorageDomains
+
+	reversedDomains := slices.Clone(domains)
+	slices.Reverse(reversedDomains)
+
+	// Build the maps with the domains inserted in a different order,
+	// and with a different number of values, so the slab layouts differ
+
+	storage1, inter1, accountStorageMap1, accountValues1 := newAccountStorageMap(t, domains, 10, 1)
+	storage2, inter2, accountStorageMap2, accountValues2 := newAccountStorageMap(t, reversedDomains, 50, 2)
+
+	expectedDomains := slices.Clone(domains)
+	sort.Slice(expectedDomains, func(i, j int) bool {
+		return expectedDomains[i].Identifier() < expectedDomains[j].Identifier()
+	})
+
+	require.Equal(t, expectedDomains, accountStorageMap1.DomainsSorted())
+	require.Equal(t, expectedDomains, accountStorageMap2.DomainsSorted())
+
+	iterateSorted := func(
+		t *testing.T,
+		inter *interpreter.Interpreter,
+		accountStorageMap *interpreter.AccountStorageMap,
+		accountValues accountStorageMapValues,
+	) []common.StorageDomain {
+		var domains []common.StorageDomain
+
+		iterator := accountStorageMap.IteratorSorted()
+		for {
+			domain, domainStorageMap := iterator.Next()
+			if domain == common.StorageDomainUnknown {
+				break
+			}
+			domains = append(domains, domain)
+
+			checkDomainStorageMapData(t, inter, domainStorageMap, accountValues[domain])
+		}
+
+		return domains
+	}
+
+	require.Equal(t, expectedDomains, iterateSorted(t, inter1, accountStorageMap1, accountValues1))
+	require.Equal(t, expectedDomains, iterateSorted(t, inter2, accountStorageMap2, accountValues2))
+
+	CheckAtreeStorageHealth(t, storage1, []atree.SlabID{accountStorageMap1.SlabID()})
+	CheckAtreeStorageHealth(t, storage2, []atree.SlabID{accountStorageMap2.SlabID()})
+}
+
+func TestAccountStorageMapIteratorConcurrentModification(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	existingDomains := []common.StorageDomain{
+		common.PathDomainStorage.StorageDomain(),
+		common.PathDomainPublic.StorageDomain(),
+		common.StorageDomainContract,
+	}
+
+	newAccountStorageMap := func(t *testing.T) (*interpreter.Interpreter, *interpreter.AccountStorageMap) {
+		random := rand.New(rand.NewSource(42))
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off AtreeStorageValidationEnabled, see other tests.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
+		const count = 10
+		accountStorageMap, _ := createAccountStorageMap(storage, inter, address, existingDomains, count, random)
+
+		return inter, accountStorageMap
+	}
+
+	t.Run("new domain", func(t *testing.T) {
+		t.Parallel()
+
+		inter, accountStorageMap := newAccountStorageMap(t)
+
+		iterator := accountStorageMap.Iterator()
+
+		domain, domainStorageMap := iterator.Next()
+		require.NotEqual(t, common.StorageDomainUnknown, domain)
+		require.NotNil(t, domainStorageMap)
+
+		// Getting an existing domain is not a modification
+
+		_, created := accountStorageMap.GetOrCreateDomain(nil, inter, existingDomains[0])
+		require.False(t, created)
+
+		_, domainStorageMap = iterator.Next()
+		require.NotNil(t, domainStorageMap)
+
+		_, created = accountStorageMap.GetOrCreateDomain(nil, inter, common.StorageDomainInbox)
+		require.True(t, created)
+
+		require.PanicsWithValue(t,
+			interpreter.ConcurrentModificationError{},
+			func() {
+				iterator.Next()
+			},
+		)
+	})
+
+	t.Run("remove domain", func(t *testing.T) {
+		t.Parallel()
+
+		inter, accountStorageMap := newAccountStorageMap(t)
+
+		iterator := accountStorageMap.IteratorForDomains(existingDomains[:1])
+
+		existed := accountStorageMap.WriteDomain(inter, existingDomains[1], nil)
+		require.True(t, existed)
+
+		require.PanicsWithValue(t,
+			interpreter.ConcurrentModificationError{},
+			func() {
+				iterator.Next()
+			},
+		)
+	})
+
+	t.Run("ordered iterator", func(t *testing.T) {
+		t.Parallel()
+
+		inter, accountStorageMap := newAccountStorageMap(t)
+
+		iterator := accountStorageMap.IteratorInOrder(existingDomains)
+
+		domain, domainStorageMap := iterator.Next()
+		require.Equal(t, existingDomains[0], domain)
+		require.NotNil(t, domainStorageMap)
+
+		accountStorageMap.Clear(inter)
+
+		require.PanicsWithValue(t,
+			interpreter.ConcurrentModificationError{},
+			func() {
+				iterator.Next()
+			},
+		)
+	})
+}
+
+func TestAccountStorageMapDomains(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		accountStorageMap := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
+		require.NotNil(t, accountStorageMap)
+		require.Equal(t, uint64(0), accountStorageMap.Count())
+
+		domains := accountStorageMap.Domains()
+		require.Equal(t, 0, len(domains))
+
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
+	})
+
+	t.Run("non-empty", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off automatic AtreeStorageValidationEnabled and explicitly check atree storage health directly.
+		// This is because AccountStorageMap isn't created through storage, so there isn't any account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, atreeValueValidationEnabled, atreeStorageValidationEnabled)
+
+		existingDomains := []common.StorageDomain{
+			common.PathDomainStorage.StorageDomain(),
+			common.PathDomainPublic.StorageDomain(),
+			common.PathDomainPrivate.StorageDomain(),
+		}
+
+		const count = 10
+		accountStorageMap, _ := createAccountStorageMap(storage, inter, address, existingDomains, count, random)
+
+		domains := accountStorageMap.Domains()
+		require.Equal(t, len(existingDomains), len(domains))
+
+		for _, domain := range existingDomains {
+			_, exist := domains[domain]
+			require.True(t, exist)
+		}
+
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
+	})
+}
+
+func TestAccountStorageMapCountAllValues(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		accountStorageMap := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
+		require.NotNil(t, accountStorageMap)
+		require.Equal(t, uint64(0), accountStorageMap.CountAllValues())
+
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
+	})
+
+	t.Run("empty domains", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off automatic AtreeStorageValidationEnabled and explicitly check atree storage health directly.
+		// This is because AccountStorageMap isn't created through storage, so there isn't any account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, atreeValueValidationEnabled, atreeStorageValidationEnabled)
+
+		domains := []common.StorageDomain{
+			common.PathDomainStorage.StorageDomain(),
+			common.PathDomainPublic.StorageDomain(),
+		}
+
+		const count = 0
+		accountStorageMap, _ := createAccountStorageMap(storage, inter, address, domains, count, random)
+		require.Equal(t, uint64(len(domains)), accountStorageMap.Count())
+		require.Equal(t, uint64(0), accountStorageMap.CountAllValues())
+
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
+	})
+
+	t.Run("non-empty", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off automatic AtreeStorageValidationEnabled and explicitly check atree storage health directly.
+		// This is because AccountStorageMap isn't created through storage, so there isn't any account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, atreeValueValidationEnabled, atreeStorageValidationEnabled)
+
+		accountStorageMap := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
+
+		domainCounts := map[common.StorageDomain]int{
+			common.PathDomainStorage.StorageDomain(): 100,
+			common.PathDomainPublic.StorageDomain():  10,
+			common.PathDomainPrivate.StorageDomain(): 1,
+			common.StorageDomainContract:             0,
+		}
+
+		var expectedCount uint64
+
+		for _, domain := range common.AllStorageDomains {
+			count, ok := domainCounts[domain]
+			if !ok {
+				continue
+			}
+
+			domainStorageMap := accountStorageMap.NewDomain(nil, inter, domain)
+			writeRandomValuesToDomainStorageMap(inter, domainStorageMap, count, random)
+
+			expectedCount += uint64(count)
+		}
+
+		require.Equal(t, uint64(len(domainCounts)), accountStorageMap.Count())
+		require.Equal(t, expectedCount, accountStorageMap.CountAllValues())
+
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
+	})
+}
+
+func TestAccountStorageMapDomainSizes(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	// accountStorageMapOverhead is the maximum size of the account storage map itself,
+	// excluding inlined domain storage maps, which is not attributed to any domain.
+	// For a few domains, the account storage map is a single root data slab,
+	// which contains the slab header, the domain keys, and the references to the domain storage maps.
+	const accountStorageMapOverhead = 256
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		accountStorageMap := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
+		require.Empty(t, accountStorageMap.DomainSizes(nil))
+	})
+
+	t.Run("non-empty", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off automatic AtreeStorageValidationEnabled and explicitly check atree storage health directly.
+		// This is because AccountStorageMap isn't created through storage, so there isn't any account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, atreeValueValidationEnabled, atreeStorageValidationEnabled)
+
+		accountStorageMap := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
+
+		domainCounts := map[common.StorageDomain]int{
+			common.PathDomainStorage.StorageDomain(): 100,
+			common.PathDomainPublic.StorageDomain():  10,
+			common.PathDomainPrivate.StorageDomain(): 1,
+			common.StorageDomainContract:             0,
+		}
+
+		for _, domain := range common.AllStorageDomains {
+			count, ok := domainCounts[domain]
+			if !ok {
+				continue
+			}
+
+			domainStorageMap := accountStorageMap.NewDomain(nil, inter, domain)
+			writeRandomValuesToDomainStorageMap(inter, domainStorageMap, count, random)
+		}
+
+		err := storage.Commit(inter, false)
+		require.NoError(t, err)
+
+		domainSizes := accountStorageMap.DomainSizes(inter)
+		require.Len(t, domainSizes, len(domainCounts))
+
+		// Larger domains have larger sizes
+		require.Greater(t,
+			domainSizes[common.PathDomainStorage.StorageDomain()],
+			domainSizes[common.PathDomainPublic.StorageDomain()],
+		)
+		require.Greater(t,
+			domainSizes[common.PathDomainPublic.StorageDomain()],
+			domainSizes[common.PathDomainPrivate.StorageDomain()],
+		)
+		require.Greater(t,
+			domainSizes[common.PathDomainPrivate.StorageDomain()],
+			domainSizes[common.StorageDomainContract],
+		)
+		require.NotZero(t, domainSizes[common.StorageDomainContract])
+
+		// Sum of domain sizes plus the account storage map overhead
+		// matches the size of all committed slabs of the account.
+
+		var domainSizesSum uint64
+		for _, size := range domainSizes { //nolint:maprange
+			domainSizesSum += size
+		}
+
+		var accountSize uint64
+		err = ledger.ForEach(func(owner, key, value []byte) error {
+			if string(owner) == string(address[:]) && key[0] == '$' {
+				accountSize += uint64(len(value))
+			}
+			return nil
+		})
+		require.NoError(t, err)
+
+		require.LessOrEqual(t, domainSizesSum, accountSize)
+		require.LessOrEqual(t, accountSize-domainSizesSum, uint64(accountStorageMapOverhead))
+
+		// Sizes are deterministic, and independent of loaded slabs
+
+		reloadedStorage := runtime.NewStorage(
+			NewTestLedgerWithData(nil, nil, ledger.StoredValues, ledger.StorageIndices),
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		reloadedAccountStorageMap := interpreter.NewAccountStorageMapWithRootID(
+			reloadedStorage,
+			accountStorageMap.SlabID(),
+		)
+
+		require.Equal(t, domainSizes, reloadedAccountStorageMap.DomainSizes(nil))
+		require.Equal(t, domainSizes, accountStorageMap.DomainSizes(inter))
+
+		// The sizes of the retrieved slabs are metered.
+		// Inlined domain storage maps are not retrieved, so not all sizes are metered
+
+		gauge := newTestMemoryGauge()
+		require.Equal(t, domainSizes, accountStorageMap.DomainSizes(gauge))
+
+		meteredSize := gauge.getMemory(common.MemoryKindBytes)
+		require.Positive(t, meteredSize)
+		require.LessOrEqual(t, meteredSize, domainSizesSum)
+
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
+	})
+}
+
+func TestAccountStorageMapSlabIDs(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		accountStorageMap := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
+
+		require.Equal(t,
+			[]atree.SlabID{accountStorageMap.SlabID()},
+			accountStorageMap.SlabIDs(),
+		)
+
+		CheckAtreeStorageHealth(t, storage, accountStorageMap.SlabIDs())
+	})
+
+	t.Run("inlined and non-inlined domains", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off automatic AtreeStorageValidationEnabled and explicitly check atree storage health directly.
+		// This is because AccountStorageMap isn't created through storage, so there isn't any account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, atreeValueValidationEnabled, atreeStorageValidationEnabled)
+
+		accountStorageMap := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
+
+		domainCounts := map[common.StorageDomain]int{
+			common.PathDomainStorage.StorageDomain(): 100,
+			common.PathDomainPublic.StorageDomain():  1,
+			common.StorageDomainContract:             50,
+		}
+
+		for _, domain := range common.AllStorageDomains {
+			count, ok := domainCounts[domain]
+			if !ok {
+				continue
+			}
+
+			domainStorageMap := accountStorageMap.NewDomain(nil, inter, domain)
+			writeRandomValuesToDomainStorageMap(inter, domainStorageMap, count, random)
+		}
+
+		err := storage.Commit(inter, false)
+		require.NoError(t, err)
+
+		var expectedDomainSlabIDs []atree.SlabID
+
+		iterator := accountStorageMap.Iterator()
+		for {
+			domain, domainStorageMap := iterator.Next()
+			if domain == common.StorageDomainUnknown {
+				break
+			}
+
+			if domain == common.PathDomainPublic.StorageDomain() {
+				require.True(t, domainStorageMap.Inlined())
+				continue
+			}
+
+			require.False(t, domainStorageMap.Inlined())
+			expectedDomainSlabIDs = append(expectedDomainSlabIDs, domainStorageMap.SlabID())
+		}
+		require.Len(t, expectedDomainSlabIDs, 2)
+
+		slabIDs := accountStorageMap.SlabIDs()
+		require.Equal(t,
+			append([]atree.SlabID{accountStorageMap.SlabID()}, expectedDomainSlabIDs...),
+			slabIDs,
+		)
+
+		// atree only reports the account storage map root slab as a root slab
+
+		rootSlabIDs, err := atree.CheckStorageHealth(storage, -1)
+		require.NoError(t, err)
+		require.Equal(t,
+			map[atree.SlabID]struct{}{
+				accountStorageMap.SlabID(): {},
+			},
+			rootSlabIDs,
+		)
+
+		// The domain storage map root slabs are referenced by the account storage map root slab
+
+		childSlabIDs, brokenSlabIDs, err := storage.GetAllChildReferences(accountStorageMap.SlabID())
+		require.NoError(t, err)
+		require.Empty(t, brokenSlabIDs)
+
+		for _, domainSlabID := range slabIDs[1:] {
+			require.Contains(t, childSlabIDs, domainSlabID)
+		}
+	})
+}
+
+func TestAccountStorageMapModifiedDomains(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	storageDomain := common.PathDomainStorage.StorageDomain()
+	publicDomain := common.PathDomainPublic.StorageDomain()
+	privateDomain := common.PathDomainPrivate.StorageDomain()
+	contractDomain := common.StorageDomainContract
+
+	test := func(t *testing.T, count int, inlined bool) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off automatic AtreeStorageValidationEnabled and explicitly check atree storage health directly.
+		// This is because AccountStorageMap isn't created through storage, so there isn't any account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, atreeValueValidationEnabled, atreeStorageValidationEnabled)
+
+		accountStorageMap := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
+
+		requireModifiedDomains := func(expected ...common.StorageDomain) {
+			expectedDomains := make(map[common.StorageDomain]struct{}, len(expected))
+			for _, domain := range expected {
+				expectedDomains[domain] = struct{}{}
+			}
+			require.Equal(t, expectedDomains, accountStorageMap.ModifiedDomains(nil))
+		}
+
+		commit := func() {
+			err := storage.Commit(inter, false)
+			require.NoError(t, err)
+		}
+
+		writeValue := func(domain common.StorageDomain, key string, value int64) {
+			domainStorageMap := accountStorageMap.GetDomain(nil, inter, domain, false)
+			require.NotNil(t, domainStorageMap)
+			domainStorageMap.WriteValue(
+				inter,
+				interpreter.StringStorageMapKey(key),
+				interpreter.NewUnmeteredIntValueFromInt64(value),
+			)
+		}
+
+		// New domains are modified
+
+		for _, domain := range []common.StorageDomain{storageDomain, publicDomain, contractDomain} {
+			domainStorageMap := accountStorageMap.NewDomain(nil, inter, domain)
+			writeRandomValuesToDomainStorageMap(inter, domainStorageMap, count, random)
+			require.Equal(t, inlined, domainStorageMap.Inlined())
+		}
+
+		requireModifiedDomains(storageDomain, publicDomain, contractDomain)
+
+		// No domain is modified after commit
+
+		commit()
+
+		requireModifiedDomains()
+
+		// Modify a subset of domains
+
+		writeValue(storageDomain, "a", 1)
+		writeValue(contractDomain, "b", 2)
+
+		requireModifiedDomains(storageDomain, contractDomain)
+
+		commit()
+
+		requireModifiedDomains()
+
+		// Reverted modifications are only not reported for inlined domains,
+		// as their content is compared with the committed state.
+		// Domains stored in separate slabs are reported, as their slabs have unsaved changes.
+
+		writeValue(publicDomain, "c", 3)
+
+		requireModifiedDomains(publicDomain)
+
+		domainStorageMap := accountStorageMap.GetDomain(nil, inter, publicDomain, false)
+		domainStorageMap.RemoveValue(inter, interpreter.StringStorageMapKey("c"))
+
+		if inlined {
+			requireModifiedDomains()
+		} else {
+			requireModifiedDomains(publicDomain)
+		}
+
+		// Removed and created domains are modified
+
+		accountStorageMap.WriteDomain(inter, publicDomain, nil)
+		accountStorageMap.NewDomain(nil, inter, privateDomain)
+
+		requireModifiedDomains(publicDomain, privateDomain)
+
+		commit()
+
+		requireModifiedDomains()
+
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
+	}
+
+	t.Run("inlined domains", func(t *testing.T) {
+		test(t, 1, true)
+	})
+
+	t.Run("non-inlined domains", func(t *testing.T) {
+		test(t, 100, false)
+	})
+}
+
+func TestAccountStorageMapDeepCopy(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+	newAddress := common.MustBytesToAddress([]byte{0x2})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off AtreeStorageValidationEnabled and explicitly check atree storage health at the end of test.
+		// This is because AccountStorageMap isn't created through runtime.Storage, so there isn't any
+		// account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
+		accountStorageMap := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
+		require.NotNil(t, accountStorageMap)
+
+		copiedAccountStorageMap, err := accountStorageMap.DeepCopy(inter, atree.Address(newAddress))
+		require.NoError(t, err)
+		require.NotNil(t, copiedAccountStorageMap)
+		require.Equal(t, uint64(0), copiedAccountStorageMap.Count())
+		require.Equal(t, atree.Address(newAddress), copiedAccountStorageMap.SlabID().Address())
+
+		CheckAtreeStorageHealth(
+			t,
+			storage,
+			[]atree.SlabID{
+				accountStorageMap.SlabID(),
+				copiedAccountStorageMap.SlabID(),
+			},
+		)
+	})
+
+	t.Run("non-empty", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off AtreeStorageValidationEnabled and explicitly check atree storage health at the end of test.
+		// This is because AccountStorageMap isn't created through runtime.Storage, so there isn't any
+		// account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
+		existingDomains := []common.StorageDomain{
+			common.PathDomainStorage.StorageDomain(),
+			common.PathDomainPublic.StorageDomain(),
+			common.StorageDomainContract,
+		}
+
+		const count = 10
+		accountStorageMap, accountValues := createAccountStorageMap(storage, inter, address, existingDomains, count, random)
+
+		copiedAccountStorageMap, err := accountStorageMap.DeepCopy(inter, atree.Address(newAddress))
+		require.NoError(t, err)
+		require.Equal(t, atree.Address(newAddress), copiedAccountStorageMap.SlabID().Address())
+
+		checkAccountStorageMapData(t, inter, accountStorageMap, accountValues)
+		checkAccountStorageMapData(t, inter, copiedAccountStorageMap, accountValues)
+
+		CheckAtreeStorageHealth(
+			t,
+			storage,
+			[]atree.SlabID{
+				accountStorageMap.SlabID(),
+				copiedAccountStorageMap.SlabID(),
+			},
+		)
+	})
+
+	t.Run("resource", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		type ownerChange struct {
+			oldOwner common.Address
+			newOwner common.Address
+		}
+
+		var ownerChanges []ownerChange
+
+		inter, err := interpreter.NewInterpreter(
+			nil,
+			TestLocation,
+			&interpreter.Config{
+				Storage:                     storage,
+				AtreeValueValidationEnabled: true,
+				// Turn off AtreeStorageValidationEnabled and explicitly check atree storage health at the end of test.
+				// This is because AccountStorageMap isn't created through runtime.Storage, so there isn't any
+				// account register to match AccountStorageMap root slab.
+				AtreeStorageValidationEnabled: false,
+				OnResourceOwnerChange: func(
+					_ *interpreter.Interpreter,
+					_ *interpreter.CompositeValue,
+					oldOwner common.Address,
+					newOwner common.Address,
+				) {
+					ownerChanges = append(
+						ownerChanges,
+						ownerChange{
+							oldOwner: oldOwner,
+							newOwner: newOwner,
+						},
+					)
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		accountStorageMap := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
+
+		domain := common.PathDomainStorage.StorageDomain()
+		domainStorageMap := accountStorageMap.NewDomain(nil, inter, domain)
+
+		resource := interpreter.NewCompositeValue(
+			inter,
+			interpreter.EmptyLocationRange,
+			TestLocation,
+			"TestResource",
+			common.CompositeKindResource,
+			[]interpreter.CompositeField{
+				{
+					Name:  "test",
+					Value: interpreter.NewUnmeteredUInt8Value(42),
+				},
+			},
+			address,
+		)
+
+		key := interpreter.StringStorageMapKey("resource")
+		domainStorageMap.WriteValue(inter, key, resource)
+
+		ownerChanges = nil
+
+		copiedAccountStorageMap, err := accountStorageMap.DeepCopy(inter, atree.Address(newAddress))
+		require.NoError(t, err)
+
+		require.Equal(
+			t,
+			[]ownerChange{
+				{
+					oldOwner: address,
+					newOwner: newAddress,
+				},
+			},
+			ownerChanges,
+		)
+
+		// Source resource is intact
+
+		sourceValue := accountStorageMap.GetDomain(nil, inter, domain, false).ReadValue(nil, key)
+		require.IsType(t, &interpreter.CompositeValue{}, sourceValue)
+		sourceResource := sourceValue.(*interpreter.CompositeValue)
+		require.Equal(t, address, sourceResource.GetOwner())
+		require.Equal(
+			t,
+			interpreter.NewUnmeteredUInt8Value(42),
+			sourceResource.GetField(inter, "test"),
+		)
+
+		// Copied resource is owned by new address
+
+		copiedValue := copiedAccountStorageMap.GetDomain(nil, inter, domain, false).ReadValue(nil, key)
+		require.IsType(t, &interpreter.CompositeValue{}, copiedValue)
+		copiedResource := copiedValue.(*interpreter.CompositeValue)
+		require.Equal(t, newAddress, copiedResource.GetOwner())
+		require.Equal(
+			t,
+			interpreter.NewUnmeteredUInt8Value(42),
+			copiedResource.GetField(inter, "test"),
+		)
+
+		CheckAtreeStorageHealth(
+			t,
+			storage,
+			[]atree.SlabID{
+				accountStorageMap.SlabID(),
+				copiedAccountStorageMap.SlabID(),
+			},
+		)
+	})
+
+	t.Run("same address", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off AtreeStorageValidationEnabled and explicitly check atree storage health at the end of test.
+		// This is because AccountStorageMap isn't created through runtime.Storage, so there isn't any
+		// account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
+		existingDomains := []common.StorageDomain{common.PathDomainStorage.StorageDomain()}
+
+		const count = 10
+		accountStorageMap, accountValues := createAccountStorageMap(storage, inter, address, existingDomains, count, random)
+
+		_, err := accountStorageMap.DeepCopy(inter, atree.Address(address))
+		require.Error(t, err)
+
+		checkAccountStorageMapData(t, inter, accountStorageMap, accountValues)
+
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{accountStorageMap.SlabID()})
+	})
+}
+
+func TestAccountStorageMapMerge(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+	otherAddress := common.MustBytesToAddress([]byte{0x2})
+
+	newStorageAndInterpreter := func(t *testing.T) (*runtime.Storage, *interpreter.Interpreter) {
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off AtreeStorageValidationEnabled and explicitly check atree storage health at the end of test.
+		// This is because AccountStorageMap isn't created through runtime.Storage, so there isn't any
+		// account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
+		return storage, inter
+	}
+
+	domain := common.PathDomainStorage.StorageDomain()
+
+	existingKey := interpreter.StringStorageMapKey("existing")
+	conflictingKey := interpreter.StringStorageMapKey("conflicting")
+	otherKey := interpreter.StringStorageMapKey("other")
+
+	// createOverlappingAccountStorageMaps creates two account storage maps
+	// which both have conflictingKey in the storage domain.
+	createOverlappingAccountStorageMaps := func(
+		storage atree.SlabStorage,
+		inter *interpreter.Interpreter,
+	) (
+		accountStorageMap *interpreter.AccountStorageMap,
+		otherAccountStorageMap *interpreter.AccountStorageMap,
+	) {
+		accountStorageMap = interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
+		domainStorageMap := accountStorageMap.NewDomain(nil, inter, domain)
+		domainStorageMap.WriteValue(inter, existingKey, interpreter.NewUnmeteredIntValueFromInt64(1))
+		domainStorageMap.WriteValue(inter, conflictingKey, interpreter.NewUnmeteredIntValueFromInt64(2))
+
+		otherAccountStorageMap = interpreter.NewAccountStorageMap(nil, storage, atree.Address(otherAddress))
+		otherDomainStorageMap := otherAccountStorageMap.NewDomain(nil, inter, domain)
+		otherDomainStorageMap.WriteValue(inter, conflictingKey, interpreter.NewUnmeteredIntValueFromInt64(3))
+		otherDomainStorageMap.WriteValue(inter, otherKey, interpreter.NewUnmeteredIntValueFromInt64(4))
+
+		return
+	}
+
+	otherAccountValues := accountStorageMapValues{
+		domain: {
+			conflictingKey: interpreter.NewUnmeteredIntValueFromInt64(3),
+			otherKey:       interpreter.NewUnmeteredIntValueFromInt64(4),
+		},
+	}
+
+	t.Run("disjoint domains", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		storage, inter := newStorageAndInterpreter(t)
+
+		const count = 10
+
+		accountStorageMap, accountValues := createAccountStorageMap(
+			storage,
+			inter,
+			address,
+			[]common.StorageDomain{
+				common.PathDomainStorage.StorageDomain(),
+			},
+			count,
+			random,
+		)
+
+		otherAccountStorageMap, otherAccountValues := createAccountStorageMap(
+			storage,
+			inter,
+			otherAddress,
+			[]common.StorageDomain{
+				common.PathDomainPublic.StorageDomain(),
+				common.StorageDomainContract,
+			},
+			count,
+			random,
+		)
+
+		err := accountStorageMap.Merge(inter, otherAccountStorageMap, interpreter.ConflictResolutionError)
+		require.NoError(t, err)
+
+		mergedAccountValues := make(accountStorageMapValues)
+		for domain, domainValues := range accountValues {
+			mergedAccountValues[domain] = domainValues
+		}
+		for domain, domainValues := range otherAccountValues {
+			mergedAccountValues[domain] = domainValues
+		}
+
+		checkAccountStorageMapData(t, inter, accountStorageMap, mergedAccountValues)
+		checkAccountStorageMapData(t, inter, otherAccountStorageMap, otherAccountValues)
+
+		CheckAtreeStorageHealth(
+			t,
+			storage,
+			[]atree.SlabID{
+				accountStorageMap.SlabID(),
+				otherAccountStorageMap.SlabID(),
+			},
+		)
+	})
+
+	t.Run("overlapping keys, keep existing", func(t *testing.T) {
+		t.Parallel()
+
+		storage, inter := newStorageAndInterpreter(t)
+
+		accountStorageMap, otherAccountStorageMap := createOverlappingAccountStorageMaps(storage, inter)
+
+		err := accountStorageMap.Merge(inter, otherAccountStorageMap, interpreter.ConflictResolutionKeepExisting)
+		require.NoError(t, err)
+
+		checkAccountStorageMapData(
+			t,
+			inter,
+			accountStorageMap,
+			accountStorageMapValues{
+				domain: {
+					existingKey:    interpreter.NewUnmeteredIntValueFromInt64(1),
+					conflictingKey: interpreter.NewUnmeteredIntValueFromInt64(2),
+					otherKey:       interpreter.NewUnmeteredIntValueFromInt64(4),
+				},
+			},
+		)
+		checkAccountStorageMapData(t, inter, otherAccountStorageMap, otherAccountValues)
+
+		CheckAtreeStorageHealth(
+			t,
+			storage,
+			[]atree.SlabID{
+				accountStorageMap.SlabID(),
+				otherAccountStorageMap.SlabID(),
+			},
+		)
+	})
+
+	t.Run("overlapping keys, overwrite", func(t *testing.T) {
+		t.Parallel()
+
+		storage, inter := newStorageAndInterpreter(t)
+
+		accountStorageMap, otherAccountStorageMap := createOverlappingAccountStorageMaps(storage, inter)
+
+		err := accountStorageMap.Merge(inter, otherAccountStorageMap, interpreter.ConflictResolutionOverwrite)
+		require.NoError(t, err)
+
+		checkAccountStorageMapData(
+			t,
+			inter,
+			accountStorageMap,
+			accountStorageMapValues{
+				domain: {
+					existingKey:    interpreter.NewUnmeteredIntValueFromInt64(1),
+					conflictingKey: interpreter.NewUnmeteredIntValueFromInt64(3),
+					otherKey:       interpreter.NewUnmeteredIntValueFromInt64(4),
+				},
+			},
+		)
+		checkAccountStorageMapData(t, inter, otherAccountStorageMap, otherAccountValues)
+
+		CheckAtreeStorageHealth(
+			t,
+			storage,
+			[]atree.SlabID{
+				accountStorageMap.SlabID(),
+				otherAccountStorageMap.SlabID(),
+			},
+		)
+	})
+
+	t.Run("overlapping keys, error", func(t *testing.T) {
+		t.Parallel()
+
+		storage, inter := newStorageAndInterpreter(t)
+
+		accountStorageMap, otherAccountStorageMap := createOverlappingAccountStorageMaps(storage, inter)
+
+		err := accountStorageMap.Merge(inter, otherAccountStorageMap, interpreter.ConflictResolutionError)
+		require.Equal(
+			t,
+			interpreter.StorageMergeConflictError{
+				Address: address,
+				Domain:  domain,
+				Key:     conflictingKey,
+			},
+			err,
+		)
+
+		// Account storage map is unchanged
+		checkAccountStorageMapData(
+			t,
+			inter,
+			accountStorageMap,
+			accountStorageMapValues{
+				domain: {
+					existingKey:    interpreter.NewUnmeteredIntValueFromInt64(1),
+					conflictingKey: interpreter.NewUnmeteredIntValueFromInt64(2),
+				},
+			},
+		)
+		checkAccountStorageMapData(t, inter, otherAccountStorageMap, otherAccountValues)
+
+		CheckAtreeStorageHealth(
+			t,
+			storage,
+			[]atree.SlabID{
+				accountStorageMap.SlabID(),
+				otherAccountStorageMap.SlabID(),
+			},
+		)
+	})
+
+	t.Run("overlapping resource, overwrite", func(t *testing.T) {
+		t.Parallel()
+
+		storage, inter := newStorageAndInterpreter(t)
+
+		accountStorageMap := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
+		domainStorageMap := accountStorageMap.NewDomain(nil, inter, domain)
+
+		resource := interpreter.NewCompositeValue(
+			inter,
+			interpreter.EmptyLocationRange,
+			TestLocation,
+			"TestResource",
+			common.CompositeKindResource,
+			nil,
+			address,
+		)
+		domainStorageMap.WriteValue(inter, conflictingKey, resource)
+
+		otherAccountStorageMap := interpreter.NewAccountStorageMap(nil, storage, atree.Address(otherAddress))
+		otherDomainStorageMap := otherAccountStorageMap.NewDomain(nil, inter, domain)
+		otherDomainStorageMap.WriteValue(inter, conflictingKey, interpreter.NewUnmeteredIntValueFromInt64(3))
+
+		err := accountStorageMap.Merge(inter, otherAccountStorageMap, interpreter.ConflictResolutionOverwrite)
+		require.ErrorAs(t, err, &interpreter.ResourceLossError{})
+
+		// Resource is not dropped
+		existingValue := accountStorageMap.GetDomain(nil, inter, domain, false).ReadValue(nil, conflictingKey)
+		require.IsType(t, &interpreter.CompositeValue{}, existingValue)
+
+		CheckAtreeStorageHealth(
+			t,
+			storage,
+			[]atree.SlabID{
+				accountStorageMap.SlabID(),
+				otherAccountStorageMap.SlabID(),
+			},
+		)
+	})
+}
+
+func TestAccountStorageMapsEqual(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+	otherAddress := common.MustBytesToAddress([]byte{0x2})
+
+	domains := []common.StorageDomain{
+		common.PathDomainStorage.StorageDomain(),
+		common.PathDomainPublic.StorageDomain(),
+		common.StorageDomainContract,
+	}
+
+	newStorageAndInterpreter := func(t *testing.T) (*runtime.Storage, *interpreter.Interpreter) {
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off automatic AtreeStorageValidationEnabled and explicitly check atree storage health directly.
+		// This is because AccountStorageMap isn't created through storage, so there isn't any account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, atreeValueValidationEnabled, atreeStorageValidationEnabled)
+
+		return storage, inter
+	}
+
+	// newAccountStorageMaps returns two account storage maps with the same content,
+	// in separate accounts. Domains of the second account storage map are written in reverse order.
+	newAccountStorageMaps := func(t *testing.T) (
+		*interpreter.Interpreter,
+		*interpreter.AccountStorageMap,
+		*interpreter.AccountStorageMap,
+		accountStorageMapValues,
+	) {
+		random := rand.New(rand.NewSource(42))
+
+		storage, inter := newStorageAndInterpreter(t)
+
+		const count = 10
+		accountStorageMap1, accountValues := createAccountStorageMap(storage, inter, address, domains, count, random)
+
+		accountStorageMap2 := interpreter.NewAccountStorageMap(nil, storage, atree.Address(otherAddress))
+
+		reversedDomains := slices.Clone(domains)
+		slices.Reverse(reversedDomains)
+
+		for _, domain := range reversedDomains {
+			domainStorageMap := accountStorageMap2.NewDomain(nil, inter, domain)
+			for key, value := range accountValues[domain] { //nolint:maprange
+				domainStorageMap.WriteValue(inter, key, value)
+			}
+		}
+
+		return inter, accountStorageMap1, accountStorageMap2, accountValues
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		inter, accountStorageMap, _, _ := newAccountStorageMaps(t)
+
+		require.True(t, interpreter.AccountStorageMapsEqual(inter, nil, nil))
+		require.False(t, interpreter.AccountStorageMapsEqual(inter, accountStorageMap, nil))
+		require.False(t, interpreter.AccountStorageMapsEqual(inter, nil, accountStorageMap))
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		storage, inter := newStorageAndInterpreter(t)
+
+		accountStorageMap1 := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
+		accountStorageMap2 := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
+
+		require.True(t, interpreter.AccountStorageMapsEqual(inter, accountStorageMap1, accountStorageMap2))
+	})
+
+	t.Run("equal", func(t *testing.T) {
+		t.Parallel()
+
+		inter, accountStorageMap1, accountStorageMap2, _ := newAccountStorageMaps(t)
+
+		require.True(t, interpreter.AccountStorageMapsEqual(inter, accountStorageMap1, accountStorageMap1))
+		require.True(t, interpreter.AccountStorageMapsEqual(inter, accountStorageMap1, accountStorageMap2))
+		require.True(t, interpreter.AccountStorageMapsEqual(inter, accountStorageMap2, accountStorageMap1))
+	})
+
+	t.Run("extra domain", func(t *testing.T) {
+		t.Parallel()
+
+		inter, accountStorageMap1, accountStorageMap2, _ := newAccountStorageMaps(t)
+
+		accountStorageMap2.NewDomain(nil, inter, common.PathDomainPrivate.StorageDomain())
+
+		require.False(t, interpreter.AccountStorageMapsEqual(inter, accountStorageMap1, accountStorageMap2))
+		require.False(t, interpreter.AccountStorageMapsEqual(inter, accountStorageMap2, accountStorageMap1))
+	})
+
+	t.Run("different domain", func(t *testing.T) {
+		t.Parallel()
+
+		inter, accountStorageMap1, accountStorageMap2, _ := newAccountStorageMaps(t)
+
+		accountStorageMap2.WriteDomain(inter, domains[0], nil)
+		accountStorageMap2.NewDomain(nil, inter, common.PathDomainPrivate.StorageDomain())
+
+		require.False(t, interpreter.AccountStorageMapsEqual(inter, accountStorageMap1, accountStorageMap2))
+		require.False(t, interpreter.AccountStorageMapsEqual(inter, accountStorageMap2, accountStorageMap1))
+	})
+
+	t.Run("different value", func(t *testing.T) {
+		t.Parallel()
+
+		inter, accountStorageMap1, accountStorageMap2, accountValues := newAccountStorageMaps(t)
+
+		var key interpreter.StorageMapKey
+		for k := range accountValues[domains[0]] { //nolint:maprange
+			key = k
+			break
+		}
+
+		accountStorageMap2.GetDomain(nil, inter, domains[0], false).
+			WriteValue(inter, key, interpreter.NewUnmeteredStringValue("different"))
+
+		require.False(t, interpreter.AccountStorageMapsEqual(inter, accountStorageMap1, accountStorageMap2))
+		require.False(t, interpreter.AccountStorageMapsEqual(inter, accountStorageMap2, accountStorageMap1))
+	})
+
+	t.Run("different key", func(t *testing.T) {
+		t.Parallel()
+
+		inter, accountStorageMap1, accountStorageMap2, accountValues := newAccountStorageMaps(t)
+
+		var key interpreter.StorageMapKey
+		for k := range accountValues[domains[0]] { //nolint:maprange
+			key = k
+			break
+		}
+
+		domainStorageMap := accountStorageMap2.GetDomain(nil, inter, domains[0], false)
+		value := domainStorageMap.ReadValue(nil, key)
+		domainStorageMap.WriteValue(inter, key, nil)
+		domainStorageMap.WriteValue(inter, interpreter.StringStorageMapKey("different"), value)
+
+		require.False(t, interpreter.AccountStorageMapsEqual(inter, accountStorageMap1, accountStorageMap2))
+		require.False(t, interpreter.AccountStorageMapsEqual(inter, accountStorageMap2, accountStorageMap1))
+	})
+}
+
+func TestAccountStorageMapContentHash(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	hasher := func(data []byte) []byte {
+		digest := sha256.Sum256(data)
+		return digest[:]
+	}
+
+	domains := []common.StorageDomain{
+		common.PathDomainStorage.StorageDomain(),
+		common.PathDomainPublic.StorageDomain(),
+		common.StorageDomainContract,
+	}
+
+	const count = 50
+
+	newValue := func(inter *interpreter.Interpreter, i int) interpreter.Value {
+		switch i % 5 {
+		case 0:
+			// Large value that is stored in its own slabs.
+			return interpreter.NewUnmeteredStringValue(strings.Repeat("a", 1_000+i))
+
+		case 1:
+			// Large dictionary that is stored in multiple slabs.
+			keysAndValues := make([]interpreter.Value, 0, 2*(i+50))
+			for j := range i + 50 {
+				keysAndValues = append(
+					keysAndValues,
+					interpreter.NewUnmeteredStringValue(strconv.Itoa(j)),
+					interpreter.NewUnmeteredIntValueFromInt64(int64(j)),
+				)
+			}
+			return interpreter.NewDictionaryValueWithAddress(
+				inter,
+				interpreter.EmptyLocationRange,
+				&interpreter.DictionaryStaticType{
+					KeyType:   interpreter.PrimitiveStaticTypeString,
+					ValueType: interpreter.PrimitiveStaticTypeInt,
+				},
+				address,
+				keysAndValues...,
+			)
+
+		case 2, 3:
+			// Small array that is inlined.
+			values := make([]interpreter.Value, 0, i)
+			for j := range i {
+				values = append(values, interpreter.NewUnmeteredIntValueFromInt64(int64(j)))
+			}
+			array := interpreter.NewArrayValue(
+				inter,
+				interpreter.EmptyLocationRange,
+				&interpreter.VariableSizedStaticType{
+					Type: interpreter.PrimitiveStaticTypeInt,
+				},
+				address,
+				values...,
+			)
+			if i%5 == 3 {
+				return interpreter.NewUnmeteredSomeValueNonCopying(array)
+			}
+			return array
+
+		default:
+			return interpreter.NewUnmeteredIntValueFromInt64(int64(i))
+		}
+	}
+
+	newAccountStorageMap := func(
+		t *testing.T,
+		indices []int,
+		removedIndices []int,
+	) (
+		*runtime.Storage,
+		*interpreter.Interpreter,
+		*interpreter.AccountStorageMap,
+	) {
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off automatic AtreeStorageValidationEnabled and explicitly check atree storage health directly.
+		// This is because AccountStorageMap isn't created through storage, so there isn't any account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, atreeValueValidationEnabled, atreeStorageValidationEnabled)
+
+		accountStorageMap := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
+
+		for _, domain := range domains {
+			domainStorageMap := accountStorageMap.NewDomain(nil, inter, domain)
+
+			for _, i := range indices {
+				key := interpreter.StringStorageMapKey(strconv.Itoa(i))
+				domainStorageMap.WriteValue(inter, key, newValue(inter, i))
+			}
+
+			for _, i := range removedIndices {
+				key := interpreter.StringStorageMapKey(strconv.Itoa(i))
+				domainStorageMap.WriteValue(inter, key, nil)
+			}
+		}
+
+		return storage, inter, accountStorageMap
+	}
+
+	indices := make([]int, 0, count)
+	for i := range count {
+		indices = append(indices, i)
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, accountStorageMap1 := newAccountStorageMap(t, nil, nil)
+		_, _, accountStorageMap2 := newAccountStorageMap(t, nil, nil)
+
+		hash := accountStorageMap1.ContentHash(hasher)
+		require.NotEmpty(t, hash)
+		require.Equal(t, hash, accountStorageMap1.ContentHash(hasher))
+		require.Equal(t, hash, accountStorageMap2.ContentHash(hasher))
+	})
+
+	t.Run("different layouts", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, accountStorageMap1 := newAccountStorageMap(t, indices, nil)
+
+		// Write values in reverse order,
+		// and write additional values which are removed again.
+
+		reversedIndices := slices.Clone(indices)
+		slices.Reverse(reversedIndices)
+
+		var removedIndices []int
+		for i := count; i < 2*count; i++ {
+			removedIndices = append(removedIndices, i)
+		}
+
+		storage2, _, accountStorageMap2 := newAccountStorageMap(
+			t,
+			append(reversedIndices, removedIndices...),
+			removedIndices,
+		)
+
+		hash := accountStorageMap1.ContentHash(hasher)
+		require.Equal(t, hash, accountStorageMap2.ContentHash(hasher))
+
+		CheckAtreeStorageHealth(t, storage2, []atree.SlabID{accountStorageMap2.SlabID()})
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		t.Parallel()
+
+		storage, inter, accountStorageMap := newAccountStorageMap(t, indices, nil)
+
+		hash := accountStorageMap.ContentHash(hasher)
+
+		err := storage.Commit(inter, false)
+		require.NoError(t, err)
+
+		require.Equal(t, hash, accountStorageMap.ContentHash(hasher))
+
+		ledger := storage.Ledger.(TestLedger)
+
+		// Load account storage map from raw data, without any cached slabs.
+
+		reloadedStorage := runtime.NewStorage(
+			NewTestLedgerWithData(nil, nil, ledger.StoredValues, ledger.StorageIndices),
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		reloadedAccountStorageMap := interpreter.NewAccountStorageMapWithRootID(
+			reloadedStorage,
+			accountStorageMap.SlabID(),
+		)
+
+		require.Equal(t, hash, reloadedAccountStorageMap.ContentHash(hasher))
+	})
+
+	t.Run("different content", func(t *testing.T) {
+		t.Parallel()
+
+		_, inter, accountStorageMap := newAccountStorageMap(t, indices, nil)
+
+		hash := accountStorageMap.ContentHash(hasher)
+
+		// Update value
+
+		domainStorageMap := accountStorageMap.GetDomain(nil, inter, domains[0], false)
+		domainStorageMap.WriteValue(
+			inter,
+			interpreter.StringStorageMapKey("4"),
+			interpreter.NewUnmeteredIntValueFromInt64(5),
+		)
+
+		updatedHash := accountStorageMap.ContentHash(hasher)
+		require.NotEqual(t, hash, updatedHash)
+
+		// Move value to another domain
+
+		domainStorageMap.WriteValue(inter, interpreter.StringStorageMapKey("4"), nil)
+
+		domainStorageMap = accountStorageMap.GetDomain(nil, inter, domains[1], false)
+		domainStorageMap.WriteValue(
+			inter,
+			interpreter.StringStorageMapKey("other"),
+			interpreter.NewUnmeteredIntValueFromInt64(5),
+		)
+
+		movedHash := accountStorageMap.ContentHash(hasher)
+		require.NotEqual(t, hash, movedHash)
+		require.NotEqual(t, updatedHash, movedHash)
+
+		// Remove domain
+
+		accountStorageMap.WriteDomain(inter, domains[2], nil)
+		require.NotEqual(t, movedHash, accountStorageMap.ContentHash(hasher))
+	})
+}
+
+func TestAccountStorageMapEncodeTo(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	domains := []common.StorageDomain{
+		common.PathDomainStorage.StorageDomain(),
+		common.PathDomainPublic.StorageDomain(),
+		common.StorageDomainContract,
+	}
+
+	newStorageAndInterpreterWithConfig := func(
+		t *testing.T,
+		config runtime.StorageConfig,
+	) (
+		*runtime.Storage,
+		*interpreter.Interpreter,
+	) {
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			config,
+		)
+
+		// Turn off AtreeStorageValidationEnabled and explicitly check atree storage health at the end of test.
+		// This is because AccountStorageMap isn't created through runtime.Storage, so there isn't any
+		// account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
+		return storage, inter
+	}
+
+	newStorageAndInterpreter := func(t *testing.T) (*runtime.Storage, *interpreter.Interpreter) {
+		return newStorageAndInterpreterWithConfig(t, runtime.StorageConfig{})
+	}
+
+	roundTripWithConfig := func(
+		t *testing.T,
+		accountStorageMap *interpreter.AccountStorageMap,
+		config runtime.StorageConfig,
+	) (
+		*runtime.Storage,
+		*interpreter.Interpreter,
+		*interpreter.AccountStorageMap,
+	) {
+		var buf bytes.Buffer
+		err := accountStorageMap.EncodeTo(&buf)
+		require.NoError(t, err)
+
+		// Decode into a fresh ledger
+		storage, inter := newStorageAndInterpreterWithConfig(t, config)
+
+		decodedAccountStorageMap, err := interpreter.DecodeAccountStorageMap(
+			&buf,
+			inter,
+			storage,
+			atree.Address(address),
+		)
+		require.NoError(t, err)
+		require.Equal(t, 0, buf.Len())
+
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{decodedAccountStorageMap.SlabID()})
+
+		return storage, inter, decodedAccountStorageMap
+	}
+
+	roundTrip := func(
+		t *testing.T,
+		accountStorageMap *interpreter.AccountStorageMap,
+	) (
+		*runtime.Storage,
+		*interpreter.Interpreter,
+		*interpreter.AccountStorageMap,
+	) {
+		return roundTripWithConfig(t, accountStorageMap, runtime.StorageConfig{})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		storage, _ := newStorageAndInterpreter(t)
+
+		accountStorageMap := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
+
+		_, inter, decodedAccountStorageMap := roundTrip(t, accountStorageMap)
+
+		checkAccountStorageMapData(t, inter, decodedAccountStorageMap, accountStorageMapValues{})
+	})
+
+	t.Run("non-empty", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		storage, inter := newStorageAndInterpreter(t)
+
+		const count = 10
+		accountStorageMap, accountValues := createAccountStorageMap(storage, inter, address, domains, count, random)
+
+		err := storage.Commit(inter, false)
+		require.NoError(t, err)
+
+		_, decodedInter, decodedAccountStorageMap := roundTrip(t, accountStorageMap)
+
+		checkAccountStorageMapData(t, decodedInter, decodedAccountStorageMap, accountValues)
+	})
+
+	t.Run("nested values", func(t *testing.T) {
+		t.Parallel()
+
+		hasher := func(data []byte) []byte {
+			digest := sha256.Sum256(data)
+			return digest[:]
+		}
+
+		storage, inter := newStorageAndInterpreter(t)
+
+		accountStorageMap := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
+		domainStorageMap := accountStorageMap.NewDomain(nil, inter, domains[0])
+
+		// Large dictionary that is stored in multiple slabs.
+		keysAndValues := make([]interpreter.Value, 0, 2*100)
+		for i := range 100 {
+			keysAndValues = append(
+				keysAndValues,
+				interpreter.NewUnmeteredStringValue(strconv.Itoa(i)),
+				interpreter.NewUnmeteredStringValue(strings.Repeat("b", 100+i)),
+			)
+		}
+		domainStorageMap.WriteValue(
+			inter,
+			interpreter.StringStorageMapKey("dictionary"),
+			interpreter.NewDictionaryValueWithAddress(
+				inter,
+				interpreter.EmptyLocationRange,
+				&interpreter.DictionaryStaticType{
+					KeyType:   interpreter.PrimitiveStaticTypeString,
+					ValueType: interpreter.PrimitiveStaticTypeString,
+				},
+				address,
+				keysAndValues...,
+			),
+		)
+
+		// Optional array of structs.
+		arrayType := &interpreter.VariableSizedStaticType{
+			Type: interpreter.PrimitiveStaticTypeAnyStruct,
+		}
+		elements := make([]interpreter.Value, 0, 10)
+		for i := range 10 {
+			elements = append(
+				elements,
+				interpreter.NewCompositeValue(
+					inter,
+					interpreter.EmptyLocationRange,
+					TestLocation,
+					"TestStruct",
+					common.CompositeKindStructure,
+					[]interpreter.CompositeField{
+						{
+							Name:  "test",
+							Value: interpreter.NewUnmeteredUInt8Value(uint8(i)),
+						},
+					},
+					address,
+				),
+			)
+		}
+		domainStorageMap.WriteValue(
+			inter,
+			interpreter.StringStorageMapKey("array"),
+			interpreter.NewUnmeteredSomeValueNonCopying(
+				interpreter.NewArrayValue(
+					inter,
+					interpreter.EmptyLocationRange,
+					arrayType,
+					address,
+					elements...,
+				),
+			),
+		)
+
+		domainStorageMap.WriteValue(
+			inter,
+			interpreter.Uint64StorageMapKey(42),
+			interpreter.NewUnmeteredIntValueFromInt64(42),
+		)
+
+		_, _, decodedAccountStorageMap := roundTrip(t, accountStorageMap)
+
+		require.Equal(t,
+			accountStorageMap.ContentHash(hasher),
+			decodedAccountStorageMap.ContentHash(hasher),
+		)
+	})
+
+	t.Run("max inline string size", func(t *testing.T) {
+		t.Parallel()
+
+		hasher := func(data []byte) []byte {
+			digest := sha256.Sum256(data)
+			return digest[:]
+		}
+
+		config := runtime.StorageConfig{
+			MaxInlineStringSize: 10,
+		}
+
+		storage, inter := newStorageAndInterpreterWithConfig(t, config)
+
+		accountStorageMap := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
+		domainStorageMap := accountStorageMap.NewDomain(nil, inter, domains[0])
+
+		// Strings larger than the limit are stored in separate slabs
+
+		domainValues := make(domainStorageMapValues)
+		for i := range 10 {
+			key := interpreter.StringStorageMapKey(strings.Repeat("k", 20+i))
+			value := interpreter.NewUnmeteredStringValue(strings.Repeat("v", 20+i))
+			domainStorageMap.WriteValue(inter, key, value)
+			domainValues[key] = value
+		}
+
+		slabCount := storage.PersistentSlabStorage.Deltas()
+
+		_, decodedInter, decodedAccountStorageMap := roundTripWithConfig(t, accountStorageMap, config)
+
+		// Encoding did not store any slabs
+		require.Equal(t, slabCount, storage.PersistentSlabStorage.Deltas())
+
+		checkAccountStorageMapData(
+			t,
+			decodedInter,
+			decodedAccountStorageMap,
+			accountStorageMapValues{
+				domains[0]: domainValues,
+			},
+		)
+
+		require.Equal(t,
+			accountStorageMap.ContentHash(hasher),
+			decodedAccountStorageMap.ContentHash(hasher),
+		)
+
+		// Hashing did not store any slabs
+		require.Equal(t, slabCount, storage.PersistentSlabStorage.Deltas())
+	})
+
+	t.Run("flush", func(t *testing.T) {
+		t.Parallel()
+
+		hasher := func(data []byte) []byte {
+			digest := sha256.Sum256(data)
+			return digest[:]
+		}
+
+		storage, inter := newStorageAndInterpreter(t)
+
+		accountStorageMap := interpreter.NewAccountStorageMap(nil, storage, atree.Address(address))
+		domainStorageMap := accountStorageMap.NewDomain(nil, inter, domains[0])
+
+		const entryCount = 10
+		for i := range entryCount {
+			domainStorageMap.WriteValue(
+				inter,
+				interpreter.Uint64StorageMapKey(i),
+				interpreter.NewUnmeteredIntValueFromInt64(int64(i)),
+			)
+		}
+
+		// Large array, which is encoded in a single entry
+		elements := make([]interpreter.Value, 0, 1000)
+		for range 1000 {
+			elements = append(
+				elements,
+				interpreter.NewUnmeteredStringValue(strings.Repeat("a", 1000)),
+			)
+		}
+		domainStorageMap.WriteValue(
+			inter,
+			interpreter.StringStorageMapKey("array"),
+			interpreter.NewArrayValue(
+				inter,
+				interpreter.EmptyLocationRange,
+				&interpreter.VariableSizedStaticType{
+					Type: interpreter.PrimitiveStaticTypeString,
+				},
+				address,
+				elements...,
+			),
+		)
+
+		var w recordingWriter
+		err := accountStorageMap.EncodeTo(&w)
+		require.NoError(t, err)
+
+		// The encoded data is written after each entry,
+		// and the large array is written in several parts
+
+		require.Greater(t, len(w.writeSizes), entryCount+1)
+
+		totalSize := w.Len()
+		for _, writeSize := range w.writeSizes {
+			require.Less(t, writeSize, totalSize/4)
+		}
+
+		decodeStorage, decodeInter := newStorageAndInterpreter(t)
+
+		decodedAccountStorageMap, err := interpreter.DecodeAccountStorageMap(
+			&w,
+			decodeInter,
+			decodeStorage,
+			atree.Address(address),
+		)
+		require.NoError(t, err)
+
+		require.Equal(t,
+			accountStorageMap.ContentHash(hasher),
+			decodedAccountStorageMap.ContentHash(hasher),
+		)
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		t.Parallel()
+
+		storage, inter := newStorageAndInterpreter(t)
+
+		// [2, []]
+		data := []byte{0x82, 0x02, 0x80}
+
+		_, err := interpreter.DecodeAccountStorageMap(
+			bytes.NewReader(data),
+			inter,
+			storage,
+			atree.Address(address),
+		)
+		require.ErrorContains(t, err, "unsupported version 2")
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		storage, inter := newStorageAndInterpreter(t)
+
+		const count = 10
+		accountStorageMap, _ := createAccountStorageMap(storage, inter, address, domains, count, random)
+
+		var buf bytes.Buffer
+		err := accountStorageMap.EncodeTo(&buf)
+		require.NoError(t, err)
+
+		decodeStorage, decodeInter := newStorageAndInterpreter(t)
+
+		_, err = interpreter.DecodeAccountStorageMap(
+			bytes.NewReader(buf.Bytes()[:buf.Len()/2]),
+			decodeInter,
+			decodeStorage,
+			atree.Address(address),
+		)
+		require.Error(t, err)
+	})
+}
+
+// recordingWriter is a buffer which records the size of each write.
+type recordingWriter struct {
+	bytes.Buffer
+	writeSizes []int
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	w.writeSizes = append(w.writeSizes, len(p))
+	return w.Buffer.Write(p)
+}
+
 func TestAccountStorageMapLoadFromRootSlabID(t *testing.T) {
 	t.Parallel()
 
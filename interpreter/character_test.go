@@ -75,3 +75,171 @@ func TestInterpretCharacterUtf8Field(t *testing.T) {
 		interpreter.NewUnmeteredUInt8Value(170),
 	)
 }
+
+func TestInterpretCharacterValue(t *testing.T) {
+
+	t.Parallel()
+
+	type test struct {
+		character string
+		value     uint32
+	}
+
+	tests := []test{
+		// ASCII
+		{"a", 0x61},
+		{"0", 0x30},
+		{"\\n", 0xA},
+		// Basic Multilingual Plane
+		{"\\u{E9}", 0xE9},
+		{"\\u{4E2D}", 0x4E2D},
+		// Astral plane
+		{"\\u{1F490}", 0x1F490},
+		{"👪", 0x1F46A},
+		// Multiple Unicode scalars: only the first Unicode scalar is returned
+		{"a\\u{332}", 0x61},
+		{"\\u{1F476}\\u{1F3FB}", 0x1F476},
+	}
+
+	for _, test := range tests {
+
+		t.Run(test.character, func(t *testing.T) {
+
+			t.Parallel()
+
+			inter := parseCheckAndInterpret(t, fmt.Sprintf(`
+              let c: Character = "%s"
+              let value = c.value
+            `, test.character))
+
+			require.Equal(t,
+				interpreter.NewUnmeteredUInt32Value(test.value),
+				inter.Globals.Get("value").GetValue(inter),
+			)
+		})
+	}
+}
+
+func TestInterpretCharacterClassificationFields(t *testing.T) {
+
+	t.Parallel()
+
+	type test struct {
+		character    string
+		isLetter     bool
+		isDigit      bool
+		isWhitespace bool
+	}
+
+	tests := []test{
+		{"a", true, false, false},
+		{"Z", true, false, false},
+		{"0", false, true, false},
+		{"9", false, true, false},
+		{" ", false, false, true},
+		{"\\t", false, false, true},
+		{"\\n", false, false, true},
+		{"_", false, false, false},
+		{"!", false, false, false},
+		// Unicode letters
+		{"\\u{E9}", true, false, false},
+		{"\\u{3A9}", true, false, false},
+		{"\\u{4E2D}", true, false, false},
+		// Unicode digits: Arabic-Indic digit three, Devanagari digit seven
+		{"\\u{663}", false, true, false},
+		{"\\u{96D}", false, true, false},
+		// Unicode whitespace: no-break space, ideographic space
+		{"\\u{A0}", false, false, true},
+		{"\\u{3000}", false, false, true},
+		// Multiple Unicode scalars: only the first Unicode scalar is classified
+		{"e\\u{301}", true, false, false},
+		{"\\r\\n", false, false, true},
+		{"\\u{1F476}\\u{1F3FB}", false, false, false},
+	}
+
+	for _, test := range tests {
+
+		t.Run(test.character, func(t *testing.T) {
+
+			t.Parallel()
+
+			inter := parseCheckAndInterpret(t, fmt.Sprintf(`
+              let c: Character = "%s"
+              let isLetter = c.isLetter
+              let isDigit = c.isDigit
+              let isWhitespace = c.isWhitespace
+            `, test.character))
+
+			require.Equal(t,
+				interpreter.BoolValue(test.isLetter),
+				inter.Globals.Get("isLetter").GetValue(inter),
+			)
+			require.Equal(t,
+				interpreter.BoolValue(test.isDigit),
+				inter.Globals.Get("isDigit").GetValue(inter),
+			)
+			require.Equal(t,
+				interpreter.BoolValue(test.isWhitespace),
+				inter.Globals.Get("isWhitespace").GetValue(inter),
+			)
+		})
+	}
+}
+
+func TestInterpretCharacterCompare(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      fun test(_ a: Character, _ b: Character): Int {
+          return a.compare(b)
+      }
+
+      fun testLess(_ a: Character, _ b: Character): Bool {
+          return a < b
+      }
+    `)
+
+	type test struct {
+		a, b     string
+		expected int64
+	}
+
+	tests := []test{
+		{"a", "a", 0},
+		{"a", "b", -1},
+		{"b", "a", 1},
+		{"A", "a", -1},
+		{"1", "a", -1},
+		{"\u00e9", "e", 1},
+		// Characters are normalized, so canonically equivalent characters are equal
+		{"\u00e9", "e\u0301", 0},
+		{"\U0001F476", "\U0001F476\U0001F3FB", -1},
+	}
+
+	for _, test := range tests {
+
+		a := interpreter.NewUnmeteredCharacterValue(test.a)
+		b := interpreter.NewUnmeteredCharacterValue(test.b)
+
+		result, err := inter.Invoke("test", a, b)
+		require.NoError(t, err)
+
+		RequireValuesEqual(
+			t,
+			inter,
+			interpreter.NewUnmeteredIntValueFromInt64(test.expected),
+			result,
+		)
+
+		// The result is consistent with the comparison operators
+
+		less, err := inter.Invoke("testLess", a, b)
+		require.NoError(t, err)
+
+		require.Equal(t,
+			interpreter.BoolValue(test.expected < 0),
+			less,
+		)
+	}
+}
@@ -74,4 +74,12 @@ type Config struct {
 	ValidateAccountCapabilitiesGetHandler ValidateAccountCapabilitiesGetHandlerFunc
 	// ValidateAccountCapabilitiesPublishHandler is used to handle when a capability of an account is got.
 	ValidateAccountCapabilitiesPublishHandler ValidateAccountCapabilitiesPublishHandlerFunc
+	// MaxValueDepth is the maximum nesting depth of container values (arrays, dictionaries, and composites)
+	// when values are transferred or deeply removed.
+	// Zero means DefaultMaxValueDepth.
+	MaxValueDepth int
 }
+
+// DefaultMaxValueDepth is the default maximum nesting depth of container values,
+// see Config.MaxValueDepth.
+const DefaultMaxValueDepth = 10_000
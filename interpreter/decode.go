@@ -61,6 +61,43 @@ func (e UnsupportedTagDecodingError) Error() string {
 	)
 }
 
+// UnknownCBORTagError is returned in strict decoding mode,
+// when an encoded storable, type info, static type, static authorization, or location
+// has a CBOR tag which is unknown at its position, see DecodingConfig.StrictDecoding
+type UnknownCBORTagError struct {
+	Tag uint64
+}
+
+var _ errors.InternalError = UnknownCBORTagError{}
+
+func (UnknownCBORTagError) IsInternalError() {}
+
+func (e UnknownCBORTagError) Error() string {
+	return fmt.Sprintf(
+		"%s unknown CBOR tag: %d",
+		errors.InternalErrorMessagePrefix,
+		e.Tag,
+	)
+}
+
+// Deprecated: InvalidAccountLinkEncodingError is returned when
+// the content of an encoded account link is not CBOR null
+type InvalidAccountLinkEncodingError struct {
+	ActualType string
+}
+
+var _ errors.InternalError = InvalidAccountLinkEncodingError{}
+
+func (InvalidAccountLinkEncodingError) IsInternalError() {}
+
+func (e InvalidAccountLinkEncodingError) Error() string {
+	return fmt.Sprintf(
+		"%s invalid account link encoding: expected null, got %s",
+		errors.InternalErrorMessagePrefix,
+		e.ActualType,
+	)
+}
+
 type InvalidStringLengthError struct {
 	Length uint64
 }
@@ -118,6 +155,14 @@ func decodeInt64(d StorableDecoder) (int64, error) {
 	return d.decoder.DecodeInt64()
 }
 
+// DecodingConfig configures the decoding of storables and type infos.
+type DecodingConfig struct {
+	// StrictDecoding rejects CBOR tags which are unknown at their position
+	// with an UnknownCBORTagError, instead of the default, unspecific decoding errors.
+	// This is e.g. useful to detect legacy or corrupt slabs during migrations.
+	StrictDecoding bool
+}
+
 func DecodeStorable(
 	decoder *cbor.StreamDecoder,
 	slabID atree.SlabID,
@@ -127,10 +172,26 @@ func DecodeStorable(
 	atree.Storable,
 	error,
 ) {
-	return NewStorableDecoder(decoder, slabID, inlinedExtraData, memoryGauge).decodeStorable()
+	return DecodeStorableWithConfig(decoder, slabID, inlinedExtraData, memoryGauge, DecodingConfig{})
+}
+
+// DecodeStorableWithConfig is like DecodeStorable, but decodes with the given configuration.
+func DecodeStorableWithConfig(
+	decoder *cbor.StreamDecoder,
+	slabID atree.SlabID,
+	inlinedExtraData []atree.ExtraData,
+	memoryGauge common.MemoryGauge,
+	config DecodingConfig,
+) (
+	atree.Storable,
+	error,
+) {
+	d := NewStorableDecoder(decoder, slabID, inlinedExtraData, memoryGauge)
+	d.setConfig(config)
+	return d.decodeStorable()
 }
 
-func newStorableDecoderFunc(memoryGauge common.MemoryGauge) atree.StorableDecoder {
+func newStorableDecoderFunc(memoryGauge common.MemoryGauge, config DecodingConfig) atree.StorableDecoder {
 	return func(
 		decoder *cbor.StreamDecoder,
 		slabID atree.SlabID,
@@ -139,7 +200,7 @@ func newStorableDecoderFunc(memoryGauge common.MemoryGauge) atree.StorableDecode
 		atree.Storable,
 		error,
 	) {
-		return NewStorableDecoder(decoder, slabID, inlinedExtraData, memoryGauge).decodeStorable()
+		return DecodeStorableWithConfig(decoder, slabID, inlinedExtraData, memoryGauge, config)
 	}
 }
 
@@ -161,12 +222,20 @@ func NewStorableDecoder(
 	}
 }
 
+// setConfig sets the configuration of the decoder,
+// and of the embedded type and location decoders.
+func (d *StorableDecoder) setConfig(config DecodingConfig) {
+	d.config = config
+	d.TypeDecoder.setConfig(config)
+}
+
 type StorableDecoder struct {
 	TypeDecoder
 	memoryGauge      common.MemoryGauge
 	decoder          *cbor.StreamDecoder
 	slabID           atree.SlabID
 	inlinedExtraData []atree.ExtraData
+	config           DecodingConfig
 }
 
 func (d StorableDecoder) decodeStorable() (atree.Storable, error) {
@@ -226,14 +295,14 @@ func (d StorableDecoder) decodeStorable() (atree.Storable, error) {
 		case atree.CBORTagInlinedArray:
 			return atree.DecodeInlinedArrayStorable(
 				d.decoder,
-				newStorableDecoderFunc(d.memoryGauge),
+				newStorableDecoderFunc(d.memoryGauge, d.config),
 				d.slabID,
 				d.inlinedExtraData)
 
 		case atree.CBORTagInlinedMap:
 			return atree.DecodeInlinedMapStorable(
 				d.decoder,
-				newStorableDecoderFunc(d.memoryGauge),
+				newStorableDecoderFunc(d.memoryGauge, d.config),
 				d.slabID,
 				d.inlinedExtraData,
 			)
@@ -241,7 +310,7 @@ func (d StorableDecoder) decodeStorable() (atree.Storable, error) {
 		case atree.CBORTagInlinedCompactMap:
 			return atree.DecodeInlinedCompactMapStorable(
 				d.decoder,
-				newStorableDecoderFunc(d.memoryGauge),
+				newStorableDecoderFunc(d.memoryGauge, d.config),
 				d.slabID,
 				d.inlinedExtraData,
 			)
@@ -380,6 +449,11 @@ func (d StorableDecoder) decodeStorable() (atree.Storable, error) {
 			storable, err = d.decodeAccountLink()
 
 		default:
+			if d.config.StrictDecoding {
+				return nil, UnknownCBORTagError{
+					Tag: num,
+				}
+			}
 			return nil, UnsupportedTagDecodingError{
 				Tag: num,
 			}
@@ -1470,8 +1544,14 @@ func (d StorableDecoder) decodePathLink() (PathLinkValue, error) {
 
 // Deprecated: decodeAccountLink
 func (d StorableDecoder) decodeAccountLink() (AccountLinkValue, error) {
-	err := d.decoder.Skip()
+	// The content of an account link is always null, see cborAccountLinkValue
+	err := d.decoder.DecodeNil()
 	if err != nil {
+		if e, ok := err.(*cbor.WrongTypeError); ok {
+			return AccountLinkValue{}, InvalidAccountLinkEncodingError{
+				ActualType: e.ActualType.String(),
+			}
+		}
 		return AccountLinkValue{}, err
 	}
 
@@ -1481,6 +1561,7 @@ func (d StorableDecoder) decodeAccountLink() (AccountLinkValue, error) {
 type TypeDecoder struct {
 	decoder     *cbor.StreamDecoder
 	memoryGauge common.MemoryGauge
+	config      DecodingConfig
 	LocationDecoder
 }
 
@@ -1498,6 +1579,13 @@ func NewTypeDecoder(
 	}
 }
 
+// setConfig sets the configuration of the decoder,
+// and of the embedded location decoder.
+func (d *TypeDecoder) setConfig(config DecodingConfig) {
+	d.config = config
+	d.LocationDecoder.config = config
+}
+
 func (d TypeDecoder) DecodeStaticType() (StaticType, error) {
 	number, err := d.decoder.DecodeTagNumber()
 	if err != nil {
@@ -1545,6 +1633,11 @@ func (d TypeDecoder) DecodeStaticType() (StaticType, error) {
 		return d.decodeInclusiveRangeStaticType()
 
 	default:
+		if d.config.StrictDecoding {
+			return nil, UnknownCBORTagError{
+				Tag: number,
+			}
+		}
 		return nil, errors.NewUnexpectedError("invalid static type encoding tag: %d", number)
 	}
 }
@@ -1843,6 +1936,11 @@ func (d TypeDecoder) decodeStaticAuthorization() (Authorization, error) {
 
 		return entitlementSet, nil
 	}
+	if d.config.StrictDecoding {
+		return nil, UnknownCBORTagError{
+			Tag: number,
+		}
+	}
 	return nil, errors.NewUnexpectedError("invalid static authorization encoding tag: %d", number)
 }
 
@@ -2155,7 +2253,17 @@ func (d TypeDecoder) decodeInclusiveRangeStaticType() (StaticType, error) {
 }
 
 func DecodeTypeInfo(decoder *cbor.StreamDecoder, memoryGauge common.MemoryGauge) (atree.TypeInfo, error) {
+	return DecodeTypeInfoWithConfig(decoder, memoryGauge, DecodingConfig{})
+}
+
+// DecodeTypeInfoWithConfig is like DecodeTypeInfo, but decodes with the given configuration.
+func DecodeTypeInfoWithConfig(
+	decoder *cbor.StreamDecoder,
+	memoryGauge common.MemoryGauge,
+	config DecodingConfig,
+) (atree.TypeInfo, error) {
 	d := NewTypeDecoder(decoder, memoryGauge)
+	d.setConfig(config)
 
 	ty, err := d.decoder.NextType()
 	if err != nil {
@@ -2179,6 +2287,11 @@ func DecodeTypeInfo(decoder *cbor.StreamDecoder, memoryGauge common.MemoryGauge)
 		case values.CBORTagCompositeValue:
 			return d.decodeCompositeTypeInfo()
 		default:
+			if config.StrictDecoding {
+				return nil, UnknownCBORTagError{
+					Tag: tag,
+				}
+			}
 			return nil, errors.NewUnexpectedError("invalid type info CBOR tag: %d", tag)
 		}
 
@@ -2197,6 +2310,7 @@ func DecodeTypeInfo(decoder *cbor.StreamDecoder, memoryGauge common.MemoryGauge)
 type LocationDecoder struct {
 	decoder     *cbor.StreamDecoder
 	memoryGauge common.MemoryGauge
+	config      DecodingConfig
 }
 
 func NewLocationDecoder(
@@ -2249,6 +2363,11 @@ func (d LocationDecoder) DecodeLocation() (common.Location, error) {
 		return d.decodeScriptLocation()
 
 	default:
+		if d.config.StrictDecoding {
+			return nil, UnknownCBORTagError{
+				Tag: number,
+			}
+		}
 		return nil, errors.NewUnexpectedError("invalid location encoding tag: %d", number)
 	}
 }
@@ -0,0 +1,163 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/errors"
+	"github.com/onflow/cadence/sema"
+)
+
+// DeepEqual returns true if the two values are structurally equal.
+//
+// Unlike EquatableValue.Equal, composites, arrays, dictionaries, and optionals
+// are compared element by element using DeepEqual, and the uuid field of resources
+// is ignored, so independently created resources with the same contents are equal.
+// All other values are compared using EquatableValue.Equal.
+//
+// DeepEqual is intended for testing and diffing only.
+// It must NOT be used in code paths that affect consensus.
+func DeepEqual(context ValueComparisonContext, a, b Value) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+
+	switch a := a.(type) {
+	case *CompositeValue:
+		other, ok := b.(*CompositeValue)
+		return ok && deepEqualComposites(context, a, other)
+
+	case *ArrayValue:
+		other, ok := b.(*ArrayValue)
+		return ok && deepEqualArrays(context, a, other)
+
+	case *DictionaryValue:
+		other, ok := b.(*DictionaryValue)
+		return ok && deepEqualDictionaries(context, a, other)
+
+	case *SomeValue:
+		other, ok := b.(*SomeValue)
+		return ok && DeepEqual(context, a.InnerValue(), other.InnerValue())
+	}
+
+	equatableValue, ok := a.(EquatableValue)
+	return ok && equatableValue.Equal(context, EmptyLocationRange, b)
+}
+
+func deepEqualComposites(context ValueComparisonContext, a, b *CompositeValue) bool {
+	if a.Kind != b.Kind ||
+		a.FieldCount() != b.FieldCount() ||
+		!a.StaticType(context).Equal(b.StaticType(context)) {
+
+		return false
+	}
+
+	ignoreUUID := a.Kind == common.CompositeKindResource
+
+	equal := true
+
+	// NOTE: Do NOT iterate over both composites,
+	// iteration order of fields may be different
+	a.ForEachFieldName(func(fieldName string) (resume bool) {
+		if ignoreUUID && fieldName == sema.ResourceUUIDFieldName {
+			return true
+		}
+
+		equal = DeepEqual(
+			context,
+			a.GetField(context, fieldName),
+			b.GetField(context, fieldName),
+		)
+		return equal
+	})
+
+	return equal
+}
+
+func deepEqualArrays(context ValueComparisonContext, a, b *ArrayValue) bool {
+	count := a.Count()
+
+	if count != b.Count() {
+		return false
+	}
+
+	if a.Type == nil {
+		if b.Type != nil {
+			return false
+		}
+	} else if b.Type == nil ||
+		!a.Type.Equal(b.Type) {
+
+		return false
+	}
+
+	for i := 0; i < count; i++ {
+		if !DeepEqual(
+			context,
+			a.Get(context, EmptyLocationRange, i),
+			b.Get(context, EmptyLocationRange, i),
+		) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func deepEqualDictionaries(context ValueComparisonContext, a, b *DictionaryValue) bool {
+	if a.Count() != b.Count() ||
+		!a.Type.Equal(b.Type) {
+
+		return false
+	}
+
+	iterator, err := a.dictionary.ReadOnlyIterator()
+	if err != nil {
+		panic(errors.NewExternalError(err))
+	}
+
+	for {
+		key, value, err := iterator.Next()
+		if err != nil {
+			panic(errors.NewExternalError(err))
+		}
+		if key == nil {
+			return true
+		}
+
+		// NOTE: Do NOT iterate over both dictionaries,
+		// iteration order of entries may be different
+		otherValue, ok := b.Get(
+			context,
+			EmptyLocationRange,
+			MustConvertStoredValue(context, key),
+		)
+		if !ok {
+			return false
+		}
+
+		if !DeepEqual(
+			context,
+			MustConvertStoredValue(context, value),
+			otherValue,
+		) {
+			return false
+		}
+	}
+}
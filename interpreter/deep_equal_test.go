@@ -0,0 +1,137 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/interpreter"
+)
+
+func TestInterpretDeepEqual(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      resource R {
+          let id: Int
+          let tags: {String: Int}
+          var child: @R?
+
+          init(id: Int, child: @R?) {
+              self.id = id
+              self.tags = {"a": id, "b": id * 2}
+              self.child <- child
+          }
+      }
+
+      struct S {
+          let values: [Int]
+          init(_ values: [Int]) {
+              self.values = values
+          }
+      }
+
+      fun newResource(_ id: Int, _ childID: Int): @R {
+          return <- create R(id: id, child: <- create R(id: childID, child: nil))
+      }
+
+      fun newResources(_ id: Int): @[R] {
+          return <- [<- create R(id: id, child: nil), <- create R(id: id + 1, child: nil)]
+      }
+
+      fun newStruct(_ value: Int): S {
+          return S([1, value])
+      }
+    `)
+
+	invoke := func(name string, arguments ...int64) interpreter.Value {
+		values := make([]interpreter.Value, 0, len(arguments))
+		for _, argument := range arguments {
+			values = append(values, interpreter.NewUnmeteredIntValueFromInt64(argument))
+		}
+
+		value, err := inter.Invoke(name, values...)
+		require.NoError(t, err)
+		return value
+	}
+
+	t.Run("resources, equal", func(t *testing.T) {
+
+		a := invoke("newResource", 1, 2)
+		b := invoke("newResource", 1, 2)
+
+		// Resources created independently have different UUIDs,
+		// so they are not equal according to Equal
+		require.False(t,
+			a.(interpreter.EquatableValue).Equal(inter, interpreter.EmptyLocationRange, b),
+		)
+
+		assert.True(t, interpreter.DeepEqual(inter, a, b))
+		assert.True(t, interpreter.DeepEqual(inter, b, a))
+	})
+
+	t.Run("resources, nested field differs", func(t *testing.T) {
+
+		a := invoke("newResource", 1, 2)
+		b := invoke("newResource", 1, 3)
+
+		assert.False(t, interpreter.DeepEqual(inter, a, b))
+		assert.False(t, interpreter.DeepEqual(inter, b, a))
+	})
+
+	t.Run("resource arrays, equal", func(t *testing.T) {
+
+		a := invoke("newResources", 1)
+		b := invoke("newResources", 1)
+
+		assert.True(t, interpreter.DeepEqual(inter, a, b))
+	})
+
+	t.Run("resource arrays, element differs", func(t *testing.T) {
+
+		a := invoke("newResources", 1)
+		b := invoke("newResources", 2)
+
+		assert.False(t, interpreter.DeepEqual(inter, a, b))
+	})
+
+	t.Run("structs", func(t *testing.T) {
+
+		a := invoke("newStruct", 1)
+		b := invoke("newStruct", 1)
+		c := invoke("newStruct", 2)
+
+		assert.True(t, interpreter.DeepEqual(inter, a, b))
+		assert.False(t, interpreter.DeepEqual(inter, a, c))
+	})
+
+	t.Run("different kinds", func(t *testing.T) {
+
+		a := invoke("newResource", 1, 2)
+		b := invoke("newStruct", 1)
+
+		assert.False(t, interpreter.DeepEqual(inter, a, b))
+		assert.False(t, interpreter.DeepEqual(inter, a, nil))
+		assert.True(t, interpreter.DeepEqual(inter, nil, nil))
+	})
+}
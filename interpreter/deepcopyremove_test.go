@@ -28,6 +28,7 @@ import (
 	"github.com/onflow/cadence/common"
 	"github.com/onflow/cadence/interpreter"
 	. "github.com/onflow/cadence/test_utils/common_utils"
+	. "github.com/onflow/cadence/test_utils/interpreter_utils"
 )
 
 func TestValueDeepCopyAndDeepRemove(t *testing.T) {
@@ -99,3 +100,119 @@ func TestValueDeepCopyAndDeepRemove(t *testing.T) {
 
 	require.Equal(t, 1, count)
 }
+
+func TestInterpretCloneValueDeep(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("nested structs", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          access(all) struct Inner {
+              access(all) var x: Int
+
+              init(x: Int) {
+                  self.x = x
+              }
+          }
+
+          access(all) struct Outer {
+              access(all) let inner: Inner
+              access(all) let values: [Int]
+              access(all) let inners: {String: Inner}
+
+              init() {
+                  self.inner = Inner(x: 1)
+                  self.values = [1, 2, 3]
+                  self.inners = {"a": Inner(x: 2)}
+              }
+          }
+
+          let outer = Outer()
+        `)
+
+		original := inter.Globals.Get("outer").GetValue(inter).(*interpreter.CompositeValue)
+
+		clone := interpreter.CloneValueDeep(inter, original).(*interpreter.CompositeValue)
+
+		require.NotSame(t, original, clone)
+		require.Equal(t, original.StaticType(inter), clone.StaticType(inter))
+		RequireValuesEqual(t, inter, original, clone)
+
+		// Mutate the nested values of the clone
+
+		locationRange := interpreter.EmptyLocationRange
+
+		clone.GetMember(inter, locationRange, "inner").(*interpreter.CompositeValue).
+			SetMember(inter, locationRange, "x", interpreter.NewUnmeteredIntValueFromInt64(10))
+
+		clone.GetMember(inter, locationRange, "values").(*interpreter.ArrayValue).
+			Append(inter, locationRange, interpreter.NewUnmeteredIntValueFromInt64(4))
+
+		clone.GetMember(inter, locationRange, "inners").(*interpreter.DictionaryValue).
+			Remove(inter, locationRange, interpreter.NewUnmeteredStringValue("a"))
+
+		// The original is unaffected
+
+		require.Equal(t,
+			interpreter.NewUnmeteredIntValueFromInt64(1),
+			original.GetMember(inter, locationRange, "inner").(*interpreter.CompositeValue).
+				GetMember(inter, locationRange, "x"),
+		)
+		require.Equal(t,
+			3,
+			original.GetMember(inter, locationRange, "values").(*interpreter.ArrayValue).
+				Count(),
+		)
+		require.Equal(t,
+			1,
+			original.GetMember(inter, locationRange, "inners").(*interpreter.DictionaryValue).
+				Count(),
+		)
+
+		// The clone is changed
+
+		require.Equal(t,
+			interpreter.NewUnmeteredIntValueFromInt64(10),
+			clone.GetMember(inter, locationRange, "inner").(*interpreter.CompositeValue).
+				GetMember(inter, locationRange, "x"),
+		)
+		require.Equal(t,
+			4,
+			clone.GetMember(inter, locationRange, "values").(*interpreter.ArrayValue).
+				Count(),
+		)
+		require.Equal(t,
+			0,
+			clone.GetMember(inter, locationRange, "inners").(*interpreter.DictionaryValue).
+				Count(),
+		)
+	})
+
+	t.Run("resource", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          access(all) resource R {}
+
+          fun test(): @[R] {
+              return <-[<-create R()]
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		require.PanicsWithError(t,
+			interpreter.ResourceCloneError{
+				Type: value.StaticType(inter),
+			}.Error(),
+			func() {
+				interpreter.CloneValueDeep(inter, value)
+			},
+		)
+	})
+}
@@ -20,6 +20,7 @@ package interpreter
 
 import (
 	goerrors "errors"
+	"math"
 	"time"
 
 	"github.com/onflow/atree"
@@ -31,6 +32,9 @@ import (
 // DomainStorageMap is an ordered map which stores values in an account domain.
 type DomainStorageMap struct {
 	orderedMap *atree.OrderedMap
+	// modificationCount is incremented on each modification of the storage map,
+	// and is used by iterators to detect modifications during iteration.
+	modificationCount uint64
 }
 
 // NewDomainStorageMap creates new domain storage map for given address.
@@ -110,6 +114,7 @@ func NewDomainStorageMapWithAtreeValue(value atree.Value) *DomainStorageMap {
 }
 
 // ValueExists returns true if the given key exists in the storage map.
+// Unlike ReadValue, the stored value is not loaded and decoded.
 func (s *DomainStorageMap) ValueExists(key StorageMapKey) bool {
 	exists, err := s.orderedMap.Has(
 		key.AtreeValueCompare,
@@ -170,6 +175,8 @@ func (s *DomainStorageMap) SetValue(context ValueTransferContext, key StorageMap
 		panic(errors.NewExternalError(err))
 	}
 
+	s.modificationCount++
+
 	existed = existingStorable != nil
 	if existed {
 		existingValue := StoredValue(context, existingStorable, context.Storage())
@@ -200,6 +207,8 @@ func (s *DomainStorageMap) RemoveValue(context ValueRemoveContext, key StorageMa
 		panic(errors.NewExternalError(err))
 	}
 
+	s.modificationCount++
+
 	// Key
 
 	// NOTE: Key is just an atree.Value, not an interpreter.Value,
@@ -221,6 +230,59 @@ func (s *DomainStorageMap) RemoveValue(context ValueRemoveContext, key StorageMa
 	return
 }
 
+// RemoveKeys removes the values for the given keys in the storage map, if they exist.
+// Keys which do not exist are skipped.
+// Unlike calling RemoveValue for each key, the storage mutation is only recorded once,
+// and the atree value and storage are only validated once, after all keys are removed.
+// Returns the number of keys which existed.
+func (s *DomainStorageMap) RemoveKeys(context ValueRemoveContext, keys []StorageMapKey) (removed int) {
+	if len(keys) == 0 {
+		return 0
+	}
+
+	context.RecordStorageMutation()
+
+	storage := context.Storage()
+
+	for _, key := range keys {
+		existingKeyStorable, existingValueStorable, err := s.orderedMap.Remove(
+			key.AtreeValueCompare,
+			key.AtreeValueHashInput,
+			key.AtreeValue(),
+		)
+		if err != nil {
+			var keyNotFoundError *atree.KeyNotFoundError
+			if goerrors.As(err, &keyNotFoundError) {
+				continue
+			}
+			panic(errors.NewExternalError(err))
+		}
+
+		s.modificationCount++
+
+		// Key
+
+		// NOTE: Key is just an atree.Value, not an interpreter.Value,
+		// so do not need (can) convert and not need to deep remove
+		RemoveReferencedSlab(context, existingKeyStorable)
+
+		// Value
+
+		if existingValueStorable != nil {
+			existingValue := StoredValue(context, existingValueStorable, storage)
+			existingValue.DeepRemove(context, true) // existingValue is standalone because it was removed from parent container.
+			RemoveReferencedSlab(context, existingValueStorable)
+
+			removed++
+		}
+	}
+
+	context.MaybeValidateAtreeValue(s.orderedMap)
+	context.MaybeValidateAtreeStorage()
+
+	return
+}
+
 // DeepRemove removes all elements (and their slabs) of domain storage map.
 func (s *DomainStorageMap) DeepRemove(context ValueRemoveContext, hasNoParentContainer bool) {
 
@@ -243,6 +305,8 @@ func (s *DomainStorageMap) DeepRemove(context ValueRemoveContext, hasNoParentCon
 
 	// Remove keys and values
 
+	s.modificationCount++
+
 	storage := s.orderedMap.Storage
 
 	err := s.orderedMap.PopIterate(func(keyStorable atree.Storable, valueStorable atree.Storable) {
@@ -285,6 +349,64 @@ func (s *DomainStorageMap) Inlined() bool {
 	return s.orderedMap.Inlined()
 }
 
+// ByteSize returns the total size of all slabs of the domain storage map and its values.
+// If the domain storage map is inlined, the size of its inlined data is included instead of its root slab.
+// The size of each retrieved slab is metered using the given memory gauge, as the slabs are loaded.
+func (s *DomainStorageMap) ByteSize(gauge common.MemoryGauge) uint64 {
+	storage := s.orderedMap.Storage
+
+	var root atree.Storable
+	if s.orderedMap.Inlined() {
+		// Use the largest max inline size, so the inlined domain storage map is not uninlined.
+		var err error
+		root, err = s.orderedMap.Storable(storage, s.orderedMap.Address(), math.MaxUint64)
+		if err != nil {
+			panic(errors.NewExternalError(err))
+		}
+	} else {
+		root = retrieveSlabMetered(gauge, storage, s.orderedMap.SlabID())
+	}
+
+	return uint64(root.ByteSize()) + referencedSlabsByteSize(gauge, storage, root)
+}
+
+// referencedSlabsByteSize returns the total size of all slabs referenced by the given storable,
+// directly or indirectly through inlined storables.
+// The size of the given storable itself is not included.
+func referencedSlabsByteSize(gauge common.MemoryGauge, storage atree.SlabStorage, storable atree.Storable) uint64 {
+	var size uint64
+
+	for _, child := range storable.ChildStorables() {
+		if slabIDStorable, ok := child.(atree.SlabIDStorable); ok {
+			child = retrieveSlabMetered(gauge, storage, atree.SlabID(slabIDStorable))
+			size += uint64(child.ByteSize())
+		}
+
+		size += referencedSlabsByteSize(gauge, storage, child)
+	}
+
+	return size
+}
+
+// retrieveSlabMetered is like retrieveSlab, but meters the size of the retrieved slab
+// using the given memory gauge.
+func retrieveSlabMetered(gauge common.MemoryGauge, storage atree.SlabStorage, slabID atree.SlabID) atree.Slab {
+	slab := retrieveSlab(storage, slabID)
+	common.UseMemory(gauge, common.NewBytesMemoryUsage(int(slab.ByteSize())))
+	return slab
+}
+
+func retrieveSlab(storage atree.SlabStorage, slabID atree.SlabID) atree.Slab {
+	slab, found, err := storage.Retrieve(slabID)
+	if err != nil {
+		panic(errors.NewExternalError(err))
+	}
+	if !found {
+		panic(errors.NewUnexpectedError("slab %s not found", slabID))
+	}
+	return slab
+}
+
 // Iterator returns an iterator (StorageMapIterator),
 // which allows iterating over the keys and values of the storage map
 func (s *DomainStorageMap) Iterator(gauge common.MemoryGauge) DomainStorageMapIterator {
@@ -297,22 +419,179 @@ func (s *DomainStorageMap) Iterator(gauge common.MemoryGauge) DomainStorageMapIt
 	}
 
 	return DomainStorageMapIterator{
-		gauge:       gauge,
-		mapIterator: mapIterator,
-		storage:     s.orderedMap.Storage,
+		gauge:             gauge,
+		mapIterator:       mapIterator,
+		storage:           s.orderedMap.Storage,
+		domainStorageMap:  s,
+		modificationCount: s.modificationCount,
+	}
+}
+
+// ReverseIterator returns an iterator (DomainStorageMapReverseIterator),
+// which allows iterating over the keys and values of the storage map in reverse iteration order,
+// i.e. in the reverse of the order of the iterator returned by Iterator.
+//
+// NOTE: atree maps only support forward iteration, so all key-value pairs
+// are read when the iterator is created. Values are converted lazily.
+func (s *DomainStorageMap) ReverseIterator(gauge common.MemoryGauge) *DomainStorageMapReverseIterator {
+	mapIterator, err := s.orderedMap.Iterator(
+		StorageMapKeyAtreeValueComparator,
+		StorageMapKeyAtreeValueHashInput,
+	)
+	if err != nil {
+		panic(errors.NewExternalError(err))
+	}
+
+	count := s.Count()
+	keys := make([]atree.Value, 0, count)
+	values := make([]atree.Value, 0, count)
+
+	for {
+		k, v, err := mapIterator.Next()
+		if err != nil {
+			panic(errors.NewExternalError(err))
+		}
+
+		if k == nil || v == nil {
+			break
+		}
+
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+
+	return &DomainStorageMapReverseIterator{
+		gauge:             gauge,
+		keys:              keys,
+		values:            values,
+		index:             len(keys),
+		domainStorageMap:  s,
+		modificationCount: s.modificationCount,
 	}
 }
 
-// DomainStorageMapIterator is an iterator over DomainStorageMap
+// Keys returns the keys of the storage map, in iteration order.
+func (s *DomainStorageMap) Keys(gauge common.MemoryGauge) []StorageMapKey {
+	keys := make([]StorageMapKey, 0, s.Count())
+
+	iterator := s.Iterator(gauge)
+
+	for {
+		k := iterator.NextKey()
+		if k == nil {
+			break
+		}
+
+		key, err := convertAtreeValueToStorageMapKey(k)
+		if err != nil {
+			panic(err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// Values returns the values of the storage map, in iteration order.
+func (s *DomainStorageMap) Values(gauge common.MemoryGauge) []Value {
+	values := make([]Value, 0, s.Count())
+
+	iterator := s.Iterator(gauge)
+
+	for {
+		value := iterator.NextValue()
+		if value == nil {
+			break
+		}
+
+		values = append(values, value)
+	}
+
+	return values
+}
+
+// ForEach calls the given function for each key and value of the storage map, in iteration order.
+// Iteration stops when the function returns false, and no further values are loaded.
+func (s *DomainStorageMap) ForEach(
+	gauge common.MemoryGauge,
+	f func(key StorageMapKey, value Value) (resume bool),
+) {
+	iterator := s.Iterator(gauge)
+
+	for {
+		k, value := iterator.Next()
+		if k == nil {
+			break
+		}
+
+		key, err := convertAtreeValueToStorageMapKey(k)
+		if err != nil {
+			panic(err)
+		}
+
+		if !f(key, value) {
+			break
+		}
+	}
+}
+
+// IterateKeysWithPrefix calls the given function for each string key of the storage map
+// which starts with the given prefix, in iteration order.
+// Iteration stops when the function returns false.
+//
+// NOTE: The underlying atree map is ordered by the hash of the keys, not by the keys themselves,
+// so non-matching keys cannot be skipped: this is a full scan of all keys of the storage map,
+// and its cost is proportional to the number of keys, not to the number of matching keys.
+// Only keys are read, values are not loaded.
+func (s *DomainStorageMap) IterateKeysWithPrefix(
+	gauge common.MemoryGauge,
+	prefix string,
+	f func(key StorageMapKey) (resume bool),
+) {
+	match := StringAtreeValuePrefixMatch(prefix)
+
+	iterator := s.Iterator(gauge)
+
+	for {
+		k := iterator.NextKey()
+		if k == nil {
+			break
+		}
+
+		if !match(k) {
+			continue
+		}
+
+		if !f(StringStorageMapKey(k.(StringAtreeValue))) {
+			break
+		}
+	}
+}
+
+// DomainStorageMapIterator is an iterator over DomainStorageMap.
+// The iterator panics with a ConcurrentModificationError
+// if the storage map is modified during iteration.
 type DomainStorageMapIterator struct {
-	gauge       common.MemoryGauge
-	mapIterator atree.MapIterator
-	storage     atree.SlabStorage
+	gauge             common.MemoryGauge
+	mapIterator       atree.MapIterator
+	storage           atree.SlabStorage
+	domainStorageMap  *DomainStorageMap
+	modificationCount uint64
+}
+
+// checkModification panics if the storage map was modified since the iterator was created.
+func (s *DomainStorageMap) checkModification(modificationCount uint64) {
+	if s.modificationCount != modificationCount {
+		panic(ConcurrentModificationError{})
+	}
 }
 
 // Next returns the next key and value of the storage map iterator.
 // If there is no further key-value pair, (nil, nil) is returned.
 func (i DomainStorageMapIterator) Next() (atree.Value, Value) {
+	i.domainStorageMap.checkModification(i.modificationCount)
+
 	k, v, err := i.mapIterator.Next()
 	if err != nil {
 		panic(errors.NewExternalError(err))
@@ -333,6 +612,8 @@ func (i DomainStorageMapIterator) Next() (atree.Value, Value) {
 // NextKey returns the next key of the storage map iterator.
 // If there is no further key, "" is returned.
 func (i DomainStorageMapIterator) NextKey() atree.Value {
+	i.domainStorageMap.checkModification(i.modificationCount)
+
 	k, err := i.mapIterator.NextKey()
 	if err != nil {
 		panic(errors.NewExternalError(err))
@@ -344,6 +625,8 @@ func (i DomainStorageMapIterator) NextKey() atree.Value {
 // NextValue returns the next value in the storage map iterator.
 // If there is no further value, nil is returned.
 func (i DomainStorageMapIterator) NextValue() Value {
+	i.domainStorageMap.checkModification(i.modificationCount)
+
 	v, err := i.mapIterator.NextValue()
 	if err != nil {
 		panic(errors.NewExternalError(err))
@@ -355,3 +638,58 @@ func (i DomainStorageMapIterator) NextValue() Value {
 
 	return MustConvertStoredValue(i.gauge, v)
 }
+
+// DomainStorageMapReverseIterator is an iterator over DomainStorageMap,
+// which yields the key-value pairs in reverse iteration order.
+type DomainStorageMapReverseIterator struct {
+	gauge             common.MemoryGauge
+	keys              []atree.Value
+	values            []atree.Value
+	index             int
+	domainStorageMap  *DomainStorageMap
+	modificationCount uint64
+}
+
+// Next returns the next key and value of the storage map iterator.
+// If there is no further key-value pair, (nil, nil) is returned.
+func (i *DomainStorageMapReverseIterator) Next() (atree.Value, Value) {
+	i.domainStorageMap.checkModification(i.modificationCount)
+
+	if i.index <= 0 {
+		return nil, nil
+	}
+	i.index--
+
+	// NOTE: Key is just an atree.Value, not an interpreter.Value,
+	// so do not need (can) convert
+
+	value := MustConvertStoredValue(i.gauge, i.values[i.index])
+
+	return i.keys[i.index], value
+}
+
+// NextKey returns the next key of the storage map iterator.
+// If there is no further key, nil is returned.
+func (i *DomainStorageMapReverseIterator) NextKey() atree.Value {
+	i.domainStorageMap.checkModification(i.modificationCount)
+
+	if i.index <= 0 {
+		return nil
+	}
+	i.index--
+
+	return i.keys[i.index]
+}
+
+// NextValue returns the next value in the storage map iterator.
+// If there is no further value, nil is returned.
+func (i *DomainStorageMapReverseIterator) NextValue() Value {
+	i.domainStorageMap.checkModification(i.modificationCount)
+
+	if i.index <= 0 {
+		return nil
+	}
+	i.index--
+
+	return MustConvertStoredValue(i.gauge, i.values[i.index])
+}
@@ -0,0 +1,124 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+// DomainDiff is the difference between two domain storage maps.
+type DomainDiff struct {
+	// Added are the keys which only exist in the second map.
+	Added []StorageMapKey
+	// Removed are the keys which only exist in the first map.
+	Removed []StorageMapKey
+	// Changed are the keys which exist in both maps, but have unequal values.
+	Changed []DomainDiffChange
+}
+
+// DomainDiffChange is a key whose value differs between two domain storage maps.
+type DomainDiffChange struct {
+	Key      StorageMapKey
+	OldValue Value
+	NewValue Value
+}
+
+// IsEmpty returns true if the diff has no added, removed, or changed keys.
+func (d DomainDiff) IsEmpty() bool {
+	return len(d.Added) == 0 &&
+		len(d.Removed) == 0 &&
+		len(d.Changed) == 0
+}
+
+// DiffDomainStorageMaps returns the keys which were added, removed, or changed
+// between the given domain storage maps. A nil map is treated as an empty map.
+//
+// Values are compared using EquatableValue.Equal, so a key whose value was replaced
+// by an equal value is not reported as changed. Values which are not equatable
+// are always reported as changed.
+//
+// Removed and changed keys are in the iteration order of the first map,
+// added keys are in the iteration order of the second map.
+func DiffDomainStorageMaps(
+	context ValueComparisonContext,
+	before *DomainStorageMap,
+	after *DomainStorageMap,
+) DomainDiff {
+	var diff DomainDiff
+
+	if before != nil {
+		iterator := before.Iterator(context)
+
+		for {
+			k, oldValue := iterator.Next()
+			if k == nil {
+				break
+			}
+
+			key, err := convertAtreeValueToStorageMapKey(k)
+			if err != nil {
+				panic(err)
+			}
+
+			var newValue Value
+			if after != nil {
+				newValue = after.ReadValue(context, key)
+			}
+
+			if newValue == nil {
+				diff.Removed = append(diff.Removed, key)
+				continue
+			}
+
+			equatableValue, ok := oldValue.(EquatableValue)
+			if ok && equatableValue.Equal(context, EmptyLocationRange, newValue) {
+				continue
+			}
+
+			diff.Changed = append(
+				diff.Changed,
+				DomainDiffChange{
+					Key:      key,
+					OldValue: oldValue,
+					NewValue: newValue,
+				},
+			)
+		}
+	}
+
+	if after != nil {
+		iterator := after.Iterator(context)
+
+		for {
+			k := iterator.NextKey()
+			if k == nil {
+				break
+			}
+
+			key, err := convertAtreeValueToStorageMapKey(k)
+			if err != nil {
+				panic(err)
+			}
+
+			if before != nil && before.ValueExists(key) {
+				continue
+			}
+
+			diff.Added = append(diff.Added, key)
+		}
+	}
+
+	return diff
+}
@@ -20,7 +20,9 @@ package interpreter_test
 
 import (
 	"math/rand"
+	"slices"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/onflow/atree"
@@ -109,6 +111,82 @@ func TestDomainStorageMapValueExists(t *testing.T) {
 	})
 }
 
+func TestDomainStorageMapValueExistsDoesNotLoadValue(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	largeValueKey := interpreter.StringStorageMapKey("large")
+	smallValueKey := interpreter.StringStorageMapKey("small")
+
+	init := func() (atree.SlabID, map[string][]byte, map[string]uint64) {
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off automatic AtreeStorageValidationEnabled and explicitly check atree storage health directly.
+		// This is because AccountStorageMap isn't created through storage, so there isn't any account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, atreeValueValidationEnabled, atreeStorageValidationEnabled)
+
+		domainStorageMap := interpreter.NewDomainStorageMap(nil, storage, atree.Address(address))
+
+		// Large value is stored in its own slabs.
+		largeValue := interpreter.NewUnmeteredStringValue(strings.Repeat("a", 10_000))
+		domainStorageMap.WriteValue(inter, largeValueKey, largeValue)
+
+		smallValue := interpreter.NewUnmeteredIntValueFromInt64(1)
+		domainStorageMap.WriteValue(inter, smallValueKey, smallValue)
+
+		err := storage.Commit(inter, false)
+		require.NoError(t, err)
+
+		valueID := domainStorageMap.ValueID()
+		return atreeValueIDToSlabID(valueID), ledger.StoredValues, ledger.StorageIndices
+	}
+
+	domainStorageMapRootSlabID, storedValues, storageIndices := init()
+
+	var readCount int
+
+	ledger := NewTestLedgerWithData(
+		func(_, _, _ []byte) {
+			readCount++
+		},
+		nil,
+		storedValues,
+		storageIndices,
+	)
+	storage := runtime.NewStorage(
+		ledger,
+		nil,
+		runtime.StorageConfig{},
+	)
+
+	domainStorageMap := interpreter.NewDomainStorageMapWithRootID(storage, domainStorageMapRootSlabID)
+
+	// Loading domain storage map reads its root slab
+	require.Equal(t, 1, readCount)
+
+	require.True(t, domainStorageMap.ValueExists(largeValueKey))
+	require.True(t, domainStorageMap.ValueExists(smallValueKey))
+	require.False(t, domainStorageMap.ValueExists(interpreter.StringStorageMapKey("missing")))
+
+	// Large value is not loaded
+	require.Equal(t, 1, readCount)
+
+	// Reading large value loads its slabs
+	value := domainStorageMap.ReadValue(nil, largeValueKey)
+	require.NotNil(t, value)
+	require.Greater(t, readCount, 1)
+
+	CheckAtreeStorageHealth(t, storage, []atree.SlabID{domainStorageMapRootSlabID})
+}
+
 func TestDomainStorageMapReadValue(t *testing.T) {
 	t.Parallel()
 
@@ -367,6 +445,138 @@ func TestDomainStorageMapRemoveValue(t *testing.T) {
 	})
 }
 
+func TestDomainStorageMapRemoveKeys(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off AtreeStorageValidationEnabled and explicitly check atree storage health at the end of test.
+		// This is because AccountStorageMap isn't created through runtime.Storage, so there isn't any
+		// account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
+		domainStorageMap := interpreter.NewDomainStorageMap(nil, storage, atree.Address(address))
+		require.NotNil(t, domainStorageMap)
+
+		removed := domainStorageMap.RemoveKeys(
+			inter,
+			[]interpreter.StorageMapKey{
+				interpreter.StringStorageMapKey("a"),
+				interpreter.Uint64StorageMapKey(1),
+			},
+		)
+		require.Equal(t, 0, removed)
+		require.Equal(t, uint64(0), domainStorageMap.Count())
+
+		valueID := domainStorageMap.ValueID()
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{atreeValueIDToSlabID(valueID)})
+	})
+
+	t.Run("existing and missing keys", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off AtreeStorageValidationEnabled and explicitly check atree storage health at the end of test.
+		// This is because AccountStorageMap isn't created through runtime.Storage, so there isn't any
+		// account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
+		const count = 10
+		domainStorageMap, domainValues := createDomainStorageMap(storage, inter, address, count, random)
+
+		// Write large arrays, which are stored in separate slabs,
+		// to check that removed values are deep removed.
+
+		var arrayKeys []interpreter.StorageMapKey
+
+		for i := range 3 {
+			elements := make([]interpreter.Value, 100)
+			for j := range elements {
+				elements[j] = interpreter.NewUnmeteredIntValueFromInt64(int64(j))
+			}
+
+			array := interpreter.NewArrayValue(
+				inter,
+				interpreter.EmptyLocationRange,
+				&interpreter.VariableSizedStaticType{
+					Type: interpreter.PrimitiveStaticTypeInt,
+				},
+				address,
+				elements...,
+			)
+
+			key := interpreter.StringStorageMapKey("array" + strconv.Itoa(i))
+			domainStorageMap.WriteValue(inter, key, array)
+
+			arrayKeys = append(arrayKeys, key)
+		}
+
+		// Remove half of the existing values, all arrays, and missing keys
+
+		var keys []interpreter.StorageMapKey
+
+		for key := range domainValues { //nolint:maprange
+			if len(keys) == count/2 {
+				break
+			}
+			keys = append(keys, key)
+			delete(domainValues, key)
+		}
+
+		keys = append(keys, arrayKeys...)
+
+		expectedRemoved := len(keys)
+
+		keys = append(
+			keys,
+			interpreter.StringStorageMapKey("missing"),
+			interpreter.Uint64StorageMapKey(1),
+			// Duplicate key is only removed once
+			arrayKeys[0],
+		)
+
+		removed := domainStorageMap.RemoveKeys(inter, keys)
+		require.Equal(t, expectedRemoved, removed)
+
+		checkDomainStorageMapData(t, inter, domainStorageMap, domainValues)
+
+		valueID := domainStorageMap.ValueID()
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{atreeValueIDToSlabID(valueID)})
+	})
+}
+
 func TestDomainStorageMapIteratorNext(t *testing.T) {
 	t.Parallel()
 
@@ -476,6 +686,162 @@ func TestDomainStorageMapIteratorNext(t *testing.T) {
 	})
 }
 
+func TestDomainStorageMapIteratorConcurrentModification(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	newDomainStorageMap := func(t *testing.T) (*interpreter.Interpreter, *interpreter.DomainStorageMap, domainStorageMapValues) {
+		random := rand.New(rand.NewSource(42))
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off AtreeStorageValidationEnabled, see other tests.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
+		const count = 10
+		domainStorageMap, domainValues := createDomainStorageMap(storage, inter, address, count, random)
+
+		return inter, domainStorageMap, domainValues
+	}
+
+	t.Run("set", func(t *testing.T) {
+		t.Parallel()
+
+		inter, domainStorageMap, _ := newDomainStorageMap(t)
+
+		iterator := domainStorageMap.Iterator(nil)
+
+		key, value := iterator.Next()
+		require.NotNil(t, key)
+		require.NotNil(t, value)
+
+		domainStorageMap.WriteValue(
+			inter,
+			interpreter.StringStorageMapKey("new"),
+			interpreter.NewUnmeteredIntValueFromInt64(1),
+		)
+
+		require.PanicsWithValue(t,
+			interpreter.ConcurrentModificationError{},
+			func() {
+				iterator.Next()
+			},
+		)
+		require.PanicsWithValue(t,
+			interpreter.ConcurrentModificationError{},
+			func() {
+				iterator.NextKey()
+			},
+		)
+		require.PanicsWithValue(t,
+			interpreter.ConcurrentModificationError{},
+			func() {
+				iterator.NextValue()
+			},
+		)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		t.Parallel()
+
+		inter, domainStorageMap, domainValues := newDomainStorageMap(t)
+
+		iterator := domainStorageMap.Iterator(nil)
+
+		key := iterator.NextKey()
+		require.NotNil(t, key)
+
+		// Removing a non-existent key is not a modification
+
+		existed := domainStorageMap.WriteValue(inter, interpreter.StringStorageMapKey("missing"), nil)
+		require.False(t, existed)
+
+		require.NotNil(t, iterator.NextKey())
+
+		for key := range domainValues { //nolint:maprange
+			existed := domainStorageMap.WriteValue(inter, key, nil)
+			require.True(t, existed)
+			break
+		}
+
+		require.PanicsWithValue(t,
+			interpreter.ConcurrentModificationError{},
+			func() {
+				iterator.NextKey()
+			},
+		)
+	})
+
+	t.Run("reverse iterator", func(t *testing.T) {
+		t.Parallel()
+
+		inter, domainStorageMap, _ := newDomainStorageMap(t)
+
+		iterator := domainStorageMap.ReverseIterator(nil)
+
+		key, value := iterator.Next()
+		require.NotNil(t, key)
+		require.NotNil(t, value)
+
+		domainStorageMap.RemoveKeys(
+			inter,
+			[]interpreter.StorageMapKey{
+				interpreter.StringStorageMapKey(key.(interpreter.StringAtreeValue)),
+			},
+		)
+
+		require.PanicsWithValue(t,
+			interpreter.ConcurrentModificationError{},
+			func() {
+				iterator.Next()
+			},
+		)
+	})
+
+	t.Run("no modification", func(t *testing.T) {
+		t.Parallel()
+
+		inter, domainStorageMap, domainValues := newDomainStorageMap(t)
+
+		iterator := domainStorageMap.Iterator(nil)
+
+		// Reading is not a modification
+
+		for key := range domainValues { //nolint:maprange
+			require.NotNil(t, domainStorageMap.ReadValue(nil, key))
+		}
+
+		count := 0
+		for key, _ := iterator.Next(); key != nil; key, _ = iterator.Next() {
+			count++
+		}
+		require.Equal(t, len(domainValues), count)
+
+		// A new iterator can be used after a modification
+
+		domainStorageMap.WriteValue(
+			inter,
+			interpreter.StringStorageMapKey("new"),
+			interpreter.NewUnmeteredIntValueFromInt64(1),
+		)
+
+		require.Len(t, domainStorageMap.Keys(nil), len(domainValues)+1)
+	})
+}
+
 func TestDomainStorageMapIteratorNextKey(t *testing.T) {
 	t.Parallel()
 
@@ -693,7 +1059,7 @@ func TestDomainStorageMapIteratorNextValue(t *testing.T) {
 	})
 }
 
-func TestDomainStorageMapLoadFromRootSlabID(t *testing.T) {
+func TestDomainStorageMapKeysAndValues(t *testing.T) {
 	t.Parallel()
 
 	address := common.MustBytesToAddress([]byte{0x1})
@@ -701,60 +1067,664 @@ func TestDomainStorageMapLoadFromRootSlabID(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		t.Parallel()
 
-		init := func() (atree.SlabID, domainStorageMapValues, map[string][]byte, map[string]uint64) {
-			ledger := NewTestLedger(nil, nil)
-			storage := runtime.NewStorage(
-				ledger,
-				nil,
-				runtime.StorageConfig{},
-			)
-
-			inter := NewTestInterpreterWithStorage(t, storage)
-
-			domainStorageMap := interpreter.NewDomainStorageMap(nil, storage, atree.Address(address))
-			require.NotNil(t, domainStorageMap)
-			require.Equal(t, uint64(0), domainStorageMap.Count())
-
-			err := storage.Commit(inter, false)
-			require.NoError(t, err)
-
-			valueID := domainStorageMap.ValueID()
-			return atreeValueIDToSlabID(valueID), make(domainStorageMapValues), ledger.StoredValues, ledger.StorageIndices
-		}
-
-		domainStorageMapRootSlabID, domainValues, storedValues, storageIndices := init()
-
-		ledger := NewTestLedgerWithData(nil, nil, storedValues, storageIndices)
+		ledger := NewTestLedger(nil, nil)
 		storage := runtime.NewStorage(
 			ledger,
 			nil,
 			runtime.StorageConfig{},
 		)
 
-		domainStorageMap := interpreter.NewDomainStorageMapWithRootID(storage, domainStorageMapRootSlabID)
+		domainStorageMap := interpreter.NewDomainStorageMap(nil, storage, atree.Address(address))
+		require.NotNil(t, domainStorageMap)
 		require.Equal(t, uint64(0), domainStorageMap.Count())
 
-		inter := NewTestInterpreterWithStorage(t, storage)
-
-		checkDomainStorageMapData(t, inter, domainStorageMap, domainValues)
+		require.Empty(t, domainStorageMap.Keys(nil))
+		require.Empty(t, domainStorageMap.Values(nil))
 
-		CheckAtreeStorageHealth(t, storage, []atree.SlabID{domainStorageMapRootSlabID})
+		valueID := domainStorageMap.ValueID()
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{atreeValueIDToSlabID(valueID)})
 	})
 
 	t.Run("non-empty", func(t *testing.T) {
 		t.Parallel()
 
-		init := func() (atree.SlabID, domainStorageMapValues, map[string][]byte, map[string]uint64) {
-			random := rand.New(rand.NewSource(42))
+		random := rand.New(rand.NewSource(42))
 
-			ledger := NewTestLedger(nil, nil)
-			storage := runtime.NewStorage(
-				ledger,
-				nil,
-				runtime.StorageConfig{},
-			)
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
 
-			// Turn off automatic AtreeStorageValidationEnabled and explicitly check atree storage health directly.
+		// Turn off AtreeStorageValidationEnabled and explicitly check atree storage health at the end of test.
+		// This is because AccountStorageMap isn't created through runtime.Storage, so there isn't any
+		// account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
+		const count = 10
+		domainStorageMap, domainValues := createDomainStorageMap(storage, inter, address, count, random)
+
+		keys := domainStorageMap.Keys(nil)
+		values := domainStorageMap.Values(nil)
+
+		require.Equal(t, count, len(keys))
+		require.Equal(t, count, len(values))
+
+		// Check keys and values line up with manual iteration
+
+		iterator := domainStorageMap.Iterator(nil)
+
+		index := 0
+		for {
+			k, v := iterator.Next()
+			if k == nil {
+				break
+			}
+
+			kv := k.(interpreter.StringAtreeValue)
+			key := interpreter.StringStorageMapKey(kv)
+
+			require.Equal(t, key, keys[index])
+			checkCadenceValue(t, inter, values[index], v)
+			checkCadenceValue(t, inter, values[index], domainValues[key])
+
+			index++
+		}
+		require.Equal(t, count, index)
+
+		valueID := domainStorageMap.ValueID()
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{atreeValueIDToSlabID(valueID)})
+	})
+}
+
+func TestDomainStorageMapMixedKeys(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	// Interleave string and uint64 keys.
+	// The string key "aaaaaaaa" has the same hash input
+	// as the uint64 key 0x6161616161616161.
+	keys := []interpreter.StorageMapKey{
+		interpreter.StringStorageMapKey("aaaaaaaa"),
+		interpreter.Uint64StorageMapKey(0x6161616161616161),
+	}
+	for i := range 20 {
+		keys = append(
+			keys,
+			interpreter.StringStorageMapKey(strconv.Itoa(i)),
+			interpreter.Uint64StorageMapKey(uint64(i)),
+		)
+	}
+
+	iterationOrder := func(domainStorageMap *interpreter.DomainStorageMap) []interpreter.StorageMapKey {
+		var keys []interpreter.StorageMapKey
+		iterator := domainStorageMap.Iterator(nil)
+		for {
+			k, _ := iterator.Next()
+			if k == nil {
+				break
+			}
+
+			switch k := k.(type) {
+			case interpreter.StringAtreeValue:
+				keys = append(keys, interpreter.StringStorageMapKey(k))
+			case interpreter.Uint64AtreeValue:
+				keys = append(keys, interpreter.Uint64StorageMapKey(k))
+			default:
+				require.Fail(t, "unexpected key type", "%T", k)
+			}
+		}
+		return keys
+	}
+
+	init := func() (atree.SlabID, []interpreter.StorageMapKey, map[string][]byte, map[string]uint64) {
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off automatic AtreeStorageValidationEnabled and explicitly check atree storage health directly.
+		// This is because AccountStorageMap isn't created through storage, so there isn't any account register to match AccountStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, atreeValueValidationEnabled, atreeStorageValidationEnabled)
+
+		domainStorageMap := interpreter.NewDomainStorageMap(nil, storage, atree.Address(address))
+
+		for i, key := range keys {
+			existed := domainStorageMap.WriteValue(inter, key, interpreter.NewUnmeteredIntValueFromInt64(int64(i)))
+			require.False(t, existed)
+		}
+		require.Equal(t, uint64(len(keys)), domainStorageMap.Count())
+
+		for i, key := range keys {
+			require.True(t, domainStorageMap.ValueExists(key))
+
+			value := domainStorageMap.ReadValue(nil, key)
+			checkCadenceValue(t, inter, value, interpreter.NewUnmeteredIntValueFromInt64(int64(i)))
+		}
+
+		require.False(t, domainStorageMap.ValueExists(interpreter.StringStorageMapKey("missing")))
+		require.False(t, domainStorageMap.ValueExists(interpreter.Uint64StorageMapKey(1000)))
+
+		// Iteration is stable
+		order := iterationOrder(domainStorageMap)
+		require.ElementsMatch(t, keys, order)
+		require.Equal(t, order, iterationOrder(domainStorageMap))
+		require.Equal(t, order, domainStorageMap.Keys(nil))
+
+		err := storage.Commit(inter, false)
+		require.NoError(t, err)
+
+		valueID := domainStorageMap.ValueID()
+		return atreeValueIDToSlabID(valueID), order, ledger.StoredValues, ledger.StorageIndices
+	}
+
+	domainStorageMapRootSlabID, order, storedValues, storageIndices := init()
+
+	// Load domain storage map from storage and check iteration order is unchanged
+
+	ledger := NewTestLedgerWithData(nil, nil, storedValues, storageIndices)
+	storage := runtime.NewStorage(
+		ledger,
+		nil,
+		runtime.StorageConfig{},
+	)
+
+	inter := NewTestInterpreterWithStorage(t, storage)
+
+	domainStorageMap := interpreter.NewDomainStorageMapWithRootID(storage, domainStorageMapRootSlabID)
+
+	require.Equal(t, order, iterationOrder(domainStorageMap))
+
+	for i, key := range keys {
+		require.True(t, domainStorageMap.ValueExists(key))
+
+		value := domainStorageMap.ReadValue(nil, key)
+		checkCadenceValue(t, inter, value, interpreter.NewUnmeteredIntValueFromInt64(int64(i)))
+	}
+
+	CheckAtreeStorageHealth(t, storage, []atree.SlabID{domainStorageMapRootSlabID})
+}
+
+func TestDomainStorageMapIterateKeysWithPrefix(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	ledger := NewTestLedger(nil, nil)
+	storage := runtime.NewStorage(
+		ledger,
+		nil,
+		runtime.StorageConfig{},
+	)
+
+	// Turn off AtreeStorageValidationEnabled and explicitly check atree storage health at the end of test.
+	// This is because DomainStorageMap isn't created through runtime.Storage, so there isn't any
+	// account register to match DomainStorageMap root slab.
+	const atreeValueValidationEnabled = true
+	const atreeStorageValidationEnabled = false
+	inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+		t,
+		storage,
+		atreeValueValidationEnabled,
+		atreeStorageValidationEnabled,
+	)
+
+	domainStorageMap := interpreter.NewDomainStorageMap(nil, storage, atree.Address(address))
+
+	expectedKeys := map[interpreter.StorageMapKey]struct{}{}
+
+	for i := range 100 {
+		for _, prefix := range []string{"flow", "flowToken", "fl", "other", ""} {
+			key := interpreter.StringStorageMapKey(prefix + strconv.Itoa(i))
+			domainStorageMap.WriteValue(inter, key, interpreter.NewUnmeteredIntValueFromInt64(int64(i)))
+
+			if strings.HasPrefix(string(key), "flow") {
+				expectedKeys[key] = struct{}{}
+			}
+		}
+
+		// Uint64 keys are never matched
+		domainStorageMap.WriteValue(
+			inter,
+			interpreter.Uint64StorageMapKey(uint64(i)),
+			interpreter.NewUnmeteredIntValueFromInt64(int64(i)),
+		)
+	}
+
+	t.Run("all", func(t *testing.T) {
+		visitedKeys := map[interpreter.StorageMapKey]struct{}{}
+
+		domainStorageMap.IterateKeysWithPrefix(
+			nil,
+			"flow",
+			func(key interpreter.StorageMapKey) bool {
+				_, visited := visitedKeys[key]
+				require.False(t, visited)
+
+				visitedKeys[key] = struct{}{}
+				return true
+			},
+		)
+
+		require.Equal(t, expectedKeys, visitedKeys)
+	})
+
+	t.Run("short-circuit", func(t *testing.T) {
+		const limit = 10
+
+		var visitedKeys []interpreter.StorageMapKey
+
+		domainStorageMap.IterateKeysWithPrefix(
+			nil,
+			"flow",
+			func(key interpreter.StorageMapKey) bool {
+				visitedKeys = append(visitedKeys, key)
+				return len(visitedKeys) < limit
+			},
+		)
+
+		require.Len(t, visitedKeys, limit)
+		for _, key := range visitedKeys {
+			require.Contains(t, expectedKeys, key)
+		}
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		domainStorageMap.IterateKeysWithPrefix(
+			nil,
+			"missing",
+			func(key interpreter.StorageMapKey) bool {
+				require.Fail(t, "unexpected key", "%v", key)
+				return true
+			},
+		)
+	})
+
+	valueID := domainStorageMap.ValueID()
+	CheckAtreeStorageHealth(t, storage, []atree.SlabID{atreeValueIDToSlabID(valueID)})
+}
+
+func TestDomainStorageMapForEach(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	const count = 10
+
+	init := func() (atree.SlabID, domainStorageMapValues, map[string][]byte, map[string]uint64) {
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off AtreeStorageValidationEnabled, as the domain storage map
+		// isn't created through runtime.Storage.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
+		domainStorageMap := interpreter.NewDomainStorageMap(nil, storage, atree.Address(address))
+
+		domainValues := make(domainStorageMapValues)
+
+		for i := range count {
+			key := interpreter.StringStorageMapKey(strconv.Itoa(i))
+
+			// Large values are stored in their own slabs,
+			// so loading a value reads a register
+			value := interpreter.NewUnmeteredStringValue(strings.Repeat(strconv.Itoa(i), 1_000))
+
+			domainStorageMap.WriteValue(inter, key, value)
+			domainValues[key] = value
+		}
+
+		err := storage.Commit(inter, false)
+		require.NoError(t, err)
+
+		return atreeValueIDToSlabID(domainStorageMap.ValueID()), domainValues, ledger.StoredValues, ledger.StorageIndices
+	}
+
+	rootSlabID, domainValues, storedValues, storageIndices := init()
+
+	// newDomainStorageMap loads the domain storage map from a copy of the committed registers,
+	// and returns a pointer to the number of subsequent register reads.
+	newDomainStorageMap := func() (*interpreter.DomainStorageMap, *int) {
+		storedValuesCopy := make(map[string][]byte, len(storedValues))
+		for key, value := range storedValues { //nolint:maprange
+			storedValuesCopy[key] = value
+		}
+
+		storageIndicesCopy := make(map[string]uint64, len(storageIndices))
+		for key, value := range storageIndices { //nolint:maprange
+			storageIndicesCopy[key] = value
+		}
+
+		var reads int
+		ledger := NewTestLedgerWithData(
+			func(_, _, _ []byte) {
+				reads++
+			},
+			nil,
+			storedValuesCopy,
+			storageIndicesCopy,
+		)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		domainStorageMap := interpreter.NewDomainStorageMapWithRootID(storage, rootSlabID)
+		require.Equal(t, uint64(count), domainStorageMap.Count())
+
+		reads = 0
+
+		return domainStorageMap, &reads
+	}
+
+	t.Run("all", func(t *testing.T) {
+		t.Parallel()
+
+		domainStorageMap, reads := newDomainStorageMap()
+
+		visitedValues := make(domainStorageMapValues)
+
+		domainStorageMap.ForEach(
+			nil,
+			func(key interpreter.StorageMapKey, value interpreter.Value) bool {
+				require.NotContains(t, visitedValues, key)
+				visitedValues[key] = value
+				return true
+			},
+		)
+
+		require.Equal(t, count, *reads)
+		require.Equal(t, domainValues, visitedValues)
+	})
+
+	t.Run("early termination", func(t *testing.T) {
+		t.Parallel()
+
+		domainStorageMap, reads := newDomainStorageMap()
+
+		const limit = 3
+
+		var visitedKeys []interpreter.StorageMapKey
+
+		domainStorageMap.ForEach(
+			nil,
+			func(key interpreter.StorageMapKey, value interpreter.Value) bool {
+				require.Equal(t, domainValues[key], value)
+
+				visitedKeys = append(visitedKeys, key)
+				return len(visitedKeys) < limit
+			},
+		)
+
+		require.Len(t, visitedKeys, limit)
+
+		// The values after the termination are not loaded
+		require.Equal(t, limit, *reads)
+	})
+
+	t.Run("metering", func(t *testing.T) {
+		t.Parallel()
+
+		forEachGauge := newTestMemoryGauge()
+		iteratorGauge := newTestMemoryGauge()
+
+		domainStorageMap, _ := newDomainStorageMap()
+		domainStorageMap.ForEach(
+			forEachGauge,
+			func(_ interpreter.StorageMapKey, _ interpreter.Value) bool {
+				return true
+			},
+		)
+
+		domainStorageMap, _ = newDomainStorageMap()
+		iterator := domainStorageMap.Iterator(iteratorGauge)
+		for {
+			key, _ := iterator.Next()
+			if key == nil {
+				break
+			}
+		}
+
+		require.Equal(t, iteratorGauge.meter, forEachGauge.meter)
+	})
+}
+
+func TestDomainStorageMapReverseIterator(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	newDomainStorageMap := func(t *testing.T) (*interpreter.Interpreter, *interpreter.DomainStorageMap) {
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off AtreeStorageValidationEnabled, because DomainStorageMap isn't created through runtime.Storage,
+		// so there isn't any account register to match DomainStorageMap root slab.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
+		return inter, interpreter.NewDomainStorageMap(nil, storage, atree.Address(address))
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		_, domainStorageMap := newDomainStorageMap(t)
+
+		iterator := domainStorageMap.ReverseIterator(nil)
+
+		k, v := iterator.Next()
+		require.Nil(t, k)
+		require.Nil(t, v)
+
+		require.Nil(t, iterator.NextKey())
+		require.Nil(t, iterator.NextValue())
+	})
+
+	t.Run("mixed keys", func(t *testing.T) {
+		t.Parallel()
+
+		inter, domainStorageMap := newDomainStorageMap(t)
+
+		for i := range 100 {
+			domainStorageMap.WriteValue(
+				inter,
+				interpreter.StringStorageMapKey(strconv.Itoa(i)),
+				interpreter.NewUnmeteredIntValueFromInt64(int64(i)),
+			)
+			domainStorageMap.WriteValue(
+				inter,
+				interpreter.Uint64StorageMapKey(uint64(i)),
+				interpreter.NewUnmeteredIntValueFromInt64(int64(-i)),
+			)
+		}
+
+		var forwardKeys []atree.Value
+		var forwardValues []interpreter.Value
+
+		iterator := domainStorageMap.Iterator(nil)
+		for {
+			k, v := iterator.Next()
+			if k == nil {
+				break
+			}
+			forwardKeys = append(forwardKeys, k)
+			forwardValues = append(forwardValues, v)
+		}
+		require.Len(t, forwardKeys, 200)
+
+		slices.Reverse(forwardKeys)
+		slices.Reverse(forwardValues)
+
+		// Next
+
+		var reverseKeys []atree.Value
+		var reverseValues []interpreter.Value
+
+		reverseIterator := domainStorageMap.ReverseIterator(nil)
+		for {
+			k, v := reverseIterator.Next()
+			if k == nil {
+				require.Nil(t, v)
+				break
+			}
+			reverseKeys = append(reverseKeys, k)
+			reverseValues = append(reverseValues, v)
+		}
+
+		require.Equal(t, forwardKeys, reverseKeys)
+		require.Equal(t, len(forwardValues), len(reverseValues))
+		for i, value := range reverseValues {
+			checkCadenceValue(t, inter, value, forwardValues[i])
+		}
+
+		// NextKey
+
+		reverseKeys = nil
+
+		reverseIterator = domainStorageMap.ReverseIterator(nil)
+		for {
+			k := reverseIterator.NextKey()
+			if k == nil {
+				break
+			}
+			reverseKeys = append(reverseKeys, k)
+		}
+
+		require.Equal(t, forwardKeys, reverseKeys)
+
+		// NextValue
+
+		reverseValues = nil
+
+		reverseIterator = domainStorageMap.ReverseIterator(nil)
+		for {
+			v := reverseIterator.NextValue()
+			if v == nil {
+				break
+			}
+			reverseValues = append(reverseValues, v)
+		}
+
+		require.Equal(t, len(forwardValues), len(reverseValues))
+		for i, value := range reverseValues {
+			checkCadenceValue(t, inter, value, forwardValues[i])
+		}
+
+		// Reverse iteration is stable
+
+		var secondReverseKeys []atree.Value
+
+		reverseIterator = domainStorageMap.ReverseIterator(nil)
+		for {
+			k := reverseIterator.NextKey()
+			if k == nil {
+				break
+			}
+			secondReverseKeys = append(secondReverseKeys, k)
+		}
+
+		require.Equal(t, reverseKeys, secondReverseKeys)
+	})
+}
+
+func TestDomainStorageMapLoadFromRootSlabID(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		init := func() (atree.SlabID, domainStorageMapValues, map[string][]byte, map[string]uint64) {
+			ledger := NewTestLedger(nil, nil)
+			storage := runtime.NewStorage(
+				ledger,
+				nil,
+				runtime.StorageConfig{},
+			)
+
+			inter := NewTestInterpreterWithStorage(t, storage)
+
+			domainStorageMap := interpreter.NewDomainStorageMap(nil, storage, atree.Address(address))
+			require.NotNil(t, domainStorageMap)
+			require.Equal(t, uint64(0), domainStorageMap.Count())
+
+			err := storage.Commit(inter, false)
+			require.NoError(t, err)
+
+			valueID := domainStorageMap.ValueID()
+			return atreeValueIDToSlabID(valueID), make(domainStorageMapValues), ledger.StoredValues, ledger.StorageIndices
+		}
+
+		domainStorageMapRootSlabID, domainValues, storedValues, storageIndices := init()
+
+		ledger := NewTestLedgerWithData(nil, nil, storedValues, storageIndices)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		domainStorageMap := interpreter.NewDomainStorageMapWithRootID(storage, domainStorageMapRootSlabID)
+		require.Equal(t, uint64(0), domainStorageMap.Count())
+
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		checkDomainStorageMapData(t, inter, domainStorageMap, domainValues)
+
+		CheckAtreeStorageHealth(t, storage, []atree.SlabID{domainStorageMapRootSlabID})
+	})
+
+	t.Run("non-empty", func(t *testing.T) {
+		t.Parallel()
+
+		init := func() (atree.SlabID, domainStorageMapValues, map[string][]byte, map[string]uint64) {
+			random := rand.New(rand.NewSource(42))
+
+			ledger := NewTestLedger(nil, nil)
+			storage := runtime.NewStorage(
+				ledger,
+				nil,
+				runtime.StorageConfig{},
+			)
+
+			// Turn off automatic AtreeStorageValidationEnabled and explicitly check atree storage health directly.
 			// This is because AccountStorageMap isn't created through storage, so there isn't any account register to match AccountStorageMap root slab.
 			const atreeValueValidationEnabled = true
 			const atreeStorageValidationEnabled = false
@@ -812,3 +1782,122 @@ func atreeValueIDToSlabID(vid atree.ValueID) atree.SlabID {
 		atree.SlabIndex(vid[8:]),
 	)
 }
+
+func TestDomainStorageMapDiff(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	newStorageAndInterpreter := func(t *testing.T) (*runtime.Storage, *interpreter.Interpreter) {
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(
+			ledger,
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off AtreeStorageValidationEnabled, as the domain storage maps
+		// aren't created through runtime.Storage.
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
+		return storage, inter
+	}
+
+	t.Run("nil", func(t *testing.T) {
+		t.Parallel()
+
+		_, inter := newStorageAndInterpreter(t)
+
+		diff := interpreter.DiffDomainStorageMaps(inter, nil, nil)
+		require.True(t, diff.IsEmpty())
+	})
+
+	t.Run("added, removed, changed", func(t *testing.T) {
+		t.Parallel()
+
+		storage, inter := newStorageAndInterpreter(t)
+
+		before := interpreter.NewDomainStorageMap(nil, storage, atree.Address(address))
+		after := interpreter.NewDomainStorageMap(nil, storage, atree.Address(address))
+
+		// Same value object in both maps
+		sameValue := interpreter.NewUnmeteredIntValueFromInt64(1)
+		before.WriteValue(inter, interpreter.StringStorageMapKey("same"), sameValue)
+		after.WriteValue(inter, interpreter.StringStorageMapKey("same"), sameValue)
+
+		// Different, but equal value objects
+		before.WriteValue(inter, interpreter.StringStorageMapKey("equal"), interpreter.NewUnmeteredStringValue("a"))
+		after.WriteValue(inter, interpreter.StringStorageMapKey("equal"), interpreter.NewUnmeteredStringValue("a"))
+
+		// Unequal values
+		before.WriteValue(inter, interpreter.StringStorageMapKey("changed"), interpreter.NewUnmeteredIntValueFromInt64(2))
+		after.WriteValue(inter, interpreter.StringStorageMapKey("changed"), interpreter.NewUnmeteredIntValueFromInt64(3))
+
+		before.WriteValue(inter, interpreter.StringStorageMapKey("removed"), interpreter.NewUnmeteredIntValueFromInt64(4))
+
+		after.WriteValue(inter, interpreter.Uint64StorageMapKey(5), interpreter.NewUnmeteredIntValueFromInt64(5))
+
+		diff := interpreter.DiffDomainStorageMaps(inter, before, after)
+		require.False(t, diff.IsEmpty())
+
+		require.Equal(t,
+			[]interpreter.StorageMapKey{interpreter.Uint64StorageMapKey(5)},
+			diff.Added,
+		)
+		require.Equal(t,
+			[]interpreter.StorageMapKey{interpreter.StringStorageMapKey("removed")},
+			diff.Removed,
+		)
+		require.Equal(t,
+			[]interpreter.DomainDiffChange{
+				{
+					Key:      interpreter.StringStorageMapKey("changed"),
+					OldValue: interpreter.NewUnmeteredIntValueFromInt64(2),
+					NewValue: interpreter.NewUnmeteredIntValueFromInt64(3),
+				},
+			},
+			diff.Changed,
+		)
+
+		// The reverse diff swaps added and removed keys, and old and new values
+
+		reverseDiff := interpreter.DiffDomainStorageMaps(inter, after, before)
+
+		require.Equal(t, diff.Added, reverseDiff.Removed)
+		require.Equal(t, diff.Removed, reverseDiff.Added)
+		require.Equal(t,
+			[]interpreter.DomainDiffChange{
+				{
+					Key:      interpreter.StringStorageMapKey("changed"),
+					OldValue: interpreter.NewUnmeteredIntValueFromInt64(3),
+					NewValue: interpreter.NewUnmeteredIntValueFromInt64(2),
+				},
+			},
+			reverseDiff.Changed,
+		)
+	})
+
+	t.Run("nil before", func(t *testing.T) {
+		t.Parallel()
+
+		storage, inter := newStorageAndInterpreter(t)
+
+		after := interpreter.NewDomainStorageMap(nil, storage, atree.Address(address))
+		after.WriteValue(inter, interpreter.StringStorageMapKey("a"), interpreter.NewUnmeteredIntValueFromInt64(1))
+
+		diff := interpreter.DiffDomainStorageMaps(inter, nil, after)
+		require.Equal(t,
+			[]interpreter.StorageMapKey{interpreter.StringStorageMapKey("a")},
+			diff.Added,
+		)
+		require.Empty(t, diff.Removed)
+		require.Empty(t, diff.Changed)
+	})
+}
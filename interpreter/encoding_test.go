@@ -3783,6 +3783,175 @@ func TestEncodeDecodePathValue(t *testing.T) {
 	})
 }
 
+func TestEncodeDecodeAccountLinkValue(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		testEncodeDecode(t,
+			encodeDecodeTest{
+				value: AccountLinkValue{},
+				encoded: []byte{
+					// tag
+					0xd8, values.CBORTagAccountLinkValue, //nolint:staticcheck
+					// null
+					0xf6,
+				},
+				deepEquality: true,
+			},
+		)
+	})
+
+	testInvalid := func(t *testing.T, encoded []byte, expectedActualType string) {
+		decoder := CBORDecMode.NewByteStreamDecoder(encoded)
+		_, err := DecodeStorable(decoder, atree.SlabIDUndefined, nil, nil)
+		RequireError(t, err)
+
+		var decodingErr InvalidAccountLinkEncodingError
+		require.ErrorAs(t, err, &decodingErr)
+		assert.Equal(t, expectedActualType, decodingErr.ActualType)
+	}
+
+	t.Run("invalid: positive integer", func(t *testing.T) {
+
+		t.Parallel()
+
+		testInvalid(
+			t,
+			[]byte{
+				// tag
+				0xd8, values.CBORTagAccountLinkValue, //nolint:staticcheck
+				// positive integer 0
+				0x0,
+			},
+			"CBOR uint type",
+		)
+	})
+
+	t.Run("invalid: array", func(t *testing.T) {
+
+		t.Parallel()
+
+		testInvalid(
+			t,
+			[]byte{
+				// tag
+				0xd8, values.CBORTagAccountLinkValue, //nolint:staticcheck
+				// array, 0 items follow
+				0x80,
+			},
+			"CBOR array type",
+		)
+	})
+
+	t.Run("invalid: undefined", func(t *testing.T) {
+
+		t.Parallel()
+
+		testInvalid(
+			t,
+			[]byte{
+				// tag
+				0xd8, values.CBORTagAccountLinkValue, //nolint:staticcheck
+				// undefined
+				0xf7,
+			},
+			"CBOR other type",
+		)
+	})
+}
+
+func TestDecodeLinkValue(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("path link, round-trip", func(t *testing.T) {
+
+		t.Parallel()
+
+		value := PathLinkValue{
+			TargetPath: publicPathValue,
+			Type: &ReferenceStaticType{
+				Authorization:  UnauthorizedAccess,
+				ReferencedType: PrimitiveStaticTypeInt,
+			},
+		}
+
+		encoded, err := encodeStorable(value, CBOREncMode)
+		require.NoError(t, err)
+
+		decoder := CBORDecMode.NewByteStreamDecoder(encoded)
+		decoded, err := DecodePathLinkValue(decoder, nil)
+		require.NoError(t, err)
+
+		require.Equal(t, value, decoded)
+	})
+
+	t.Run("account link, round-trip", func(t *testing.T) {
+
+		t.Parallel()
+
+		value := AccountLinkValue{}
+
+		encoded, err := encodeStorable(value, CBOREncMode)
+		require.NoError(t, err)
+
+		decoder := CBORDecMode.NewByteStreamDecoder(encoded)
+		decoded, err := DecodeAccountLinkValue(decoder, nil)
+		require.NoError(t, err)
+
+		require.Equal(t, value, decoded)
+	})
+
+	t.Run("path link, invalid length", func(t *testing.T) {
+
+		t.Parallel()
+
+		encoded := []byte{
+			// tag
+			0xd8, values.CBORTagPathLinkValue, //nolint:staticcheck
+			// array, 1 item follows
+			0x81,
+			// positive integer 0
+			0x0,
+		}
+
+		decoder := CBORDecMode.NewByteStreamDecoder(encoded)
+		_, err := DecodePathLinkValue(decoder, nil)
+		RequireError(t, err)
+
+		require.ErrorContains(t, err, "expected [2]any, got [1]any")
+	})
+
+	t.Run("wrong tag", func(t *testing.T) {
+
+		t.Parallel()
+
+		encoded := []byte{
+			// tag
+			0xd8, values.CBORTagAccountLinkValue, //nolint:staticcheck
+			// null
+			0xf6,
+		}
+
+		decoder := CBORDecMode.NewByteStreamDecoder(encoded)
+		_, err := DecodePathLinkValue(decoder, nil)
+		RequireError(t, err)
+
+		encoded = []byte{
+			// positive integer 0
+			0x0,
+		}
+
+		decoder = CBORDecMode.NewByteStreamDecoder(encoded)
+		_, err = DecodeAccountLinkValue(decoder, nil)
+		RequireError(t, err)
+	})
+}
+
 func TestEncodeDecodeCapabilityValue(t *testing.T) {
 
 	t.Parallel()
@@ -4787,3 +4956,119 @@ func TestEncodeDecodeAccountCapabilityControllerValue(t *testing.T) {
 		)
 	})
 }
+
+func TestDecodeStrictUnknownCBORTag(t *testing.T) {
+
+	t.Parallel()
+
+	// A CBOR tag which is not used by Cadence or atree, followed by null
+	const unknownTag = 1000
+	unknownTagged := []byte{0xd9, 0x03, 0xe8, 0xf6}
+
+	strictConfig := DecodingConfig{
+		StrictDecoding: true,
+	}
+
+	decodeStorable := func(encoded []byte, config DecodingConfig) error {
+		decoder := CBORDecMode.NewByteStreamDecoder(encoded)
+		_, err := DecodeStorableWithConfig(decoder, atree.SlabIDUndefined, nil, nil, config)
+		return err
+	}
+
+	storableTests := map[string][]byte{
+		"storable": unknownTagged,
+		"nested storable": append(
+			[]byte{
+				// tag
+				0xd8, values.CBORTagSomeValue,
+			},
+			unknownTagged...,
+		),
+		"static type": append(
+			[]byte{
+				// tag
+				0xd8, values.CBORTagTypeValue,
+				// array, 1 item follows
+				0x81,
+			},
+			unknownTagged...,
+		),
+		"location": append(
+			append(
+				[]byte{
+					// tag
+					0xd8, values.CBORTagTypeValue,
+					// array, 1 item follows
+					0x81,
+					// tag
+					0xd8, values.CBORTagCompositeStaticType,
+					// array, 2 items follow
+					0x82,
+				},
+				unknownTagged...,
+			),
+			// UTF-8 string, length 1
+			0x61,
+			// S
+			0x53,
+		),
+	}
+
+	for name, encoded := range storableTests { //nolint:maprange
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Lenient (default)
+
+			err := decodeStorable(encoded, DecodingConfig{})
+			require.Error(t, err)
+			require.NotErrorAs(t, err, &UnknownCBORTagError{})
+
+			// Strict
+
+			err = decodeStorable(encoded, strictConfig)
+			require.Error(t, err)
+
+			var unknownTagErr UnknownCBORTagError
+			require.ErrorAs(t, err, &unknownTagErr)
+			require.Equal(t, uint64(unknownTag), unknownTagErr.Tag)
+		})
+	}
+
+	t.Run("type info", func(t *testing.T) {
+		t.Parallel()
+
+		// Lenient (default)
+
+		decoder := CBORDecMode.NewByteStreamDecoder(unknownTagged)
+		_, err := DecodeTypeInfo(decoder, nil)
+		require.Error(t, err)
+		require.NotErrorAs(t, err, &UnknownCBORTagError{})
+
+		// Strict
+
+		decoder = CBORDecMode.NewByteStreamDecoder(unknownTagged)
+		_, err = DecodeTypeInfoWithConfig(decoder, nil, strictConfig)
+		require.Error(t, err)
+
+		var unknownTagErr UnknownCBORTagError
+		require.ErrorAs(t, err, &unknownTagErr)
+		require.Equal(t, uint64(unknownTag), unknownTagErr.Tag)
+	})
+
+	t.Run("known tag", func(t *testing.T) {
+		t.Parallel()
+
+		encoded := []byte{
+			// tag
+			0xd8, values.CBORTagUInt8Value,
+			// positive integer 42
+			0x18, 0x2a,
+		}
+
+		decoder := CBORDecMode.NewByteStreamDecoder(encoded)
+		storable, err := DecodeStorableWithConfig(decoder, atree.SlabIDUndefined, nil, nil, strictConfig)
+		require.NoError(t, err)
+		require.Equal(t, NewUnmeteredUInt8Value(42), storable)
+	})
+}
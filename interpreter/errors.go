@@ -26,6 +26,7 @@ import (
 	"github.com/onflow/cadence/ast"
 	"github.com/onflow/cadence/common"
 	"github.com/onflow/cadence/errors"
+	"github.com/onflow/cadence/format"
 	"github.com/onflow/cadence/pretty"
 	"github.com/onflow/cadence/sema"
 )
@@ -585,6 +586,94 @@ func (e StringSliceIndicesError) Error() string {
 	)
 }
 
+// NegativeStringRepeatCountError is returned when String.repeat is called with a negative count.
+type NegativeStringRepeatCountError struct {
+	LocationRange
+	Count int
+}
+
+var _ errors.UserError = NegativeStringRepeatCountError{}
+
+func (NegativeStringRepeatCountError) IsUserError() {}
+
+func (e NegativeStringRepeatCountError) Error() string {
+	return fmt.Sprintf(
+		"string repeat count must not be negative, got %d",
+		e.Count,
+	)
+}
+
+// NonPositiveStringChunkSizeError is returned when String.chunked is called with a size that is not positive.
+type NonPositiveStringChunkSizeError struct {
+	LocationRange
+	Size int
+}
+
+var _ errors.UserError = NonPositiveStringChunkSizeError{}
+
+func (NonPositiveStringChunkSizeError) IsUserError() {}
+
+func (e NonPositiveStringChunkSizeError) Error() string {
+	return fmt.Sprintf(
+		"string chunk size must be positive, got %d",
+		e.Size,
+	)
+}
+
+// InvalidStringFormatError is returned when a format string passed to String.format is malformed,
+// e.g. when a placeholder is not closed, or a brace is not escaped.
+type InvalidStringFormatError struct {
+	LocationRange
+	Format string
+	Offset int
+}
+
+var _ errors.UserError = InvalidStringFormatError{}
+
+func (InvalidStringFormatError) IsUserError() {}
+
+func (e InvalidStringFormatError) Error() string {
+	return fmt.Sprintf(
+		"invalid format string %s: invalid placeholder or unescaped brace at offset %d",
+		format.String(e.Format),
+		e.Offset,
+	)
+}
+
+// StringFormatArgumentIndexOutOfBoundsError
+type StringFormatArgumentIndexOutOfBoundsError struct {
+	LocationRange
+	Index int
+	Count int
+}
+
+var _ errors.UserError = StringFormatArgumentIndexOutOfBoundsError{}
+
+func (StringFormatArgumentIndexOutOfBoundsError) IsUserError() {}
+
+func (e StringFormatArgumentIndexOutOfBoundsError) Error() string {
+	return fmt.Sprintf(
+		"format argument index out of bounds: %d, but argument count is %d",
+		e.Index,
+		e.Count,
+	)
+}
+
+// InvalidCodePointError is returned when a value is not a valid Unicode scalar value,
+// i.e. it is a surrogate or outside the Unicode code space
+type InvalidCodePointError struct {
+	LocationRange
+	CodePoint uint32
+}
+
+var _ errors.UserError = InvalidCodePointError{}
+
+func (InvalidCodePointError) IsUserError() {}
+
+func (e InvalidCodePointError) Error() string {
+	return fmt.Sprintf("invalid Unicode scalar value: U+%04X", e.CodePoint)
+}
+
 // EventEmissionUnavailableError
 type EventEmissionUnavailableError struct {
 	LocationRange
@@ -885,6 +974,21 @@ func (ContainerMutatedDuringIterationError) Error() string {
 	return "resource container modified during iteration"
 }
 
+// ConcurrentModificationError is reported when a storage map is modified during iteration,
+// i.e. between calls to the Next functions of an iterator of the storage map.
+type ConcurrentModificationError struct{}
+
+var _ errors.InternalError = ConcurrentModificationError{}
+
+func (ConcurrentModificationError) IsInternalError() {}
+
+func (ConcurrentModificationError) Error() string {
+	return fmt.Sprintf(
+		"%s storage map modified during iteration",
+		errors.InternalErrorMessagePrefix,
+	)
+}
+
 // InvalidHexByteError
 type InvalidHexByteError struct {
 	LocationRange
@@ -993,6 +1097,23 @@ func (RecursiveTransferError) Error() string {
 	return "recursive transfer of value"
 }
 
+// ValueDepthLimitExceededError
+type ValueDepthLimitExceededError struct {
+	LocationRange
+	Limit int
+}
+
+var _ errors.UserError = ValueDepthLimitExceededError{}
+
+func (ValueDepthLimitExceededError) IsUserError() {}
+
+func (e ValueDepthLimitExceededError) Error() string {
+	return fmt.Sprintf(
+		"value nesting depth limit exceeded: limit %d",
+		e.Limit,
+	)
+}
+
 func WrappedExternalError(err error) error {
 	switch err := err.(type) {
 	case
@@ -1191,3 +1312,95 @@ func (GetCapabilityError) IsUserError() {}
 func (e GetCapabilityError) Error() string {
 	return "cannot get capability"
 }
+
+// StorageMergeConflictError
+type StorageMergeConflictError struct {
+	Address common.Address
+	Domain  common.StorageDomain
+	Key     StorageMapKey
+}
+
+var _ errors.UserError = StorageMergeConflictError{}
+
+func (StorageMergeConflictError) IsUserError() {}
+
+func (e StorageMergeConflictError) Error() string {
+	return fmt.Sprintf(
+		"failed to merge storage: key %v in domain %s of account %s already exists",
+		e.Key.AtreeValue(),
+		e.Domain.Identifier(),
+		e.Address.HexWithPrefix(),
+	)
+}
+
+// DomainAlreadyExistsError
+type DomainAlreadyExistsError struct {
+	Address common.Address
+	Domain  common.StorageDomain
+}
+
+var _ errors.UserError = DomainAlreadyExistsError{}
+
+func (DomainAlreadyExistsError) IsUserError() {}
+
+func (e DomainAlreadyExistsError) Error() string {
+	return fmt.Sprintf(
+		"failed to write domain: domain %s of account %s already exists and is not empty",
+		e.Domain.Identifier(),
+		e.Address.HexWithPrefix(),
+	)
+}
+
+// DuplicateVirtualImportGlobalError is reported when merging virtual imports
+// which declare a global with the same name.
+type DuplicateVirtualImportGlobalError struct {
+	Name string
+}
+
+var _ errors.InternalError = DuplicateVirtualImportGlobalError{}
+
+func (DuplicateVirtualImportGlobalError) IsInternalError() {}
+
+func (e DuplicateVirtualImportGlobalError) Error() string {
+	return fmt.Sprintf(
+		"%s failed to merge virtual imports: duplicate global %q",
+		errors.InternalErrorMessagePrefix,
+		e.Name,
+	)
+}
+
+// DuplicateVirtualImportTypeCodeError is reported when merging virtual imports
+// which declare type codes for the same type.
+type DuplicateVirtualImportTypeCodeError struct {
+	TypeID sema.TypeID
+}
+
+var _ errors.InternalError = DuplicateVirtualImportTypeCodeError{}
+
+func (DuplicateVirtualImportTypeCodeError) IsInternalError() {}
+
+func (e DuplicateVirtualImportTypeCodeError) Error() string {
+	return fmt.Sprintf(
+		"%s failed to merge virtual imports: duplicate type code for type %s",
+		errors.InternalErrorMessagePrefix,
+		e.TypeID,
+	)
+}
+
+// ResourceCloneError is reported when a resource-kinded value is deep cloned,
+// as cloning a resource would duplicate it.
+type ResourceCloneError struct {
+	Type StaticType
+}
+
+var _ errors.InternalError = ResourceCloneError{}
+
+func (ResourceCloneError) IsInternalError() {}
+
+func (e ResourceCloneError) Error() string {
+	return fmt.Sprintf(
+		"%s cannot clone resource of type %s",
+		errors.InternalErrorMessagePrefix,
+		e.Type.String(),
+	)
+}
@@ -19,6 +19,8 @@
 package interpreter
 
 import (
+	"slices"
+
 	"github.com/onflow/cadence/sema"
 )
 
@@ -26,6 +28,12 @@ import (
 
 type Import interface {
 	isImport()
+	// AsVirtual returns the import as a virtual import,
+	// and true if the import is a VirtualImport.
+	AsVirtual() (VirtualImport, bool)
+	// AsInterpreter returns the interpreter of the import,
+	// and true if the import is an InterpreterImport.
+	AsInterpreter() (*Interpreter, bool)
 }
 
 // VirtualImport
@@ -39,10 +47,156 @@ type VirtualImport struct {
 	TypeCodes   TypeCodes
 	Elaboration *sema.Elaboration
 	Globals     []VirtualImportGlobal
+	// globalIndices maps the names of the globals to their index in Globals.
+	// It is built by NewVirtualImport, and is never modified afterwards,
+	// so copies of the virtual import can share it.
+	globalIndices map[string]int
+}
+
+// NewVirtualImport returns a new virtual import with the given type codes, elaboration, and globals.
+// The globals are indexed by name, so FindGlobal does not need to scan them.
+//
+// NOTE: The index is immutable, so the globals must not be modified afterwards.
+func NewVirtualImport(
+	typeCodes TypeCodes,
+	elaboration *sema.Elaboration,
+	globals []VirtualImportGlobal,
+) VirtualImport {
+	globalIndices := make(map[string]int, len(globals))
+
+	for index, global := range globals {
+		// First global with the name wins
+		if _, ok := globalIndices[global.Name]; ok {
+			continue
+		}
+		globalIndices[global.Name] = index
+	}
+
+	return VirtualImport{
+		TypeCodes:     typeCodes,
+		Elaboration:   elaboration,
+		Globals:       globals,
+		globalIndices: globalIndices,
+	}
 }
 
 func (VirtualImport) isImport() {}
 
+func (i VirtualImport) AsVirtual() (VirtualImport, bool) {
+	return i, true
+}
+
+func (VirtualImport) AsInterpreter() (*Interpreter, bool) {
+	return nil, false
+}
+
+// FindGlobal returns the global with the given name, if any.
+// If multiple globals have the same name, the first one is returned.
+//
+// The lookup uses the index built by NewVirtualImport.
+// If the virtual import was not created using NewVirtualImport, the globals are scanned.
+// FindGlobal does not modify the virtual import, so it is safe for concurrent use.
+func (i VirtualImport) FindGlobal(name string) (VirtualImportGlobal, bool) {
+	if i.globalIndices == nil {
+		for _, global := range i.Globals {
+			if global.Name == name {
+				return global, true
+			}
+		}
+
+		return VirtualImportGlobal{}, false
+	}
+
+	index, ok := i.globalIndices[name]
+	if !ok {
+		return VirtualImportGlobal{}, false
+	}
+
+	return i.Globals[index], true
+}
+
+// MergeVirtualImports merges the given virtual imports into a single virtual import.
+// The globals are concatenated in the order of the given imports,
+// and the elaborations and type codes are merged.
+// Returns a DuplicateVirtualImportGlobalError if multiple imports declare a global with the same name,
+// and a DuplicateVirtualImportTypeCodeError if multiple imports declare type codes for the same type.
+func MergeVirtualImports(imports ...VirtualImport) (VirtualImport, error) {
+
+	var globals []VirtualImportGlobal
+	globalNames := map[string]struct{}{}
+
+	typeCodes := TypeCodes{
+		CompositeCodes: map[sema.TypeID]CompositeTypeCode{},
+		InterfaceCodes: map[sema.TypeID]WrapperCode{},
+	}
+
+	var elaboration *sema.Elaboration
+
+	for _, virtualImport := range imports {
+
+		// Globals
+
+		for _, global := range virtualImport.Globals {
+			if _, ok := globalNames[global.Name]; ok {
+				return VirtualImport{}, DuplicateVirtualImportGlobalError{
+					Name: global.Name,
+				}
+			}
+			globalNames[global.Name] = struct{}{}
+
+			globals = append(globals, global)
+		}
+
+		// Type codes
+
+		for _, typeID := range sortedTypeIDs(virtualImport.TypeCodes.CompositeCodes) {
+			if _, ok := typeCodes.CompositeCodes[typeID]; ok {
+				return VirtualImport{}, DuplicateVirtualImportTypeCodeError{
+					TypeID: typeID,
+				}
+			}
+			typeCodes.CompositeCodes[typeID] = virtualImport.TypeCodes.CompositeCodes[typeID]
+		}
+
+		for _, typeID := range sortedTypeIDs(virtualImport.TypeCodes.InterfaceCodes) {
+			if _, ok := typeCodes.InterfaceCodes[typeID]; ok {
+				return VirtualImport{}, DuplicateVirtualImportTypeCodeError{
+					TypeID: typeID,
+				}
+			}
+			typeCodes.InterfaceCodes[typeID] = virtualImport.TypeCodes.InterfaceCodes[typeID]
+		}
+
+		// Elaboration
+
+		if virtualImport.Elaboration != nil {
+			if elaboration == nil {
+				elaboration = sema.NewElaboration(nil)
+			}
+			elaboration.MergeTypes(virtualImport.Elaboration)
+		}
+	}
+
+	return NewVirtualImport(
+		typeCodes,
+		elaboration,
+		globals,
+	), nil
+}
+
+func sortedTypeIDs[T any](codes map[sema.TypeID]T) []sema.TypeID {
+	typeIDs := make([]sema.TypeID, 0, len(codes))
+
+	// NOTE: map range is safe, as type IDs are sorted below
+	for typeID := range codes { //nolint:maprange
+		typeIDs = append(typeIDs, typeID)
+	}
+
+	slices.Sort(typeIDs)
+
+	return typeIDs
+}
+
 // InterpreterImport
 
 type InterpreterImport struct {
@@ -50,3 +204,11 @@ type InterpreterImport struct {
 }
 
 func (InterpreterImport) isImport() {}
+
+func (InterpreterImport) AsVirtual() (VirtualImport, bool) {
+	return VirtualImport{}, false
+}
+
+func (i InterpreterImport) AsInterpreter() (*Interpreter, bool) {
+	return i.Interpreter, true
+}
@@ -19,6 +19,7 @@
 package interpreter_test
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -401,3 +402,353 @@ func TestInterpretResourceConstructionThroughIndirectImport(t *testing.T) {
 		resourceConstructionError.CompositeType,
 	)
 }
+
+func TestMergeVirtualImports(t *testing.T) {
+
+	t.Parallel()
+
+	newCompositeType := func(identifier string) *sema.CompositeType {
+		return &sema.CompositeType{
+			Location:   common.IdentifierLocation(identifier),
+			Identifier: identifier,
+			Kind:       common.CompositeKindContract,
+		}
+	}
+
+	fooType := newCompositeType("Foo")
+	barType := newCompositeType("Bar")
+
+	newVirtualImport := func(compositeType *sema.CompositeType, globalNames ...string) interpreter.VirtualImport {
+		elaboration := sema.NewElaboration(nil)
+		elaboration.SetCompositeType(compositeType.ID(), compositeType)
+
+		var globals []interpreter.VirtualImportGlobal
+		for i, name := range globalNames {
+			globals = append(
+				globals,
+				interpreter.VirtualImportGlobal{
+					Name:  name,
+					Value: interpreter.NewUnmeteredIntValueFromInt64(int64(i)),
+				},
+			)
+		}
+
+		return interpreter.VirtualImport{
+			TypeCodes: interpreter.TypeCodes{
+				CompositeCodes: map[sema.TypeID]interpreter.CompositeTypeCode{
+					compositeType.ID(): {},
+				},
+				InterfaceCodes: map[sema.TypeID]interpreter.WrapperCode{},
+			},
+			Elaboration: elaboration,
+			Globals:     globals,
+		}
+	}
+
+	t.Run("disjoint", func(t *testing.T) {
+
+		t.Parallel()
+
+		fooImport := newVirtualImport(fooType, "foo1", "foo2")
+		barImport := newVirtualImport(barType, "bar")
+
+		merged, err := interpreter.MergeVirtualImports(fooImport, barImport)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			[]interpreter.VirtualImportGlobal{
+				fooImport.Globals[0],
+				fooImport.Globals[1],
+				barImport.Globals[0],
+			},
+			merged.Globals,
+		)
+
+		assert.Equal(t,
+			map[sema.TypeID]interpreter.CompositeTypeCode{
+				fooType.ID(): {},
+				barType.ID(): {},
+			},
+			merged.TypeCodes.CompositeCodes,
+		)
+		assert.Empty(t, merged.TypeCodes.InterfaceCodes)
+
+		require.NotNil(t, merged.Elaboration)
+		assert.Same(t, fooType, merged.Elaboration.CompositeType(fooType.ID()))
+		assert.Same(t, barType, merged.Elaboration.CompositeType(barType.ID()))
+
+		// The given imports are unchanged
+
+		assert.Len(t, fooImport.TypeCodes.CompositeCodes, 1)
+		assert.Nil(t, fooImport.Elaboration.CompositeType(barType.ID()))
+	})
+
+	t.Run("no imports", func(t *testing.T) {
+
+		t.Parallel()
+
+		merged, err := interpreter.MergeVirtualImports()
+		require.NoError(t, err)
+
+		assert.Empty(t, merged.Globals)
+		assert.Nil(t, merged.Elaboration)
+	})
+
+	t.Run("without elaborations and type codes", func(t *testing.T) {
+
+		t.Parallel()
+
+		merged, err := interpreter.MergeVirtualImports(
+			interpreter.VirtualImport{
+				Globals: []interpreter.VirtualImportGlobal{
+					{
+						Name:  "foo",
+						Value: interpreter.TrueValue,
+					},
+				},
+			},
+			interpreter.VirtualImport{},
+		)
+		require.NoError(t, err)
+
+		assert.Len(t, merged.Globals, 1)
+		assert.Nil(t, merged.Elaboration)
+		assert.Empty(t, merged.TypeCodes.CompositeCodes)
+	})
+
+	t.Run("conflicting globals", func(t *testing.T) {
+
+		t.Parallel()
+
+		fooImport := newVirtualImport(fooType, "foo", "baz")
+		barImport := newVirtualImport(barType, "bar", "baz")
+
+		_, err := interpreter.MergeVirtualImports(fooImport, barImport)
+		RequireError(t, err)
+
+		var duplicateGlobalError interpreter.DuplicateVirtualImportGlobalError
+		require.ErrorAs(t, err, &duplicateGlobalError)
+
+		assert.Equal(t, "baz", duplicateGlobalError.Name)
+	})
+
+	t.Run("conflicting globals in same import", func(t *testing.T) {
+
+		t.Parallel()
+
+		fooImport := newVirtualImport(fooType, "foo", "foo")
+
+		_, err := interpreter.MergeVirtualImports(fooImport)
+		RequireError(t, err)
+
+		var duplicateGlobalError interpreter.DuplicateVirtualImportGlobalError
+		require.ErrorAs(t, err, &duplicateGlobalError)
+
+		assert.Equal(t, "foo", duplicateGlobalError.Name)
+	})
+
+	t.Run("conflicting type codes", func(t *testing.T) {
+
+		t.Parallel()
+
+		fooImport := newVirtualImport(fooType, "foo")
+		otherFooImport := newVirtualImport(fooType, "otherFoo")
+
+		_, err := interpreter.MergeVirtualImports(fooImport, otherFooImport)
+		RequireError(t, err)
+
+		var duplicateTypeCodeError interpreter.DuplicateVirtualImportTypeCodeError
+		require.ErrorAs(t, err, &duplicateTypeCodeError)
+
+		assert.Equal(t, fooType.ID(), duplicateTypeCodeError.TypeID)
+	})
+}
+
+func TestVirtualImportFindGlobal(t *testing.T) {
+
+	t.Parallel()
+
+	globals := []interpreter.VirtualImportGlobal{
+		{
+			Name:  "foo",
+			Value: interpreter.NewUnmeteredIntValueFromInt64(1),
+		},
+		{
+			Name:  "bar",
+			Value: interpreter.NewUnmeteredIntValueFromInt64(2),
+		},
+		{
+			Name:  "foo",
+			Value: interpreter.NewUnmeteredIntValueFromInt64(3),
+		},
+	}
+
+	test := func(name string, virtualImport interpreter.VirtualImport) {
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			t.Run("present", func(t *testing.T) {
+
+				t.Parallel()
+
+				global, ok := virtualImport.FindGlobal("bar")
+				require.True(t, ok)
+				assert.Equal(t, globals[1], global)
+			})
+
+			t.Run("absent", func(t *testing.T) {
+
+				t.Parallel()
+
+				global, ok := virtualImport.FindGlobal("baz")
+				require.False(t, ok)
+				assert.Equal(t, interpreter.VirtualImportGlobal{}, global)
+			})
+
+			t.Run("duplicate", func(t *testing.T) {
+
+				t.Parallel()
+
+				// First global wins
+				global, ok := virtualImport.FindGlobal("foo")
+				require.True(t, ok)
+				assert.Equal(t, globals[0], global)
+
+				// Repeated lookups are identical
+				global, ok = virtualImport.FindGlobal("foo")
+				require.True(t, ok)
+				assert.Equal(t, globals[0], global)
+			})
+
+			t.Run("import", func(t *testing.T) {
+
+				t.Parallel()
+
+				var imported interpreter.Import = virtualImport
+
+				importedVirtualImport, ok := imported.AsVirtual()
+				require.True(t, ok)
+
+				global, ok := importedVirtualImport.FindGlobal("bar")
+				require.True(t, ok)
+				assert.Equal(t, globals[1], global)
+			})
+
+			t.Run("concurrent", func(t *testing.T) {
+
+				t.Parallel()
+
+				var wg sync.WaitGroup
+
+				for range 4 {
+					wg.Add(1)
+					go func() {
+						defer wg.Done()
+
+						global, ok := virtualImport.FindGlobal("foo")
+						assert.True(t, ok)
+						assert.Equal(t, globals[0], global)
+					}()
+				}
+
+				wg.Wait()
+			})
+		})
+	}
+
+	test(
+		"indexed",
+		interpreter.NewVirtualImport(interpreter.TypeCodes{}, nil, globals),
+	)
+
+	test(
+		"not indexed",
+		interpreter.VirtualImport{
+			Globals: globals,
+		},
+	)
+
+	t.Run("empty", func(t *testing.T) {
+
+		t.Parallel()
+
+		var virtualImport interpreter.VirtualImport
+
+		_, ok := virtualImport.FindGlobal("foo")
+		require.False(t, ok)
+
+		virtualImport = interpreter.NewVirtualImport(interpreter.TypeCodes{}, nil, nil)
+
+		_, ok = virtualImport.FindGlobal("foo")
+		require.False(t, ok)
+	})
+
+	t.Run("merged", func(t *testing.T) {
+
+		t.Parallel()
+
+		merged, err := interpreter.MergeVirtualImports(
+			interpreter.VirtualImport{
+				Globals: globals[:1],
+			},
+			interpreter.VirtualImport{
+				Globals: globals[1:2],
+			},
+		)
+		require.NoError(t, err)
+
+		global, ok := merged.FindGlobal("bar")
+		require.True(t, ok)
+		assert.Equal(t, globals[1], global)
+	})
+}
+
+func TestImportKind(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("virtual import", func(t *testing.T) {
+
+		t.Parallel()
+
+		virtualImport := interpreter.VirtualImport{
+			Globals: []interpreter.VirtualImportGlobal{
+				{
+					Name:  "foo",
+					Value: interpreter.TrueValue,
+				},
+			},
+		}
+
+		var imported interpreter.Import = virtualImport
+
+		actualVirtualImport, ok := imported.AsVirtual()
+		require.True(t, ok)
+		assert.Equal(t, virtualImport, actualVirtualImport)
+
+		actualInterpreter, ok := imported.AsInterpreter()
+		require.False(t, ok)
+		assert.Nil(t, actualInterpreter)
+	})
+
+	t.Run("interpreter import", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := NewTestInterpreter(t)
+
+		var imported interpreter.Import = interpreter.InterpreterImport{
+			Interpreter: inter,
+		}
+
+		actualInterpreter, ok := imported.AsInterpreter()
+		require.True(t, ok)
+		assert.Same(t, inter, actualInterpreter)
+
+		actualVirtualImport, ok := imported.AsVirtual()
+		require.False(t, ok)
+		assert.Equal(t, interpreter.VirtualImport{}, actualVirtualImport)
+	})
+}
@@ -18,6 +18,10 @@
 
 package interpreter
 
+import (
+	"github.com/onflow/cadence/common"
+)
+
 type valueInspector func(Value) bool
 
 func (f valueInspector) WalkValue(_ *Interpreter, value Value) ValueWalker {
@@ -36,3 +40,72 @@ func InspectValue(interpreter *Interpreter, value Value, f func(Value) bool, loc
 		locationRange,
 	)
 }
+
+// AccountValueHistogram returns the number of values stored in the given account, by category of their static type,
+// including nested values, e.g. the elements of arrays, the keys and values of dictionaries,
+// and the fields of composites.
+//
+// The categories are "Array", "Dictionary", "Composite", "Optional", "Capability",
+// "InclusiveRange", "Primitive" (e.g. numbers, strings, paths), and "Other".
+func AccountValueHistogram(inter *Interpreter, address common.Address) map[string]int {
+	histogram := map[string]int{}
+
+	storage := inter.Storage()
+
+	for _, domain := range common.AllStorageDomains {
+		const createIfNotExists = false
+		domainStorageMap := storage.GetDomainStorageMap(inter, address, domain, createIfNotExists)
+		if domainStorageMap == nil {
+			continue
+		}
+
+		iterator := domainStorageMap.Iterator(inter)
+		for {
+			value := iterator.NextValue()
+			if value == nil {
+				break
+			}
+
+			InspectValue(
+				inter,
+				value,
+				func(value Value) bool {
+					// Ignore the end of a value's children
+					if value == nil {
+						return true
+					}
+
+					category := staticTypeCategory(value.StaticType(inter))
+					histogram[category]++
+
+					return true
+				},
+				EmptyLocationRange,
+			)
+		}
+	}
+
+	return histogram
+}
+
+// staticTypeCategory returns the category of the given static type, see AccountValueHistogram.
+func staticTypeCategory(staticType StaticType) string {
+	switch staticType.(type) {
+	case ArrayStaticType:
+		return "Array"
+	case *DictionaryStaticType:
+		return "Dictionary"
+	case *CompositeStaticType:
+		return "Composite"
+	case *OptionalStaticType:
+		return "Optional"
+	case *CapabilityStaticType:
+		return "Capability"
+	case *InclusiveRangeStaticType:
+		return "InclusiveRange"
+	case PrimitiveStaticType:
+		return "Primitive"
+	default:
+		return "Other"
+	}
+}
@@ -21,6 +21,8 @@ package interpreter_test
 import (
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/onflow/cadence/common"
 	. "github.com/onflow/cadence/interpreter"
 	. "github.com/onflow/cadence/test_utils/interpreter_utils"
@@ -144,3 +146,112 @@ func TestInspectValue(t *testing.T) {
 		)
 	})
 }
+
+func TestAccountValueHistogram(t *testing.T) {
+
+	t.Parallel()
+
+	inter := newTestInterpreter(t)
+
+	address := common.MustBytesToAddress([]byte{0x1})
+	otherAddress := common.MustBytesToAddress([]byte{0x2})
+
+	writeValue := func(address common.Address, domain common.StorageDomain, key string, value Value) {
+		const createIfNotExists = true
+		domainStorageMap := inter.Storage().GetDomainStorageMap(inter, address, domain, createIfNotExists)
+		domainStorageMap.WriteValue(inter, StringStorageMapKey(key), value)
+	}
+
+	// Array with three primitive elements:
+	// 1 array, 3 primitives
+
+	writeValue(
+		address,
+		common.StorageDomainPathStorage,
+		"array",
+		NewArrayValue(
+			inter,
+			EmptyLocationRange,
+			&VariableSizedStaticType{
+				Type: PrimitiveStaticTypeInt,
+			},
+			address,
+			NewUnmeteredIntValueFromInt64(1),
+			NewUnmeteredIntValueFromInt64(2),
+			NewUnmeteredIntValueFromInt64(3),
+		),
+	)
+
+	// Dictionary with an optional value:
+	// 1 dictionary, 1 optional, 2 primitives (key and value)
+
+	writeValue(
+		address,
+		common.StorageDomainPathStorage,
+		"dictionary",
+		NewDictionaryValueWithAddress(
+			inter,
+			EmptyLocationRange,
+			&DictionaryStaticType{
+				KeyType: PrimitiveStaticTypeString,
+				ValueType: &OptionalStaticType{
+					Type: PrimitiveStaticTypeInt,
+				},
+			},
+			address,
+			NewUnmeteredStringValue("a"),
+			NewUnmeteredSomeValueNonCopying(NewUnmeteredIntValueFromInt64(1)),
+		),
+	)
+
+	// Composite in another domain, with a nested array field:
+	// 1 composite, 1 array, 1 primitive
+
+	compositeValue := newTestCompositeValue(inter, address)
+	compositeValue.SetMember(
+		inter,
+		EmptyLocationRange,
+		"values",
+		NewArrayValue(
+			inter,
+			EmptyLocationRange,
+			&VariableSizedStaticType{
+				Type: PrimitiveStaticTypeString,
+			},
+			address,
+			NewUnmeteredStringValue("b"),
+		),
+	)
+
+	writeValue(address, common.StorageDomainPathPublic, "composite", compositeValue)
+
+	// Primitive: 1 primitive
+
+	writeValue(address, common.StorageDomainPathPublic, "primitive", NewUnmeteredStringValue("c"))
+
+	// Values of other accounts are not counted
+
+	writeValue(otherAddress, common.StorageDomainPathStorage, "primitive", NewUnmeteredStringValue("d"))
+
+	require.Equal(t,
+		map[string]int{
+			"Array":      2,
+			"Dictionary": 1,
+			"Composite":  1,
+			"Optional":   1,
+			"Primitive":  7,
+		},
+		AccountValueHistogram(inter, address),
+	)
+
+	require.Equal(t,
+		map[string]int{
+			"Primitive": 1,
+		},
+		AccountValueHistogram(inter, otherAddress),
+	)
+
+	require.Empty(t,
+		AccountValueHistogram(inter, common.MustBytesToAddress([]byte{0x3})),
+	)
+}
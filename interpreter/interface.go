@@ -128,6 +128,12 @@ type ValueTransferContext interface {
 		valueID atree.ValueID,
 		locationRange LocationRange,
 	)
+
+	// EnterNestedValue must be called when a transfer or deep removal of a container value starts,
+	// and panics with a ValueDepthLimitExceededError if the maximum nesting depth is exceeded.
+	EnterNestedValue(locationRange LocationRange)
+	// ExitNestedValue must be called when a transfer or deep removal of a container value ends.
+	ExitNestedValue()
 }
 
 var _ ValueTransferContext = &Interpreter{}
@@ -548,6 +554,14 @@ func (ctx NoOpStringContext) EnforceNotResourceDestruction(_ atree.ValueID, _ Lo
 	panic(errors.NewUnreachableError())
 }
 
+func (ctx NoOpStringContext) EnterNestedValue(_ LocationRange) {
+	panic(errors.NewUnreachableError())
+}
+
+func (ctx NoOpStringContext) ExitNestedValue() {
+	panic(errors.NewUnreachableError())
+}
+
 func (ctx NoOpStringContext) ReadStored(_ common.Address, _ common.StorageDomain, _ StorageMapKey) Value {
 	panic(errors.NewUnreachableError())
 }
@@ -2845,6 +2845,37 @@ func bigIntValueParser(convert func(*big.Int) (Value, bool)) stringValueParser {
 	}
 }
 
+var intValueParser = bigIntValueParser(func(b *big.Int) (Value, bool) {
+	return NewUnmeteredIntValueFromBigInt(b), true
+})
+
+var uintValueParser = bigIntValueParser(func(b *big.Int) (Value, bool) {
+	if b.Sign() < 0 {
+		return nil, false
+	}
+	return NewUnmeteredUIntValueFromBigInt(b), true
+})
+
+func fix64ValueParser(memoryGauge common.MemoryGauge, input string) OptionalValue {
+	n, err := fixedpoint.ParseFix64(input)
+	if err != nil {
+		return NilOptionalValue
+	}
+
+	val := NewFix64Value(memoryGauge, n.Int64)
+	return NewSomeValueNonCopying(memoryGauge, val)
+}
+
+func ufix64ValueParser(memoryGauge common.MemoryGauge, input string) OptionalValue {
+	n, err := fixedpoint.ParseUFix64(input)
+	if err != nil {
+		return NilOptionalValue
+	}
+
+	val := NewUFix64Value(memoryGauge, n.Uint64)
+	return NewSomeValueNonCopying(memoryGauge, val)
+}
+
 // check if val is in the inclusive interval [low, high]
 func inRange(val *big.Int, low *big.Int, high *big.Int) bool {
 	return -1 < val.Cmp(low) && val.Cmp(high) < 1
@@ -2882,9 +2913,7 @@ var fromStringFunctionValues = func() map[string]fromStringFunctionValue {
 			}
 			return
 		})),
-		newFromStringFunction(sema.IntType, bigIntValueParser(func(b *big.Int) (Value, bool) {
-			return NewUnmeteredIntValueFromBigInt(b), true
-		})),
+		newFromStringFunction(sema.IntType, intValueParser),
 
 		// unsigned int values from 8 bit -> infinity
 		newFromStringFunction(sema.UInt8Type, unsignedIntValueParser(8, NewUInt8Value, u64_8)),
@@ -2903,9 +2932,7 @@ var fromStringFunctionValues = func() map[string]fromStringFunctionValue {
 			}
 			return
 		})),
-		newFromStringFunction(sema.UIntType, bigIntValueParser(func(b *big.Int) (Value, bool) {
-			return NewUnmeteredUIntValueFromBigInt(b), true
-		})),
+		newFromStringFunction(sema.UIntType, uintValueParser),
 
 		// machine-sized word types
 		newFromStringFunction(sema.Word8Type, unsignedIntValueParser(8, NewWord8Value, u64_8)),
@@ -2926,24 +2953,8 @@ var fromStringFunctionValues = func() map[string]fromStringFunctionValue {
 		})),
 
 		// fixed-points
-		newFromStringFunction(sema.Fix64Type, func(memoryGauge common.MemoryGauge, input string) OptionalValue {
-			n, err := fixedpoint.ParseFix64(input)
-			if err != nil {
-				return NilOptionalValue
-			}
-
-			val := NewFix64Value(memoryGauge, n.Int64)
-			return NewSomeValueNonCopying(memoryGauge, val)
-
-		}),
-		newFromStringFunction(sema.UFix64Type, func(memoryGauge common.MemoryGauge, input string) OptionalValue {
-			n, err := fixedpoint.ParseUFix64(input)
-			if err != nil {
-				return NilOptionalValue
-			}
-			val := NewUFix64Value(memoryGauge, n.Uint64)
-			return NewSomeValueNonCopying(memoryGauge, val)
-		}),
+		newFromStringFunction(sema.Fix64Type, fix64ValueParser),
+		newFromStringFunction(sema.UFix64Type, ufix64ValueParser),
 	}
 
 	values := make(map[string]fromStringFunctionValue, len(declarations))
@@ -5886,6 +5897,26 @@ func (interpreter *Interpreter) OnResourceOwnerChange(resource *CompositeValue,
 	onResourceOwnerChange(interpreter, resource, oldOwner, newOwner)
 }
 
+func (interpreter *Interpreter) EnterNestedValue(locationRange LocationRange) {
+	maxDepth := interpreter.SharedState.Config.MaxValueDepth
+	if maxDepth == 0 {
+		maxDepth = DefaultMaxValueDepth
+	}
+
+	if interpreter.SharedState.valueDepth >= maxDepth {
+		panic(ValueDepthLimitExceededError{
+			Limit:         maxDepth,
+			LocationRange: locationRange,
+		})
+	}
+
+	interpreter.SharedState.valueDepth++
+}
+
+func (interpreter *Interpreter) ExitNestedValue() {
+	interpreter.SharedState.valueDepth--
+}
+
 func (interpreter *Interpreter) TracingEnabled() bool {
 	return interpreter.SharedState.Config.TracingEnabled
 }
@@ -0,0 +1,281 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/errors"
+)
+
+// TODO: remove once migrated
+
+// LinkCapabilityIssuer issues a capability which replaces a deprecated link.
+//
+// For path links, the target path is the storage path the link (transitively) targets.
+// For account links, the target path is EmptyPathValue,
+// and an account capability must be issued.
+type LinkCapabilityIssuer func(borrowType *ReferenceStaticType, targetPath PathValue) CapabilityValue
+
+// linkMigrationAccountBorrowType is the borrow type of capabilities replacing account links
+var linkMigrationAccountBorrowType = NewReferenceStaticType(
+	nil,
+	FullyEntitledAccountAccess,
+	PrimitiveStaticTypeAccount,
+)
+
+// MigrateLink returns a capability which is equivalent to the given link,
+// stored in the account with the given address.
+//
+// Path links which target other links are followed until a storage path is reached.
+// Links to account links result in account capabilities.
+//
+// Returns nil if the link cannot be migrated, e.g. if the link's borrow type is not a reference type,
+// or if the link is dangling or cyclic.
+func MigrateLink(
+	inter *Interpreter,
+	address common.Address,
+	link LinkValue,
+	issue LinkCapabilityIssuer,
+) CapabilityValue {
+
+	switch link := link.(type) {
+	case AccountLinkValue:
+		return issue(linkMigrationAccountBorrowType, EmptyPathValue)
+
+	case PathLinkValue:
+		borrowType, ok := link.Type.(*ReferenceStaticType)
+		if !ok {
+			return nil
+		}
+
+		targetPath, isAccountLink, ok := resolvePathLink(inter, address, link)
+		if !ok {
+			// Dangling or cyclic link
+			return nil
+		}
+
+		if isAccountLink {
+			return issue(borrowType, EmptyPathValue)
+		}
+
+		return issue(borrowType, targetPath)
+
+	default:
+		panic(errors.NewUnreachableError())
+	}
+}
+
+// resolvePathLink follows the given path link, and the links it targets,
+// until a storage path or an account link is reached.
+//
+// Returns the storage path if a storage path is reached,
+// or isAccountLink true if an account link is reached.
+// Returns ok false if the link is dangling or cyclic.
+// NOTE: Whether a value is stored at the returned storage path is not checked.
+func resolvePathLink(
+	inter *Interpreter,
+	address common.Address,
+	link PathLinkValue,
+) (
+	targetPath PathValue,
+	isAccountLink bool,
+	ok bool,
+) {
+	targetPath = link.TargetPath
+	seenPaths := map[PathValue]struct{}{}
+
+	for targetPath.Domain != common.PathDomainStorage {
+
+		if _, ok := seenPaths[targetPath]; ok {
+			// Cyclic link
+			return EmptyPathValue, false, false
+		}
+		seenPaths[targetPath] = struct{}{}
+
+		if targetPath.Domain == common.PathDomainUnknown {
+			return EmptyPathValue, false, false
+		}
+
+		target := inter.ReadStored(
+			address,
+			targetPath.Domain.StorageDomain(),
+			StringStorageMapKey(targetPath.Identifier),
+		)
+
+		switch target := target.(type) {
+		case PathLinkValue:
+			targetPath = target.TargetPath
+
+		case AccountLinkValue:
+			return EmptyPathValue, true, true
+
+		default:
+			// Dangling link
+			return EmptyPathValue, false, false
+		}
+	}
+
+	return targetPath, false, true
+}
+
+// MigrateLinks replaces the links stored in the private and public domains
+// of the account with the given address with equivalent capabilities,
+// issued by the given issuer.
+//
+// Links which cannot be migrated (see MigrateLink) are left unchanged.
+// Returns the number of migrated links.
+func MigrateLinks(
+	inter *Interpreter,
+	address common.Address,
+	issue LinkCapabilityIssuer,
+) (migrated int) {
+
+	type storedLink struct {
+		domain     common.StorageDomain
+		key        StorageMapKey
+		link       LinkValue
+		capability CapabilityValue
+	}
+
+	var links []*storedLink
+
+	for _, domain := range []common.StorageDomain{
+		common.StorageDomainPathPrivate,
+		common.StorageDomainPathPublic,
+	} {
+		storageMap := inter.Storage().GetDomainStorageMap(inter, address, domain, false)
+		if storageMap == nil {
+			continue
+		}
+
+		iterator := storageMap.Iterator(inter)
+		for key, value := iterator.Next(); key != nil; key, value = iterator.Next() {
+			link, ok := value.(LinkValue)
+			if !ok {
+				continue
+			}
+
+			storageMapKey, err := convertAtreeValueToStorageMapKey(key)
+			if err != nil {
+				panic(err)
+			}
+
+			links = append(
+				links,
+				&storedLink{
+					domain: domain,
+					key:    storageMapKey,
+					link:   link,
+				},
+			)
+		}
+	}
+
+	// Migrate all links first, before replacing any of them,
+	// so that links targeting other links can still be resolved
+
+	for _, storedLink := range links {
+		storedLink.capability = MigrateLink(inter, address, storedLink.link, issue)
+	}
+
+	for _, storedLink := range links {
+		if storedLink.capability == nil {
+			continue
+		}
+
+		inter.WriteStored(address, storedLink.domain, storedLink.key, storedLink.capability)
+		migrated++
+	}
+
+	return migrated
+}
+
+// DanglingLink is a path link which does not resolve to a stored value.
+type DanglingLink struct {
+	// Path is the private or public path at which the link is stored
+	Path PathValue
+	Link PathLinkValue
+}
+
+// FindDanglingLinks returns the path links stored in the private and public domains
+// of the account with the given address, which do not resolve to a stored value.
+//
+// Path links which target other links are followed until a storage path is reached,
+// so a link is also dangling if a link it targets is dangling, or if it is part of a cycle.
+// Account links are always considered resolvable.
+func FindDanglingLinks(
+	inter *Interpreter,
+	address common.Address,
+) (danglingLinks []DanglingLink) {
+
+	for _, domain := range []common.PathDomain{
+		common.PathDomainPrivate,
+		common.PathDomainPublic,
+	} {
+		storageMap := inter.Storage().GetDomainStorageMap(inter, address, domain.StorageDomain(), false)
+		if storageMap == nil {
+			continue
+		}
+
+		iterator := storageMap.Iterator(inter)
+		for key, value := iterator.Next(); key != nil; key, value = iterator.Next() {
+			link, ok := value.(PathLinkValue)
+			if !ok {
+				continue
+			}
+
+			identifier, ok := key.(StringAtreeValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+
+			if isResolvablePathLink(inter, address, link) {
+				continue
+			}
+
+			danglingLinks = append(
+				danglingLinks,
+				DanglingLink{
+					Path: NewPathValue(inter, domain, string(identifier)),
+					Link: link,
+				},
+			)
+		}
+	}
+
+	return danglingLinks
+}
+
+func isResolvablePathLink(inter *Interpreter, address common.Address, link PathLinkValue) bool {
+	targetPath, isAccountLink, ok := resolvePathLink(inter, address, link)
+	if !ok {
+		return false
+	}
+
+	if isAccountLink {
+		return true
+	}
+
+	target := inter.ReadStored(
+		address,
+		targetPath.Domain.StorageDomain(),
+		StringStorageMapKey(targetPath.Identifier),
+	)
+	return target != nil
+}
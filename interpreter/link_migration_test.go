@@ -0,0 +1,335 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/interpreter"
+	"github.com/onflow/cadence/runtime"
+	. "github.com/onflow/cadence/test_utils/interpreter_utils"
+	. "github.com/onflow/cadence/test_utils/runtime_utils"
+)
+
+func TestMigrateLinks(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+	addressValue := interpreter.AddressValue(address)
+
+	borrowType := interpreter.NewReferenceStaticType(
+		nil,
+		interpreter.UnauthorizedAccess,
+		interpreter.PrimitiveStaticTypeAnyStruct,
+	)
+
+	accountBorrowType := interpreter.NewReferenceStaticType(
+		nil,
+		interpreter.FullyEntitledAccountAccess,
+		interpreter.PrimitiveStaticTypeAccount,
+	)
+
+	storagePath := interpreter.NewUnmeteredPathValue(common.PathDomainStorage, "vault")
+	privatePath := interpreter.NewUnmeteredPathValue(common.PathDomainPrivate, "vault")
+	publicPath := interpreter.NewUnmeteredPathValue(common.PathDomainPublic, "vault")
+	privateAccountPath := interpreter.NewUnmeteredPathValue(common.PathDomainPrivate, "account")
+	publicAccountPath := interpreter.NewUnmeteredPathValue(common.PathDomainPublic, "account")
+	danglingPath := interpreter.NewUnmeteredPathValue(common.PathDomainPublic, "dangling")
+	cyclicPath := interpreter.NewUnmeteredPathValue(common.PathDomainPublic, "cyclic")
+	nonReferencePath := interpreter.NewUnmeteredPathValue(common.PathDomainPublic, "nonReference")
+	capabilityPath := interpreter.NewUnmeteredPathValue(common.PathDomainPublic, "capability")
+
+	existingCapability := interpreter.NewUnmeteredCapabilityValue(
+		100,
+		addressValue,
+		borrowType,
+	)
+
+	// Create the fixture: write links to storage and commit
+
+	ledger := NewTestLedger(nil, nil)
+
+	func() {
+		storage := runtime.NewStorage(ledger, nil, runtime.StorageConfig{})
+
+		// Turn off atree validation, as deprecated link values do not support it
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, false, false)
+
+		write := func(path interpreter.PathValue, value interpreter.Value) {
+			inter.WriteStored(
+				address,
+				path.Domain.StorageDomain(),
+				interpreter.StringStorageMapKey(path.Identifier),
+				value,
+			)
+		}
+
+		// /public/vault -> /private/vault -> /storage/vault
+		write(publicPath, interpreter.PathLinkValue{
+			Type:       borrowType,
+			TargetPath: privatePath,
+		})
+		write(privatePath, interpreter.PathLinkValue{
+			Type:       borrowType,
+			TargetPath: storagePath,
+		})
+
+		// /public/account -> /private/account -> account
+		write(publicAccountPath, interpreter.PathLinkValue{
+			Type:       accountBorrowType,
+			TargetPath: privateAccountPath,
+		})
+		write(privateAccountPath, interpreter.AccountLinkValue{})
+
+		// /public/dangling -> /private/missing
+		write(danglingPath, interpreter.PathLinkValue{
+			Type:       borrowType,
+			TargetPath: interpreter.NewUnmeteredPathValue(common.PathDomainPrivate, "missing"),
+		})
+
+		// /public/cyclic -> /public/cyclic
+		write(cyclicPath, interpreter.PathLinkValue{
+			Type:       borrowType,
+			TargetPath: cyclicPath,
+		})
+
+		// /public/nonReference -> /storage/vault
+		write(nonReferencePath, interpreter.PathLinkValue{
+			Type:       interpreter.PrimitiveStaticTypeAnyStruct,
+			TargetPath: storagePath,
+		})
+
+		write(capabilityPath, existingCapability)
+
+		err := storage.Commit(inter, false)
+		require.NoError(t, err)
+	}()
+
+	// Load the fixture and migrate the links
+
+	type issuedCapability struct {
+		borrowType *interpreter.ReferenceStaticType
+		targetPath interpreter.PathValue
+	}
+
+	var issuedCapabilities []issuedCapability
+
+	issue := func(
+		borrowType *interpreter.ReferenceStaticType,
+		targetPath interpreter.PathValue,
+	) interpreter.CapabilityValue {
+		issuedCapabilities = append(
+			issuedCapabilities,
+			issuedCapability{
+				borrowType: borrowType,
+				targetPath: targetPath,
+			},
+		)
+
+		return interpreter.NewUnmeteredCapabilityValue(
+			interpreter.UInt64Value(len(issuedCapabilities)),
+			addressValue,
+			borrowType,
+		)
+	}
+
+	func() {
+		storage := runtime.NewStorage(
+			NewTestLedgerWithData(nil, nil, ledger.StoredValues, ledger.StorageIndices),
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, false, false)
+
+		migrated := interpreter.MigrateLinks(inter, address, issue)
+		assert.Equal(t, 4, migrated)
+
+		err := storage.Commit(inter, false)
+		require.NoError(t, err)
+	}()
+
+	// Links targeting links are resolved before any link is replaced
+
+	require.Len(t, issuedCapabilities, 4)
+	assert.ElementsMatch(t,
+		[]issuedCapability{
+			// /private/vault
+			{borrowType: borrowType, targetPath: storagePath},
+			// /private/account
+			{borrowType: accountBorrowType, targetPath: interpreter.EmptyPathValue},
+			// /public/vault
+			{borrowType: borrowType, targetPath: storagePath},
+			// /public/account
+			{borrowType: accountBorrowType, targetPath: interpreter.EmptyPathValue},
+		},
+		issuedCapabilities,
+	)
+
+	// Reload the migrated storage and check the stored values
+
+	storage := runtime.NewStorage(
+		NewTestLedgerWithData(nil, nil, ledger.StoredValues, ledger.StorageIndices),
+		nil,
+		runtime.StorageConfig{},
+	)
+
+	inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, false, false)
+
+	read := func(path interpreter.PathValue) interpreter.Value {
+		return inter.ReadStored(
+			address,
+			path.Domain.StorageDomain(),
+			interpreter.StringStorageMapKey(path.Identifier),
+		)
+	}
+
+	requireCapability := func(path interpreter.PathValue, expectedBorrowType interpreter.StaticType) {
+		value := read(path)
+		require.IsType(t, &interpreter.IDCapabilityValue{}, value, path.String())
+
+		capability := value.(*interpreter.IDCapabilityValue)
+		assert.NotEqual(t, interpreter.InvalidCapabilityID, capability.ID)
+		assert.Equal(t, addressValue, capability.Address())
+		assert.True(t, expectedBorrowType.Equal(capability.BorrowType))
+
+		assert.Equal(t,
+			interpreter.NewCapabilityStaticType(nil, expectedBorrowType),
+			capability.StaticType(inter),
+		)
+	}
+
+	requireCapability(publicPath, borrowType)
+	requireCapability(privatePath, borrowType)
+	requireCapability(publicAccountPath, accountBorrowType)
+	requireCapability(privateAccountPath, accountBorrowType)
+
+	// Links which cannot be migrated are left unchanged
+
+	assert.IsType(t, interpreter.PathLinkValue{}, read(danglingPath))
+	assert.IsType(t, interpreter.PathLinkValue{}, read(cyclicPath))
+	assert.IsType(t, interpreter.PathLinkValue{}, read(nonReferencePath))
+
+	// Other values are left unchanged
+
+	capability := read(capabilityPath)
+	require.IsType(t, &interpreter.IDCapabilityValue{}, capability)
+	assert.Equal(t, existingCapability.ID, capability.(*interpreter.IDCapabilityValue).ID)
+}
+
+func TestFindDanglingLinks(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	borrowType := interpreter.NewReferenceStaticType(
+		nil,
+		interpreter.UnauthorizedAccess,
+		interpreter.PrimitiveStaticTypeAnyStruct,
+	)
+
+	storagePath := interpreter.NewUnmeteredPathValue(common.PathDomainStorage, "vault")
+	removedStoragePath := interpreter.NewUnmeteredPathValue(common.PathDomainStorage, "removed")
+	privatePath := interpreter.NewUnmeteredPathValue(common.PathDomainPrivate, "vault")
+	publicPath := interpreter.NewUnmeteredPathValue(common.PathDomainPublic, "vault")
+	privateAccountPath := interpreter.NewUnmeteredPathValue(common.PathDomainPrivate, "account")
+	publicAccountPath := interpreter.NewUnmeteredPathValue(common.PathDomainPublic, "account")
+	removedPath := interpreter.NewUnmeteredPathValue(common.PathDomainPublic, "removed")
+	chainedRemovedPath := interpreter.NewUnmeteredPathValue(common.PathDomainPrivate, "removed")
+	missingPath := interpreter.NewUnmeteredPathValue(common.PathDomainPublic, "missing")
+	cyclicPath := interpreter.NewUnmeteredPathValue(common.PathDomainPublic, "cyclic")
+	capabilityPath := interpreter.NewUnmeteredPathValue(common.PathDomainPublic, "capability")
+
+	storage := runtime.NewStorage(NewTestLedger(nil, nil), nil, runtime.StorageConfig{})
+
+	// Turn off atree validation, as deprecated link values do not support it
+	inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, false, false)
+
+	write := func(path interpreter.PathValue, value interpreter.Value) {
+		inter.WriteStored(
+			address,
+			path.Domain.StorageDomain(),
+			interpreter.StringStorageMapKey(path.Identifier),
+			value,
+		)
+	}
+
+	newLink := func(targetPath interpreter.PathValue) interpreter.PathLinkValue {
+		return interpreter.PathLinkValue{
+			Type:       borrowType,
+			TargetPath: targetPath,
+		}
+	}
+
+	write(storagePath, interpreter.NewUnmeteredIntValueFromInt64(42))
+
+	// /public/vault -> /private/vault -> /storage/vault
+	write(publicPath, newLink(privatePath))
+	write(privatePath, newLink(storagePath))
+
+	// /public/account -> /private/account -> account
+	write(publicAccountPath, newLink(privateAccountPath))
+	write(privateAccountPath, interpreter.AccountLinkValue{})
+
+	// /private/removed -> /public/removed -> /storage/removed (removed)
+	write(removedStoragePath, interpreter.NewUnmeteredIntValueFromInt64(1))
+	write(removedPath, newLink(removedStoragePath))
+	write(chainedRemovedPath, newLink(removedPath))
+	write(removedStoragePath, nil)
+
+	// /public/missing -> /private/missing
+	missingLink := newLink(interpreter.NewUnmeteredPathValue(common.PathDomainPrivate, "missing"))
+	write(missingPath, missingLink)
+
+	// /public/cyclic -> /public/cyclic
+	write(cyclicPath, newLink(cyclicPath))
+
+	write(
+		capabilityPath,
+		interpreter.NewUnmeteredCapabilityValue(
+			1,
+			interpreter.AddressValue(address),
+			borrowType,
+		),
+	)
+
+	danglingLinks := interpreter.FindDanglingLinks(inter, address)
+
+	assert.ElementsMatch(t,
+		[]interpreter.DanglingLink{
+			{Path: removedPath, Link: newLink(removedStoragePath)},
+			{Path: chainedRemovedPath, Link: newLink(removedPath)},
+			{Path: missingPath, Link: missingLink},
+			{Path: cyclicPath, Link: newLink(cyclicPath)},
+		},
+		danglingLinks,
+	)
+
+	// Accounts without links have no dangling links
+
+	assert.Empty(t,
+		interpreter.FindDanglingLinks(inter, common.MustBytesToAddress([]byte{0x2})),
+	)
+}
@@ -20,6 +20,8 @@ package interpreter_test
 
 import (
 	"fmt"
+	"math"
+	"strings"
 	"testing"
 
 	"github.com/onflow/cadence/activations"
@@ -6826,6 +6828,218 @@ func TestInterpretStringMetering(t *testing.T) {
 		// 1 + 4 (max UTF8 encoding)
 		assert.Equal(t, uint64(5), meter.getMemory(common.MemoryKindStringValue))
 	})
+
+	t.Run("concat", func(t *testing.T) {
+
+		t.Parallel()
+
+		script := `
+          fun main(a: String, b: String): String {
+              return a.concat(b)
+          }
+        `
+		meter := newTestMemoryGauge()
+		inter := parseCheckAndInterpretWithMemoryMetering(t, script, meter)
+
+		stringMemoryBefore := meter.getMemory(common.MemoryKindStringValue)
+		rawStringMemoryBefore := meter.getMemory(common.MemoryKindRawString)
+
+		_, err := inter.Invoke(
+			"main",
+			interpreter.NewUnmeteredStringValue("abc"),
+			interpreter.NewUnmeteredStringValue("defgh"),
+		)
+		require.NoError(t, err)
+
+		// 1 + 8 (abcdefgh)
+		assert.Equal(t,
+			uint64(9),
+			meter.getMemory(common.MemoryKindStringValue)-stringMemoryBefore,
+		)
+		assert.Equal(t,
+			uint64(9),
+			meter.getMemory(common.MemoryKindRawString)-rawStringMemoryBefore,
+		)
+	})
+
+	t.Run("concat, limit exceeded", func(t *testing.T) {
+
+		t.Parallel()
+
+		script := `
+          fun main(a: String, b: String): String {
+              return a.concat(b)
+          }
+        `
+
+		const length = 1024
+
+		// The operands are within the limit, but the combined string is not
+		const limit = 2 * length
+
+		var rawStringMemoryUsages []common.MemoryUsage
+
+		limitExceededErr := fmt.Errorf("memory limit exceeded")
+
+		meter := testMemoryGaugeFunc(func(usage common.MemoryUsage) error {
+			switch usage.Kind {
+			case common.MemoryKindStringValue:
+				if usage.Amount > limit {
+					return limitExceededErr
+				}
+
+			case common.MemoryKindRawString:
+				rawStringMemoryUsages = append(rawStringMemoryUsages, usage)
+			}
+			return nil
+		})
+
+		inter := parseCheckAndInterpretWithMemoryMetering(t, script, meter)
+
+		a := interpreter.NewUnmeteredStringValue(strings.Repeat("a", length))
+		b := interpreter.NewUnmeteredStringValue(strings.Repeat("b", length))
+
+		rawStringMemoryUsages = nil
+
+		_, err := inter.Invoke("main", a, b)
+		RequireError(t, err)
+
+		require.ErrorIs(t, err, limitExceededErr)
+
+		// The combined string was never allocated,
+		// so its raw string memory was never metered
+
+		for _, usage := range rawStringMemoryUsages {
+			assert.LessOrEqual(t, usage.Amount, uint64(length+1))
+		}
+	})
+
+	t.Run("repeat", func(t *testing.T) {
+
+		t.Parallel()
+
+		script := `
+          fun main(a: String, count: Int): String {
+              return a.repeat(count)
+          }
+        `
+		meter := newTestMemoryGauge()
+		inter := parseCheckAndInterpretWithMemoryMetering(t, script, meter)
+
+		stringMemoryBefore := meter.getMemory(common.MemoryKindStringValue)
+		rawStringMemoryBefore := meter.getMemory(common.MemoryKindRawString)
+
+		_, err := inter.Invoke(
+			"main",
+			interpreter.NewUnmeteredStringValue("abc"),
+			interpreter.NewUnmeteredIntValueFromInt64(3),
+		)
+		require.NoError(t, err)
+
+		// 1 + 9 (abcabcabc)
+		assert.Equal(t,
+			uint64(10),
+			meter.getMemory(common.MemoryKindStringValue)-stringMemoryBefore,
+		)
+		assert.Equal(t,
+			uint64(10),
+			meter.getMemory(common.MemoryKindRawString)-rawStringMemoryBefore,
+		)
+	})
+
+	t.Run("repeat, 32-bit overflow, limit exceeded", func(t *testing.T) {
+
+		t.Parallel()
+
+		script := `
+          fun main(a: String, count: Int): String {
+              return a.repeat(count)
+          }
+        `
+
+		const length = 1 << 16
+
+		// The product of length and count overflows 32-bit multiplication,
+		// i.e. it would wrap around to a small length
+		const count = 1<<16 + 1
+
+		const limit = 1 << 20
+
+		limitExceededErr := fmt.Errorf("memory limit exceeded")
+
+		var stringMemoryUsages []common.MemoryUsage
+
+		meter := testMemoryGaugeFunc(func(usage common.MemoryUsage) error {
+			if usage.Kind == common.MemoryKindStringValue {
+				stringMemoryUsages = append(stringMemoryUsages, usage)
+				if usage.Amount > limit {
+					return limitExceededErr
+				}
+			}
+			return nil
+		})
+
+		inter := parseCheckAndInterpretWithMemoryMetering(t, script, meter)
+
+		a := interpreter.NewUnmeteredStringValue(strings.Repeat("a", length))
+
+		_, err := inter.Invoke(
+			"main",
+			a,
+			interpreter.NewUnmeteredIntValueFromInt64(count),
+		)
+		RequireError(t, err)
+
+		require.ErrorIs(t, err, limitExceededErr)
+
+		// The full length was metered, not the wrapped around 32-bit length
+		lastUsage := stringMemoryUsages[len(stringMemoryUsages)-1]
+		assert.Equal(t, uint64(length*count+1), lastUsage.Amount)
+	})
+
+	t.Run("repeat, 64-bit overflow", func(t *testing.T) {
+
+		t.Parallel()
+
+		script := `
+          fun main(a: String, count: Int): String {
+              return a.repeat(count)
+          }
+        `
+
+		var stringMemoryUsages []common.MemoryUsage
+
+		meter := testMemoryGaugeFunc(func(usage common.MemoryUsage) error {
+			if usage.Kind == common.MemoryKindStringValue {
+				stringMemoryUsages = append(stringMemoryUsages, usage)
+			}
+			return nil
+		})
+
+		inter := parseCheckAndInterpretWithMemoryMetering(t, script, meter)
+
+		stringMemoryUsages = nil
+
+		_, err := inter.Invoke(
+			"main",
+			interpreter.NewUnmeteredStringValue("ab"),
+			interpreter.NewUnmeteredIntValueFromInt64(math.MaxInt64/2+1),
+		)
+		RequireError(t, err)
+
+		require.ErrorAs(t, err, &interpreter.OverflowError{})
+
+		// The result was never metered
+		assert.Empty(t, stringMemoryUsages)
+	})
+}
+
+type testMemoryGaugeFunc func(usage common.MemoryUsage) error
+
+var _ common.MemoryGauge = testMemoryGaugeFunc(nil)
+
+func (f testMemoryGaugeFunc) MeterMemory(usage common.MemoryUsage) error {
+	return f(usage)
 }
 
 func TestInterpretCharacterMetering(t *testing.T) {
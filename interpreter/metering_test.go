@@ -564,6 +564,30 @@ func TestInterpretStdlibComputationMetering(t *testing.T) {
 		assert.Equal(t, uint(4), computationMeteredValues[common.ComputationKindLoop])
 	})
 
+	t.Run("string concatAll", func(t *testing.T) {
+		t.Parallel()
+
+		computationMeteredValues := make(map[common.ComputationKind]uint)
+		inter, err := parseCheckAndInterpretWithOptions(t, `
+            fun main() {
+                let s = String.concatAll(["one", "two", "three", "four"])
+            }`,
+			ParseCheckAndInterpretOptions{
+				Config: &interpreter.Config{
+					OnMeterComputation: func(compKind common.ComputationKind, intensity uint) {
+						computationMeteredValues[compKind] += intensity
+					},
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		_, err = inter.Invoke("main")
+		require.NoError(t, err)
+
+		assert.Equal(t, uint(4), computationMeteredValues[common.ComputationKindLoop])
+	})
+
 	t.Run("string concat", func(t *testing.T) {
 		t.Parallel()
 
@@ -659,4 +683,35 @@ func TestInterpretStdlibComputationMetering(t *testing.T) {
 
 		assert.Equal(t, uint(58), computationMeteredValues[common.ComputationKindLoop])
 	})
+
+	t.Run("string tokens", func(t *testing.T) {
+		t.Parallel()
+
+		computationMeteredValues := make(map[common.ComputationKind]uint)
+		inter, err := parseCheckAndInterpretWithOptions(t, `
+            fun main() {
+                let iterator = "abc/d/ef//".tokens(separator: "/")
+                iterator.next()
+                iterator.next()
+                iterator.next()
+                iterator.next()
+                iterator.next()
+                iterator.next()
+            }`,
+			ParseCheckAndInterpretOptions{
+				Config: &interpreter.Config{
+					OnMeterComputation: func(compKind common.ComputationKind, intensity uint) {
+						computationMeteredValues[compKind] += intensity
+					},
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		_, err = inter.Invoke("main")
+		require.NoError(t, err)
+
+		// One per part, plus the scanned bytes of the parts and separators
+		assert.Equal(t, uint(15), computationMeteredValues[common.ComputationKindLoop])
+	})
 }
@@ -1091,6 +1091,11 @@ func TestInterpretStringSlicing(t *testing.T) {
 		EndPos:   ast.Position{Offset: 141, Line: 4, Column: 56},
 	}
 
+	range3 := ast.Range{
+		StartPos: ast.Position{Offset: 116, Line: 4, Column: 31},
+		EndPos:   ast.Position{Offset: 142, Line: 4, Column: 57},
+	}
+
 	type test struct {
 		str        string
 		from       int
@@ -1108,12 +1113,50 @@ func TestInterpretStringSlicing(t *testing.T) {
 		{"abcdef", 2, 3, "c", nil},
 		{"abcdef", 5, 6, "f", nil},
 		{"abcdef", 1, 6, "bcdef", nil},
+		// Negative indices are offsets from the end
+		{"abcdef", 0, -1, "abcde", nil},
+		{"abcdef", -1, 6, "f", nil},
+		{"abcdef", -3, -1, "de", nil},
+		{"abcdef", -6, -3, "abc", nil},
+		{"abcdef", -6, 6, "abcdef", nil},
+		{"abcdef", 2, -1, "cde", nil},
+		{"abcdef", -2, -2, "", nil},
 		// Invalid indices
 		{"abcdef", -1, 0, "", func(t *testing.T, err error) {
+			var indexErr interpreter.InvalidSliceIndexError
+			require.ErrorAs(t, err, &indexErr)
+
+			assert.Equal(t, 5, indexErr.FromIndex)
+			assert.Equal(t, 0, indexErr.UpToIndex)
+			assert.Equal(t,
+				range2.StartPos,
+				indexErr.LocationRange.StartPosition(),
+			)
+			assert.Equal(t,
+				range2.EndPos,
+				indexErr.LocationRange.EndPosition(nil),
+			)
+		}},
+		{"abcdef", -2, -4, "", func(t *testing.T, err error) {
+			var indexErr interpreter.InvalidSliceIndexError
+			require.ErrorAs(t, err, &indexErr)
+
+			assert.Equal(t, 4, indexErr.FromIndex)
+			assert.Equal(t, 2, indexErr.UpToIndex)
+			assert.Equal(t,
+				range3.StartPos,
+				indexErr.LocationRange.StartPosition(),
+			)
+			assert.Equal(t,
+				range3.EndPos,
+				indexErr.LocationRange.EndPosition(nil),
+			)
+		}},
+		{"abcdef", -7, 0, "", func(t *testing.T, err error) {
 			var sliceErr interpreter.StringSliceIndicesError
 			require.ErrorAs(t, err, &sliceErr)
 
-			assert.Equal(t, -1, sliceErr.FromIndex)
+			assert.Equal(t, -7, sliceErr.FromIndex)
 			assert.Equal(t, 0, sliceErr.UpToIndex)
 			assert.Equal(t, 6, sliceErr.Length)
 			assert.Equal(t,
@@ -1125,12 +1168,12 @@ func TestInterpretStringSlicing(t *testing.T) {
 				sliceErr.LocationRange.EndPosition(nil),
 			)
 		}},
-		{"abcdef", 0, -1, "", func(t *testing.T, err error) {
+		{"abcdef", 0, -7, "", func(t *testing.T, err error) {
 			var sliceErr interpreter.StringSliceIndicesError
 			require.ErrorAs(t, err, &sliceErr)
 
 			assert.Equal(t, 0, sliceErr.FromIndex)
-			assert.Equal(t, -1, sliceErr.UpToIndex)
+			assert.Equal(t, -7, sliceErr.UpToIndex)
 			assert.Equal(t, 6, sliceErr.Length)
 			assert.Equal(t,
 				range2.StartPos,
@@ -1141,6 +1184,22 @@ func TestInterpretStringSlicing(t *testing.T) {
 				sliceErr.LocationRange.EndPosition(nil),
 			)
 		}},
+		{"abcdef", -7, 10, "", func(t *testing.T, err error) {
+			var sliceErr interpreter.StringSliceIndicesError
+			require.ErrorAs(t, err, &sliceErr)
+
+			assert.Equal(t, -7, sliceErr.FromIndex)
+			assert.Equal(t, 10, sliceErr.UpToIndex)
+			assert.Equal(t, 6, sliceErr.Length)
+			assert.Equal(t,
+				range3.StartPos,
+				sliceErr.LocationRange.StartPosition(),
+			)
+			assert.Equal(t,
+				range3.EndPos,
+				sliceErr.LocationRange.EndPosition(nil),
+			)
+		}},
 		{"abcdef", 0, 10, "", func(t *testing.T, err error) {
 			var sliceErr interpreter.StringSliceIndicesError
 			require.ErrorAs(t, err, &sliceErr)
@@ -1180,6 +1239,7 @@ func TestInterpretStringSlicing(t *testing.T) {
 		{"cafe\\u{301}ba\\u{308}", 3, 6, "e\u0301ba\u0308", nil},
 		{"cafe\\u{301}ba\\u{308}be", 3, 8, "e\u0301ba\u0308be", nil},
 		{"cafe\\u{301}b", 4, 5, "b", nil},
+		{"cafe\\u{301}b", -2, -1, "e\u0301", nil},
 		{"cafe\\u{301}ba\\u{308}", 4, 6, "ba\u0308", nil},
 		{"cafe\\u{301}ba\\u{308}be", 4, 8, "ba\u0308be", nil},
 		{"cafe\\u{301}ba\\u{308}be", 3, 4, "e\u0301", nil},
@@ -20,14 +20,18 @@ package interpreter_test
 
 import (
 	"fmt"
+	"math/rand"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/onflow/cadence/common"
 	"github.com/onflow/cadence/interpreter"
+	"github.com/onflow/cadence/runtime"
 	"github.com/onflow/cadence/sema"
 	. "github.com/onflow/cadence/test_utils/interpreter_utils"
+	. "github.com/onflow/cadence/test_utils/runtime_utils"
 )
 
 func TestInterpretPath(t *testing.T) {
@@ -148,3 +152,159 @@ func TestInterpretConvertStringToPath(t *testing.T) {
 		test(domain)
 	}
 }
+
+func TestComparePaths(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("equal", func(t *testing.T) {
+
+		t.Parallel()
+
+		for _, domain := range common.AllPathDomains {
+			a := interpreter.NewUnmeteredPathValue(domain, "foo")
+			b := interpreter.NewUnmeteredPathValue(domain, "foo")
+
+			assert.Equal(t, 0, interpreter.ComparePaths(a, b))
+		}
+	})
+
+	t.Run("domain before identifier", func(t *testing.T) {
+
+		t.Parallel()
+
+		storagePath := interpreter.NewUnmeteredPathValue(common.PathDomainStorage, "z")
+		privatePath := interpreter.NewUnmeteredPathValue(common.PathDomainPrivate, "a")
+		publicPath := interpreter.NewUnmeteredPathValue(common.PathDomainPublic, "a")
+
+		assert.Equal(t, -1, interpreter.ComparePaths(storagePath, privatePath))
+		assert.Equal(t, 1, interpreter.ComparePaths(privatePath, storagePath))
+
+		assert.Equal(t, -1, interpreter.ComparePaths(privatePath, publicPath))
+		assert.Equal(t, 1, interpreter.ComparePaths(publicPath, privatePath))
+
+		assert.Equal(t, -1, interpreter.ComparePaths(storagePath, publicPath))
+		assert.Equal(t, 1, interpreter.ComparePaths(publicPath, storagePath))
+	})
+
+	t.Run("shared prefixes", func(t *testing.T) {
+
+		t.Parallel()
+
+		for _, domain := range common.AllPathDomains {
+			empty := interpreter.NewUnmeteredPathValue(domain, "")
+			foo := interpreter.NewUnmeteredPathValue(domain, "foo")
+			foo2 := interpreter.NewUnmeteredPathValue(domain, "foo2")
+			fooBar := interpreter.NewUnmeteredPathValue(domain, "fooBar")
+			fop := interpreter.NewUnmeteredPathValue(domain, "fop")
+
+			assert.Equal(t, -1, interpreter.ComparePaths(empty, foo))
+			assert.Equal(t, -1, interpreter.ComparePaths(foo, foo2))
+			assert.Equal(t, -1, interpreter.ComparePaths(foo2, fooBar))
+			assert.Equal(t, -1, interpreter.ComparePaths(fooBar, fop))
+			assert.Equal(t, 1, interpreter.ComparePaths(fop, foo))
+		}
+	})
+
+	t.Run("sort", func(t *testing.T) {
+
+		t.Parallel()
+
+		var expected []interpreter.PathValue
+		for _, domain := range []common.PathDomain{
+			common.PathDomainStorage,
+			common.PathDomainPrivate,
+			common.PathDomainPublic,
+		} {
+			for _, identifier := range []string{"", "A", "a", "ab", "abc", "b"} {
+				expected = append(
+					expected,
+					interpreter.NewUnmeteredPathValue(domain, identifier),
+				)
+			}
+		}
+
+		paths := slices.Clone(expected)
+		rand.New(rand.NewSource(42)).Shuffle(
+			len(paths),
+			func(i, j int) {
+				paths[i], paths[j] = paths[j], paths[i]
+			},
+		)
+
+		interpreter.SortPaths(paths)
+
+		assert.Equal(t, expected, paths)
+	})
+
+	t.Run("sort iterated link domains", func(t *testing.T) {
+
+		t.Parallel()
+
+		address := common.MustBytesToAddress([]byte{0x1})
+
+		storage := runtime.NewStorage(
+			NewTestLedger(nil, nil),
+			nil,
+			runtime.StorageConfig{},
+		)
+
+		// Turn off atree validation, as deprecated link values do not support it
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, false, false)
+
+		identifiers := []string{"flowTokenReceiver", "flowTokenBalance", "flow", "a", "z"}
+
+		targetPath := interpreter.NewUnmeteredPathValue(common.PathDomainStorage, "target")
+
+		var paths []interpreter.PathValue
+
+		for _, pathDomain := range []common.PathDomain{
+			common.PathDomainPublic,
+			common.PathDomainPrivate,
+		} {
+			domainStorageMap := storage.GetDomainStorageMap(
+				inter,
+				address,
+				pathDomain.StorageDomain(),
+				true,
+			)
+
+			for _, identifier := range identifiers {
+				domainStorageMap.WriteValue(
+					inter,
+					interpreter.StringStorageMapKey(identifier),
+					interpreter.PathLinkValue{
+						Type:       interpreter.PrimitiveStaticTypeAnyStruct,
+						TargetPath: targetPath,
+					},
+				)
+			}
+
+			iterator := domainStorageMap.Iterator(nil)
+			for key := iterator.NextKey(); key != nil; key = iterator.NextKey() {
+				identifier := string(key.(interpreter.StringAtreeValue))
+				paths = append(
+					paths,
+					interpreter.NewUnmeteredPathValue(pathDomain, identifier),
+				)
+			}
+		}
+
+		interpreter.SortPaths(paths)
+
+		var expected []interpreter.PathValue
+		for _, pathDomain := range []common.PathDomain{
+			common.PathDomainPrivate,
+			common.PathDomainPublic,
+		} {
+			for _, identifier := range []string{"a", "flow", "flowTokenBalance", "flowTokenReceiver", "z"} {
+				expected = append(
+					expected,
+					interpreter.NewUnmeteredPathValue(pathDomain, identifier),
+				)
+			}
+		}
+
+		assert.Equal(t, expected, paths)
+	})
+}
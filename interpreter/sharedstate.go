@@ -45,6 +45,8 @@ type SharedState struct {
 	MutationDuringCapabilityControllerIteration bool
 	containerValueIteration                     map[atree.ValueID]struct{}
 	destroyedResources                          map[atree.ValueID]struct{}
+	// valueDepth is the current nesting depth of container values which are transferred or deeply removed
+	valueDepth int
 }
 
 func NewSharedState(config *Config) *SharedState {
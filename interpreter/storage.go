@@ -270,3 +270,179 @@ func StorableSize(storable atree.Storable) (uint32, error) {
 
 	return uint32(size), nil
 }
+
+// EstimateStorableSize returns the estimated number of bytes the given value occupies
+// when it is stored in the account with the given address, e.g. in a domain storage map,
+// without writing to storage.
+//
+// The estimate is the size of the value's storable in the parent container,
+// plus the sizes of all slabs the value is stored in separately:
+// containers which are not inlined, and large values, like large strings,
+// which are stored in their own slabs.
+//
+// Containers which are currently inlined into another container cannot be estimated on their own.
+func EstimateStorableSize(context StorageContext, value Value, address atree.Address) (uint64, error) {
+	return estimateStorableSize(
+		context.Storage(),
+		value,
+		address,
+		atree.MaxInlineMapElementSize(),
+	)
+}
+
+func estimateStorableSize(
+	storage Storage,
+	value Value,
+	address atree.Address,
+	maxInlineSize uint64,
+) (uint64, error) {
+
+	switch value := value.(type) {
+	case *SomeValue:
+		nonSomeValue, nestedLevels := value.nonSomeValue()
+
+		// See SomeValue.Storable: The SomeStorable wrapper is always encoded inline
+		someStorableEncodedPrefixSize := uint64(getSomeStorableEncodedPrefixSize(nestedLevels))
+
+		size, err := estimateStorableSize(
+			storage,
+			nonSomeValue.(Value),
+			address,
+			maxInlineSize-someStorableEncodedPrefixSize,
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		return someStorableEncodedPrefixSize + size, nil
+
+	case atreeContainerBackedValue:
+		atreeValue, _ := value.(atree.WrapperValue).UnwrapAtreeValue()
+
+		container, ok := atreeValue.(interface {
+			Inlined() bool
+			Inlinable(maxInlineSize uint64) bool
+			SlabID() atree.SlabID
+			Type() atree.TypeInfo
+		})
+		if !ok {
+			return 0, errors.NewUnreachableError()
+		}
+
+		if container.Inlined() {
+			return 0, errors.NewUnexpectedError("cannot estimate storable size of inlined container")
+		}
+
+		// The sizes of slabs do not depend on the address,
+		// so the slabs of the container can be used as-is
+
+		rootSlab, found, err := storage.Retrieve(container.SlabID())
+		if err != nil {
+			return 0, err
+		}
+		if !found {
+			return 0, errors.NewUnexpectedError("missing root slab %s", container.SlabID())
+		}
+
+		size, err := storableChildSlabsSize(storage, rootSlab)
+		if err != nil {
+			return 0, err
+		}
+
+		// The root slab is either inlined into the parent container,
+		// or stored separately and referenced from the parent container.
+		// In both cases, the type info is encoded as extra data,
+		// which is not included in the byte size of the slab
+		size += uint64(rootSlab.ByteSize())
+
+		typeInfoSize, err := encodedTypeInfoSize(container.Type())
+		if err != nil {
+			return 0, err
+		}
+		size += typeInfoSize
+
+		if !container.Inlinable(maxInlineSize) {
+			size += uint64(mustStorableSize(atree.SlabIDStorable(container.SlabID())))
+		}
+
+		return size, nil
+
+	default:
+		if _, ok := value.(*StringValue); ok {
+			maxInlineSize = maxInlineStringSize(storage, maxInlineSize)
+		}
+
+		// Large values are stored in a separate slab,
+		// so get the storable using a scratch storage,
+		// instead of writing to the actual storage
+
+		scratchStorage := NewInMemoryStorage(nil)
+
+		storable, err := value.Storable(scratchStorage, address, maxInlineSize)
+		if err != nil {
+			return 0, err
+		}
+
+		size, err := StorableSize(storable)
+		if err != nil {
+			return 0, err
+		}
+
+		childSlabsSize, err := storableChildSlabsSize(scratchStorage, storable)
+		if err != nil {
+			return 0, err
+		}
+
+		return uint64(size) + childSlabsSize, nil
+	}
+}
+
+// encodedTypeInfoSize returns the size of the encoded type info in bytes.
+func encodedTypeInfoSize(typeInfo atree.TypeInfo) (uint64, error) {
+	var writer writeCounter
+	enc := atree.NewEncoder(&writer, CBOREncMode)
+
+	err := typeInfo.Encode(enc.CBOR)
+	if err != nil {
+		return 0, err
+	}
+
+	err = enc.CBOR.Flush()
+	if err != nil {
+		return 0, err
+	}
+
+	return writer.length, nil
+}
+
+// storableChildSlabsSize returns the total size of all slabs referenced by the given storable,
+// directly or indirectly.
+func storableChildSlabsSize(storage atree.SlabStorage, storable atree.Storable) (uint64, error) {
+	var size uint64
+
+	if slabIDStorable, ok := storable.(atree.SlabIDStorable); ok {
+		slabID := atree.SlabID(slabIDStorable)
+
+		slab, found, err := storage.Retrieve(slabID)
+		if err != nil {
+			return 0, err
+		}
+		if !found {
+			return 0, errors.NewUnexpectedError("missing slab %s", slabID)
+		}
+
+		size += uint64(slab.ByteSize())
+
+		storable = slab
+	}
+
+	for _, childStorable := range storable.ChildStorables() {
+		childSize, err := storableChildSlabsSize(storage, childStorable)
+		if err != nil {
+			return 0, err
+		}
+		size += childSize
+	}
+
+	return size, nil
+}
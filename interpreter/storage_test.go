@@ -19,6 +19,7 @@
 package interpreter_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/onflow/atree"
@@ -29,9 +30,11 @@ import (
 	"github.com/onflow/cadence/ast"
 	"github.com/onflow/cadence/common"
 	. "github.com/onflow/cadence/interpreter"
+	"github.com/onflow/cadence/runtime"
 	"github.com/onflow/cadence/sema"
 	. "github.com/onflow/cadence/test_utils/common_utils"
 	. "github.com/onflow/cadence/test_utils/interpreter_utils"
+	. "github.com/onflow/cadence/test_utils/runtime_utils"
 )
 
 func TestCompositeStorage(t *testing.T) {
@@ -982,3 +985,194 @@ func TestNestedContainerMutationAfterMove(t *testing.T) {
 		require.Equal(t, "S.test.TestResource(test: 11)", childValue4.String())
 	})
 }
+
+func TestEstimateStorableSize(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	const domain = common.StorageDomainPathStorage
+
+	// storedSize returns the total size of the registers of the account
+	storedSize := func(ledger TestLedger) (size uint64) {
+		for key, value := range ledger.StoredValues { //nolint:maprange
+			if strings.HasPrefix(key, string(address[:])) {
+				size += uint64(len(value))
+			}
+		}
+		return
+	}
+
+	test := func(t *testing.T, newValue func(inter *Interpreter) Value) {
+
+		ledger := NewTestLedger(nil, nil)
+		storage := runtime.NewStorage(ledger, nil, runtime.StorageConfig{})
+
+		// Turn off AtreeStorageValidationEnabled, as the transferred value
+		// is only referenced after it is written to the domain storage map
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
+		// Create the domain storage map, with an unrelated value
+
+		domainStorageMap := storage.GetDomainStorageMap(inter, address, domain, true)
+		domainStorageMap.WriteValue(inter, StringStorageMapKey("other"), NewUnmeteredIntValueFromInt64(1))
+
+		err := storage.Commit(inter, false)
+		require.NoError(t, err)
+
+		sizeBefore := storedSize(ledger)
+
+		value := newValue(inter)
+
+		estimate, err := EstimateStorableSize(inter, value, atree.Address(address))
+		require.NoError(t, err)
+
+		// Estimating does not write to storage
+
+		err = storage.Commit(inter, false)
+		require.NoError(t, err)
+		require.Equal(t, sizeBefore, storedSize(ledger))
+
+		// Store the value
+
+		value = value.Transfer(
+			inter,
+			EmptyLocationRange,
+			atree.Address(address),
+			true,
+			nil,
+			nil,
+			true, // value is standalone
+		)
+
+		domainStorageMap.WriteValue(inter, StringStorageMapKey("value"), value)
+
+		err = storage.Commit(inter, false)
+		require.NoError(t, err)
+
+		actual := storedSize(ledger) - sizeBefore
+
+		// The actual size also includes the key and the element overhead in the domain storage map,
+		// and the encodings of slabs differ slightly, e.g. in the slab header
+		const tolerance = 32
+		require.InDelta(t, actual, estimate, tolerance+float64(actual)*0.05)
+
+		require.NoError(t, storage.CheckHealth())
+	}
+
+	newIntArray := func(inter *Interpreter, count int) *ArrayValue {
+		values := make([]Value, count)
+		for i := range count {
+			values[i] = NewUnmeteredIntValueFromInt64(int64(i))
+		}
+		return NewArrayValue(
+			inter,
+			EmptyLocationRange,
+			&VariableSizedStaticType{
+				Type: PrimitiveStaticTypeInt,
+			},
+			common.ZeroAddress,
+			values...,
+		)
+	}
+
+	t.Run("small int", func(t *testing.T) {
+		t.Parallel()
+
+		test(t, func(_ *Interpreter) Value {
+			return NewUnmeteredIntValueFromInt64(42)
+		})
+	})
+
+	t.Run("small string", func(t *testing.T) {
+		t.Parallel()
+
+		test(t, func(_ *Interpreter) Value {
+			return NewUnmeteredStringValue("hello")
+		})
+	})
+
+	t.Run("large string", func(t *testing.T) {
+		t.Parallel()
+
+		test(t, func(_ *Interpreter) Value {
+			return NewUnmeteredStringValue(strings.Repeat("a", 10_000))
+		})
+	})
+
+	t.Run("optional large string", func(t *testing.T) {
+		t.Parallel()
+
+		test(t, func(_ *Interpreter) Value {
+			return NewUnmeteredSomeValueNonCopying(
+				NewUnmeteredStringValue(strings.Repeat("a", 10_000)),
+			)
+		})
+	})
+
+	t.Run("small array", func(t *testing.T) {
+		t.Parallel()
+
+		test(t, func(inter *Interpreter) Value {
+			return newIntArray(inter, 3)
+		})
+	})
+
+	t.Run("large array", func(t *testing.T) {
+		t.Parallel()
+
+		test(t, func(inter *Interpreter) Value {
+			return newIntArray(inter, 10_000)
+		})
+	})
+
+	t.Run("array of large strings", func(t *testing.T) {
+		t.Parallel()
+
+		test(t, func(inter *Interpreter) Value {
+			return NewArrayValue(
+				inter,
+				EmptyLocationRange,
+				&VariableSizedStaticType{
+					Type: PrimitiveStaticTypeString,
+				},
+				common.ZeroAddress,
+				NewUnmeteredStringValue(strings.Repeat("a", 1_000)),
+				NewUnmeteredStringValue(strings.Repeat("b", 1_000)),
+				NewUnmeteredStringValue("c"),
+			)
+		})
+	})
+
+	t.Run("inlined container", func(t *testing.T) {
+		t.Parallel()
+
+		storage := NewInMemoryStorage(nil)
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		outer := NewArrayValue(
+			inter,
+			EmptyLocationRange,
+			&VariableSizedStaticType{
+				Type: &VariableSizedStaticType{
+					Type: PrimitiveStaticTypeInt,
+				},
+			},
+			common.ZeroAddress,
+			newIntArray(inter, 1),
+		)
+
+		inner := outer.Get(inter, EmptyLocationRange, 0)
+
+		_, err := EstimateStorableSize(inter, inner, atree.Address(address))
+		require.Error(t, err)
+	})
+}
@@ -24,6 +24,13 @@ import (
 	"github.com/onflow/cadence/errors"
 )
 
+// StorageMapKey is a key of a storage map (e.g. DomainStorageMap).
+//
+// Keys of different kinds can be mixed in the same storage map:
+// the hash input of a key only depends on its own value,
+// and keys of different kinds never compare equal,
+// even if their hash inputs are equal.
+// Storage maps with mixed key kinds are iterated in a deterministic order.
 type StorageMapKey interface {
 	isStorageMapKey()
 	AtreeValue() atree.Value
@@ -39,7 +46,7 @@ var _ StorageMapKey = StringStorageMapKey("")
 func (StringStorageMapKey) isStorageMapKey() {}
 
 func (StringStorageMapKey) AtreeValueHashInput(v atree.Value, scratch []byte) ([]byte, error) {
-	return StringAtreeValueHashInput(v, scratch)
+	return StorageMapKeyAtreeValueHashInput(v, scratch)
 }
 
 func (StringStorageMapKey) AtreeValueCompare(
@@ -47,14 +54,17 @@ func (StringStorageMapKey) AtreeValueCompare(
 	value atree.Value,
 	otherStorable atree.Storable,
 ) (bool, error) {
-	return StringAtreeValueComparator(slabStorage, value, otherStorable)
+	return StorageMapKeyAtreeValueComparator(slabStorage, value, otherStorable)
 }
 
 func (k StringStorageMapKey) AtreeValue() atree.Value {
 	return StringAtreeValue(k)
 }
 
-// Uint64StorageMapKey is a StorageMapKey backed by a simple Uint64AtreeValue
+// Uint64StorageMapKey is a StorageMapKey backed by a simple Uint64AtreeValue.
+// It is used for numeric keys, e.g. storage domains in AccountStorageMap,
+// or IDs in ID-indexed domains.
+// The hash input is the 8-byte big-endian encoding of the key.
 type Uint64StorageMapKey Uint64AtreeValue
 
 var _ StorageMapKey = Uint64StorageMapKey(0)
@@ -62,7 +72,7 @@ var _ StorageMapKey = Uint64StorageMapKey(0)
 func (Uint64StorageMapKey) isStorageMapKey() {}
 
 func (Uint64StorageMapKey) AtreeValueHashInput(v atree.Value, scratch []byte) ([]byte, error) {
-	return Uint64AtreeValueHashInput(v, scratch)
+	return StorageMapKeyAtreeValueHashInput(v, scratch)
 }
 
 func (Uint64StorageMapKey) AtreeValueCompare(
@@ -70,41 +80,53 @@ func (Uint64StorageMapKey) AtreeValueCompare(
 	value atree.Value,
 	otherStorable atree.Storable,
 ) (bool, error) {
-	return Uint64AtreeValueComparator(slabStorage, value, otherStorable)
+	return StorageMapKeyAtreeValueComparator(slabStorage, value, otherStorable)
 }
 
 func (k Uint64StorageMapKey) AtreeValue() atree.Value {
 	return Uint64AtreeValue(k)
 }
 
+// convertAtreeValueToStorageMapKey returns the StorageMapKey for the given atree key value.
+func convertAtreeValueToStorageMapKey(value atree.Value) (StorageMapKey, error) {
+	switch value := value.(type) {
+	case StringAtreeValue:
+		return StringStorageMapKey(value), nil
+
+	case Uint64AtreeValue:
+		return Uint64StorageMapKey(value), nil
+
+	default:
+		return nil, errors.NewUnexpectedError("storage map key expected StringAtreeValue or Uint64AtreeValue, got %T", value)
+	}
+}
+
+// StorageMapKeyAtreeValueHashInput returns the hash input of the given storage map key.
+// NOTE: atree might call the hash input function of a key with the value of another key,
+// e.g. on hash collision, so the hash input function must support all kinds of keys.
 func StorageMapKeyAtreeValueHashInput(value atree.Value, scratch []byte) ([]byte, error) {
-	var smk StorageMapKey
 	switch value := value.(type) {
 	case StringAtreeValue:
-		smk = StringStorageMapKey(value)
+		return StringAtreeValueHashInput(value, scratch)
 
 	case Uint64AtreeValue:
-		smk = Uint64StorageMapKey(value)
+		return Uint64AtreeValueHashInput(value, scratch)
 
 	default:
 		return nil, errors.NewUnexpectedError("StorageMapKeyAtreeValueHashInput expected StringAtreeValue or Uint64AtreeValue, got %T", value)
 	}
-
-	return smk.AtreeValueHashInput(value, scratch)
 }
 
+// StorageMapKeyAtreeValueComparator returns true if the given storage map key is equal to the given storable.
 func StorageMapKeyAtreeValueComparator(slabStorage atree.SlabStorage, value atree.Value, otherStorable atree.Storable) (bool, error) {
-	var smk StorageMapKey
 	switch value := value.(type) {
 	case StringAtreeValue:
-		smk = StringStorageMapKey(value)
+		return StringAtreeValueComparator(slabStorage, value, otherStorable)
 
 	case Uint64AtreeValue:
-		smk = Uint64StorageMapKey(value)
+		return Uint64AtreeValueComparator(slabStorage, value, otherStorable)
 
 	default:
 		return false, errors.NewUnexpectedError("StorageMapKeyAtreeValueComparator expected StringAtreeValue or Uint64AtreeValue, got %T", value)
 	}
-
-	return smk.AtreeValueCompare(slabStorage, value, otherStorable)
 }
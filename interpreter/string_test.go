@@ -20,8 +20,13 @@ package interpreter_test
 
 import (
 	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/cadence/common"
@@ -196,6 +201,10 @@ func TestInterpretStringFromUtf8(t *testing.T) {
 		{"[]", ""},
 		// invalid codepoint
 		{"[0xc3, 0x28]", nil},
+		// incomplete sequence at the end
+		{"[0x61, 0xF0, 0x9F, 0x98]", nil},
+		// invalid byte in the middle
+		{"[0x61, 0x80, 0x62]", nil},
 	}
 
 	for _, testCase := range testCases {
@@ -230,6 +239,68 @@ func TestInterpretStringFromUtf8(t *testing.T) {
 	}
 }
 
+func TestInterpretStringFromUtf8Metering(t *testing.T) {
+	t.Parallel()
+
+	const count = 100_000
+
+	test := func(t *testing.T, firstByte byte) (interpreter.Value, uint64) {
+		meter := newTestMemoryGauge()
+
+		inter := parseCheckAndInterpretWithMemoryMetering(
+			t,
+			`
+              fun test(_ bytes: [UInt8]): String? {
+                  return String.fromUTF8(bytes)
+              }
+            `,
+			meter,
+		)
+
+		values := make([]interpreter.Value, count)
+		values[0] = interpreter.NewUnmeteredUInt8Value(firstByte)
+		for i := 1; i < count; i++ {
+			values[i] = interpreter.NewUnmeteredUInt8Value('a')
+		}
+
+		bytes := interpreter.NewArrayValue(
+			inter,
+			interpreter.EmptyLocationRange,
+			&interpreter.VariableSizedStaticType{
+				Type: interpreter.PrimitiveStaticTypeUInt8,
+			},
+			common.ZeroAddress,
+			values...,
+		)
+
+		stringMemoryBefore := meter.getMemory(common.MemoryKindStringValue)
+
+		result, err := inter.Invoke("test", bytes)
+		require.NoError(t, err)
+
+		return result, meter.getMemory(common.MemoryKindStringValue) - stringMemoryBefore
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		result, stringMemory := test(t, 'a')
+
+		require.IsType(t, &interpreter.SomeValue{}, result)
+		require.Equal(t, uint64(count+1), stringMemory)
+	})
+
+	t.Run("invalid start", func(t *testing.T) {
+		t.Parallel()
+
+		result, stringMemory := test(t, 0xFF)
+
+		require.Equal(t, interpreter.Nil, result)
+		// No string is allocated for invalid input
+		require.Equal(t, uint64(0), stringMemory)
+	})
+}
+
 func TestInterpretStringFromCharacters(t *testing.T) {
 
 	t.Parallel()
@@ -251,6 +322,110 @@ func TestInterpretStringFromCharacters(t *testing.T) {
 	)
 }
 
+func TestInterpretStringCodePoints(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("field", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [UInt32] {
+              return "a\u{E9}\u{1F490}\u{10FFFF}".codePoints
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		RequireValuesEqual(
+			t,
+			inter,
+			interpreter.NewArrayValue(
+				inter,
+				interpreter.EmptyLocationRange,
+				&interpreter.VariableSizedStaticType{
+					Type: interpreter.PrimitiveStaticTypeUInt32,
+				},
+				common.ZeroAddress,
+				interpreter.NewUnmeteredUInt32Value('a'),
+				interpreter.NewUnmeteredUInt32Value(0xE9),
+				interpreter.NewUnmeteredUInt32Value(0x1F490),
+				interpreter.NewUnmeteredUInt32Value(0x10FFFF),
+			),
+			result,
+		)
+	})
+
+	t.Run("fromCodePoints", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): String {
+              return String.fromCodePoints([0x46, 0x1F490, 0x20, 0x1F46A])
+          }
+        `)
+
+		result, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		RequireValuesEqual(
+			t,
+			inter,
+			interpreter.NewUnmeteredStringValue("F\U0001F490 \U0001F46A"),
+			result,
+		)
+	})
+
+	t.Run("round-trip", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(_ s: String): Bool {
+              return String.fromCodePoints(s.codePoints) == s
+          }
+        `)
+
+		for _, str := range []string{
+			"",
+			"abc",
+			"Flowers \U0001F490 are beautiful",
+			"👪❤️",
+			"\U00010000\U0010FFFF",
+			"e\u0301",
+		} {
+			result, err := inter.Invoke("test", interpreter.NewUnmeteredStringValue(str))
+			require.NoError(t, err)
+
+			require.Equal(t, interpreter.TrueValue, result, str)
+		}
+	})
+
+	for _, codePoint := range []uint32{0xD800, 0xDBFF, 0xDC00, 0xDFFF, 0x110000, 0xFFFFFFFF} {
+
+		t.Run(fmt.Sprintf("invalid: %X", codePoint), func(t *testing.T) {
+
+			t.Parallel()
+
+			inter := parseCheckAndInterpret(t, `
+              fun test(_ codePoint: UInt32): String {
+                  return String.fromCodePoints([0x41, codePoint])
+              }
+            `)
+
+			_, err := inter.Invoke("test", interpreter.NewUnmeteredUInt32Value(codePoint))
+			RequireError(t, err)
+
+			var typedErr interpreter.InvalidCodePointError
+			require.ErrorAs(t, err, &typedErr)
+			require.Equal(t, codePoint, typedErr.CodePoint)
+		})
+	}
+}
+
 func TestInterpretStringUtf8Field(t *testing.T) {
 
 	t.Parallel()
@@ -309,126 +484,531 @@ func TestInterpretStringUtf8Field(t *testing.T) {
 	)
 }
 
-func TestInterpretStringToLower(t *testing.T) {
+func TestInterpretStringToBytes(t *testing.T) {
 
 	t.Parallel()
 
 	inter := parseCheckAndInterpret(t, `
-      fun test(): String {
-          return "Flowers".toLower()
+      fun test(_ s: String): Bool {
+          let bytes = s.toBytes()
+          if bytes.length != s.utf8.length {
+              return false
+          }
+          var i = 0
+          while i < bytes.length {
+              if bytes[i] != s.utf8[i] {
+                  return false
+              }
+              i = i + 1
+          }
+          return true
       }
     `)
 
-	result, err := inter.Invoke("test")
-	require.NoError(t, err)
+	for _, s := range []string{
+		"",
+		"abc",
+		"Flowers \U0001F490 are beautiful",
+		"caf\u00e9 \u65e5\u672c\u8a9e \U0001F469\u200D\U0001F4BB",
+	} {
+		result, err := inter.Invoke("test", interpreter.NewUnmeteredStringValue(s))
+		require.NoError(t, err)
 
-	require.Equal(t,
-		interpreter.NewUnmeteredStringValue("flowers"),
-		result,
-	)
+		require.Equal(t, interpreter.TrueValue, result, s)
+	}
 }
 
-func TestInterpretStringAccess(t *testing.T) {
+func TestInterpretStringIsASCII(t *testing.T) {
 
 	t.Parallel()
 
 	inter := parseCheckAndInterpret(t, `
-    fun test(): Type {
-        let c: Character = "x"[0]
-        return c.getType() 
-    }
+      fun test(_ s: String): Bool {
+          return s.isASCII
+      }
     `)
 
-	result, err := inter.Invoke("test")
-	require.NoError(t, err)
+	type testCase struct {
+		input    string
+		expected bool
+	}
 
-	require.Equal(t,
-		interpreter.TypeValue{Type: interpreter.PrimitiveStaticTypeCharacter},
-		result,
-	)
+	for _, testCase := range []testCase{
+		{input: "", expected: true},
+		{input: "abc", expected: true},
+		{input: "Hello, World!\n", expected: true},
+		{input: "caf\u00e9", expected: false},
+		// Letter with combining low line, which has no precomposed form
+		{input: "a\u0332", expected: false},
+		{input: "abc\U0001F46A", expected: false},
+	} {
+		t.Run(testCase.input, func(t *testing.T) {
+
+			result, err := inter.Invoke("test", interpreter.NewUnmeteredStringValue(testCase.input))
+			require.NoError(t, err)
+
+			RequireValuesEqual(
+				t,
+				inter,
+				interpreter.BoolValue(testCase.expected),
+				result,
+			)
+		})
+	}
 }
 
-func TestInterpretCharacterLiteralType(t *testing.T) {
+func TestInterpretStringByteLengthAndScalarCount(t *testing.T) {
 
 	t.Parallel()
 
 	inter := parseCheckAndInterpret(t, `
-    fun test(): Type {
-        let c: Character = "x"
-        return c.getType() 
-    }
+      fun test(_ s: String): [Int] {
+          return [s.length, s.scalarCount, s.byteLength]
+      }
     `)
 
-	result, err := inter.Invoke("test")
-	require.NoError(t, err)
+	type testCase struct {
+		input       string
+		length      int64
+		scalarCount int64
+		byteLength  int64
+	}
 
-	require.Equal(t,
-		interpreter.TypeValue{Type: interpreter.PrimitiveStaticTypeCharacter},
-		result,
-	)
+	for _, testCase := range []testCase{
+		{input: "", length: 0, scalarCount: 0, byteLength: 0},
+		{input: "abc", length: 3, scalarCount: 3, byteLength: 3},
+		// Letter with combining low line, which has no precomposed form
+		{input: "a\u0332", length: 1, scalarCount: 2, byteLength: 3},
+		// Emoji with skin tone modifier
+		{input: "\U0001F44D\U0001F3FD", length: 1, scalarCount: 2, byteLength: 8},
+		// Emoji with combining enclosing keycap
+		{input: "a\u0332\U0001F44D\U0001F3FD1\uFE0F\u20E3", length: 3, scalarCount: 7, byteLength: 18},
+	} {
+		t.Run(testCase.input, func(t *testing.T) {
+
+			result, err := inter.Invoke("test", interpreter.NewUnmeteredStringValue(testCase.input))
+			require.NoError(t, err)
+
+			RequireValuesEqual(
+				t,
+				inter,
+				interpreter.NewArrayValue(
+					inter,
+					interpreter.EmptyLocationRange,
+					&interpreter.VariableSizedStaticType{
+						Type: interpreter.PrimitiveStaticTypeInt,
+					},
+					common.ZeroAddress,
+					interpreter.NewUnmeteredIntValueFromInt64(testCase.length),
+					interpreter.NewUnmeteredIntValueFromInt64(testCase.scalarCount),
+					interpreter.NewUnmeteredIntValueFromInt64(testCase.byteLength),
+				),
+				result,
+			)
+		})
+	}
 }
 
-func TestInterpretOneCharacterStringLiteralType(t *testing.T) {
+func TestInterpretStringMatches(t *testing.T) {
 
 	t.Parallel()
 
 	inter := parseCheckAndInterpret(t, `
-    fun test(): Type {
-        let c: String = "x"
-        return c.getType() 
-    }
+      fun test(_ s: String, _ pattern: String): Bool {
+          return s.matches(pattern: pattern)
+      }
     `)
 
-	result, err := inter.Invoke("test")
-	require.NoError(t, err)
+	type testCase struct {
+		input    string
+		pattern  string
+		expected bool
+	}
 
-	require.Equal(t,
-		interpreter.TypeValue{Type: interpreter.PrimitiveStaticTypeString},
-		result,
-	)
+	for _, testCase := range []testCase{
+		// Literals, anchored at both ends
+		{input: "", pattern: "", expected: true},
+		{input: "abc", pattern: "abc", expected: true},
+		{input: "abc", pattern: "ab", expected: false},
+		{input: "abc", pattern: "bc", expected: false},
+		{input: "abc", pattern: "", expected: false},
+		{input: "", pattern: "a", expected: false},
+
+		// Any sequence
+		{input: "", pattern: "*", expected: true},
+		{input: "abc", pattern: "*", expected: true},
+		{input: "abc", pattern: "a*", expected: true},
+		{input: "abc", pattern: "*c", expected: true},
+		{input: "abc", pattern: "a*c", expected: true},
+		{input: "abc", pattern: "a*b*c", expected: true},
+		{input: "abc", pattern: "**", expected: true},
+		{input: "abc", pattern: "*b", expected: false},
+		{input: "abcbc", pattern: "a*bc", expected: true},
+		{input: "key.1.value", pattern: "key.*.value", expected: true},
+		{input: "key.1.other", pattern: "key.*.value", expected: false},
+
+		// Any character
+		{input: "abc", pattern: "a?c", expected: true},
+		{input: "abc", pattern: "???", expected: true},
+		{input: "abc", pattern: "??", expected: false},
+		{input: "abc", pattern: "????", expected: false},
+		{input: "", pattern: "?", expected: false},
+		{input: "abc", pattern: "?*", expected: true},
+
+		// Escapes
+		{input: "a*c", pattern: "a\\*c", expected: true},
+		{input: "abc", pattern: "a\\*c", expected: false},
+		{input: "a?c", pattern: "a\\?c", expected: true},
+		{input: "abc", pattern: "a\\?c", expected: false},
+		{input: "a\\c", pattern: "a\\\\c", expected: true},
+		{input: "a\\", pattern: "a\\", expected: true},
+		{input: "ab", pattern: "a\\b", expected: true},
+
+		// Multibyte characters: '?' matches one grapheme
+		{input: "\u00e9", pattern: "?", expected: true},
+		{input: "\U0001F44D\U0001F3FD", pattern: "?", expected: true},
+		{input: "\U0001F44D\U0001F3FD", pattern: "??", expected: false},
+		{input: "a\u0332b", pattern: "?b", expected: true},
+		{input: "a\u0332b", pattern: "??", expected: true},
+		{input: "a\u0332b", pattern: "???", expected: false},
+		// A literal character does not match a part of a grapheme
+		{input: "a\u0332b", pattern: "a?", expected: false},
+		{input: "\U0001F469\u200D\U0001F4BB!", pattern: "?!", expected: true},
+		{input: "\u65e5\u672c\u8a9e", pattern: "\u65e5*", expected: true},
+	} {
+		t.Run(testCase.input+" "+testCase.pattern, func(t *testing.T) {
+
+			result, err := inter.Invoke(
+				"test",
+				interpreter.NewUnmeteredStringValue(testCase.input),
+				interpreter.NewUnmeteredStringValue(testCase.pattern),
+			)
+			require.NoError(t, err)
+
+			require.Equal(t,
+				interpreter.BoolValue(testCase.expected),
+				result,
+			)
+		})
+	}
 }
 
-func TestInterpretCharacterLiteralTypeNoAnnotation(t *testing.T) {
+func TestInterpretStringRepeat(t *testing.T) {
 
 	t.Parallel()
 
 	inter := parseCheckAndInterpret(t, `
-    fun test(): Type {
-        let c = "x"
-        return c.getType() 
-    }
+      fun test(_ s: String, _ count: Int): String {
+          return s.repeat(count)
+      }
     `)
 
-	result, err := inter.Invoke("test")
-	require.NoError(t, err)
+	type test struct {
+		str    string
+		count  int64
+		result string
+	}
 
-	require.Equal(t,
-		interpreter.TypeValue{Type: interpreter.PrimitiveStaticTypeString},
-		result,
-	)
+	for _, test := range []test{
+		{"abc", 0, ""},
+		{"abc", 1, "abc"},
+		{"abc", 3, "abcabcabc"},
+		{"", 10, ""},
+		{"☺", 2, "☺☺"},
+	} {
+		result, err := inter.Invoke(
+			"test",
+			interpreter.NewUnmeteredStringValue(test.str),
+			interpreter.NewUnmeteredIntValueFromInt64(test.count),
+		)
+		require.NoError(t, err)
+
+		RequireValuesEqual(
+			t,
+			inter,
+			interpreter.NewUnmeteredStringValue(test.result),
+			result,
+		)
+	}
+
+	t.Run("negative count", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := inter.Invoke(
+			"test",
+			interpreter.NewUnmeteredStringValue("abc"),
+			interpreter.NewUnmeteredIntValueFromInt64(-1),
+		)
+		RequireError(t, err)
+
+		var typedErr interpreter.NegativeStringRepeatCountError
+		require.ErrorAs(t, err, &typedErr)
+		require.Equal(t, -1, typedErr.Count)
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := inter.Invoke(
+			"test",
+			interpreter.NewUnmeteredStringValue("abc"),
+			interpreter.NewUnmeteredIntValueFromInt64(math.MaxInt64/2),
+		)
+		RequireError(t, err)
+
+		require.ErrorAs(t, err, &interpreter.OverflowError{})
+	})
 }
 
-func TestInterpretConvertCharacterToString(t *testing.T) {
+func TestInterpretStringCenter(t *testing.T) {
 
 	t.Parallel()
 
 	inter := parseCheckAndInterpret(t, `
-    fun test(): String {
-        let c: Character = "x"
-        return c.toString()
-    }
+      fun test(_ s: String, _ width: Int, _ c: Character): String {
+          return s.center(width: width, with: c)
+      }
     `)
 
-	result, err := inter.Invoke("test")
-	require.NoError(t, err)
+	type test struct {
+		str       string
+		width     int64
+		character string
+		result    string
+	}
 
-	require.Equal(t,
-		interpreter.NewUnmeteredStringValue("x"),
-		result,
-	)
+	for _, test := range []test{
+		// even padding
+		{"ab", 6, "*", "**ab**"},
+		{"", 2, "*", "**"},
+		// odd padding, extra character on the right
+		{"ab", 5, "*", "*ab**"},
+		{"abc", 4, "*", "abc*"},
+		// already wide enough
+		{"abc", 3, "*", "abc"},
+		{"abc", 2, "*", "abc"},
+		{"abc", 0, "*", "abc"},
+		{"abc", -1, "*", "abc"},
+		// width is in characters, not bytes
+		{"☺", 3, "-", "-☺-"},
+		{"a\u0332", 3, "-", "-a\u0332-"},
+		// multibyte fill
+		{"ab", 5, "☺", "☺ab☺☺"},
+		{"ab", 4, "👪", "👪ab👪"},
+		{"ab", 4, "a\u0332", "a\u0332aba\u0332"},
+	} {
+		result, err := inter.Invoke(
+			"test",
+			interpreter.NewUnmeteredStringValue(test.str),
+			interpreter.NewUnmeteredIntValueFromInt64(test.width),
+			interpreter.NewUnmeteredCharacterValue(test.character),
+		)
+		require.NoError(t, err)
+
+		RequireValuesEqual(
+			t,
+			inter,
+			interpreter.NewUnmeteredStringValue(test.result),
+			result,
+		)
+	}
+
+	t.Run("overflow", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := inter.Invoke(
+			"test",
+			interpreter.NewUnmeteredStringValue("abc"),
+			interpreter.NewUnmeteredIntValueFromInt64(math.MaxInt64),
+			interpreter.NewUnmeteredCharacterValue("👪"),
+		)
+		RequireError(t, err)
+
+		require.ErrorAs(t, err, &interpreter.OverflowError{})
+	})
 }
 
-func TestInterpretCompareCharacters(t *testing.T) {
+func TestInterpretStringToLower(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      fun test(): String {
+          return "Flowers".toLower()
+      }
+    `)
+
+	result, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	require.Equal(t,
+		interpreter.NewUnmeteredStringValue("flowers"),
+		result,
+	)
+}
+
+func TestInterpretStringTrimStartAndTrimEnd(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      fun trimStart(_ s: String): String {
+          return s.trimStart()
+      }
+
+      fun trimEnd(_ s: String): String {
+          return s.trimEnd()
+      }
+    `)
+
+	type testCase struct {
+		input     string
+		trimStart string
+		trimEnd   string
+	}
+
+	for _, testCase := range []testCase{
+		{input: "", trimStart: "", trimEnd: ""},
+		{input: "abc", trimStart: "abc", trimEnd: "abc"},
+		{input: "  abc  ", trimStart: "abc  ", trimEnd: "  abc"},
+		// Interior whitespace is preserved
+		{input: " a  b\tc ", trimStart: "a  b\tc ", trimEnd: " a  b\tc"},
+		// Various Unicode whitespace
+		{input: "\t\n\r\n\u00A0\u2003abc\u3000\v\f", trimStart: "abc\u3000\v\f", trimEnd: "\t\n\r\n\u00A0\u2003abc"},
+		{input: " \t\n ", trimStart: "", trimEnd: ""},
+		// Whitespace with combining low line is not only whitespace
+		{input: " \u0332abc ", trimStart: " \u0332abc ", trimEnd: " \u0332abc"},
+		{input: " abc \u0332", trimStart: "abc \u0332", trimEnd: " abc \u0332"},
+	} {
+		t.Run(testCase.input, func(t *testing.T) {
+
+			result, err := inter.Invoke("trimStart", interpreter.NewUnmeteredStringValue(testCase.input))
+			require.NoError(t, err)
+
+			RequireValuesEqual(
+				t,
+				inter,
+				interpreter.NewUnmeteredStringValue(testCase.trimStart),
+				result,
+			)
+
+			result, err = inter.Invoke("trimEnd", interpreter.NewUnmeteredStringValue(testCase.input))
+			require.NoError(t, err)
+
+			RequireValuesEqual(
+				t,
+				inter,
+				interpreter.NewUnmeteredStringValue(testCase.trimEnd),
+				result,
+			)
+		})
+	}
+}
+
+func TestInterpretStringAccess(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+    fun test(): Type {
+        let c: Character = "x"[0]
+        return c.getType() 
+    }
+    `)
+
+	result, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	require.Equal(t,
+		interpreter.TypeValue{Type: interpreter.PrimitiveStaticTypeCharacter},
+		result,
+	)
+}
+
+func TestInterpretCharacterLiteralType(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+    fun test(): Type {
+        let c: Character = "x"
+        return c.getType() 
+    }
+    `)
+
+	result, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	require.Equal(t,
+		interpreter.TypeValue{Type: interpreter.PrimitiveStaticTypeCharacter},
+		result,
+	)
+}
+
+func TestInterpretOneCharacterStringLiteralType(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+    fun test(): Type {
+        let c: String = "x"
+        return c.getType() 
+    }
+    `)
+
+	result, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	require.Equal(t,
+		interpreter.TypeValue{Type: interpreter.PrimitiveStaticTypeString},
+		result,
+	)
+}
+
+func TestInterpretCharacterLiteralTypeNoAnnotation(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+    fun test(): Type {
+        let c = "x"
+        return c.getType() 
+    }
+    `)
+
+	result, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	require.Equal(t,
+		interpreter.TypeValue{Type: interpreter.PrimitiveStaticTypeString},
+		result,
+	)
+}
+
+func TestInterpretConvertCharacterToString(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+    fun test(): String {
+        let c: Character = "x"
+        return c.toString()
+    }
+    `)
+
+	result, err := inter.Invoke("test")
+	require.NoError(t, err)
+
+	require.Equal(t,
+		interpreter.NewUnmeteredStringValue("x"),
+		result,
+	)
+}
+
+func TestInterpretCompareCharacters(t *testing.T) {
 
 	t.Parallel()
 
@@ -501,74 +1081,979 @@ func TestInterpretStringJoin(t *testing.T) {
 	testCase(t, "testSingletonArray", interpreter.NewUnmeteredStringValue("pqrS"))
 }
 
-func TestInterpretStringSplit(t *testing.T) {
+const stringConcatAllProgram = `
+  fun concatAll(_ strings: [String]): String {
+      return String.concatAll(strings)
+  }
+
+  fun concatFold(_ strings: [String]): String {
+      var result = ""
+      for s in strings {
+          result = result.concat(s)
+      }
+      return result
+  }
+`
+
+func TestInterpretStringConcatAll(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, stringConcatAllProgram)
+
+	for _, strs := range [][]string{
+		{},
+		{""},
+		{"pqrS"},
+		{"", ""},
+		{"a", "", "b"},
+		{"👪", "❤️", "Abc"},
+		{"e", "\u0301", "\U0001F44D", "\U0001F3FD"},
+	} {
+		t.Run(strings.Join(strs, ","), func(t *testing.T) {
+
+			elements := make([]interpreter.Value, 0, len(strs))
+			for _, str := range strs {
+				elements = append(elements, interpreter.NewUnmeteredStringValue(str))
+			}
+
+			newArray := func() *interpreter.ArrayValue {
+				return interpreter.NewArrayValue(
+					inter,
+					interpreter.EmptyLocationRange,
+					&interpreter.VariableSizedStaticType{
+						Type: interpreter.PrimitiveStaticTypeString,
+					},
+					common.ZeroAddress,
+					elements...,
+				)
+			}
+
+			result, err := inter.Invoke("concatAll", newArray())
+			require.NoError(t, err)
+
+			expected, err := inter.Invoke("concatFold", newArray())
+			require.NoError(t, err)
+
+			RequireValuesEqual(t, inter, expected, result)
+		})
+	}
+}
+
+func BenchmarkInterpretStringConcatAll(b *testing.B) {
+
+	inter := parseCheckAndInterpret(b, stringConcatAllProgram)
+
+	elements := make([]interpreter.Value, 0, 100)
+	for i := 0; i < 100; i++ {
+		elements = append(elements, interpreter.NewUnmeteredStringValue(strconv.Itoa(i)))
+	}
+
+	array := interpreter.NewArrayValue(
+		inter,
+		interpreter.EmptyLocationRange,
+		&interpreter.VariableSizedStaticType{
+			Type: interpreter.PrimitiveStaticTypeString,
+		},
+		common.ZeroAddress,
+		elements...,
+	)
+
+	for _, functionName := range []string{"concatAll", "concatFold"} {
+		b.Run(functionName, func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				_, err := inter.Invoke(functionName, array)
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
+func TestInterpretStringFormat(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+		struct Inner {
+			let flag: Bool
+
+			init(flag: Bool) {
+				self.flag = flag
+			}
+		}
+
+		struct Outer {
+			let name: String
+			let inner: Inner
+
+			init(name: String, inner: Inner) {
+				self.name = name
+				self.inner = inner
+			}
+		}
+
+		fun testIntegers(): String {
+			return String.format("{0} + {1} = {2}", arguments: [1, 2 as UInt8, -3 as Int64])
+		}
+
+		fun testBools(): String {
+			return String.format("{1} and {0} and {1}", arguments: [true, false])
+		}
+
+		fun testStrings(): String {
+			return String.format("Hello, {0}{1}", arguments: ["👪", "!" as Character])
+		}
+
+		fun testNestedStructs(): String {
+			return String.format(
+				"outer: {0}",
+				arguments: [Outer(name: "a", inner: Inner(flag: true))]
+			)
+		}
+
+		fun testEscapedBraces(): String {
+			return String.format("{{{0}}} {{}}", arguments: [42])
+		}
+
+		fun testNoPlaceholders(): String {
+			return String.format("plain", arguments: [])
+		}
+	`)
+
+	testCase := func(t *testing.T, funcName string, expected *interpreter.StringValue) {
+		t.Run(funcName, func(t *testing.T) {
+			result, err := inter.Invoke(funcName)
+			require.NoError(t, err)
+
+			RequireValuesEqual(
+				t,
+				inter,
+				expected,
+				result,
+			)
+		})
+	}
+
+	testCase(t, "testIntegers", interpreter.NewUnmeteredStringValue("1 + 2 = -3"))
+	testCase(t, "testBools", interpreter.NewUnmeteredStringValue("false and true and false"))
+	testCase(t, "testStrings", interpreter.NewUnmeteredStringValue("Hello, 👪!"))
+	testCase(
+		t,
+		"testNestedStructs",
+		interpreter.NewUnmeteredStringValue(
+			`outer: S.test.Outer(name: "a", inner: S.test.Inner(flag: true))`,
+		),
+	)
+	testCase(t, "testEscapedBraces", interpreter.NewUnmeteredStringValue("{42} {}"))
+	testCase(t, "testNoPlaceholders", interpreter.NewUnmeteredStringValue("plain"))
+
+	t.Run("index out of bounds", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+			fun test(): String {
+				return String.format("{0} {2}", arguments: [1, 2])
+			}
+		`)
+
+		_, err := inter.Invoke("test")
+		RequireError(t, err)
+
+		var typedErr interpreter.StringFormatArgumentIndexOutOfBoundsError
+		require.ErrorAs(t, err, &typedErr)
+		require.Equal(t, 2, typedErr.Index)
+		require.Equal(t, 2, typedErr.Count)
+	})
+
+	for _, format := range []string{"{", "}", "{0", "{}", "{a}", "{-1}", "{+0}", "a } b"} {
+
+		t.Run(fmt.Sprintf("invalid format: %s", format), func(t *testing.T) {
+
+			t.Parallel()
+
+			inter := parseCheckAndInterpret(t, fmt.Sprintf(
+				`
+					fun test(): String {
+						return String.format("%s", arguments: [1])
+					}
+				`,
+				format,
+			))
+
+			_, err := inter.Invoke("test")
+			RequireError(t, err)
+
+			var typedErr interpreter.InvalidStringFormatError
+			require.ErrorAs(t, err, &typedErr)
+			require.Equal(t, format, typedErr.Format)
+		})
+	}
+}
+
+func TestInterpretStringToInt(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+		fun testToInt(_ string: String): Int? {
+			return String.toInt(string)
+		}
+
+		fun testToUInt(_ string: String): UInt? {
+			return String.toUInt(string)
+		}
+	`)
+
+	invoke := func(t *testing.T, funcName string, input string) interpreter.Value {
+		result, err := inter.Invoke(
+			funcName,
+			interpreter.NewUnmeteredStringValue(input),
+		)
+		require.NoError(t, err)
+		return result
+	}
+
+	hugeInt, ok := new(big.Int).SetString("123456789012345678901234567890123456789", 10)
+	require.True(t, ok)
+
+	t.Run("toInt, valid", func(t *testing.T) {
+		t.Parallel()
+
+		for input, expected := range map[string]*big.Int{
+			"0":   big.NewInt(0),
+			"42":  big.NewInt(42),
+			"+42": big.NewInt(42),
+			"-42": big.NewInt(-42),
+			"007": big.NewInt(7),
+			"123456789012345678901234567890123456789":  hugeInt,
+			"-123456789012345678901234567890123456789": new(big.Int).Neg(hugeInt),
+		} {
+			RequireValuesEqual(
+				t,
+				inter,
+				interpreter.NewUnmeteredSomeValueNonCopying(
+					interpreter.NewUnmeteredIntValueFromBigInt(expected),
+				),
+				invoke(t, "testToInt", input),
+			)
+		}
+	})
+
+	t.Run("toUInt, valid", func(t *testing.T) {
+		t.Parallel()
+
+		for input, expected := range map[string]*big.Int{
+			"0":   big.NewInt(0),
+			"42":  big.NewInt(42),
+			"+42": big.NewInt(42),
+			"-0":  big.NewInt(0),
+			"123456789012345678901234567890123456789": hugeInt,
+		} {
+			RequireValuesEqual(
+				t,
+				inter,
+				interpreter.NewUnmeteredSomeValueNonCopying(
+					interpreter.NewUnmeteredUIntValueFromBigInt(expected),
+				),
+				invoke(t, "testToUInt", input),
+			)
+		}
+	})
+
+	malformed := []string{
+		"",
+		" ",
+		" 42",
+		"42 ",
+		"+",
+		"-",
+		"--42",
+		"4 2",
+		"4_2",
+		"0x2a",
+		"42.0",
+		"1e3",
+		"abc",
+		"٤٢",
+	}
+
+	t.Run("toInt, malformed", func(t *testing.T) {
+		t.Parallel()
+
+		for _, input := range malformed {
+			assert.Equal(t,
+				interpreter.Nil,
+				invoke(t, "testToInt", input),
+				input,
+			)
+		}
+	})
+
+	t.Run("toUInt, malformed", func(t *testing.T) {
+		t.Parallel()
+
+		for _, input := range malformed {
+			assert.Equal(t,
+				interpreter.Nil,
+				invoke(t, "testToUInt", input),
+				input,
+			)
+		}
+	})
+
+	t.Run("toUInt, negative", func(t *testing.T) {
+		t.Parallel()
+
+		for _, input := range []string{
+			"-1",
+			"-123456789012345678901234567890123456789",
+		} {
+			assert.Equal(t,
+				interpreter.Nil,
+				invoke(t, "testToUInt", input),
+				input,
+			)
+		}
+	})
+}
+
+func TestInterpretStringToFixedPoint(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+		fun testToFix64(_ string: String): Fix64? {
+			return String.toFix64(string)
+		}
+
+		fun testToUFix64(_ string: String): UFix64? {
+			return String.toUFix64(string)
+		}
+
+		fun testLiterals(): [AnyStruct] {
+			return [
+				String.toFix64("92233720368.54775807") == 92233720368.54775807,
+				String.toFix64("-92233720368.54775808") == -92233720368.54775808,
+				String.toUFix64("184467440737.09551615") == 184467440737.09551615,
+				String.toUFix64("0.00000001") == 0.00000001,
+				String.toFix64("-1.5") == -1.5
+			]
+		}
+	`)
+
+	invoke := func(t *testing.T, funcName string, input string) interpreter.Value {
+		result, err := inter.Invoke(
+			funcName,
+			interpreter.NewUnmeteredStringValue(input),
+		)
+		require.NoError(t, err)
+		return result
+	}
+
+	t.Run("toFix64, valid", func(t *testing.T) {
+		t.Parallel()
+
+		for input, expected := range map[string]int64{
+			"0.0":                   0,
+			"1.0":                   100_000_000,
+			"-1.5":                  -150_000_000,
+			"1.23":                  123_000_000,
+			"0.00000001":            1,
+			"-0.00000001":           -1,
+			"1.12345678":            112_345_678,
+			"92233720368.54775807":  math.MaxInt64,
+			"-92233720368.54775808": math.MinInt64,
+		} {
+			RequireValuesEqual(
+				t,
+				inter,
+				interpreter.NewUnmeteredSomeValueNonCopying(
+					interpreter.NewUnmeteredFix64Value(expected),
+				),
+				invoke(t, "testToFix64", input),
+			)
+		}
+	})
+
+	t.Run("toUFix64, valid", func(t *testing.T) {
+		t.Parallel()
+
+		for input, expected := range map[string]uint64{
+			"0.0":                   0,
+			"1.0":                   100_000_000,
+			"1.5":                   150_000_000,
+			"0.00000001":            1,
+			"1.12345678":            112_345_678,
+			"184467440737.09551615": math.MaxUint64,
+		} {
+			RequireValuesEqual(
+				t,
+				inter,
+				interpreter.NewUnmeteredSomeValueNonCopying(
+					interpreter.NewUnmeteredUFix64Value(expected),
+				),
+				invoke(t, "testToUFix64", input),
+			)
+		}
+	})
+
+	t.Run("toFix64, invalid", func(t *testing.T) {
+		t.Parallel()
+
+		for _, input := range []string{
+			// out of range
+			"92233720368.54775808",
+			"-92233720368.54775809",
+			"100000000000.0",
+			// too many decimals
+			"1.123456789",
+			"0.000000001",
+			// malformed
+			"",
+			"1",
+			"-1",
+			"1.",
+			".1",
+			" 1.0",
+			"1.0 ",
+			"1.-0",
+			"1.0.0",
+			"1,0",
+			"abc",
+		} {
+			assert.Equal(t,
+				interpreter.Nil,
+				invoke(t, "testToFix64", input),
+				input,
+			)
+		}
+	})
+
+	t.Run("toUFix64, invalid", func(t *testing.T) {
+		t.Parallel()
+
+		for _, input := range []string{
+			// out of range
+			"184467440737.09551616",
+			"1000000000000.0",
+			"-1.0",
+			"-0.00000001",
+			// too many decimals
+			"1.123456789",
+			"0.000000001",
+			// malformed
+			"",
+			"1",
+			"1.",
+			".1",
+			" 1.0",
+			"1.0 ",
+			"1.+0",
+			"1.0.0",
+			"abc",
+		} {
+			assert.Equal(t,
+				interpreter.Nil,
+				invoke(t, "testToUFix64", input),
+				input,
+			)
+		}
+	})
+
+	t.Run("literals", func(t *testing.T) {
+		t.Parallel()
+
+		result, err := inter.Invoke("testLiterals")
+		require.NoError(t, err)
+
+		array := result.(*interpreter.ArrayValue)
+		for i := 0; i < array.Count(); i++ {
+			assert.Equal(t,
+				interpreter.TrueValue,
+				array.Get(inter, interpreter.EmptyLocationRange, i),
+			)
+		}
+	})
+}
+
+func TestInterpretStringSplit(t *testing.T) {
+
+	t.Parallel()
+
+	type test struct {
+		str    string
+		sep    string
+		result []string
+	}
+
+	var abcd = "abcd"
+	var faces = "☺☻☹"
+	var commas = "1,2,3,4"
+	var dots = "1....2....3....4"
+
+	tests := []test{
+		{"", "", []string{}},
+		{abcd, "", []string{"a", "b", "c", "d"}},
+		{faces, "", []string{"☺", "☻", "☹"}},
+		{"☺�☹", "", []string{"☺", "�", "☹"}},
+		{abcd, "a", []string{"", "bcd"}},
+		{abcd, "z", []string{"abcd"}},
+		{commas, ",", []string{"1", "2", "3", "4"}},
+		{dots, "...", []string{"1", ".2", ".3", ".4"}},
+		{faces, "☹", []string{"☺☻", ""}},
+		{faces, "~", []string{faces}},
+		{
+			"\\u{1F46A}////\\u{2764}\\u{FE0F}",
+			"////",
+			[]string{"\U0001F46A", "\u2764\uFE0F"},
+		},
+		{
+			"\\u{1F46A} \\u{2764}\\u{FE0F} Abc6 ;123",
+			" ",
+			[]string{"\U0001F46A", "\u2764\uFE0F", "Abc6", ";123"},
+		},
+		{
+			"Caf\\u{65}\\u{301}ABc",
+			"\\u{e9}",
+			[]string{"Caf", "ABc"},
+		},
+		{
+			"",
+			"//",
+			[]string{""},
+		},
+		{
+			"pqrS;asdf",
+			";;",
+			[]string{"pqrS;asdf"},
+		},
+		{
+			// U+1F476 U+1F3FB is 👶🏻
+			" \\u{1F476}\\u{1F3FB} ascii \\u{D}\\u{A}",
+			" ",
+			[]string{"", "\U0001F476\U0001F3FB", "ascii", "\u000D\u000A"},
+		},
+		// 🇪🇸🇸🇪🇪🇪 is "ES", "SE", "EE"
+		{
+			"\\u{1F1EA}\\u{1F1F8}\\u{1F1F8}\\u{1F1EA}\\u{1F1EA}\\u{1F1EA}",
+			"\\u{1F1F8}\\u{1F1EA}",
+			[]string{"\U0001F1EA\U0001F1F8", "\U0001F1EA\U0001F1EA"},
+		},
+	}
+
+	runTest := func(test test) {
+
+		name := fmt.Sprintf("%s, %s", test.str, test.sep)
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			inter := parseCheckAndInterpret(t,
+				fmt.Sprintf(
+					`
+                      fun test(): [String] {
+                        let s = "%s"
+                        return s.split(separator: "%s")
+                      }
+                    `,
+					test.str,
+					test.sep,
+				),
+			)
+
+			value, err := inter.Invoke("test")
+			require.NoError(t, err)
+
+			require.IsType(t, &interpreter.ArrayValue{}, value)
+			actual := value.(*interpreter.ArrayValue)
+
+			require.Equal(t, len(test.result), actual.Count())
+
+			for partIndex, expected := range test.result {
+				actualPart := actual.Get(
+					inter,
+					interpreter.EmptyLocationRange,
+					partIndex,
+				)
+
+				require.IsType(t, &interpreter.StringValue{}, actualPart)
+				actualPartString := actualPart.(*interpreter.StringValue)
+
+				require.Equal(t, expected, actualPartString.Str)
+			}
+		})
+	}
+
+	for _, test := range tests {
+		runTest(test)
+	}
+}
+
+func TestInterpretStringSplitIndices(t *testing.T) {
+
+	t.Parallel()
+
+	inter := parseCheckAndInterpret(t, `
+      fun splitIndices(_ s: String, _ separator: String): [Int] {
+          return s.splitIndices(separator: separator)
+      }
+
+      fun split(_ s: String, _ separator: String): [String] {
+          return s.split(separator: separator)
+      }
+
+      /// Slices the parts of the string using the split indices
+      fun sliceParts(_ s: String, _ separator: String): [String] {
+          let indices = s.splitIndices(separator: separator)
+          let parts: [String] = []
+          var i = 0
+          while i < indices.length {
+              var upTo = s.length
+              if i + 1 < indices.length {
+                  upTo = indices[i + 1] - separator.length
+              }
+              parts.append(s.slice(from: indices[i], upTo: upTo))
+              i = i + 1
+          }
+          return parts
+      }
+    `)
+
+	type test struct {
+		str     string
+		sep     string
+		indices []int64
+	}
+
+	tests := []test{
+		{"", "", []int64{}},
+		{"abcd", "", []int64{0, 1, 2, 3}},
+		{"☺☻☹", "", []int64{0, 1, 2}},
+		{"abcd", "a", []int64{0, 1}},
+		{"abcd", "z", []int64{0}},
+		{"1,2,3,4", ",", []int64{0, 2, 4, 6}},
+		{",1,,2,", ",", []int64{0, 1, 3, 4, 6}},
+		{"1....2....3....4", "...", []int64{0, 4, 9, 14}},
+		{"☺☻☹", "☹", []int64{0, 3}},
+		{"\U0001F46A////\u2764\uFE0F", "////", []int64{0, 5}},
+		{"a\u0332,b\u0332,c", ",", []int64{0, 2, 4}},
+		{"", "//", []int64{0}},
+		{"pqrS;asdf", ";;", []int64{0}},
+		// 🇪🇸🇸🇪🇪🇪 is "ES", "SE", "EE"
+		{"\U0001F1EA\U0001F1F8\U0001F1F8\U0001F1EA\U0001F1EA\U0001F1EA", "\U0001F1F8\U0001F1EA", []int64{0, 2}},
+	}
+
+	for _, test := range tests {
+
+		t.Run(fmt.Sprintf("%s, %s", test.str, test.sep), func(t *testing.T) {
+
+			str := interpreter.NewUnmeteredStringValue(test.str)
+			sep := interpreter.NewUnmeteredStringValue(test.sep)
+
+			indices, err := inter.Invoke("splitIndices", str, sep)
+			require.NoError(t, err)
+
+			expectedIndices := make([]interpreter.Value, 0, len(test.indices))
+			for _, index := range test.indices {
+				expectedIndices = append(expectedIndices, interpreter.NewUnmeteredIntValueFromInt64(index))
+			}
+
+			RequireValuesEqual(
+				t,
+				inter,
+				interpreter.NewArrayValue(
+					inter,
+					interpreter.EmptyLocationRange,
+					&interpreter.VariableSizedStaticType{
+						Type: interpreter.PrimitiveStaticTypeInt,
+					},
+					common.ZeroAddress,
+					expectedIndices...,
+				),
+				indices,
+			)
+
+			// Slicing the string using the indices results in the same parts as split
+
+			parts, err := inter.Invoke("split", str, sep)
+			require.NoError(t, err)
+
+			slicedParts, err := inter.Invoke("sliceParts", str, sep)
+			require.NoError(t, err)
+
+			RequireValuesEqual(t, inter, parts, slicedParts)
+		})
+	}
+}
+
+func TestInterpretStringSplitWithLimit(t *testing.T) {
+
+	t.Parallel()
+
+	type test struct {
+		str    string
+		sep    string
+		limit  int
+		result []string
+	}
+
+	var abcd = "abcd"
+	var commas = "1,2,3,4"
+	var faces = "☺☻☹"
+
+	tests := []test{
+		// Limit of zero or less splits fully
+		{commas, ",", 0, []string{"1", "2", "3", "4"}},
+		{commas, ",", -1, []string{"1", "2", "3", "4"}},
+		// Limit of one returns the whole string
+		{commas, ",", 1, []string{commas}},
+		{abcd, "", 1, []string{abcd}},
+		{"", ",", 1, []string{""}},
+		{commas, ",", 2, []string{"1", "2,3,4"}},
+		{commas, ",", 3, []string{"1", "2", "3,4"}},
+		// Limit equal to or greater than the number of parts splits fully
+		{commas, ",", 4, []string{"1", "2", "3", "4"}},
+		{commas, ",", 5, []string{"1", "2", "3", "4"}},
+		{commas, ",,", 2, []string{commas}},
+		{"1,,2,,3", ",,", 2, []string{"1", "2,,3"}},
+		{",,", ",", 2, []string{"", ","}},
+		// Empty separator splits into characters
+		{abcd, "", 0, []string{"a", "b", "c", "d"}},
+		{abcd, "", 2, []string{"a", "bcd"}},
+		{abcd, "", 4, []string{"a", "b", "c", "d"}},
+		{abcd, "", 10, []string{"a", "b", "c", "d"}},
+		{faces, "", 2, []string{"☺", "☻☹"}},
+		{"", "", 1, []string{}},
+	}
+
+	runTest := func(test test) {
+
+		name := fmt.Sprintf("%s, %s, %d", test.str, test.sep, test.limit)
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			inter := parseCheckAndInterpret(t,
+				fmt.Sprintf(
+					`
+                      fun test(): [String] {
+                        let s = "%s"
+                        return s.split(separator: "%s", limit: %d)
+                      }
+                    `,
+					test.str,
+					test.sep,
+					test.limit,
+				),
+			)
+
+			value, err := inter.Invoke("test")
+			require.NoError(t, err)
+
+			require.IsType(t, &interpreter.ArrayValue{}, value)
+			actual := value.(*interpreter.ArrayValue)
+
+			require.Equal(t, len(test.result), actual.Count())
+
+			for partIndex, expected := range test.result {
+				actualPart := actual.Get(
+					inter,
+					interpreter.EmptyLocationRange,
+					partIndex,
+				)
+
+				require.IsType(t, &interpreter.StringValue{}, actualPart)
+				actualPartString := actualPart.(*interpreter.StringValue)
+
+				require.Equal(t, expected, actualPartString.Str)
+			}
+		})
+	}
+
+	for _, test := range tests {
+		runTest(test)
+	}
+}
+
+func TestInterpretStringTokens(t *testing.T) {
+
+	t.Parallel()
+
+	type test struct {
+		str string
+		sep string
+	}
+
+	tests := []test{
+		{"", ""},
+		{"", ","},
+		{"abcd", ""},
+		{"☺☻☹", ""},
+		{"abcd", "a"},
+		{"abcd", "z"},
+		{"1,2,3,4", ","},
+		{",1,,2,", ","},
+		{"1....2....3....4", "..."},
+		{"aaaaa", "aa"},
+		{"a,b,", ","},
+		{"☺☻☹", "☹"},
+		{"\\u{1F46A}////\\u{2764}\\u{FE0F}", "////"},
+		{"\\u{1F46A} \\u{2764}\\u{FE0F} Abc6 ;123", " "},
+		{"cafe\\u{301}ba\\u{308}", ""},
+		// 🇪🇸🇪🇪 ("ES", "EE") does NOT contain 🇸🇪 ("SE")
+		{"\\u{1F1EA}\\u{1F1F8}\\u{1F1EA}\\u{1F1EA}", "\\u{1F1F8}\\u{1F1EA}"},
+	}
+
+	runTest := func(test test) {
+
+		name := fmt.Sprintf("%s, %s", test.str, test.sep)
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			inter := parseCheckAndInterpret(t,
+				fmt.Sprintf(
+					`
+                      let s = "%s"
+                      let sep = "%s"
+
+                      fun tokens(): [String] {
+                          let result: [String] = []
+                          for part in s.tokens(separator: sep) {
+                              result.append(part)
+                          }
+                          return result
+                      }
+
+                      fun next(): [String] {
+                          let result: [String] = []
+                          let iterator = s.tokens(separator: sep)
+                          while true {
+                              let part = iterator.next()
+                              if part == nil {
+                                  break
+                              }
+                              result.append(part!)
+                          }
+                          return result
+                      }
+
+                      fun split(): [String] {
+                          return s.split(separator: sep)
+                      }
+                    `,
+					test.str,
+					test.sep,
+				),
+			)
+
+			expected, err := inter.Invoke("split")
+			require.NoError(t, err)
+
+			for _, name := range []string{"tokens", "next"} {
+				actual, err := inter.Invoke(name)
+				require.NoError(t, err)
+
+				AssertValuesEqual(t, inter, expected, actual)
+			}
+		})
+	}
+
+	for _, test := range tests {
+		runTest(test)
+	}
+
+	t.Run("lazy", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [String?] {
+              let iterator = "a,b,c".tokens(separator: ",")
+              let first = iterator.next()
+
+              // Iterating over the iterator does not advance it
+              let rest: [String] = []
+              for part in iterator {
+                  rest.append(part)
+              }
+
+              // Copies of the iterator are independent
+              let copy = iterator
+              copy.next()
+
+              return [first, rest[0], rest[1], iterator.next(), copy.next(), copy.next(), copy.next()]
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		optionalString := func(str string) interpreter.Value {
+			return interpreter.NewUnmeteredSomeValueNonCopying(
+				interpreter.NewUnmeteredStringValue(str),
+			)
+		}
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewArrayValue(
+				inter,
+				interpreter.EmptyLocationRange,
+				&interpreter.VariableSizedStaticType{
+					Type: &interpreter.OptionalStaticType{
+						Type: interpreter.PrimitiveStaticTypeString,
+					},
+				},
+				common.ZeroAddress,
+				optionalString("a"),
+				optionalString("b"),
+				optionalString("c"),
+				optionalString("b"),
+				optionalString("c"),
+				interpreter.Nil,
+				interpreter.Nil,
+			),
+			value,
+		)
+	})
+}
+
+func TestInterpretStringChunked(t *testing.T) {
 
 	t.Parallel()
 
 	type test struct {
 		str    string
-		sep    string
+		size   int
 		result []string
 	}
 
-	var abcd = "abcd"
-	var faces = "☺☻☹"
-	var commas = "1,2,3,4"
-	var dots = "1....2....3....4"
-
 	tests := []test{
-		{"", "", []string{}},
-		{abcd, "", []string{"a", "b", "c", "d"}},
-		{faces, "", []string{"☺", "☻", "☹"}},
-		{"☺�☹", "", []string{"☺", "�", "☹"}},
-		{abcd, "a", []string{"", "bcd"}},
-		{abcd, "z", []string{"abcd"}},
-		{commas, ",", []string{"1", "2", "3", "4"}},
-		{dots, "...", []string{"1", ".2", ".3", ".4"}},
-		{faces, "☹", []string{"☺☻", ""}},
-		{faces, "~", []string{faces}},
-		{
-			"\\u{1F46A}////\\u{2764}\\u{FE0F}",
-			"////",
-			[]string{"\U0001F46A", "\u2764\uFE0F"},
-		},
-		{
-			"\\u{1F46A} \\u{2764}\\u{FE0F} Abc6 ;123",
-			" ",
-			[]string{"\U0001F46A", "\u2764\uFE0F", "Abc6", ";123"},
-		},
-		{
-			"Caf\\u{65}\\u{301}ABc",
-			"\\u{e9}",
-			[]string{"Caf", "ABc"},
-		},
-		{
-			"",
-			"//",
-			[]string{""},
-		},
-		{
-			"pqrS;asdf",
-			";;",
-			[]string{"pqrS;asdf"},
-		},
-		{
-			// U+1F476 U+1F3FB is 👶🏻
-			" \\u{1F476}\\u{1F3FB} ascii \\u{D}\\u{A}",
-			" ",
-			[]string{"", "\U0001F476\U0001F3FB", "ascii", "\u000D\u000A"},
-		},
-		// 🇪🇸🇸🇪🇪🇪 is "ES", "SE", "EE"
-		{
-			"\\u{1F1EA}\\u{1F1F8}\\u{1F1F8}\\u{1F1EA}\\u{1F1EA}\\u{1F1EA}",
-			"\\u{1F1F8}\\u{1F1EA}",
-			[]string{"\U0001F1EA\U0001F1F8", "\U0001F1EA\U0001F1EA"},
-		},
+		{"", 1, []string{}},
+		{"abcdef", 1, []string{"a", "b", "c", "d", "e", "f"}},
+		{"abcdef", 2, []string{"ab", "cd", "ef"}},
+		{"abcdefg", 3, []string{"abc", "def", "g"}},
+		{"abcdef", 6, []string{"abcdef"}},
+		{"abcdef", 10, []string{"abcdef"}},
+		{"☺☻☹", 2, []string{"☺☻", "☹"}},
+		// Characters are grapheme clusters, so no character is split
+		{"cafe\\u{301}ba\\u{308}", 4, []string{"caf\u00e9", "b\u00e4"}},
+		{"ab\\u{1F476}\\u{1F3FB}cd", 3, []string{"ab\U0001F476\U0001F3FB", "cd"}},
+		{"\\u{1F1EA}\\u{1F1F8}\\u{1F1F8}\\u{1F1EA}\\u{1F1EA}\\u{1F1EA}", 2, []string{"\U0001F1EA\U0001F1F8\U0001F1F8\U0001F1EA", "\U0001F1EA\U0001F1EA"}},
 	}
 
 	runTest := func(test test) {
 
-		name := fmt.Sprintf("%s, %s", test.str, test.sep)
+		name := fmt.Sprintf("%s, %d", test.str, test.size)
 
 		t.Run(name, func(t *testing.T) {
 
@@ -579,11 +2064,11 @@ func TestInterpretStringSplit(t *testing.T) {
 					`
                       fun test(): [String] {
                         let s = "%s"
-                        return s.split(separator: "%s")
+                        return s.chunked(size: %d)
                       }
                     `,
 					test.str,
-					test.sep,
+					test.size,
 				),
 			)
 
@@ -613,6 +2098,29 @@ func TestInterpretStringSplit(t *testing.T) {
 	for _, test := range tests {
 		runTest(test)
 	}
+
+	t.Run("non-positive size", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(_ size: Int): [String] {
+              return "abc".chunked(size: size)
+          }
+        `)
+
+		for _, size := range []int64{0, -1} {
+			_, err := inter.Invoke(
+				"test",
+				interpreter.NewUnmeteredIntValueFromInt64(size),
+			)
+			RequireError(t, err)
+
+			var typedErr interpreter.NonPositiveStringChunkSizeError
+			require.ErrorAs(t, err, &typedErr)
+			require.Equal(t, int(size), typedErr.Size)
+		}
+	})
 }
 
 func TestInterpretStringReplaceAll(t *testing.T) {
@@ -713,6 +2221,69 @@ func TestInterpretStringReplaceAll(t *testing.T) {
 	}
 }
 
+func TestInterpretStringReplaceAllCaseInsensitive(t *testing.T) {
+
+	t.Parallel()
+
+	type test struct {
+		str             string
+		old             string
+		new             string
+		caseInsensitive bool
+		result          string
+	}
+
+	tests := []test{
+		{"xAbCyabcZABC", "ABC", "-", false, "xAbCyabcZ-"},
+		{"xAbCyabcZABC", "ABC", "-", true, "x-y-Z-"},
+		{"xAbCyabcZABC", "abc", "DeF", true, "xDeFyDeFZDeF"},
+		{"aAaA", "aa", "b", true, "bb"},
+		{"aAa", "AA", "<>", true, "<>a"},
+		{"Hello", "x", "y", true, "Hello"},
+		{"", "ABC", "-", true, ""},
+		{"ab", "", "-", true, "-a-b-"},
+		{"CAFÉ café", "café", "tea", true, "tea tea"},
+		{"Caf\\u{65}\\u{301}", "CAF\\u{C9}", "X", true, "X"},
+	}
+
+	runTest := func(test test) {
+
+		name := fmt.Sprintf("%s, %s, %s, %t", test.str, test.old, test.new, test.caseInsensitive)
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			inter := parseCheckAndInterpret(t,
+				fmt.Sprintf(
+					`
+                      fun test(): String {
+                        let s = "%s"
+                        return s.replaceAll(of: "%s", with: "%s", caseInsensitive: %t)
+                      }
+                    `,
+					test.str,
+					test.old,
+					test.new,
+					test.caseInsensitive,
+				),
+			)
+
+			value, err := inter.Invoke("test")
+			require.NoError(t, err)
+
+			require.IsType(t, &interpreter.StringValue{}, value)
+			actual := value.(*interpreter.StringValue)
+
+			require.Equal(t, test.result, actual.Str)
+		})
+	}
+
+	for _, test := range tests {
+		runTest(test)
+	}
+}
+
 func TestInterpretStringContains(t *testing.T) {
 
 	t.Parallel()
@@ -875,6 +2446,204 @@ func TestInterpretStringIndex(t *testing.T) {
 	}
 }
 
+func TestInterpretStringIndexFrom(t *testing.T) {
+
+	t.Parallel()
+
+	type test struct {
+		str    string
+		subStr string
+		from   int
+		result int
+	}
+
+	tests := []test{
+		{"abcabc", "bc", 0, 1},
+		{"abcabc", "bc", 1, 1},
+		{"abcabc", "bc", 2, 4},
+		{"abcabc", "bc", 5, -1},
+		{"abcabc", "bc", 6, -1},
+		{"abcabc", "", 3, 3},
+		{"abcabc", "", 6, 6},
+		{"abcabc", "abc", 1, 3},
+
+		// U+1F476 U+1F3FB is 👶🏻
+		{"\\u{1F476}\\u{1F3FB} a \\u{1F476}\\u{1F3FB}", "\\u{1F476}\\u{1F3FB}", 1, 4},
+		{"\\u{1F476}\\u{1F3FB} a \\u{1F476}\\u{1F3FB}", "\\u{1F3FB}", 1, -1},
+	}
+
+	runTest := func(test test) {
+
+		name := fmt.Sprintf("%s, %s, %d", test.str, test.subStr, test.from)
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			inter := parseCheckAndInterpret(t,
+				fmt.Sprintf(
+					`
+                      fun test(): Int {
+                        let s = "%s"
+                        return s.index(of: "%s", from: %d)
+                      }
+                    `,
+					test.str,
+					test.subStr,
+					test.from,
+				),
+			)
+
+			value, err := inter.Invoke("test")
+			require.NoError(t, err)
+
+			require.IsType(t, interpreter.IntValue{}, value)
+			actual := value.(interpreter.IntValue)
+			require.Equal(t, test.result, actual.ToInt(interpreter.EmptyLocationRange))
+		})
+	}
+
+	for _, test := range tests {
+		runTest(test)
+	}
+
+	t.Run("all occurrences", func(t *testing.T) {
+
+		t.Parallel()
+
+		inter := parseCheckAndInterpret(t, `
+          fun test(): [Int] {
+              let s = "abcabcabc"
+              let result: [Int] = []
+              var index = s.index(of: "bc")
+              while index >= 0 {
+                  result.append(index)
+                  index = s.index(of: "bc", from: index + 1)
+              }
+              return result
+          }
+        `)
+
+		value, err := inter.Invoke("test")
+		require.NoError(t, err)
+
+		AssertValuesEqual(
+			t,
+			inter,
+			interpreter.NewArrayValue(
+				inter,
+				interpreter.EmptyLocationRange,
+				&interpreter.VariableSizedStaticType{
+					Type: interpreter.PrimitiveStaticTypeInt,
+				},
+				common.ZeroAddress,
+				interpreter.NewUnmeteredIntValueFromInt64(1),
+				interpreter.NewUnmeteredIntValueFromInt64(4),
+				interpreter.NewUnmeteredIntValueFromInt64(7),
+			),
+			value,
+		)
+	})
+
+	for name, from := range map[string]int{
+		"negative":          -1,
+		"larger than count": 4,
+	} {
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			inter := parseCheckAndInterpret(t, `
+              fun test(_ from: Int): Int {
+                  return "abc".index(of: "b", from: from)
+              }
+            `)
+
+			_, err := inter.Invoke("test", interpreter.NewUnmeteredIntValueFromInt64(int64(from)))
+			RequireError(t, err)
+
+			var indexErr interpreter.StringIndexOutOfBoundsError
+			require.ErrorAs(t, err, &indexErr)
+
+			require.Equal(t, from, indexErr.Index)
+			require.Equal(t, 3, indexErr.Length)
+		})
+	}
+}
+
+func TestInterpretStringLastIndex(t *testing.T) {
+
+	t.Parallel()
+
+	type test struct {
+		str    string
+		subStr string
+		result int
+	}
+
+	tests := []test{
+		{"", "", 0},
+		{"", "a", -1},
+		{"abcdef", "", 6},
+		{"abcdef", "a", 0},
+		{"abcdef", "f", 5},
+		{"abcdef", "ac", -1},
+		{"abcdef", "abcdef", 0},
+		{"abcdef", "abcdefg", -1},
+		{"abcabc", "abc", 3},
+		{"abcabc", "bc", 4},
+		{"archive.tar.gz", ".", 11},
+		{"aaa", "aa", 1},
+
+		// U+1F476 U+1F3FB is 👶🏻
+		{"\\u{1F476}\\u{1F3FB}.\\u{1F476}\\u{1F3FB}.x", ".", 3},
+		{"\\u{1F476}\\u{1F3FB}.\\u{1F476}\\u{1F3FB}.x", "\\u{1F476}\\u{1F3FB}", 2},
+		{"\\u{1F476}\\u{1F3FB}.\\u{1F476}\\u{1F3FB}.x", "\\u{1F476}", -1},
+		{"\\u{1F476}\\u{1F3FB}.\\u{1F476}\\u{1F3FB}.x", "", 5},
+		{"caf\\u{E9}.caf\\u{E9}", "\\u{E9}", 8},
+
+		// 🇪🇸🇪🇪🇪🇸 ("ES", "EE", "ES") contains 🇪🇸("ES")
+		{"\\u{1F1EA}\\u{1F1F8}\\u{1F1EA}\\u{1F1EA}\\u{1F1EA}\\u{1F1F8}", "\\u{1F1EA}\\u{1F1F8}", 2},
+		// 🇪🇸🇪🇪 ("ES", "EE") does NOT contain 🇸🇪 ("SE")
+		{"\\u{1F1EA}\\u{1F1F8}\\u{1F1EA}\\u{1F1EA}", "\\u{1F1F8}\\u{1F1EA}", -1},
+	}
+
+	runTest := func(test test) {
+
+		name := fmt.Sprintf("%s, %s", test.str, test.subStr)
+
+		t.Run(name, func(t *testing.T) {
+
+			t.Parallel()
+
+			inter := parseCheckAndInterpret(t,
+				fmt.Sprintf(
+					`
+                      fun test(): Int {
+                        let s = "%s"
+                        return s.lastIndex(of: "%s")
+                      }
+                    `,
+					test.str,
+					test.subStr,
+				),
+			)
+
+			value, err := inter.Invoke("test")
+			require.NoError(t, err)
+
+			require.IsType(t, interpreter.IntValue{}, value)
+			actual := value.(interpreter.IntValue)
+			require.Equal(t, test.result, actual.ToInt(interpreter.EmptyLocationRange))
+		})
+	}
+
+	for _, test := range tests {
+		runTest(test)
+	}
+}
+
 func TestInterpretStringCount(t *testing.T) {
 
 	t.Parallel()
@@ -19,6 +19,9 @@
 package interpreter
 
 import (
+	"math"
+	"strings"
+
 	"github.com/onflow/atree"
 
 	"github.com/onflow/cadence/common"
@@ -39,7 +42,52 @@ func (v StringAtreeValue) Storable(
 	atree.Storable,
 	error,
 ) {
-	return values.MaybeLargeImmutableStorable(v, storage, address, maxInlineSize)
+	return maybeLargeImmutableStringStorable(v, storage, address, maxInlineSize)
+}
+
+// stringInlineSizeLimiter is an optional interface for storages
+// which limit the size of strings that are stored inline,
+// e.g. to reproduce a certain storage layout during migrations.
+type stringInlineSizeLimiter interface {
+	// MaxInlineStringSize returns the maximum size of strings which are stored inline,
+	// or zero if there is no limit in addition to the one given by atree.
+	MaxInlineStringSize() uint64
+}
+
+// maxInlineStringSize returns the given max inline size,
+// lowered to the inline size limit for strings of the storage, if any.
+//
+// A max inline size of math.MaxUint64 requests that the value is always encoded inline,
+// e.g. when encoding values for export or for hashing, so it is not limited.
+func maxInlineStringSize(storage atree.SlabStorage, maxInlineSize uint64) uint64 {
+	if maxInlineSize == math.MaxUint64 {
+		return maxInlineSize
+	}
+
+	if limiter, ok := storage.(stringInlineSizeLimiter); ok {
+		limit := limiter.MaxInlineStringSize()
+		if limit > 0 && limit < maxInlineSize {
+			return limit
+		}
+	}
+
+	return maxInlineSize
+}
+
+// maybeLargeImmutableStringStorable is like values.MaybeLargeImmutableStorable,
+// but additionally respects the inline size limit of the storage, if any.
+func maybeLargeImmutableStringStorable(
+	storable atree.Storable,
+	storage atree.SlabStorage,
+	address atree.Address,
+	maxInlineSize uint64,
+) (
+	atree.Storable,
+	error,
+) {
+	maxInlineSize = maxInlineStringSize(storage, maxInlineSize)
+
+	return values.MaybeLargeImmutableStorable(storable, storage, address, maxInlineSize)
 }
 
 func NewStringAtreeValue(gauge common.MemoryGauge, s string) StringAtreeValue {
@@ -88,6 +136,24 @@ func StringAtreeValueComparator(storage atree.SlabStorage, value atree.Value, ot
 	if err != nil {
 		return false, err
 	}
-	result := value.(StringAtreeValue) == otherValue.(StringAtreeValue)
+	// NOTE: other value might not be a StringAtreeValue,
+	// e.g. a Uint64AtreeValue key in a storage map with mixed key types
+	otherStringValue, ok := otherValue.(StringAtreeValue)
+	if !ok {
+		return false, nil
+	}
+	result := value.(StringAtreeValue) == otherStringValue
 	return result, nil
 }
+
+// StringAtreeValuePrefixMatch returns a function which reports
+// if a value is a StringAtreeValue starting with the given prefix.
+func StringAtreeValuePrefixMatch(prefix string) func(value atree.Value) bool {
+	return func(value atree.Value) bool {
+		stringValue, ok := value.(StringAtreeValue)
+		if !ok {
+			return false
+		}
+		return strings.HasPrefix(string(stringValue), prefix)
+	}
+}
@@ -64,3 +64,46 @@ func TestLargeStringAtreeValueInSeparateSlab(t *testing.T) {
 
 	require.Equal(t, expected, actual)
 }
+
+func TestStringAtreeValuePrefixMatch(t *testing.T) {
+
+	t.Parallel()
+
+	values := []StringAtreeValue{
+		"",
+		"a",
+		"f",
+		"fl",
+		"flo",
+		"flow",
+		"flow\x00",
+		"flowToken",
+		"flowtoken",
+		"flox",
+		"flp",
+		"g",
+		"\U0001F490",
+	}
+
+	match := StringAtreeValuePrefixMatch("flow")
+
+	var matched []StringAtreeValue
+	for _, value := range values {
+		if match(value) {
+			matched = append(matched, value)
+		}
+	}
+
+	require.Equal(t,
+		[]StringAtreeValue{"flow", "flow\x00", "flowToken", "flowtoken"},
+		matched,
+	)
+
+	// Empty prefix matches all string values
+	for _, value := range values {
+		require.True(t, StringAtreeValuePrefixMatch("")(value))
+	}
+
+	// Other values are never matched
+	require.False(t, match(Uint64AtreeValue(0)))
+}
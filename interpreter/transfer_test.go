@@ -171,3 +171,70 @@ func TestInterpretTransferCheck(t *testing.T) {
 		require.NoError(t, err)
 	})
 }
+
+func TestInterpretTransferValueDepthLimit(t *testing.T) {
+
+	t.Parallel()
+
+	const maxValueDepth = 10
+
+	inter, err := parseCheckAndInterpretWithOptions(t,
+		`
+          fun nest(_ depth: Int): {String: AnyStruct} {
+              var dictionary: {String: AnyStruct} = {}
+              var i = 0
+              while i < depth {
+                  dictionary = {"a": dictionary}
+                  i = i + 1
+              }
+              return dictionary
+          }
+
+          fun test(_ depth: Int) {
+              let dictionary = nest(depth)
+              let copy = dictionary
+          }
+        `,
+		ParseCheckAndInterpretOptions{
+			Config: &interpreter.Config{
+				MaxValueDepth: maxValueDepth,
+			},
+		},
+	)
+	require.NoError(t, err)
+
+	// maxValueDepth nested dictionaries (including the innermost empty dictionary)
+
+	_, err = inter.Invoke("test", interpreter.NewUnmeteredIntValueFromInt64(maxValueDepth-1))
+	require.NoError(t, err)
+
+	// One nested dictionary more than the limit
+
+	_, err = inter.Invoke("test", interpreter.NewUnmeteredIntValueFromInt64(maxValueDepth))
+	RequireError(t, err)
+
+	var depthErr interpreter.ValueDepthLimitExceededError
+	require.ErrorAs(t, err, &depthErr)
+	require.Equal(t, maxValueDepth, depthErr.Limit)
+
+	// The depth is reset after the error
+
+	_, err = inter.Invoke("test", interpreter.NewUnmeteredIntValueFromInt64(maxValueDepth-1))
+	require.NoError(t, err)
+
+	// Deep removal is limited as well
+
+	value, err := inter.Invoke("nest", interpreter.NewUnmeteredIntValueFromInt64(maxValueDepth-1))
+	require.NoError(t, err)
+
+	inter.SharedState.Config.MaxValueDepth = maxValueDepth / 2
+
+	require.PanicsWithValue(t,
+		interpreter.ValueDepthLimitExceededError{
+			Limit: maxValueDepth / 2,
+		},
+		func() {
+			value.DeepRemove(inter, true)
+		},
+	)
+}
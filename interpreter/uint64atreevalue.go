@@ -66,6 +66,12 @@ func Uint64AtreeValueHashInput(v atree.Value, scratch []byte) ([]byte, error) {
 }
 
 func Uint64AtreeValueComparator(_ atree.SlabStorage, value atree.Value, otherStorable atree.Storable) (bool, error) {
-	result := value.(Uint64AtreeValue) == otherStorable.(Uint64AtreeValue)
+	// NOTE: other storable might not be a Uint64AtreeValue,
+	// e.g. a StringAtreeValue key in a storage map with mixed key types
+	otherUint64Value, ok := otherStorable.(Uint64AtreeValue)
+	if !ok {
+		return false, nil
+	}
+	result := value.(Uint64AtreeValue) == otherUint64Value
 	return result, nil
 }
@@ -210,6 +210,25 @@ func maybeDestroy(context ResourceDestructionContext, locationRange LocationRang
 	resourceKindedValue.Destroy(context, locationRange)
 }
 
+// CloneValueDeep returns a deep copy of the given value, e.g. to snapshot a stored value.
+// Nested composites, arrays, and dictionaries are cloned recursively,
+// and their static types are preserved.
+//
+// The clone has the same owner as the given value, but is not stored,
+// i.e. it is independent of the given value: mutating one does not affect the other.
+//
+// Resource-kinded values cannot be cloned, as cloning a resource would duplicate it,
+// and are rejected with a ResourceCloneError.
+func CloneValueDeep(context ValueCloneContext, value Value) Value {
+	if value.IsResourceKinded(context) {
+		panic(ResourceCloneError{
+			Type: value.StaticType(context),
+		})
+	}
+
+	return value.Clone(context)
+}
+
 // ReferenceTrackedResourceKindedValue is a resource-kinded value
 // that must be tracked when a reference of it is taken.
 type ReferenceTrackedResourceKindedValue interface {
@@ -250,6 +269,7 @@ type OwnedValue interface {
 type ValueIteratorContext interface {
 	common.MemoryGauge
 	NumberValueArithmeticContext
+	ComputationReporter
 }
 
 // ValueIterator is an iterator which returns values.
@@ -1324,6 +1324,9 @@ func (v *ArrayValue) Transfer(
 	preventTransfer[currentValueID] = struct{}{}
 	defer delete(preventTransfer, currentValueID)
 
+	context.EnterNestedValue(locationRange)
+	defer context.ExitNestedValue()
+
 	array := v.array
 
 	needsStoreTo := v.NeedsStoreTo(address)
@@ -1484,6 +1487,9 @@ func (v *ArrayValue) DeepRemove(context ValueRemoveContext, hasNoParentContainer
 		}()
 	}
 
+	context.EnterNestedValue(EmptyLocationRange)
+	defer context.ExitNestedValue()
+
 	// Remove nested values and storables
 
 	storage := v.array.Storage
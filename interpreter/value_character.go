@@ -19,6 +19,10 @@
 package interpreter
 
 import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
 	"golang.org/x/text/unicode/norm"
 
 	"github.com/onflow/atree"
@@ -244,10 +248,49 @@ func (v CharacterValue) GetMember(context MemberAccessibleContext, _ LocationRan
 	case sema.CharacterTypeUtf8FieldName:
 		common.UseMemory(context, common.NewBytesMemoryUsage(len(v.Str)))
 		return ByteSliceToByteArrayValue(context, []byte(v.Str))
+
+	case sema.CharacterTypeValueFieldName:
+		return NewUInt32Value(context, func() uint32 {
+			return uint32(v.firstScalar())
+		})
+
+	case sema.CharacterTypeIsLetterFieldName:
+		return BoolValue(unicode.IsLetter(v.firstScalar()))
+
+	case sema.CharacterTypeIsDigitFieldName:
+		return BoolValue(unicode.IsDigit(v.firstScalar()))
+
+	case sema.CharacterTypeIsWhitespaceFieldName:
+		return BoolValue(unicode.IsSpace(v.firstScalar()))
+
+	case sema.CharacterTypeCompareFunctionName:
+		return NewBoundHostFunctionValue(
+			context,
+			v,
+			sema.CharacterTypeCompareFunctionType,
+			func(v CharacterValue, invocation Invocation) Value {
+				other, ok := invocation.Arguments[0].(CharacterValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				return NewIntValueFromInt64(
+					invocation.InvocationContext,
+					int64(strings.Compare(v.Str, other.Str)),
+				)
+			},
+		)
 	}
 	return nil
 }
 
+// firstScalar returns the first Unicode scalar of the character,
+// which is used to classify characters consisting of multiple Unicode scalars.
+func (v CharacterValue) firstScalar() rune {
+	r, _ := utf8.DecodeRuneInString(v.Str)
+	return r
+}
+
 func (CharacterValue) RemoveMember(_ ValueTransferContext, _ LocationRange, _ string) Value {
 	// Characters have no removable members (fields / functions)
 	panic(errors.NewUnreachableError())
@@ -1182,6 +1182,9 @@ func (v *CompositeValue) Transfer(
 	preventTransfer[currentValueID] = struct{}{}
 	defer delete(preventTransfer, currentValueID)
 
+	context.EnterNestedValue(locationRange)
+	defer context.ExitNestedValue()
+
 	dictionary := v.dictionary
 
 	needsStoreTo := v.NeedsStoreTo(address)
@@ -1412,6 +1415,9 @@ func (v *CompositeValue) DeepRemove(context ValueRemoveContext, hasNoParentConta
 		}()
 	}
 
+	context.EnterNestedValue(EmptyLocationRange)
+	defer context.ExitNestedValue()
+
 	// Remove nested values and storables
 
 	storage := v.dictionary.Storage
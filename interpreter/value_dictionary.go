@@ -1302,6 +1302,9 @@ func (v *DictionaryValue) Transfer(
 	preventTransfer[currentValueID] = struct{}{}
 	defer delete(preventTransfer, currentValueID)
 
+	context.EnterNestedValue(locationRange)
+	defer context.ExitNestedValue()
+
 	dictionary := v.dictionary
 
 	needsStoreTo := v.NeedsStoreTo(address)
@@ -1500,6 +1503,9 @@ func (v *DictionaryValue) DeepRemove(context ValueRemoveContext, hasNoParentCont
 		}()
 	}
 
+	context.EnterNestedValue(EmptyLocationRange)
+	defer context.ExitNestedValue()
+
 	// Remove nested values and storables
 
 	storage := v.dictionary.Storage
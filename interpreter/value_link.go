@@ -21,8 +21,10 @@ package interpreter
 import (
 	"fmt"
 
+	"github.com/fxamacker/cbor/v2"
 	"github.com/onflow/atree"
 
+	"github.com/onflow/cadence/common"
 	"github.com/onflow/cadence/errors"
 	"github.com/onflow/cadence/values"
 )
@@ -324,6 +326,69 @@ func (v PathLinkValue) Encode(e *atree.Encoder) error {
 	return v.Type.Encode(e.CBOR)
 }
 
+// DecodePathLinkValue decodes a PathLinkValue encoded by PathLinkValue.Encode,
+// i.e. including the tag number.
+// It is intended for tests and tooling, e.g. migrations,
+// which need to decode links outside of DecodeStorable.
+func DecodePathLinkValue(
+	decoder *cbor.StreamDecoder,
+	memoryGauge common.MemoryGauge,
+) (
+	PathLinkValue,
+	error,
+) {
+	err := decodeLinkValueTagNumber(decoder, values.CBORTagPathLinkValue) //nolint:staticcheck
+	if err != nil {
+		return EmptyPathLinkValue, err
+	}
+
+	return NewStorableDecoder(decoder, atree.SlabIDUndefined, nil, memoryGauge).
+		decodePathLink()
+}
+
+// DecodeAccountLinkValue decodes an AccountLinkValue encoded by AccountLinkValue.Encode,
+// i.e. including the tag number.
+// It is intended for tests and tooling, e.g. migrations,
+// which need to decode links outside of DecodeStorable.
+func DecodeAccountLinkValue(
+	decoder *cbor.StreamDecoder,
+	memoryGauge common.MemoryGauge,
+) (
+	AccountLinkValue,
+	error,
+) {
+	err := decodeLinkValueTagNumber(decoder, values.CBORTagAccountLinkValue) //nolint:staticcheck
+	if err != nil {
+		return AccountLinkValue{}, err
+	}
+
+	return NewStorableDecoder(decoder, atree.SlabIDUndefined, nil, memoryGauge).
+		decodeAccountLink()
+}
+
+func decodeLinkValueTagNumber(decoder *cbor.StreamDecoder, expectedTag uint64) error {
+	num, err := decoder.DecodeTagNumber()
+	if err != nil {
+		if e, ok := err.(*cbor.WrongTypeError); ok {
+			return errors.NewUnexpectedError(
+				"invalid link encoding: expected CBOR tag, got %s",
+				e.ActualType.String(),
+			)
+		}
+		return err
+	}
+
+	if num != expectedTag {
+		return errors.NewUnexpectedError(
+			"invalid link encoding: expected CBOR tag %d, got %d",
+			expectedTag,
+			num,
+		)
+	}
+
+	return nil
+}
+
 // cborAccountLinkValue represents the CBOR value:
 //
 //	cbor.Tag{
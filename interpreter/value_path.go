@@ -19,6 +19,9 @@
 package interpreter
 
 import (
+	"slices"
+	"strings"
+
 	"github.com/onflow/atree"
 
 	"github.com/onflow/cadence/common"
@@ -168,6 +171,26 @@ func (v PathValue) Equal(_ ValueComparisonContext, _ LocationRange, other Value)
 		otherPath.Domain == v.Domain
 }
 
+// ComparePaths returns an integer comparing two paths.
+// Paths are ordered first by domain, then by identifier,
+// consistent with how paths are serialized (domain first, then identifier).
+// The result is 0 if a == b, -1 if a < b, and +1 if a > b.
+func ComparePaths(a, b PathValue) int {
+	switch {
+	case a.Domain < b.Domain:
+		return -1
+	case a.Domain > b.Domain:
+		return 1
+	default:
+		return strings.Compare(a.Identifier, b.Identifier)
+	}
+}
+
+// SortPaths sorts the given paths in place, in the order defined by ComparePaths
+func SortPaths(paths []PathValue) {
+	slices.SortFunc(paths, ComparePaths)
+}
+
 // HashInput returns a byte slice containing:
 // - HashInputTypePath (1 byte)
 // - domain (1 byte)
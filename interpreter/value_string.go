@@ -20,6 +20,9 @@ package interpreter
 
 import (
 	"encoding/hex"
+	"math/bits"
+	"slices"
+	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -96,6 +99,10 @@ var _ IterableValue = &StringValue{}
 
 var VarSizedArrayOfStringType = NewVariableSizedStaticType(nil, PrimitiveStaticTypeString)
 
+var VarSizedArrayOfUInt32Type = NewVariableSizedStaticType(nil, PrimitiveStaticTypeUInt32)
+
+var VarSizedArrayOfIntType = NewVariableSizedStaticType(nil, PrimitiveStaticTypeInt)
+
 func (v *StringValue) prepareGraphemes() {
 	// If the string is empty, methods of StringValue should never call prepareGraphemes,
 	// as it is not only unnecessary, but also means that the value is the empty string singleton EmptyString,
@@ -225,6 +232,11 @@ func (v *StringValue) HashInput(_ common.MemoryGauge, _ LocationRange, scratch [
 	return buffer
 }
 
+// Concat returns a new string which is the concatenation of this string and the given string.
+//
+// The memory for the combined string is metered up front, based on the sum of the operands' byte lengths,
+// so a concatenation which exceeds the memory limit fails before the combined string is allocated.
+// Like for StringAtreeValue, the raw string memory is metered based on the byte length.
 func (v *StringValue) Concat(context StringValueFunctionContext, other *StringValue, locationRange LocationRange) Value {
 
 	firstLength := len(v.Str)
@@ -232,23 +244,106 @@ func (v *StringValue) Concat(context StringValueFunctionContext, other *StringVa
 
 	newLength := safeAdd(firstLength, secondLength, locationRange)
 
-	memoryUsage := common.NewStringMemoryUsage(newLength)
+	common.UseMemory(context, common.NewStringMemoryUsage(newLength))
+
+	// NewUnmeteredStringValue normalizes (= allocates)
+	common.UseMemory(context, common.NewRawStringMemoryUsage(newLength))
 
 	// Meter computation as if the two strings were iterated.
 	context.ReportComputation(common.ComputationKindLoop, uint(newLength))
 
-	return NewStringValue(
-		context,
-		memoryUsage,
-		func() string {
-			var sb strings.Builder
+	var sb strings.Builder
+	sb.Grow(newLength)
 
-			sb.WriteString(v.Str)
-			sb.WriteString(other.Str)
+	sb.WriteString(v.Str)
+	sb.WriteString(other.Str)
 
-			return sb.String()
-		},
-	)
+	return NewUnmeteredStringValue(sb.String())
+}
+
+func (v *StringValue) Repeat(context StringValueFunctionContext, locationRange LocationRange, count IntValue) Value {
+
+	n := count.ToInt(locationRange)
+	if n < 0 {
+		panic(NegativeStringRepeatCountError{
+			Count:         n,
+			LocationRange: locationRange,
+		})
+	}
+
+	// Compute the length of the result in 64 bits,
+	// so the product can't overflow on platforms where int is 32 bits,
+	// and the result is never under-metered.
+	high, newLength64 := bits.Mul64(uint64(len(v.Str)), uint64(n))
+	if high != 0 || newLength64 > goMaxInt {
+		panic(OverflowError{
+			LocationRange: locationRange,
+		})
+	}
+
+	newLength := int(newLength64)
+
+	// Meter before allocating
+	common.UseMemory(context, common.NewStringMemoryUsage(newLength))
+
+	// NewUnmeteredStringValue normalizes (= allocates)
+	common.UseMemory(context, common.NewRawStringMemoryUsage(newLength))
+
+	// Meter computation as if the string was iterated count times.
+	context.ReportComputation(common.ComputationKindLoop, uint(newLength))
+
+	return NewUnmeteredStringValue(strings.Repeat(v.Str, n))
+}
+
+// Center returns a new string which contains the string centered in a string of the given width,
+// padded on both sides with the given character. The extra character of an odd padding is added on the right.
+// If the string is already at least as long as the given width, the string itself is returned.
+func (v *StringValue) Center(
+	context StringValueFunctionContext,
+	locationRange LocationRange,
+	width IntValue,
+	character CharacterValue,
+) Value {
+
+	padding := width.ToInt(locationRange) - v.Length()
+	if padding <= 0 {
+		return v
+	}
+
+	// Compute the length of the result in 64 bits,
+	// so the product can't overflow on platforms where int is 32 bits,
+	// and the result is never under-metered.
+	high, paddingLength64 := bits.Mul64(uint64(len(character.Str)), uint64(padding))
+	if high != 0 || paddingLength64 > uint64(goMaxInt-len(v.Str)) {
+		panic(OverflowError{
+			LocationRange: locationRange,
+		})
+	}
+
+	newLength := len(v.Str) + int(paddingLength64)
+
+	// Meter before allocating
+	common.UseMemory(context, common.NewStringMemoryUsage(newLength))
+
+	// NewUnmeteredStringValue normalizes (= allocates)
+	common.UseMemory(context, common.NewRawStringMemoryUsage(newLength))
+
+	context.ReportComputation(common.ComputationKindLoop, uint(padding))
+
+	leftPadding := padding / 2
+	rightPadding := padding - leftPadding
+
+	var builder strings.Builder
+	builder.Grow(newLength)
+	for i := 0; i < leftPadding; i++ {
+		builder.WriteString(character.Str)
+	}
+	builder.WriteString(v.Str)
+	for i := 0; i < rightPadding; i++ {
+		builder.WriteString(character.Str)
+	}
+
+	return NewUnmeteredStringValue(builder.String())
 }
 
 var EmptyString = NewUnmeteredStringValue("")
@@ -256,6 +351,37 @@ var EmptyString = NewUnmeteredStringValue("")
 func (v *StringValue) Slice(from IntValue, to IntValue, locationRange LocationRange) Value {
 	fromIndex := from.ToInt(locationRange)
 	toIndex := to.ToInt(locationRange)
+
+	// Negative indices are offsets from the end of the string.
+	// Normalize them before validating the indices.
+
+	if fromIndex < 0 || toIndex < 0 {
+		length := v.Length()
+
+		normalizedFromIndex := fromIndex
+		if normalizedFromIndex < 0 {
+			normalizedFromIndex += length
+		}
+
+		normalizedToIndex := toIndex
+		if normalizedToIndex < 0 {
+			normalizedToIndex += length
+		}
+
+		// Report the original indices if they are out of bounds even after normalization
+		if normalizedFromIndex < 0 || normalizedToIndex < 0 {
+			panic(StringSliceIndicesError{
+				FromIndex:     fromIndex,
+				UpToIndex:     toIndex,
+				Length:        length,
+				LocationRange: locationRange,
+			})
+		}
+
+		fromIndex = normalizedFromIndex
+		toIndex = normalizedToIndex
+	}
+
 	return v.slice(fromIndex, toIndex, locationRange)
 }
 
@@ -360,9 +486,31 @@ func (v *StringValue) GetMember(context MemberAccessibleContext, locationRange L
 		length := v.Length()
 		return NewIntValueFromInt64(context, int64(length))
 
+	case sema.StringTypeByteLengthFieldName:
+		return NewIntValueFromInt64(context, int64(len(v.Str)))
+
+	case sema.StringTypeScalarCountFieldName:
+		return NewIntValueFromInt64(context, int64(utf8.RuneCountInString(v.Str)))
+
+	case sema.StringTypeIsASCIIFieldName:
+		return BoolValue(v.IsASCII())
+
 	case sema.StringTypeUtf8FieldName:
 		return ByteSliceToByteArrayValue(context, []byte(v.Str))
 
+	case sema.StringTypeToBytesFunctionName:
+		return NewBoundHostFunctionValue(
+			context,
+			v,
+			sema.StringTypeToBytesFunctionType,
+			func(v *StringValue, invocation Invocation) Value {
+				return ByteSliceToByteArrayValue(invocation.InvocationContext, []byte(v.Str))
+			},
+		)
+
+	case sema.StringTypeCodePointsFieldName:
+		return v.CodePoints(context)
+
 	case sema.StringTypeConcatFunctionName:
 		return NewBoundHostFunctionValue(
 			context,
@@ -380,6 +528,50 @@ func (v *StringValue) GetMember(context MemberAccessibleContext, locationRange L
 			},
 		)
 
+	case sema.StringTypeRepeatFunctionName:
+		return NewBoundHostFunctionValue(
+			context,
+			v,
+			sema.StringTypeRepeatFunctionType,
+			func(v *StringValue, invocation Invocation) Value {
+				count, ok := invocation.Arguments[0].(IntValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				return v.Repeat(
+					invocation.InvocationContext,
+					invocation.LocationRange,
+					count,
+				)
+			},
+		)
+
+	case sema.StringTypeCenterFunctionName:
+		return NewBoundHostFunctionValue(
+			context,
+			v,
+			sema.StringTypeCenterFunctionType,
+			func(v *StringValue, invocation Invocation) Value {
+				width, ok := invocation.Arguments[0].(IntValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				character, ok := invocation.Arguments[1].(CharacterValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				return v.Center(
+					invocation.InvocationContext,
+					invocation.LocationRange,
+					width,
+					character,
+				)
+			},
+		)
+
 	case sema.StringTypeSliceFunctionName:
 		return NewBoundHostFunctionValue(
 			context,
@@ -415,6 +607,21 @@ func (v *StringValue) GetMember(context MemberAccessibleContext, locationRange L
 			},
 		)
 
+	case sema.StringTypeMatchesFunctionName:
+		return NewBoundHostFunctionValue(
+			context,
+			v,
+			sema.StringTypeMatchesFunctionType,
+			func(v *StringValue, invocation Invocation) Value {
+				pattern, ok := invocation.Arguments[0].(*StringValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				return v.Matches(invocation.InvocationContext, pattern)
+			},
+		)
+
 	case sema.StringTypeIndexFunctionName:
 		return NewBoundHostFunctionValue(
 			context,
@@ -426,7 +633,41 @@ func (v *StringValue) GetMember(context MemberAccessibleContext, locationRange L
 					panic(errors.NewUnreachableError())
 				}
 
-				return v.IndexOf(invocation.InvocationContext, other)
+				// `from` parameter is optional
+				fromIndex := 0
+				if len(invocation.Arguments) > 1 {
+					fromValue, ok := invocation.Arguments[1].(IntValue)
+					if !ok {
+						panic(errors.NewUnreachableError())
+					}
+
+					fromIndex = fromValue.ToInt(invocation.LocationRange)
+				}
+
+				return v.IndexOf(
+					invocation.InvocationContext,
+					invocation.LocationRange,
+					other,
+					fromIndex,
+				)
+			},
+		)
+
+	case sema.StringTypeLastIndexFunctionName:
+		return NewBoundHostFunctionValue(
+			context,
+			v,
+			sema.StringTypeLastIndexFunctionType,
+			func(v *StringValue, invocation Invocation) Value {
+				other, ok := invocation.Arguments[0].(*StringValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				return v.LastIndexOf(
+					invocation.InvocationContext,
+					other,
+				)
 			},
 		)
 
@@ -472,6 +713,26 @@ func (v *StringValue) GetMember(context MemberAccessibleContext, locationRange L
 			},
 		)
 
+	case sema.StringTypeTrimStartFunctionName:
+		return NewBoundHostFunctionValue(
+			context,
+			v,
+			sema.StringTypeTrimStartFunctionType,
+			func(v *StringValue, invocation Invocation) Value {
+				return v.TrimStart(invocation.InvocationContext)
+			},
+		)
+
+	case sema.StringTypeTrimEndFunctionName:
+		return NewBoundHostFunctionValue(
+			context,
+			v,
+			sema.StringTypeTrimEndFunctionType,
+			func(v *StringValue, invocation Invocation) Value {
+				return v.TrimEnd(invocation.InvocationContext)
+			},
+		)
+
 	case sema.StringTypeSplitFunctionName:
 		return NewBoundHostFunctionValue(
 			context,
@@ -483,10 +744,79 @@ func (v *StringValue) GetMember(context MemberAccessibleContext, locationRange L
 					panic(errors.NewUnreachableError())
 				}
 
+				// `limit` parameter is optional
+				limit := 0
+				if len(invocation.Arguments) > 1 {
+					limitValue, ok := invocation.Arguments[1].(IntValue)
+					if !ok {
+						panic(errors.NewUnreachableError())
+					}
+
+					limit = limitValue.ToInt(invocation.LocationRange)
+				}
+
 				return v.Split(
 					invocation.InvocationContext,
 					invocation.LocationRange,
 					separator,
+					limit,
+				)
+			},
+		)
+
+	case sema.StringTypeSplitIndicesFunctionName:
+		return NewBoundHostFunctionValue(
+			context,
+			v,
+			sema.StringTypeSplitIndicesFunctionType,
+			func(v *StringValue, invocation Invocation) Value {
+				separator, ok := invocation.Arguments[0].(*StringValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				return v.SplitIndices(
+					invocation.InvocationContext,
+					invocation.LocationRange,
+					separator,
+				)
+			},
+		)
+
+	case sema.StringTypeTokensFunctionName:
+		return NewBoundHostFunctionValue(
+			context,
+			v,
+			sema.StringTypeTokensFunctionType,
+			func(v *StringValue, invocation Invocation) Value {
+				separator, ok := invocation.Arguments[0].(*StringValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				return NewStringSplitIteratorValue(
+					invocation.InvocationContext,
+					v,
+					separator,
+				)
+			},
+		)
+
+	case sema.StringTypeChunkedFunctionName:
+		return NewBoundHostFunctionValue(
+			context,
+			v,
+			sema.StringTypeChunkedFunctionType,
+			func(v *StringValue, invocation Invocation) Value {
+				size, ok := invocation.Arguments[0].(IntValue)
+				if !ok {
+					panic(errors.NewUnreachableError())
+				}
+
+				return v.Chunked(
+					invocation.InvocationContext,
+					invocation.LocationRange,
+					size.ToInt(invocation.LocationRange),
 				)
 			},
 		)
@@ -507,6 +837,22 @@ func (v *StringValue) GetMember(context MemberAccessibleContext, locationRange L
 					panic(errors.NewUnreachableError())
 				}
 
+				if len(invocation.Arguments) > 2 {
+					caseInsensitive, ok := invocation.Arguments[2].(BoolValue)
+					if !ok {
+						panic(errors.NewUnreachableError())
+					}
+
+					if caseInsensitive {
+						return v.ReplaceAllCaseInsensitive(
+							invocation.InvocationContext,
+							invocation.LocationRange,
+							original,
+							replacement,
+						)
+					}
+				}
+
 				return v.ReplaceAll(
 					invocation.InvocationContext,
 					invocation.LocationRange,
@@ -564,48 +910,141 @@ func (v *StringValue) Length() int {
 	return v.length
 }
 
-func (v *StringValue) ToLower(interpreter StringValueFunctionContext) *StringValue {
+// IsASCII returns true if all bytes of the string are ASCII.
+// UTF-8 encodes non-ASCII scalars using bytes outside of the ASCII range only,
+// so the check stops at the first such byte.
+func (v *StringValue) IsASCII() bool {
+	for i := 0; i < len(v.Str); i++ {
+		if v.Str[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// TrimStart returns the string with leading whitespace characters removed.
+// Whole characters (grapheme clusters) are removed, and only if they consist of whitespace only,
+// so a combining mark following whitespace is preserved together with the whitespace.
+func (v *StringValue) TrimStart(context StringValueFunctionContext) *StringValue {
 
 	// Meter computation as if the string was iterated.
-	interpreter.ReportComputation(common.ComputationKindLoop, uint(len(v.Str)))
+	context.ReportComputation(common.ComputationKindLoop, uint(len(v.Str)))
 
-	// Over-estimate resulting string length,
-	// as an uppercase character may be converted to several lower-case characters, e.g İ => [i, ̇]
-	// see https://stackoverflow.com/questions/28683805/is-there-a-unicode-string-which-gets-longer-when-converted-to-lowercase
+	start := len(v.Str)
 
-	var lengthEstimate int
-	for _, r := range v.Str {
-		if r < unicode.MaxASCII {
-			lengthEstimate += 1
-		} else {
-			lengthEstimate += utf8.UTFMax
+	graphemes := uniseg.NewGraphemes(v.Str)
+	for graphemes.Next() {
+		if !isWhitespaceCharacter(graphemes.Str()) {
+			start, _ = graphemes.Positions()
+			break
 		}
 	}
 
-	memoryUsage := common.NewStringMemoryUsage(lengthEstimate)
-
-	return NewStringValue(
-		interpreter,
-		memoryUsage,
-		func() string {
-			return strings.ToLower(v.Str)
-		},
-	)
+	return v.trimmed(context, start, len(v.Str))
 }
 
-func (v *StringValue) Split(context ArrayCreationContext, locationRange LocationRange, separator *StringValue) *ArrayValue {
+// TrimEnd returns the string with trailing whitespace characters removed.
+// Whole characters (grapheme clusters) are removed, and only if they consist of whitespace only.
+func (v *StringValue) TrimEnd(context StringValueFunctionContext) *StringValue {
 
-	if len(separator.Str) == 0 {
-		return v.Explode(context, locationRange)
+	// Meter computation as if the string was iterated.
+	context.ReportComputation(common.ComputationKindLoop, uint(len(v.Str)))
+
+	end := 0
+
+	graphemes := uniseg.NewGraphemes(v.Str)
+	for graphemes.Next() {
+		if !isWhitespaceCharacter(graphemes.Str()) {
+			_, end = graphemes.Positions()
+		}
 	}
 
-	count := v.count(context, locationRange, separator) + 1
+	return v.trimmed(context, 0, end)
+}
 
-	partIndex := 0
+func (v *StringValue) trimmed(context StringValueFunctionContext, start, end int) *StringValue {
+	if start == 0 && end == len(v.Str) {
+		return v
+	}
 
-	remaining := v
+	str := v.Str[start:end]
 
-	return NewArrayValueWithIterator(
+	return NewStringValue(
+		context,
+		common.NewStringMemoryUsage(len(str)),
+		func() string {
+			return str
+		},
+	)
+}
+
+// isWhitespaceCharacter returns true if the given character (grapheme cluster)
+// only consists of whitespace.
+func isWhitespaceCharacter(character string) bool {
+	for _, r := range character {
+		if !unicode.IsSpace(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (v *StringValue) ToLower(interpreter StringValueFunctionContext) *StringValue {
+
+	// Meter computation as if the string was iterated.
+	interpreter.ReportComputation(common.ComputationKindLoop, uint(len(v.Str)))
+
+	// Over-estimate resulting string length,
+	// as an uppercase character may be converted to several lower-case characters, e.g İ => [i, ̇]
+	// see https://stackoverflow.com/questions/28683805/is-there-a-unicode-string-which-gets-longer-when-converted-to-lowercase
+
+	var lengthEstimate int
+	for _, r := range v.Str {
+		if r < unicode.MaxASCII {
+			lengthEstimate += 1
+		} else {
+			lengthEstimate += utf8.UTFMax
+		}
+	}
+
+	memoryUsage := common.NewStringMemoryUsage(lengthEstimate)
+
+	return NewStringValue(
+		interpreter,
+		memoryUsage,
+		func() string {
+			return strings.ToLower(v.Str)
+		},
+	)
+}
+
+// Split returns a Cadence array of type [String], containing the parts of the string split on the separator.
+// If limit is positive, the result has at most limit parts, and the last part is the remainder of the string.
+// If limit is zero or negative, the string is split fully.
+func (v *StringValue) Split(
+	context ArrayCreationContext,
+	locationRange LocationRange,
+	separator *StringValue,
+	limit int,
+) *ArrayValue {
+
+	if len(separator.Str) == 0 {
+		if limit > 0 && limit < v.Length() {
+			return v.explodeWithLimit(context, locationRange, limit)
+		}
+		return v.Explode(context, locationRange)
+	}
+
+	count := v.count(context, locationRange, separator) + 1
+	if limit > 0 && count > limit {
+		count = limit
+	}
+
+	partIndex := 0
+
+	remaining := v
+
+	return NewArrayValueWithIterator(
 		context,
 		VarSizedArrayOfStringType,
 		common.ZeroAddress,
@@ -648,6 +1087,191 @@ func (v *StringValue) Split(context ArrayCreationContext, locationRange Location
 	)
 }
 
+// SplitIndices returns a Cadence array of type [Int], which contains the character indices
+// at which the parts of the string start, when splitting it on the given separator,
+// i.e. the parts are the same as the elements returned by Split without a limit.
+func (v *StringValue) SplitIndices(
+	context ArrayCreationContext,
+	locationRange LocationRange,
+	separator *StringValue,
+) *ArrayValue {
+
+	// Splitting on the empty separator splits into characters
+	if len(separator.Str) == 0 {
+		length := v.Length()
+		index := 0
+
+		return NewArrayValueWithIterator(
+			context,
+			VarSizedArrayOfIntType,
+			common.ZeroAddress,
+			uint64(length),
+			func() Value {
+
+				context.ReportComputation(common.ComputationKindLoop, 1)
+
+				if index >= length {
+					return nil
+				}
+
+				value := NewIntValueFromInt64(context, int64(index))
+				index++
+				return value
+			},
+		)
+	}
+
+	count := v.count(context, locationRange, separator) + 1
+
+	separatorLength := separator.Length()
+
+	partIndex := 0
+	partStartIndex := 0
+
+	remaining := v
+
+	return NewArrayValueWithIterator(
+		context,
+		VarSizedArrayOfIntType,
+		common.ZeroAddress,
+		uint64(count),
+		func() Value {
+
+			context.ReportComputation(common.ComputationKindLoop, 1)
+
+			if partIndex >= count {
+				return nil
+			}
+
+			startIndex := partStartIndex
+
+			partIndex++
+
+			// Find the start of the next part, if any
+			if partIndex < count {
+				separatorCharacterIndex, _ := remaining.indexOf(context, separator)
+				if separatorCharacterIndex < 0 {
+					return nil
+				}
+
+				nextPartStartIndex := separatorCharacterIndex + separatorLength
+
+				partStartIndex += nextPartStartIndex
+
+				remaining = remaining.slice(
+					nextPartStartIndex,
+					remaining.Length(),
+					locationRange,
+				)
+			}
+
+			return NewIntValueFromInt64(context, int64(startIndex))
+		},
+	)
+}
+
+// Chunked returns a Cadence array of type [String], where each element contains the given number of characters,
+// except possibly the last element, which contains the remaining characters.
+// Characters are grapheme clusters, so no character is split across elements.
+func (v *StringValue) Chunked(
+	context ArrayCreationContext,
+	locationRange LocationRange,
+	size int,
+) *ArrayValue {
+
+	if size <= 0 {
+		panic(NonPositiveStringChunkSizeError{
+			Size:          size,
+			LocationRange: locationRange,
+		})
+	}
+
+	length := v.Length()
+
+	count := length / size
+	if length%size != 0 {
+		count++
+	}
+
+	// NOTE: use a separate graphemes iterator,
+	// the graphemes iterator of the value is reset by other functions, e.g. slice
+	graphemes := uniseg.NewGraphemes(v.Str)
+
+	return NewArrayValueWithIterator(
+		context,
+		VarSizedArrayOfStringType,
+		common.ZeroAddress,
+		uint64(count),
+		func() Value {
+
+			context.ReportComputation(common.ComputationKindLoop, 1)
+
+			var start, end int
+
+			characterCount := 0
+			for characterCount < size && graphemes.Next() {
+				if characterCount == 0 {
+					start, _ = graphemes.Positions()
+				}
+				_, end = graphemes.Positions()
+				characterCount++
+			}
+
+			if characterCount == 0 {
+				return nil
+			}
+
+			str := v.Str[start:end]
+
+			return NewStringValue(
+				context,
+				common.NewStringMemoryUsage(len(str)),
+				func() string {
+					return str
+				},
+			)
+		},
+	)
+}
+
+// explodeWithLimit returns a Cadence array of type [String] with the given number of elements,
+// where each element but the last is a single character of the string,
+// and the last element is the remainder of the string.
+// The limit must be positive and less than the length of the string.
+func (v *StringValue) explodeWithLimit(
+	context ArrayCreationContext,
+	locationRange LocationRange,
+	limit int,
+) *ArrayValue {
+
+	partIndex := 0
+
+	return NewArrayValueWithIterator(
+		context,
+		VarSizedArrayOfStringType,
+		common.ZeroAddress,
+		uint64(limit),
+		func() Value {
+
+			context.ReportComputation(common.ComputationKindLoop, 1)
+
+			if partIndex >= limit {
+				return nil
+			}
+
+			start := partIndex
+			partIndex++
+
+			// Set the remainder as the last part
+			if partIndex == limit {
+				return v.slice(start, v.Length(), locationRange)
+			}
+
+			return v.slice(start, partIndex, locationRange)
+		},
+	)
+}
+
 // Explode returns a Cadence array of type [String], where each element is a single character of the string
 func (v *StringValue) Explode(context ArrayCreationContext, locationRange LocationRange) *ArrayValue {
 
@@ -682,6 +1306,34 @@ func (v *StringValue) Explode(context ArrayCreationContext, locationRange Locati
 	)
 }
 
+// CodePoints returns the Unicode scalar values of the string as a `[UInt32]` array
+func (v *StringValue) CodePoints(context ArrayCreationContext) *ArrayValue {
+	str := v.Str
+	offset := 0
+
+	return NewArrayValueWithIterator(
+		context,
+		VarSizedArrayOfUInt32Type,
+		common.ZeroAddress,
+		uint64(utf8.RuneCountInString(str)),
+		func() Value {
+			if offset >= len(str) {
+				return nil
+			}
+
+			r, size := utf8.DecodeRuneInString(str[offset:])
+			offset += size
+
+			return NewUInt32Value(
+				context,
+				func() uint32 {
+					return uint32(r)
+				},
+			)
+		},
+	)
+}
+
 func (v *StringValue) ReplaceAll(
 	context StringValueFunctionContext,
 	locationRange LocationRange,
@@ -742,8 +1394,110 @@ func (v *StringValue) ReplaceAll(
 	)
 }
 
+// ReplaceAllCaseInsensitive returns a copy of the string with all non-overlapping
+// occurrences of original replaced by replacement, ignoring case.
+// Characters are compared after lowercasing them, using the same rules as toLower.
+// The replacement is inserted verbatim.
+func (v *StringValue) ReplaceAllCaseInsensitive(
+	context StringValueFunctionContext,
+	locationRange LocationRange,
+	original *StringValue,
+	replacement *StringValue,
+) *StringValue {
+
+	// Matching the empty string is not affected by case
+	if len(original.Str) == 0 || len(v.Str) == 0 {
+		return v.ReplaceAll(context, locationRange, original, replacement)
+	}
+
+	// Meter computation as if the string was iterated.
+	// This is a conservative over-estimation.
+	context.ReportComputation(common.ComputationKindLoop, uint(len(v.Str)*len(original.Str)))
+
+	originalCharacters := lowerGraphemes(original.Str)
+
+	var characters []string
+	var characterOffsets []int
+
+	graphemes := uniseg.NewGraphemes(v.Str)
+	for graphemes.Next() {
+		start, _ := graphemes.Positions()
+		characters = append(characters, strings.ToLower(graphemes.Str()))
+		characterOffsets = append(characterOffsets, start)
+	}
+	characterOffsets = append(characterOffsets, len(v.Str))
+
+	// Find the byte ranges of all non-overlapping matches, left to right
+
+	var matchOffsets [][2]int
+
+	for characterIndex := 0; characterIndex+len(originalCharacters) <= len(characters); {
+		if !slices.Equal(characters[characterIndex:characterIndex+len(originalCharacters)], originalCharacters) {
+			characterIndex++
+			continue
+		}
+
+		endCharacterIndex := characterIndex + len(originalCharacters)
+		matchOffsets = append(
+			matchOffsets,
+			[2]int{
+				characterOffsets[characterIndex],
+				characterOffsets[endCharacterIndex],
+			},
+		)
+		characterIndex = endCharacterIndex
+	}
+
+	if len(matchOffsets) == 0 {
+		return v
+	}
+
+	newByteLength := len(v.Str)
+	for _, offsets := range matchOffsets {
+		newByteLength += len(replacement.Str) - (offsets[1] - offsets[0])
+	}
+
+	memoryUsage := common.NewStringMemoryUsage(newByteLength)
+
+	return NewStringValue(
+		context,
+		memoryUsage,
+		func() string {
+			var b strings.Builder
+			b.Grow(newByteLength)
+			previousEnd := 0
+			for _, offsets := range matchOffsets {
+				b.WriteString(v.Str[previousEnd:offsets[0]])
+				b.WriteString(replacement.Str)
+				previousEnd = offsets[1]
+			}
+			b.WriteString(v.Str[previousEnd:])
+			return b.String()
+		},
+	)
+}
+
+// stringGraphemes returns the grapheme clusters of the given string
+func stringGraphemes(s string) []string {
+	var result []string
+	graphemes := uniseg.NewGraphemes(s)
+	for graphemes.Next() {
+		result = append(result, graphemes.Str())
+	}
+	return result
+}
+
+// lowerGraphemes returns the lowercased grapheme clusters of the given string
+func lowerGraphemes(s string) []string {
+	result := stringGraphemes(s)
+	for i, grapheme := range result {
+		result[i] = strings.ToLower(grapheme)
+	}
+	return result
+}
+
 func (v *StringValue) Storable(storage atree.SlabStorage, address atree.Address, maxInlineSize uint64) (atree.Storable, error) {
-	return values.MaybeLargeImmutableStorable(v, storage, address, maxInlineSize)
+	return maybeLargeImmutableStringStorable(v, storage, address, maxInlineSize)
 }
 
 func (*StringValue) NeedsStoreTo(_ atree.Address) bool {
@@ -966,18 +1720,77 @@ func (v *StringValue) isGraphemeBoundaryEndPrepared(end int) bool {
 	}
 }
 
-func (v *StringValue) IndexOf(context StringValueFunctionContext, other *StringValue) IntValue {
-	index, _ := v.indexOf(context, other)
+// IndexOf returns the character index of the first occurrence of the given string,
+// starting the search at the given character index, or -1 if the string is not found.
+func (v *StringValue) IndexOf(
+	context StringValueFunctionContext,
+	locationRange LocationRange,
+	other *StringValue,
+	fromIndex int,
+) IntValue {
+	if fromIndex == 0 {
+		index, _ := v.indexOf(context, other)
+		return NewIntValueFromInt64(context, int64(index))
+	}
+
+	length := v.Length()
+
+	if fromIndex < 0 || fromIndex > length {
+		panic(StringIndexOutOfBoundsError{
+			Index:         fromIndex,
+			Length:        length,
+			LocationRange: locationRange,
+		})
+	}
+
+	remaining := v.slice(fromIndex, length, locationRange)
+
+	index, _ := remaining.indexOf(context, other)
+	if index >= 0 {
+		index += fromIndex
+	}
+
 	return NewIntValueFromInt64(context, int64(index))
 }
 
-func (v *StringValue) indexOf(reporter ComputationReporter, other *StringValue) (characterIndex int, byteOffset int) {
-
+// LastIndexOf returns the character index of the last occurrence of the given string,
+// or -1 if the given string does not occur.
+// If the given string is empty, the length of the string is returned.
+func (v *StringValue) LastIndexOf(
+	context StringValueFunctionContext,
+	other *StringValue,
+) IntValue {
 	if len(other.Str) == 0 {
-		return 0, 0
+		return NewIntValueFromInt64(context, int64(v.Length()))
 	}
 
-	// If the string is empty, exit early.
+	if len(v.Str) == 0 {
+		return NewIntValueFromInt64(context, -1)
+	}
+
+	// Meter computation as if the string was iterated.
+	// This is a conservative over-estimation.
+	context.ReportComputation(common.ComputationKindLoop, uint(len(v.Str)*len(other.Str)))
+
+	characters := stringGraphemes(v.Str)
+	otherCharacters := stringGraphemes(other.Str)
+
+	for characterIndex := len(characters) - len(otherCharacters); characterIndex >= 0; characterIndex-- {
+		if slices.Equal(characters[characterIndex:characterIndex+len(otherCharacters)], otherCharacters) {
+			return NewIntValueFromInt64(context, int64(characterIndex))
+		}
+	}
+
+	return NewIntValueFromInt64(context, -1)
+}
+
+func (v *StringValue) indexOf(reporter ComputationReporter, other *StringValue) (characterIndex int, byteOffset int) {
+
+	if len(other.Str) == 0 {
+		return 0, 0
+	}
+
+	// If the string is empty, exit early.
 	//
 	// That ensures that if the checked value is the empty string singleton EmptyString,
 	// which should not be mutated because it may be used from different goroutines,
@@ -1047,6 +1860,126 @@ func (v *StringValue) Contains(context StringValueFunctionContext, other *String
 	return characterIndex >= 0
 }
 
+// Matches returns true if the whole string matches the given wildcard pattern.
+// The wildcard '*' matches any sequence of characters, and the wildcard '?' matches exactly one character.
+// Wildcards can be escaped with a backslash.
+func (v *StringValue) Matches(context StringValueFunctionContext, pattern *StringValue) BoolValue {
+
+	// Meter computation as if the string was iterated for each character of the pattern.
+	// This is a conservative over-estimation.
+	context.ReportComputation(common.ComputationKindLoop, uint((len(v.Str)+1)*(len(pattern.Str)+1)))
+
+	return BoolValue(matchWildcardPattern(
+		stringGraphemes(v.Str),
+		parseWildcardPattern(pattern.Str),
+	))
+}
+
+type wildcardPatternElementKind uint8
+
+const (
+	wildcardPatternElementKindLiteral wildcardPatternElementKind = iota
+	wildcardPatternElementKindAny
+	wildcardPatternElementKindAnySequence
+)
+
+type wildcardPatternElement struct {
+	kind wildcardPatternElementKind
+	// character is the matched character, if the kind is wildcardPatternElementKindLiteral
+	character string
+}
+
+// parseWildcardPattern parses the given wildcard pattern into its elements.
+// A backslash escapes the following character. A trailing backslash matches itself.
+func parseWildcardPattern(pattern string) []wildcardPatternElement {
+	characters := stringGraphemes(pattern)
+
+	elements := make([]wildcardPatternElement, 0, len(characters))
+
+	for i := 0; i < len(characters); i++ {
+		character := characters[i]
+
+		switch character {
+		case "*":
+			elements = append(elements, wildcardPatternElement{
+				kind: wildcardPatternElementKindAnySequence,
+			})
+
+		case "?":
+			elements = append(elements, wildcardPatternElement{
+				kind: wildcardPatternElementKindAny,
+			})
+
+		case "\\":
+			if i+1 < len(characters) {
+				i++
+				character = characters[i]
+			}
+			fallthrough
+
+		default:
+			elements = append(elements, wildcardPatternElement{
+				kind:      wildcardPatternElementKindLiteral,
+				character: character,
+			})
+		}
+	}
+
+	return elements
+}
+
+// matchWildcardPattern returns true if the given characters match the given pattern elements.
+// When a mismatch occurs, the last any-sequence wildcard is extended by one character, if any.
+func matchWildcardPattern(characters []string, pattern []wildcardPatternElement) bool {
+	characterIndex := 0
+	patternIndex := 0
+
+	anySequenceIndex := -1
+	anySequenceCharacterIndex := 0
+
+	for characterIndex < len(characters) {
+		if patternIndex < len(pattern) {
+			element := pattern[patternIndex]
+
+			switch element.kind {
+			case wildcardPatternElementKindAnySequence:
+				anySequenceIndex = patternIndex
+				anySequenceCharacterIndex = characterIndex
+				patternIndex++
+				continue
+
+			case wildcardPatternElementKindAny:
+				characterIndex++
+				patternIndex++
+				continue
+
+			case wildcardPatternElementKindLiteral:
+				if element.character == characters[characterIndex] {
+					characterIndex++
+					patternIndex++
+					continue
+				}
+			}
+		}
+
+		if anySequenceIndex < 0 {
+			return false
+		}
+
+		patternIndex = anySequenceIndex + 1
+		anySequenceCharacterIndex++
+		characterIndex = anySequenceCharacterIndex
+	}
+
+	for patternIndex < len(pattern) &&
+		pattern[patternIndex].kind == wildcardPatternElementKindAnySequence {
+
+		patternIndex++
+	}
+
+	return patternIndex == len(pattern)
+}
+
 func (v *StringValue) Count(context StringValueFunctionContext, locationRange LocationRange, other *StringValue) IntValue {
 	index := v.count(context, locationRange, other)
 	return NewIntValueFromInt64(context, int64(index))
@@ -1124,14 +2057,41 @@ func stringFunctionFromUtf8(invocation Invocation) Value {
 	}
 
 	inter := invocation.InvocationContext
-	// naively read the entire byte array before validating
-	buf, err := ByteArrayValueToByteSlice(inter, argument, invocation.LocationRange)
+	locationRange := invocation.LocationRange
+
+	// Validate the bytes while reading the byte array,
+	// so that reading stops at the first invalid sequence,
+	// instead of reading the entire byte array before validating
+
+	var buf []byte
+	validated := 0
+	valid := true
+	var err error
+
+	argument.Iterate(
+		inter,
+		func(element Value) (resume bool) {
+			var b byte
+			b, err = ByteValueToByte(inter, element, locationRange)
+			if err != nil {
+				return false
+			}
+
+			buf = append(buf, b)
+
+			validated, valid = validateUTF8Prefix(buf, validated)
+			return valid
+		},
+		false,
+		locationRange,
+	)
 
 	if err != nil {
 		panic(errors.NewExternalError(err))
 	}
 
-	if !utf8.Valid(buf) {
+	// The byte array may end with an incomplete sequence
+	if !valid || validated < len(buf) {
 		return Nil
 	}
 
@@ -1145,6 +2105,25 @@ func stringFunctionFromUtf8(invocation Invocation) Value {
 	)
 }
 
+// validateUTF8Prefix validates the UTF-8 sequences of buf, starting at the given offset,
+// which must be the end of the already validated prefix.
+// It returns the end of the validated prefix, and false if buf contains an invalid sequence.
+// A trailing incomplete, but possibly valid sequence is not validated yet.
+func validateUTF8Prefix(buf []byte, offset int) (int, bool) {
+	for offset < len(buf) {
+		r, size := utf8.DecodeRune(buf[offset:])
+		if r == utf8.RuneError && size <= 1 {
+			if !utf8.FullRune(buf[offset:]) {
+				// Incomplete sequence, wait for more bytes
+				break
+			}
+			return offset, false
+		}
+		offset += size
+	}
+	return offset, true
+}
+
 func stringFunctionFromCharacters(invocation Invocation) Value {
 	argument, ok := invocation.Arguments[0].(*ArrayValue)
 	if !ok {
@@ -1180,6 +2159,106 @@ func stringFunctionFromCharacters(invocation Invocation) Value {
 	return NewUnmeteredStringValue(builder.String())
 }
 
+func stringFunctionFromCodePoints(invocation Invocation) Value {
+	argument, ok := invocation.Arguments[0].(*ArrayValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	inter := invocation.InvocationContext
+
+	// NewStringMemoryUsage already accounts for empty string.
+	common.UseMemory(inter, common.NewStringMemoryUsage(0))
+	var builder strings.Builder
+
+	argument.Iterate(
+		inter,
+		func(element Value) (resume bool) {
+			codePoint, ok := element.(UInt32Value)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+
+			r := rune(codePoint)
+			if !utf8.ValidRune(r) {
+				panic(InvalidCodePointError{
+					CodePoint:     uint32(codePoint),
+					LocationRange: invocation.LocationRange,
+				})
+			}
+
+			// Construct directly instead of using NewStringMemoryUsage to avoid
+			// having to decrement by 1 due to double counting of empty string.
+			common.UseMemory(inter,
+				common.MemoryUsage{
+					Kind:   common.MemoryKindStringValue,
+					Amount: uint64(utf8.RuneLen(r)),
+				},
+			)
+			builder.WriteRune(r)
+
+			return true
+		},
+		false,
+		invocation.LocationRange,
+	)
+
+	return NewUnmeteredStringValue(builder.String())
+}
+
+func stringFunctionConcatAll(invocation Invocation) Value {
+	stringArray, ok := invocation.Arguments[0].(*ArrayValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	inter := invocation.InvocationContext
+
+	switch stringArray.Count() {
+	case 0:
+		return EmptyString
+	case 1:
+		return stringArray.Get(inter, invocation.LocationRange, 0)
+	}
+
+	// Collect the strings first, so the result can be metered and allocated once
+
+	strs := make([]string, 0, stringArray.Count())
+	length := 0
+
+	stringArray.Iterate(
+		inter,
+		func(element Value) (resume bool) {
+
+			// Meter computation for iterating the array.
+			inter.ReportComputation(common.ComputationKindLoop, 1)
+
+			str, ok := element.(*StringValue)
+			if !ok {
+				panic(errors.NewUnreachableError())
+			}
+
+			strs = append(strs, str.Str)
+			length += len(str.Str)
+
+			return true
+		},
+		false,
+		invocation.LocationRange,
+	)
+
+	common.UseMemory(inter, common.NewStringMemoryUsage(length))
+
+	var builder strings.Builder
+	builder.Grow(length)
+
+	for _, str := range strs {
+		builder.WriteString(str)
+	}
+
+	return NewUnmeteredStringValue(builder.String())
+}
+
 func stringFunctionJoin(invocation Invocation) Value {
 	stringArray, ok := invocation.Arguments[0].(*ArrayValue)
 	if !ok {
@@ -1250,6 +2329,167 @@ func stringFunctionJoin(invocation Invocation) Value {
 	return NewUnmeteredStringValue(builder.String())
 }
 
+func stringFunctionFormat(invocation Invocation) Value {
+	format, ok := invocation.Arguments[0].(*StringValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	arguments, ok := invocation.Arguments[1].(*ArrayValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	inter := invocation.InvocationContext
+	locationRange := invocation.LocationRange
+
+	argumentCount := arguments.Count()
+
+	// NewStringMemoryUsage already accounts for empty string.
+	common.UseMemory(inter, common.NewStringMemoryUsage(0))
+	var builder strings.Builder
+
+	// Construct directly instead of using NewStringMemoryUsage to avoid
+	// having to decrement by 1 due to double counting of empty string.
+	write := func(str string) {
+		common.UseMemory(inter,
+			common.MemoryUsage{
+				Kind:   common.MemoryKindStringValue,
+				Amount: uint64(len(str)),
+			},
+		)
+		builder.WriteString(str)
+	}
+
+	str := format.Str
+	length := len(str)
+	literalStart := 0
+
+	for i := 0; i < length; i++ {
+
+		// Meter computation for iterating the format string.
+		inter.ReportComputation(common.ComputationKindLoop, 1)
+
+		switch str[i] {
+		case '{':
+			write(str[literalStart:i])
+
+			// Escaped opening brace
+			if i+1 < length && str[i+1] == '{' {
+				i++
+				literalStart = i
+				continue
+			}
+
+			end := strings.IndexByte(str[i+1:], '}')
+			if end < 0 {
+				panic(InvalidStringFormatError{
+					Format:        str,
+					Offset:        i,
+					LocationRange: locationRange,
+				})
+			}
+			end += i + 1
+
+			placeholder := str[i+1 : end]
+			index, err := strconv.Atoi(placeholder)
+			if err != nil ||
+				// Only allow plain decimal digits, e.g. reject signs
+				placeholder[0] < '0' || placeholder[0] > '9' {
+
+				panic(InvalidStringFormatError{
+					Format:        str,
+					Offset:        i,
+					LocationRange: locationRange,
+				})
+			}
+
+			if index >= argumentCount {
+				panic(StringFormatArgumentIndexOutOfBoundsError{
+					Index:         index,
+					Count:         argumentCount,
+					LocationRange: locationRange,
+				})
+			}
+
+			argument := arguments.Get(inter, locationRange, index)
+			write(formatArgumentString(inter, argument, locationRange))
+
+			i = end
+			literalStart = i + 1
+
+		case '}':
+			write(str[literalStart:i])
+
+			// Escaped closing brace
+			if i+1 < length && str[i+1] == '}' {
+				i++
+				literalStart = i
+				continue
+			}
+
+			panic(InvalidStringFormatError{
+				Format:        str,
+				Offset:        i,
+				LocationRange: locationRange,
+			})
+		}
+	}
+
+	write(str[literalStart:])
+
+	return NewUnmeteredStringValue(builder.String())
+}
+
+// formatArgumentString returns the string representation of an argument of `String.format`.
+// Strings and characters are substituted as-is, i.e. without quotes and escaping.
+func formatArgumentString(context ValueStringContext, argument Value, locationRange LocationRange) string {
+	switch argument := argument.(type) {
+	case *StringValue:
+		return argument.Str
+	case CharacterValue:
+		return argument.Str
+	default:
+		return argument.MeteredString(context, SeenReferences{}, locationRange)
+	}
+}
+
+func stringFunctionToInt(invocation Invocation) Value {
+	argument, ok := invocation.Arguments[0].(*StringValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	return intValueParser(invocation.InvocationContext, argument.Str)
+}
+
+func stringFunctionToUInt(invocation Invocation) Value {
+	argument, ok := invocation.Arguments[0].(*StringValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	return uintValueParser(invocation.InvocationContext, argument.Str)
+}
+
+func stringFunctionToFix64(invocation Invocation) Value {
+	argument, ok := invocation.Arguments[0].(*StringValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	return fix64ValueParser(invocation.InvocationContext, argument.Str)
+}
+
+func stringFunctionToUFix64(invocation Invocation) Value {
+	argument, ok := invocation.Arguments[0].(*StringValue)
+	if !ok {
+		panic(errors.NewUnreachableError())
+	}
+
+	return ufix64ValueParser(invocation.InvocationContext, argument.Str)
+}
+
 // stringFunction is the `String` function. It is stateless, hence it can be re-used across interpreters.
 // Type bound functions are static functions.
 var stringFunction = func() Value {
@@ -1293,6 +2533,14 @@ var stringFunction = func() Value {
 		),
 	)
 
+	addMember(
+		sema.StringTypeFromCodePointsFunctionName,
+		NewUnmeteredStaticHostFunctionValue(
+			sema.StringTypeFromCodePointsFunctionType,
+			stringFunctionFromCodePoints,
+		),
+	)
+
 	addMember(
 		sema.StringTypeJoinFunctionName,
 		NewUnmeteredStaticHostFunctionValue(
@@ -1301,5 +2549,53 @@ var stringFunction = func() Value {
 		),
 	)
 
+	addMember(
+		sema.StringTypeConcatAllFunctionName,
+		NewUnmeteredStaticHostFunctionValue(
+			sema.StringTypeConcatAllFunctionType,
+			stringFunctionConcatAll,
+		),
+	)
+
+	addMember(
+		sema.StringTypeFormatFunctionName,
+		NewUnmeteredStaticHostFunctionValue(
+			sema.StringTypeFormatFunctionType,
+			stringFunctionFormat,
+		),
+	)
+
+	addMember(
+		sema.StringTypeToIntFunctionName,
+		NewUnmeteredStaticHostFunctionValue(
+			sema.StringTypeToIntFunctionType,
+			stringFunctionToInt,
+		),
+	)
+
+	addMember(
+		sema.StringTypeToUIntFunctionName,
+		NewUnmeteredStaticHostFunctionValue(
+			sema.StringTypeToUIntFunctionType,
+			stringFunctionToUInt,
+		),
+	)
+
+	addMember(
+		sema.StringTypeToFix64FunctionName,
+		NewUnmeteredStaticHostFunctionValue(
+			sema.StringTypeToFix64FunctionType,
+			stringFunctionToFix64,
+		),
+	)
+
+	addMember(
+		sema.StringTypeToUFix64FunctionName,
+		NewUnmeteredStaticHostFunctionValue(
+			sema.StringTypeToUFix64FunctionType,
+			stringFunctionToUFix64,
+		),
+	)
+
 	return functionValue
 }()
@@ -0,0 +1,368 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"strings"
+
+	"github.com/onflow/atree"
+	"github.com/rivo/uniseg"
+
+	"github.com/onflow/cadence/common"
+	"github.com/onflow/cadence/errors"
+	"github.com/onflow/cadence/sema"
+)
+
+// StringSplitIteratorValue is an iterator over the substrings of a string,
+// split on a separator, which produces the substrings lazily.
+//
+// The substrings are the same as the elements of the array returned by StringValue.Split
+// without a limit.
+type StringSplitIteratorValue struct {
+	str       *StringValue
+	separator *StringValue
+	// offset is the byte offset of the part of the string which has not been split yet.
+	offset int
+	// done is true if all substrings have been returned.
+	done bool
+	// graphemes iterates over the characters (grapheme clusters) of the string,
+	// so separators are only matched at character boundaries.
+	// The iterator only advances, so splitting the whole string scans it once.
+	graphemes *uniseg.Graphemes
+	// boundary is the byte offset of the character boundary graphemes is positioned at.
+	boundary int
+}
+
+var stringSplitIteratorStaticType StaticType = ConvertSemaCompositeTypeToStaticCompositeType(nil, sema.StringSplitIteratorType)
+
+func NewStringSplitIteratorValue(
+	memoryGauge common.MemoryGauge,
+	str *StringValue,
+	separator *StringValue,
+) *StringSplitIteratorValue {
+	common.UseMemory(memoryGauge, common.StringSplitIteratorValueMemoryUsage)
+
+	return &StringSplitIteratorValue{
+		str:       str,
+		separator: separator,
+		graphemes: uniseg.NewGraphemes(str.Str),
+	}
+}
+
+var _ Value = &StringSplitIteratorValue{}
+var _ MemberAccessibleValue = &StringSplitIteratorValue{}
+var _ IterableValue = &StringSplitIteratorValue{}
+
+func (*StringSplitIteratorValue) IsValue() {}
+
+func (v *StringSplitIteratorValue) Accept(context ValueVisitContext, visitor Visitor, _ LocationRange) {
+	visitor.VisitStringSplitIteratorValue(context, v)
+}
+
+func (*StringSplitIteratorValue) Walk(_ ValueWalkContext, _ func(Value), _ LocationRange) {
+	// NO-OP
+}
+
+func (*StringSplitIteratorValue) StaticType(_ ValueStaticTypeContext) StaticType {
+	return stringSplitIteratorStaticType
+}
+
+func (*StringSplitIteratorValue) IsImportable(_ ValueImportableContext, _ LocationRange) bool {
+	return false
+}
+
+func (v *StringSplitIteratorValue) String() string {
+	return v.RecursiveString(SeenReferences{})
+}
+
+func (*StringSplitIteratorValue) RecursiveString(_ SeenReferences) string {
+	return "StringSplitIterator()"
+}
+
+func (v *StringSplitIteratorValue) MeteredString(context ValueStringContext, seenReferences SeenReferences, _ LocationRange) string {
+	common.UseMemory(context, common.StringSplitIteratorValueStringMemoryUsage)
+	return v.RecursiveString(seenReferences)
+}
+
+func (*StringSplitIteratorValue) ConformsToStaticType(
+	_ ValueStaticTypeConformanceContext,
+	_ LocationRange,
+	_ TypeConformanceResults,
+) bool {
+	return true
+}
+
+func (v *StringSplitIteratorValue) Storable(_ atree.SlabStorage, _ atree.Address, _ uint64) (atree.Storable, error) {
+	return NonStorable{Value: v}, nil
+}
+
+func (*StringSplitIteratorValue) NeedsStoreTo(_ atree.Address) bool {
+	return false
+}
+
+func (*StringSplitIteratorValue) IsResourceKinded(_ ValueStaticTypeContext) bool {
+	return false
+}
+
+func (v *StringSplitIteratorValue) Transfer(
+	context ValueTransferContext,
+	_ LocationRange,
+	_ atree.Address,
+	remove bool,
+	storable atree.Storable,
+	_ map[atree.ValueID]struct{},
+	_ bool,
+) Value {
+	// TODO: actually not needed, value is not storable
+	if remove {
+		RemoveReferencedSlab(context, storable)
+	}
+
+	// The iterator is a structure, so it has value semantics:
+	// Advancing the transferred iterator must not advance this iterator.
+	return v.copy(context)
+}
+
+func (v *StringSplitIteratorValue) Clone(_ ValueCloneContext) Value {
+	return v.copy(nil)
+}
+
+func (v *StringSplitIteratorValue) copy(memoryGauge common.MemoryGauge) *StringSplitIteratorValue {
+	common.UseMemory(memoryGauge, common.StringSplitIteratorValueMemoryUsage)
+
+	graphemes := *v.graphemes
+
+	return &StringSplitIteratorValue{
+		str:       v.str,
+		separator: v.separator,
+		offset:    v.offset,
+		done:      v.done,
+		graphemes: &graphemes,
+		boundary:  v.boundary,
+	}
+}
+
+func (*StringSplitIteratorValue) DeepRemove(_ ValueRemoveContext, _ bool) {
+	// NO-OP
+}
+
+func (v *StringSplitIteratorValue) GetMember(context MemberAccessibleContext, _ LocationRange, name string) Value {
+	switch name {
+	case sema.StringSplitIteratorTypeNextFunctionName:
+		return NewBoundHostFunctionValue(
+			context,
+			v,
+			sema.StringSplitIteratorTypeNextFunctionType,
+			func(v *StringSplitIteratorValue, invocation Invocation) Value {
+				part := v.Next(invocation.InvocationContext, invocation.LocationRange)
+				if part == nil {
+					return Nil
+				}
+				return NewSomeValueNonCopying(invocation.InvocationContext, part)
+			},
+		)
+	}
+
+	return nil
+}
+
+func (*StringSplitIteratorValue) RemoveMember(_ ValueTransferContext, _ LocationRange, _ string) Value {
+	// String split iterators have no removable members (fields / functions)
+	panic(errors.NewUnreachableError())
+}
+
+func (*StringSplitIteratorValue) SetMember(_ ValueTransferContext, _ LocationRange, _ string, _ Value) bool {
+	// String split iterators have no settable members (fields / functions)
+	panic(errors.NewUnreachableError())
+}
+
+// HasNext returns true if the iterator has not returned all substrings yet.
+func (v *StringSplitIteratorValue) HasNext() bool {
+	if v.done {
+		return false
+	}
+
+	// When splitting on the empty string, the string is split into its characters,
+	// so there are no substrings once the remaining string is empty.
+	if len(v.separator.Str) == 0 {
+		return v.offset < len(v.str.Str)
+	}
+
+	return true
+}
+
+// Next returns the next substring and advances the iterator,
+// or returns nil if all substrings have been returned.
+//
+// Computation is reported for the bytes of the string which are scanned,
+// so splitting the whole string is linear in its size.
+func (v *StringSplitIteratorValue) Next(context StringValueFunctionContext, _ LocationRange) *StringValue {
+	if !v.HasNext() {
+		v.done = true
+		return nil
+	}
+
+	start := v.offset
+	var end int
+
+	if len(v.separator.Str) == 0 {
+		v.seekNextBoundary()
+		end = v.boundary
+		v.offset = end
+	} else {
+		var found bool
+		end, found = v.indexOfSeparator()
+		if found {
+			v.offset = end + len(v.separator.Str)
+		} else {
+			// Return the remainder as the last part
+			end = len(v.str.Str)
+			v.offset = end
+			v.done = true
+		}
+	}
+
+	context.ReportComputation(common.ComputationKindLoop, uint(1+v.offset-start))
+
+	part := v.str.Str[start:end]
+
+	return NewStringValue(
+		context,
+		common.NewStringMemoryUsage(len(part)),
+		func() string {
+			return part
+		},
+	)
+}
+
+// indexOfSeparator returns the byte offset of the next occurrence of the separator
+// in the remaining part of the string which starts and ends at a character boundary, if any.
+func (v *StringSplitIteratorValue) indexOfSeparator() (byteOffset int, found bool) {
+	str := v.str.Str
+	separator := v.separator.Str
+
+	for searchStart := v.offset; searchStart < len(str); {
+		relativeByteOffset := strings.Index(str[searchStart:], separator)
+		if relativeByteOffset < 0 {
+			break
+		}
+
+		byteOffset = searchStart + relativeByteOffset
+
+		if v.seekBoundary(byteOffset) {
+			// Back up the graphemes iterator, so the iteration state can be restored
+			// in case the end of the separator is not at a character boundary,
+			// as the next occurrence of the separator may start before the end
+			graphemesBackup := *v.graphemes
+			boundaryBackup := v.boundary
+
+			if v.seekBoundary(byteOffset + len(separator)) {
+				return byteOffset, true
+			}
+
+			v.graphemes = &graphemesBackup
+			v.boundary = boundaryBackup
+		}
+
+		searchStart = byteOffset + 1
+	}
+
+	return -1, false
+}
+
+// seekBoundary advances the graphemes iterator to the first character boundary
+// at or after the given byte offset, and returns true if the byte offset is a character boundary.
+func (v *StringSplitIteratorValue) seekBoundary(byteOffset int) bool {
+	for v.boundary < byteOffset {
+		if !v.seekNextBoundary() {
+			return false
+		}
+	}
+	return v.boundary == byteOffset
+}
+
+// seekNextBoundary advances the graphemes iterator to the next character boundary,
+// and returns false if there is none.
+func (v *StringSplitIteratorValue) seekNextBoundary() bool {
+	if !v.graphemes.Next() {
+		return false
+	}
+	_, v.boundary = v.graphemes.Positions()
+	return true
+}
+
+// Iterator returns an iterator over the remaining substrings.
+// Iterating does not advance this iterator.
+func (v *StringSplitIteratorValue) Iterator(_ ValueStaticTypeContext, _ LocationRange) ValueIterator {
+	return StringSplitIteratorValueIterator{
+		iterator: v.copy(nil),
+	}
+}
+
+// ForEach calls the function for each of the remaining substrings.
+// Iterating does not advance this iterator.
+func (v *StringSplitIteratorValue) ForEach(
+	context IterableValueForeachContext,
+	_ sema.Type,
+	function func(value Value) (resume bool),
+	transferElements bool,
+	locationRange LocationRange,
+) {
+	iterator := v.Iterator(context, locationRange)
+	for {
+		value := iterator.Next(context, locationRange)
+		if value == nil {
+			return
+		}
+
+		if transferElements {
+			value = value.Transfer(
+				context,
+				locationRange,
+				atree.Address{},
+				false,
+				nil,
+				nil,
+				false, // value has a parent container because it is from iterator.
+			)
+		}
+
+		if !function(value) {
+			return
+		}
+	}
+}
+
+type StringSplitIteratorValueIterator struct {
+	iterator *StringSplitIteratorValue
+}
+
+var _ ValueIterator = StringSplitIteratorValueIterator{}
+
+func (i StringSplitIteratorValueIterator) Next(context ValueIteratorContext, locationRange LocationRange) Value {
+	value := i.iterator.Next(context, locationRange)
+	if value == nil {
+		return nil
+	}
+	return value
+}
+
+func (i StringSplitIteratorValueIterator) HasNext() bool {
+	return i.iterator.HasNext()
+}
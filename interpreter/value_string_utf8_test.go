@@ -0,0 +1,100 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package interpreter
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateUTF8Prefix(t *testing.T) {
+
+	t.Parallel()
+
+	// validate feeds the bytes one by one, like String.fromUTF8,
+	// and returns the number of bytes fed until the input was rejected,
+	// or -1 if the input was not rejected.
+	validate := func(input []byte) (validated int, fed int) {
+		var buf []byte
+		valid := true
+		for i, b := range input {
+			buf = append(buf, b)
+			validated, valid = validateUTF8Prefix(buf, validated)
+			if !valid {
+				return validated, i + 1
+			}
+		}
+		return validated, -1
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		for _, input := range []string{
+			"",
+			"abc",
+			"¥",
+			"ꙮ",
+			"😔",
+			"a̲\U0001F44D\U0001F3FD",
+			"�",
+		} {
+			validated, fed := validate([]byte(input))
+			assert.Equal(t, -1, fed, input)
+			assert.Equal(t, len(input), validated, input)
+		}
+	})
+
+	t.Run("incomplete", func(t *testing.T) {
+		t.Parallel()
+
+		// The incomplete trailing sequence is not rejected, but also not validated
+		validated, fed := validate([]byte{'a', 0xF0, 0x9F, 0x98})
+		assert.Equal(t, -1, fed)
+		assert.Equal(t, 1, validated)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		type testCase struct {
+			input     []byte
+			validated int
+			fed       int
+		}
+
+		for _, testCase := range []testCase{
+			{input: []byte{0xFF}, validated: 0, fed: 1},
+			{input: []byte{0xc3, 0x28}, validated: 0, fed: 2},
+			{input: []byte{'a', 'b', 0x80, 'c'}, validated: 2, fed: 3},
+			// Surrogate halves are invalid in UTF-8
+			{input: []byte{0xED, 0xA0, 0x80}, validated: 0, fed: 2},
+		} {
+			require.False(t, utf8.Valid(testCase.input))
+
+			validated, fed := validate(testCase.input)
+			assert.Equal(t, testCase.fed, fed, testCase.input)
+			assert.Equal(t, testCase.validated, validated, testCase.input)
+		}
+	})
+
+}
@@ -63,6 +63,7 @@ type Visitor interface {
 	VisitBoundFunctionValue(context ValueVisitContext, value BoundFunctionValue)
 	VisitStorageCapabilityControllerValue(context ValueVisitContext, v *StorageCapabilityControllerValue)
 	VisitAccountCapabilityControllerValue(context ValueVisitContext, v *AccountCapabilityControllerValue)
+	VisitStringSplitIteratorValue(context ValueVisitContext, v *StringSplitIteratorValue)
 }
 
 type EmptyVisitor struct {
@@ -110,6 +111,7 @@ type EmptyVisitor struct {
 	BoundFunctionValueVisitor               func(context ValueVisitContext, value BoundFunctionValue)
 	StorageCapabilityControllerValueVisitor func(context ValueVisitContext, value *StorageCapabilityControllerValue)
 	AccountCapabilityControllerValueVisitor func(context ValueVisitContext, value *AccountCapabilityControllerValue)
+	StringSplitIteratorValueVisitor         func(context ValueVisitContext, value *StringSplitIteratorValue)
 }
 
 var _ Visitor = &EmptyVisitor{}
@@ -421,3 +423,10 @@ func (v EmptyVisitor) VisitAccountCapabilityControllerValue(context ValueVisitCo
 	}
 	v.AccountCapabilityControllerValueVisitor(context, value)
 }
+
+func (v EmptyVisitor) VisitStringSplitIteratorValue(context ValueVisitContext, value *StringSplitIteratorValue) {
+	if v.StringSplitIteratorValueVisitor == nil {
+		return
+	}
+	v.StringSplitIteratorValueVisitor(context, value)
+}
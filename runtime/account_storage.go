@@ -19,6 +19,7 @@
 package runtime
 
 import (
+	"slices"
 	"sort"
 
 	"github.com/onflow/atree"
@@ -39,6 +40,11 @@ type AccountStorage struct {
 	// newAccountStorageMapSlabIndices contains root slab indices of new account storage maps.
 	// The indices are saved using Ledger.SetValue() during commit().
 	newAccountStorageMapSlabIndices map[common.Address]atree.SlabIndex
+
+	// removedAccountStorageMapAddresses contains the addresses of removed account storage maps
+	// which have an account storage register.
+	// The registers are removed using Ledger.SetValue() during commit().
+	removedAccountStorageMapAddresses map[common.Address]struct{}
 }
 
 func NewAccountStorage(
@@ -79,6 +85,19 @@ func (s *AccountStorage) GetDomainStorageMap(
 	return
 }
 
+// hasDomain returns true if the account storage map of the given account contains the given domain.
+func (s *AccountStorage) hasDomain(
+	address common.Address,
+	domain common.StorageDomain,
+) bool {
+	accountStorageMap := s.getAccountStorageMap(address)
+	if accountStorageMap == nil {
+		return false
+	}
+
+	return accountStorageMap.DomainExists(domain)
+}
+
 // getAccountStorageMap returns AccountStorageMap if exists, or nil otherwise.
 func (s *AccountStorage) getAccountStorageMap(
 	address common.Address,
@@ -94,6 +113,13 @@ func (s *AccountStorage) getAccountStorageMap(
 		}
 	}
 
+	// The account storage register of a removed account storage map
+	// is only removed on commit, so don't load the removed account storage map.
+
+	if s.isAccountStorageMapRemoved(address) {
+		return nil
+	}
+
 	defer func() {
 		if accountStorageMap != nil {
 			s.cacheAccountStorageMap(
@@ -128,6 +154,22 @@ func (s *AccountStorage) cacheAccountStorageMap(
 	s.cachedAccountStorageMaps[address] = accountStorageMap
 }
 
+// isAccountStorageMapCached returns true if the account storage map of the given account is cached.
+func (s *AccountStorage) isAccountStorageMapCached(address common.Address) bool {
+	_, ok := s.cachedAccountStorageMaps[address]
+	return ok
+}
+
+// uncacheAccountStorageMap removes the account storage map of the given account from the cache,
+// and returns true if it was cached.
+func (s *AccountStorage) uncacheAccountStorageMap(address common.Address) bool {
+	if !s.isAccountStorageMapCached(address) {
+		return false
+	}
+	delete(s.cachedAccountStorageMaps, address)
+	return true
+}
+
 func (s *AccountStorage) storeNewAccountStorageMap(
 	address common.Address,
 ) *interpreter.AccountStorageMap {
@@ -153,6 +195,57 @@ func (s *AccountStorage) storeNewAccountStorageMap(
 	return accountStorageMap
 }
 
+// isAccountStorageMapRemoved returns true if the account storage map of the given account was removed,
+// and the removal of the account storage register is not committed yet.
+func (s *AccountStorage) isAccountStorageMapRemoved(address common.Address) bool {
+	_, ok := s.removedAccountStorageMapAddresses[address]
+	return ok
+}
+
+// removeAccountStorageMap removes the account storage map of the given account, if it exists,
+// including all domains and their values.
+// The account storage register is removed on commit.
+func (s *AccountStorage) removeAccountStorageMap(
+	inter *interpreter.Interpreter,
+	address common.Address,
+) error {
+	accountStorageMap := s.getAccountStorageMap(address)
+	if accountStorageMap == nil {
+		return nil
+	}
+
+	// Remove all domains and their values
+	accountStorageMap.Clear(inter)
+
+	// Remove account storage map slab
+	err := s.slabStorage.Remove(accountStorageMap.SlabID())
+	if err != nil {
+		return errors.NewExternalError(err)
+	}
+
+	delete(s.cachedAccountStorageMaps, address)
+
+	// If the account storage map is new, the register is not written yet
+
+	delete(s.newAccountStorageMapSlabIndices, address)
+
+	// Remove account storage register on commit, if it exists
+
+	registerExists, err := hasAccountStorageMap(s.ledger, address)
+	if err != nil {
+		return err
+	}
+
+	if registerExists {
+		if s.removedAccountStorageMapAddresses == nil {
+			s.removedAccountStorageMapAddresses = map[common.Address]struct{}{}
+		}
+		s.removedAccountStorageMapAddresses[address] = struct{}{}
+	}
+
+	return nil
+}
+
 func (s *AccountStorage) SetNewAccountStorageMapSlabIndex(
 	address common.Address,
 	slabIndex atree.SlabIndex,
@@ -164,6 +257,11 @@ func (s *AccountStorage) SetNewAccountStorageMapSlabIndex(
 }
 
 func (s *AccountStorage) commit() error {
+	err := s.commitRemovedAccountStorageMaps()
+	if err != nil {
+		return err
+	}
+
 	switch len(s.newAccountStorageMapSlabIndices) {
 	case 0:
 		// Nothing to commit.
@@ -234,6 +332,39 @@ func (s *AccountStorage) commit() error {
 	return nil
 }
 
+// commitRemovedAccountStorageMaps removes the account storage registers of removed account storage maps,
+// in deterministic order.
+// Registers of accounts which have a new account storage map are skipped, as they get overwritten.
+func (s *AccountStorage) commitRemovedAccountStorageMaps() error {
+	if len(s.removedAccountStorageMapAddresses) == 0 {
+		return nil
+	}
+
+	addresses := make([]common.Address, 0, len(s.removedAccountStorageMapAddresses))
+	for address := range s.removedAccountStorageMapAddresses { //nolint:maprange
+		if _, isNew := s.newAccountStorageMapSlabIndices[address]; isNew {
+			continue
+		}
+		addresses = append(addresses, address)
+	}
+	slices.SortFunc(addresses, common.Address.Compare)
+
+	for _, address := range addresses {
+		err := removeRegister(
+			s.ledger,
+			address,
+			[]byte(AccountStorageKey),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.removedAccountStorageMapAddresses = nil
+
+	return nil
+}
+
 func (s *AccountStorage) writeAccountStorageSlabIndex(
 	address common.Address,
 	slabIndex atree.SlabIndex,
@@ -303,21 +434,6 @@ func hasAccountStorageMap(
 	return registerExists, nil
 }
 
-// hasDomainRegister returns true if given account has given domain register.
-// NOTE: account storage format v1 has domain registers.
-func hasDomainRegister(ledger atree.Ledger, address common.Address, domain common.StorageDomain) (bool, error) {
-	_, domainExists, err := readSlabIndexFromRegister(
-		ledger,
-		address,
-		[]byte(domain.Identifier()),
-	)
-	if err != nil {
-		return false, err
-	}
-
-	return domainExists, nil
-}
-
 func (s *AccountStorage) cachedRootSlabIDs() []atree.SlabID {
 
 	var slabIDs []atree.SlabID
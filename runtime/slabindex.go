@@ -36,13 +36,9 @@ func readSlabIndexFromRegister(
 	address common.Address,
 	key []byte,
 ) (atree.SlabIndex, bool, error) {
-	var data []byte
-	var err error
-	errors.WrapPanic(func() {
-		data, err = ledger.GetValue(address[:], key)
-	})
+	data, err := readRegister(ledger, address, key)
 	if err != nil {
-		return atree.SlabIndex{}, false, interpreter.WrappedExternalError(err)
+		return atree.SlabIndex{}, false, err
 	}
 
 	dataLength := len(data)
@@ -54,8 +50,6 @@ func readSlabIndexFromRegister(
 	isStorageIndex := dataLength == storageIndexLength
 	if !isStorageIndex {
 		// Invalid data in register
-
-		// TODO: add dedicated error type?
 		return atree.SlabIndex{}, false, errors.NewUnexpectedError(
 			"invalid storage index for storage map of account '%x': expected length %d, got %d",
 			address[:], storageIndexLength, dataLength,
@@ -65,6 +59,52 @@ func readSlabIndexFromRegister(
 	return atree.SlabIndex(data), true, nil
 }
 
+// readDomainSlabIndexFromRegister returns the value of the given domain register as atree.SlabIndex.
+// NOTE: account storage format v1 has domain registers.
+// This function returns MalformedDomainRegisterError if the register value is invalid (for atree.SlabIndex).
+func readDomainSlabIndexFromRegister(
+	ledger atree.Ledger,
+	address common.Address,
+	domain common.StorageDomain,
+) (atree.SlabIndex, bool, error) {
+	data, err := readRegister(ledger, address, []byte(domain.Identifier()))
+	if err != nil {
+		return atree.SlabIndex{}, false, err
+	}
+
+	dataLength := len(data)
+
+	if dataLength == 0 {
+		return atree.SlabIndex{}, false, nil
+	}
+
+	if dataLength != storageIndexLength {
+		return atree.SlabIndex{}, false, MalformedDomainRegisterError{
+			Address: address,
+			Domain:  domain,
+			Data:    data,
+		}
+	}
+
+	return atree.SlabIndex(data), true, nil
+}
+
+func readRegister(
+	ledger atree.Ledger,
+	address common.Address,
+	key []byte,
+) ([]byte, error) {
+	var data []byte
+	var err error
+	errors.WrapPanic(func() {
+		data, err = ledger.GetValue(address[:], key)
+	})
+	if err != nil {
+		return nil, interpreter.WrappedExternalError(err)
+	}
+	return data, nil
+}
+
 func writeSlabIndexToRegister(
 	ledger atree.Ledger,
 	address common.Address,
@@ -84,3 +124,23 @@ func writeSlabIndexToRegister(
 	}
 	return nil
 }
+
+// removeRegister removes the register by writing an empty value.
+func removeRegister(
+	ledger atree.Ledger,
+	address common.Address,
+	key []byte,
+) error {
+	var err error
+	errors.WrapPanic(func() {
+		err = ledger.SetValue(
+			address[:],
+			key,
+			nil,
+		)
+	})
+	if err != nil {
+		return interpreter.WrappedExternalError(err)
+	}
+	return nil
+}
@@ -19,9 +19,17 @@
 package runtime
 
 import (
+	"cmp"
 	"fmt"
+	"hash/crc32"
+	"maps"
+	"math"
+	"math/rand"
 	"runtime"
+	"slices"
 	"sort"
+	"strings"
+	"sync/atomic"
 
 	"github.com/fxamacker/cbor/v2"
 	"github.com/onflow/atree"
@@ -30,13 +38,77 @@ import (
 	"github.com/onflow/cadence/common/orderedmap"
 	"github.com/onflow/cadence/errors"
 	"github.com/onflow/cadence/interpreter"
+	"github.com/onflow/cadence/sema"
 )
 
 const (
 	AccountStorageKey = "stored"
 )
 
-type StorageConfig struct{}
+type StorageConfig struct {
+	// CommitParallelism is the number of goroutines used to encode slabs during commit.
+	// Zero means runtime.NumCPU().
+	CommitParallelism int
+	// DisableFormatCache disables the cache of account storage formats,
+	// so the account storage format is determined by reading registers every time it is needed.
+	// This does not affect correctness, only performance, and is e.g. useful for testing format detection.
+	DisableFormatCache bool
+	// MaxInlineStringSize limits the size of strings which are stored inline, i.e. in their parent slab.
+	// Larger strings are stored in separate slabs.
+	// Zero means no additional limit, i.e. atree's inlining threshold is used.
+	// A limit larger than atree's inlining threshold has no effect.
+	// Values which are encoded inline regardless of their size, e.g. by AccountStorageMap.EncodeTo,
+	// are not affected.
+	MaxInlineStringSize uint64
+	// StrictDecoding rejects slabs with CBOR tags which are unknown at their position,
+	// with an interpreter.UnknownCBORTagError, e.g. to detect legacy or corrupt slabs during migrations.
+	StrictDecoding bool
+	// SlabChecksums enables recording a checksum of each slab written to the ledger,
+	// so the slabs can later be verified using VerifyChecksums, e.g. to detect bit-rot in the ledger.
+	// The checksums are kept in memory, separate from the slab data,
+	// and are only a diagnostic aid: they are not part of the stored state.
+	SlabChecksums bool
+	// OnSlabEncoded, if set, is called for each slab which is encoded and written during commit,
+	// with the slab's size, i.e. its contribution to the reported encoding computation,
+	// e.g. to profile which values dominate the cost of a commit.
+	// It does not affect metering.
+	OnSlabEncoded func(slabID atree.SlabID, size int)
+	// SlabCacheFlushThreshold is a coarse bound for the read cache of decoded slabs of the persistent slab storage.
+	// When the number of slabs added to the read cache since it was last flushed reaches the threshold,
+	// the whole read cache is flushed on the next commit, i.e. the decoded slabs of all accounts are dropped,
+	// together with the cached account storage maps and domain storage maps, which reference them,
+	// and decoded from the ledger again when accessed.
+	// The read cache is never flushed during a transaction, as live values reference their decoded slabs,
+	// so the read cache may exceed the threshold until the next commit.
+	// Values loaded before a commit must not be used after the commit.
+	// This is not a least-recently-used cache: if the slabs accessed repeatedly exceed the threshold,
+	// they are decoded again after every flush, so the threshold should be well above the working set.
+	// The number of cached slabs is approximate, as only slabs retrieved through the storage and committed slabs
+	// are counted, not slabs loaded by the persistent slab storage directly.
+	// Zero means no flushing, i.e. the read cache of the persistent slab storage is unbounded.
+	SlabCacheFlushThreshold int
+	// FormatCacheSize limits the number of accounts in the cache of account storage formats.
+	// When the limit is exceeded, the least recently used accounts without unsaved changes are evicted,
+	// and their format is determined from the ledger again when needed.
+	// This does not affect correctness, only performance and memory usage.
+	// Zero means no limit, i.e. the cache of account storage formats is unbounded.
+	FormatCacheSize int
+}
+
+// decodingConfig returns the configuration used to decode slabs.
+func (c StorageConfig) decodingConfig() interpreter.DecodingConfig {
+	return interpreter.DecodingConfig{
+		StrictDecoding: c.StrictDecoding,
+	}
+}
+
+// commitParallelism returns the number of goroutines used to encode slabs during commit.
+func (c StorageConfig) commitParallelism() int {
+	if c.CommitParallelism == 0 {
+		return runtime.NumCPU()
+	}
+	return c.CommitParallelism
+}
 
 type StorageFormat uint8
 
@@ -53,9 +125,34 @@ type Storage struct {
 	// Key is StorageKey{address, domain} and value is domain storage map.
 	cachedDomainStorageMaps map[interpreter.StorageDomainKey]*interpreter.DomainStorageMap
 
+	// cacheHits and cacheMisses count the lookups of domain storage maps
+	// which were, or were not, served from cachedDomainStorageMaps, see CacheStats.
+	// NOTE: plain integers are sufficient, as storage is not used concurrently.
+	cacheHits   uint64
+	cacheMisses uint64
+
+	// cachedSlabCount is the approximate number of slabs added to the read cache of the persistent slab storage
+	// since it was last dropped, if a flush threshold is configured, see StorageConfig.SlabCacheFlushThreshold.
+	cachedSlabCount int
+
+	// unsavedSlabIDs contains the IDs of the slabs of accounts which were stored or removed since the last commit,
+	// i.e. the slabs in the deltas of the persistent slab storage, see HasUnsavedSlab.
+	unsavedSlabIDs map[atree.SlabID]struct{}
+
+	// flushedSlabIDs contains the IDs of the unsaved slabs which were written to the ledger by FlushDomain,
+	// and which were not stored or removed since. They are written again on commit,
+	// but their encoding was already metered, see FlushDomain.
+	flushedSlabIDs map[atree.SlabID]struct{}
+
 	// cachedV1Accounts contains the cached result of determining
 	// if the account is in storage format v1 or not.
-	cachedV1Accounts map[common.Address]bool
+	// The entries are ordered from least to most recently used, see StorageConfig.FormatCacheSize.
+	cachedV1Accounts orderedmap.OrderedMap[common.Address, bool]
+
+	// removedV1DomainRegisters contains the domain registers of removed accounts
+	// in account storage format v1, see RemoveAccount.
+	// The registers are removed during commit.
+	removedV1DomainRegisters map[common.Address][]common.StorageDomain
 
 	// contractUpdates is a cache of contract updates.
 	// Key is StorageKey{contract_address, contract_name} and value is contract composite value.
@@ -63,7 +160,14 @@ type Storage struct {
 
 	Ledger atree.Ledger
 
-	memoryGauge common.MemoryGauge
+	// storageLedger is shared with the persistent slab storage and the account storage,
+	// so writes can be redirected to a different ledger using CommitTo,
+	// and so register reads and writes can be counted, see ReadCount and WriteCount.
+	storageLedger *storageLedger
+
+	// memoryGauge is shared with the persistent slab storage and the account storage,
+	// so the memory gauge can be replaced using SetMemoryGauge.
+	memoryGauge *storageMemoryGauge
 
 	Config StorageConfig
 
@@ -72,10 +176,19 @@ type Storage struct {
 
 var _ atree.SlabStorage = &Storage{}
 var _ interpreter.Storage = &Storage{}
+var _ interpreter.UnsavedChangesStorage = &Storage{}
 
 func NewPersistentSlabStorage(
 	ledger atree.Ledger,
 	memoryGauge common.MemoryGauge,
+) *atree.PersistentSlabStorage {
+	return newPersistentSlabStorage(ledger, memoryGauge, interpreter.DecodingConfig{})
+}
+
+func newPersistentSlabStorage(
+	ledger atree.Ledger,
+	memoryGauge common.MemoryGauge,
+	decodingConfig interpreter.DecodingConfig,
 ) *atree.PersistentSlabStorage {
 	decodeStorable := func(
 		decoder *cbor.StreamDecoder,
@@ -85,16 +198,17 @@ func NewPersistentSlabStorage(
 		atree.Storable,
 		error,
 	) {
-		return interpreter.DecodeStorable(
+		return interpreter.DecodeStorableWithConfig(
 			decoder,
 			slabID,
 			inlinedExtraData,
 			memoryGauge,
+			decodingConfig,
 		)
 	}
 
 	decodeTypeInfo := func(decoder *cbor.StreamDecoder) (atree.TypeInfo, error) {
-		return interpreter.DecodeTypeInfo(decoder, memoryGauge)
+		return interpreter.DecodeTypeInfoWithConfig(decoder, memoryGauge, decodingConfig)
 	}
 
 	ledgerStorage := atree.NewLedgerBaseStorage(ledger)
@@ -113,21 +227,451 @@ func NewStorage(
 	memoryGauge common.MemoryGauge,
 	config StorageConfig,
 ) *Storage {
-	persistentSlabStorage := NewPersistentSlabStorage(ledger, memoryGauge)
+	if config.CommitParallelism < 0 {
+		panic(InvalidCommitParallelismError{
+			CommitParallelism: config.CommitParallelism,
+		})
+	}
+
+	if config.SlabCacheFlushThreshold < 0 {
+		panic(InvalidSlabCacheFlushThresholdError{
+			SlabCacheFlushThreshold: config.SlabCacheFlushThreshold,
+		})
+	}
+
+	if config.FormatCacheSize < 0 {
+		panic(InvalidFormatCacheSizeError{
+			FormatCacheSize: config.FormatCacheSize,
+		})
+	}
+
+	storageMemoryGauge := &storageMemoryGauge{
+		memoryGauge: memoryGauge,
+	}
+
+	storageLedger := &storageLedger{
+		Ledger: ledger,
+	}
+	if config.SlabChecksums {
+		storageLedger.slabChecksums = map[atree.SlabID]uint32{}
+	}
 
-	accountStorage := NewAccountStorage(
-		ledger,
-		persistentSlabStorage,
-		memoryGauge,
+	persistentSlabStorage := newPersistentSlabStorage(
+		storageLedger,
+		storageMemoryGauge,
+		config.decodingConfig(),
 	)
 
-	return &Storage{
+	storage := &Storage{
 		Ledger:                ledger,
+		storageLedger:         storageLedger,
 		PersistentSlabStorage: persistentSlabStorage,
-		memoryGauge:           memoryGauge,
+		memoryGauge:           storageMemoryGauge,
 		Config:                config,
-		AccountStorage:        accountStorage,
 	}
+
+	// NOTE: Use the storage itself as the slab storage of the account storage maps,
+	// instead of the persistent slab storage,
+	// so that values stored in them can access the storage configuration,
+	// see MaxInlineStringSize
+	storage.AccountStorage = NewAccountStorage(
+		storageLedger,
+		storage,
+		storageMemoryGauge,
+	)
+
+	return storage
+}
+
+// storageMemoryGauge is a memory gauge which delegates to a replaceable memory gauge.
+type storageMemoryGauge struct {
+	memoryGauge common.MemoryGauge
+}
+
+var _ common.MemoryGauge = &storageMemoryGauge{}
+
+func (g *storageMemoryGauge) MeterMemory(usage common.MemoryUsage) error {
+	if g.memoryGauge == nil {
+		return nil
+	}
+	return g.memoryGauge.MeterMemory(usage)
+}
+
+// SetMemoryGauge replaces the memory gauge of the storage,
+// e.g. to meter each transaction of a batch against a separate memory gauge.
+// All subsequent operations, including the decoding of slabs, are metered against the given memory gauge.
+// Cached account storage maps and domain storage maps remain valid,
+// as they don't retain the memory gauge.
+func (s *Storage) SetMemoryGauge(memoryGauge common.MemoryGauge) {
+	s.memoryGauge.memoryGauge = memoryGauge
+}
+
+// storageLedger is a ledger which delegates to the ledger of the storage,
+// but which writes to a target ledger instead, if one is set.
+// It counts the register reads and writes, see ReadCount and WriteCount.
+//
+// If slab checksums are enabled, it records the checksum of each slab written to the ledger,
+// see VerifyChecksums. Writes to a target ledger are not recorded.
+type storageLedger struct {
+	atree.Ledger
+	targetLedger  atree.Ledger
+	readCount     atomic.Uint64
+	writeCount    atomic.Uint64
+	slabChecksums map[atree.SlabID]uint32
+	// writtenSlabIDs records the IDs of the slabs written to the ledger,
+	// if recordWrittenSlabIDs is true, see StorageConfig.OnSlabEncoded.
+	writtenSlabIDs       []atree.SlabID
+	recordWrittenSlabIDs bool
+}
+
+var _ atree.Ledger = &storageLedger{}
+
+func (l *storageLedger) GetValue(owner, key []byte) ([]byte, error) {
+	l.readCount.Add(1)
+	return l.Ledger.GetValue(owner, key)
+}
+
+func (l *storageLedger) ValueExists(owner, key []byte) (bool, error) {
+	l.readCount.Add(1)
+	return l.Ledger.ValueExists(owner, key)
+}
+
+func (l *storageLedger) SetValue(owner, key, value []byte) error {
+	l.writeCount.Add(1)
+
+	if l.recordWrittenSlabIDs && len(value) > 0 && atree.LedgerKeyIsSlabKey(string(key)) {
+		l.writtenSlabIDs = append(l.writtenSlabIDs, ledgerSlabID(owner, key))
+	}
+
+	if l.targetLedger != nil {
+		return l.targetLedger.SetValue(owner, key, value)
+	}
+
+	err := l.Ledger.SetValue(owner, key, value)
+	if err != nil {
+		return err
+	}
+
+	if l.slabChecksums != nil && atree.LedgerKeyIsSlabKey(string(key)) {
+		slabID := ledgerSlabID(owner, key)
+		if len(value) == 0 {
+			// Slab was removed
+			delete(l.slabChecksums, slabID)
+		} else {
+			l.slabChecksums[slabID] = crc32.ChecksumIEEE(value)
+		}
+	}
+
+	return nil
+}
+
+// ledgerSlabID returns the ID of the slab stored in the given register.
+// The key must be a slab key, see atree.LedgerKeyIsSlabKey.
+func ledgerSlabID(owner, key []byte) atree.SlabID {
+	var address atree.Address
+	copy(address[:], owner)
+
+	var index atree.SlabIndex
+	copy(index[:], key[1:])
+
+	return atree.NewSlabID(address, index)
+}
+
+// ReadCount returns the number of register reads performed on the ledger,
+// i.e. calls to GetValue and ValueExists, since the storage was created or the counters were reset.
+// Reads which are served from the storage's caches are not counted.
+func (s *Storage) ReadCount() uint64 {
+	return s.storageLedger.readCount.Load()
+}
+
+// WriteCount returns the number of register writes performed on the ledger,
+// i.e. calls to SetValue, since the storage was created or the counters were reset.
+func (s *Storage) WriteCount() uint64 {
+	return s.storageLedger.writeCount.Load()
+}
+
+// ResetCounters resets the register read and write counters.
+func (s *Storage) ResetCounters() {
+	s.storageLedger.readCount.Store(0)
+	s.storageLedger.writeCount.Store(0)
+}
+
+// MaxInlineStringSize returns the configured limit for the size of strings which are stored inline.
+// See StorageConfig.MaxInlineStringSize.
+func (s *Storage) MaxInlineStringSize() uint64 {
+	return s.Config.MaxInlineStringSize
+}
+
+// CommittedSlabStorage returns a read-only slab storage which reads the committed state from the ledger,
+// i.e. which ignores unsaved changes and the caches of this storage.
+func (s *Storage) CommittedSlabStorage() atree.SlabStorage {
+	return newPersistentSlabStorage(
+		s.storageLedger,
+		s.memoryGauge,
+		s.Config.decodingConfig(),
+	)
+}
+
+// CacheStats returns the number of domain storage map lookups in GetDomainStorageMap
+// which were served from the cache (hits) and which were not (misses),
+// and the current number of cached domain storage maps (size).
+func (s *Storage) CacheStats() (hits uint64, misses uint64, size int) {
+	return s.cacheHits, s.cacheMisses, len(s.cachedDomainStorageMaps)
+}
+
+// InvalidateDomainCache drops the cached domain storage map of the given account and domain,
+// so the next access reloads it from the ledger, e.g. after the ledger was mutated externally.
+//
+// Domain storage maps of accounts in storage format v2 are stored in the account storage map,
+// so for such accounts all caches of the account are dropped, see InvalidateAccountCache.
+//
+// NOTE: The persistent slab storage does not support dropping individual decoded slabs,
+// so the decoded slabs of all accounts are dropped, not just the ones of the given domain,
+// together with all cached account storage maps and domain storage maps, see dropSlabCache.
+//
+// Values loaded before the invalidation reference the dropped slabs, and must not be used afterwards,
+// so this function is only valid between transactions. If the storage has unsaved changes,
+// an UnsavedChangesCacheInvalidationError is returned, and nothing is invalidated.
+//
+// It is a no-op if nothing is cached for the given account and domain.
+func (s *Storage) InvalidateDomainCache(address common.Address, domain common.StorageDomain) error {
+	if s.hasUnsavedSlabs() {
+		return UnsavedChangesCacheInvalidationError{}
+	}
+
+	if s.AccountStorage.isAccountStorageMapCached(address) {
+		return s.InvalidateAccountCache(address)
+	}
+
+	domainStorageKey := interpreter.NewStorageDomainKey(s.memoryGauge, address, domain)
+	if _, ok := s.cachedDomainStorageMaps[domainStorageKey]; !ok {
+		return nil
+	}
+
+	s.dropSlabCache()
+
+	return nil
+}
+
+// InvalidateAccountCache drops all cached domain storage maps, the cached account storage map,
+// and the cached account storage format of the given account,
+// so the next access reloads them from the ledger, e.g. after the ledger was mutated externally.
+//
+// NOTE: The persistent slab storage does not support dropping individual decoded slabs,
+// so the decoded slabs of all accounts are dropped, not just the ones of the given account,
+// together with all cached account storage maps and domain storage maps, see dropSlabCache.
+//
+// Values loaded before the invalidation reference the dropped slabs, and must not be used afterwards,
+// so this function is only valid between transactions. If the storage has unsaved changes,
+// an UnsavedChangesCacheInvalidationError is returned, and nothing is invalidated.
+//
+// It is a no-op if nothing is cached for the given account.
+func (s *Storage) InvalidateAccountCache(address common.Address) error {
+	if s.hasUnsavedSlabs() {
+		return UnsavedChangesCacheInvalidationError{}
+	}
+
+	invalidated := false
+
+	for _, domain := range common.AllStorageDomains {
+		domainStorageKey := interpreter.NewStorageDomainKey(s.memoryGauge, address, domain)
+		if _, ok := s.cachedDomainStorageMaps[domainStorageKey]; ok {
+			invalidated = true
+		}
+	}
+
+	if _, ok := s.cachedV1Accounts.Delete(address); ok {
+		invalidated = true
+	}
+
+	if s.AccountStorage.isAccountStorageMapCached(address) {
+		invalidated = true
+	}
+
+	if invalidated {
+		s.dropSlabCache()
+	}
+
+	return nil
+}
+
+// hasUnsavedSlabs returns true if any slab was stored or removed since the last commit,
+// i.e. if a transaction is in progress.
+func (s *Storage) hasUnsavedSlabs() bool {
+	return len(s.unsavedSlabIDs) > 0
+}
+
+// dropSlabCache drops the decoded slabs cached by the persistent slab storage,
+// so slabs are decoded from the ledger again when they are accessed next.
+// The cached account storage maps and domain storage maps reference the decoded slabs,
+// so they are dropped as well.
+//
+// NOTE: The persistent slab storage does not support dropping the slabs of a single account,
+// so the cached slabs of all accounts are dropped. Unsaved changes (deltas) are not affected.
+// It must only be called between transactions, as live values reference the decoded slabs.
+func (s *Storage) dropSlabCache() {
+	s.cachedDomainStorageMaps = nil
+	s.AccountStorage.cachedAccountStorageMaps = nil
+	s.PersistentSlabStorage.DropCache()
+	s.cachedSlabCount = 0
+}
+
+// Retrieve retrieves the slab with the given ID, like the persistent slab storage,
+// and counts the slabs added to the read cache of the persistent slab storage, if a flush threshold is configured.
+// The read cache is only flushed on commit, see StorageConfig.SlabCacheFlushThreshold.
+func (s *Storage) Retrieve(id atree.SlabID) (atree.Slab, bool, error) {
+	if s.Config.SlabCacheFlushThreshold == 0 {
+		return s.PersistentSlabStorage.Retrieve(id)
+	}
+
+	loaded := s.PersistentSlabStorage.RetrieveIfLoaded(id) != nil
+
+	slab, found, err := s.PersistentSlabStorage.Retrieve(id)
+	if !loaded && found && err == nil {
+		s.cachedSlabCount++
+	}
+
+	return slab, found, err
+}
+
+// Store stores the given slab, like the persistent slab storage,
+// and records that the slab has unsaved changes, see HasUnsavedSlab.
+func (s *Storage) Store(id atree.SlabID, slab atree.Slab) error {
+	err := s.PersistentSlabStorage.Store(id, slab)
+	if err != nil {
+		return err
+	}
+
+	s.recordUnsavedSlab(id)
+
+	return nil
+}
+
+// Remove removes the slab with the given ID, like the persistent slab storage,
+// and records that the slab has unsaved changes, see HasUnsavedSlab.
+func (s *Storage) Remove(id atree.SlabID) error {
+	err := s.PersistentSlabStorage.Remove(id)
+	if err != nil {
+		return err
+	}
+
+	s.recordUnsavedSlab(id)
+
+	return nil
+}
+
+// DropDeltas drops the unsaved changes, like the persistent slab storage.
+func (s *Storage) DropDeltas() {
+	s.PersistentSlabStorage.DropDeltas()
+	s.clearUnsavedSlabs()
+}
+
+// clearUnsavedSlabs clears the tracking of unsaved slabs, after they were committed or dropped.
+func (s *Storage) clearUnsavedSlabs() {
+	s.unsavedSlabIDs = nil
+	s.flushedSlabIDs = nil
+}
+
+func (s *Storage) recordUnsavedSlab(id atree.SlabID) {
+	// Slabs with temporary addresses are never committed
+	if id.HasTempAddress() {
+		return
+	}
+
+	if s.unsavedSlabIDs == nil {
+		s.unsavedSlabIDs = map[atree.SlabID]struct{}{}
+	}
+	s.unsavedSlabIDs[id] = struct{}{}
+
+	// A flushed slab which is stored or removed again has new unsaved changes
+	delete(s.flushedSlabIDs, id)
+}
+
+// isFlushableSlab returns true if the slab with the given ID has unsaved changes,
+// which were not written to the ledger by FlushDomain yet.
+func (s *Storage) isFlushableSlab(id atree.SlabID) bool {
+	if !s.HasUnsavedSlab(id) {
+		return false
+	}
+	_, flushed := s.flushedSlabIDs[id]
+	return !flushed
+}
+
+// flushedSlabsSize returns the number and the total size of the slabs which were flushed, see FlushDomain.
+func (s *Storage) flushedSlabsSize() (count uint, size uint64) {
+	// NOTE: map range is safe, as only the sum is computed
+	for id := range s.flushedSlabIDs { //nolint:maprange
+		slab := s.PersistentSlabStorage.RetrieveIfLoaded(id)
+		if slab == nil {
+			continue
+		}
+		count++
+		size += uint64(slab.ByteSize())
+	}
+	return
+}
+
+// HasUnsavedSlab returns true if the slab with the given ID was stored or removed since the last commit.
+//
+// NOTE: Only slabs stored or removed through this storage are tracked,
+// not slabs stored or removed through the persistent slab storage directly.
+func (s *Storage) HasUnsavedSlab(id atree.SlabID) bool {
+	_, ok := s.unsavedSlabIDs[id]
+	return ok
+}
+
+// ReadRegister reads the given register of the given account directly from the ledger.
+//
+// DANGER: This is an escape hatch for low-level tooling, e.g. migrations.
+// The read bypasses the caches and the unsaved changes of the storage,
+// i.e. it only reflects the committed state, and it is not metered.
+func (s *Storage) ReadRegister(address common.Address, key []byte) ([]byte, error) {
+	return readRegister(s.storageLedger, address, key)
+}
+
+// WriteRegister writes the given register of the given account directly to the ledger.
+// An empty value removes the register.
+// All cached storage of the account is invalidated, see InvalidateAccountCache,
+// and if the register is a slab register, the cached slabs are dropped.
+//
+// Writing a register of an account which has unsaved changes is rejected
+// with an UnsavedChangesRegisterWriteError, as a later commit would overwrite the register,
+// or leave the account in an inconsistent state.
+// Like cache invalidation, writing a register is only valid between transactions,
+// so it is rejected with an UnsavedChangesCacheInvalidationError if any other account has unsaved changes.
+//
+// DANGER: This is an escape hatch for low-level tooling, e.g. migrations.
+// The written value is not validated, and the write is not metered.
+// Writing an invalid value may corrupt the storage of the account.
+func (s *Storage) WriteRegister(address common.Address, key []byte, value []byte) error {
+	if s.PersistentSlabStorage.HasUnsavedChanges(atree.Address(address)) {
+		return UnsavedChangesRegisterWriteError{
+			Address: address,
+		}
+	}
+
+	if s.hasUnsavedSlabs() {
+		return UnsavedChangesCacheInvalidationError{}
+	}
+
+	var err error
+	errors.WrapPanic(func() {
+		err = s.storageLedger.SetValue(address[:], key, value)
+	})
+	if err != nil {
+		return interpreter.WrappedExternalError(err)
+	}
+
+	err = s.InvalidateAccountCache(address)
+	if err != nil {
+		return err
+	}
+
+	if atree.LedgerKeyIsSlabKey(string(key)) {
+		s.dropSlabCache()
+	}
+
+	return nil
 }
 
 const storageIndexLength = 8
@@ -148,10 +692,13 @@ func (s *Storage) GetDomainStorageMap(
 	if s.cachedDomainStorageMaps != nil {
 		domainStorageMap = s.cachedDomainStorageMaps[domainStorageKey]
 		if domainStorageMap != nil {
+			s.cacheHits++
 			return domainStorageMap
 		}
 	}
 
+	s.cacheMisses++
+
 	defer func() {
 		// Cache domain storage map
 		if domainStorageMap != nil {
@@ -188,7 +735,7 @@ func (s *Storage) GetDomainStorageMap(
 
 	// Check if account is v1 (by reading requested domain register).
 
-	ok, err := hasDomainRegister(s.Ledger, address, domain)
+	ok, err := s.hasDomainRegister(address, domain)
 	if err != nil {
 		panic(err)
 	}
@@ -226,6 +773,155 @@ func (s *Storage) GetDomainStorageMap(
 	)
 }
 
+// GetDomainStorageMapForType is like GetDomainStorageMap,
+// but determines the domain from the given path type,
+// i.e. StoragePath, PublicPath, or PrivatePath.
+// An error is returned for any other type.
+func (s *Storage) GetDomainStorageMapForType(
+	storageMutationTracker interpreter.StorageMutationTracker,
+	address common.Address,
+	pathType sema.Type,
+	createIfNotExists bool,
+) (
+	*interpreter.DomainStorageMap,
+	error,
+) {
+	var domain common.StorageDomain
+
+	switch pathType {
+	case sema.StoragePathType:
+		domain = common.StorageDomainPathStorage
+	case sema.PublicPathType:
+		domain = common.StorageDomainPathPublic
+	case sema.PrivatePathType:
+		domain = common.StorageDomainPathPrivate
+	default:
+		return nil, InvalidPathDomainTypeError{
+			Type: pathType,
+		}
+	}
+
+	return s.GetDomainStorageMap(
+		storageMutationTracker,
+		address,
+		domain,
+		createIfNotExists,
+	), nil
+}
+
+// HasDomain returns true if the given account has the given domain,
+// either as a domain register (account storage format v1),
+// or as a domain in the account storage map (account storage format v2).
+// Unlike GetDomainStorageMap, HasDomain never creates a domain storage map.
+func (s *Storage) HasDomain(address common.Address, domain common.StorageDomain) (bool, error) {
+
+	// Check if domain storage map is cached.
+
+	if s.cachedDomainStorageMaps != nil {
+		domainStorageKey := interpreter.NewStorageDomainKey(s.memoryGauge, address, domain)
+		if s.cachedDomainStorageMaps[domainStorageKey] != nil {
+			return true, nil
+		}
+	}
+
+	// Check if cached account format is available.
+
+	format, known := s.getCachedAccountFormat(address)
+	if !known {
+
+		// Check if account is v2 (by reading "stored" register).
+		// NOTE: load and cache the account storage map directly,
+		// instead of just checking if the register exists,
+		// to avoid reading the register again below.
+
+		accountStorageMap := s.AccountStorage.getAccountStorageMap(address)
+		if accountStorageMap != nil {
+			s.cacheIsV1Account(address, false)
+
+			return accountStorageMap.DomainExists(domain), nil
+		}
+
+		// Account is either v1 account or new account.
+		// Check if account is v1 (by reading requested domain register).
+
+		exists, err := s.hasDomainRegister(address, domain)
+		if err != nil {
+			return false, err
+		}
+
+		if exists {
+			s.cacheIsV1Account(address, true)
+		}
+
+		return exists, nil
+	}
+
+	switch format {
+	case StorageFormatV1:
+		return s.hasDomainRegister(address, domain)
+
+	case StorageFormatV2:
+		return s.AccountStorage.hasDomain(address, domain), nil
+
+	default:
+		panic(errors.NewUnreachableError())
+	}
+}
+
+// GetContractNames returns the sorted names of the contracts stored in the given account,
+// i.e. the keys of the account's contract domain storage map,
+// for both account storage format v1 and v2.
+// Pending contract updates, which are only written on commit, are not included.
+func (s *Storage) GetContractNames(address common.Address) ([]string, error) {
+
+	var domainStorageMap *interpreter.DomainStorageMap
+
+	switch s.AccountStorageFormat(address) {
+	case StorageFormatV1:
+		slabIndex, exists, err := s.readDomainSlabIndex(
+			address,
+			common.StorageDomainContract,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			slabID := atree.NewSlabID(atree.Address(address), slabIndex)
+			domainStorageMap = interpreter.NewDomainStorageMapWithRootID(s, slabID)
+		}
+
+	case StorageFormatV2:
+		domainStorageMap = s.GetDomainStorageMap(
+			nil,
+			address,
+			common.StorageDomainContract,
+			false,
+		)
+	}
+
+	if domainStorageMap == nil {
+		return []string{}, nil
+	}
+
+	keys := domainStorageMap.Keys(s.memoryGauge)
+
+	names := make([]string, 0, len(keys))
+	for _, key := range keys {
+		stringKey, ok := key.(interpreter.StringStorageMapKey)
+		if !ok {
+			return nil, errors.NewUnexpectedError(
+				"invalid contract domain storage map key type: %T",
+				key,
+			)
+		}
+		names = append(names, string(stringKey))
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
 func (s *Storage) getDomainStorageMapForV2Account(
 	storageMutationTracker interpreter.StorageMutationTracker,
 	address common.Address,
@@ -270,10 +966,21 @@ func (s *Storage) getDomainStorageMap(
 }
 
 func (s *Storage) getCachedAccountFormat(address common.Address) (format StorageFormat, known bool) {
-	isV1, cached := s.cachedV1Accounts[address]
-	if !cached {
+	if s.Config.DisableFormatCache {
 		return StorageFormatUnknown, false
 	}
+
+	isV1, cached := s.cachedV1Accounts.Get(address)
+	if !cached {
+		return StorageFormatUnknown, false
+	}
+
+	if s.Config.FormatCacheSize > 0 {
+		// Mark the account as most recently used
+		s.cachedV1Accounts.Delete(address)
+		s.cachedV1Accounts.Set(address, isV1)
+	}
+
 	if isV1 {
 		return StorageFormatV1, true
 	} else {
@@ -283,7 +990,11 @@ func (s *Storage) getCachedAccountFormat(address common.Address) (format Storage
 
 // isV2Account returns true if given account is in account storage format v2.
 func (s *Storage) isV2Account(address common.Address) bool {
-	accountStorageMapExists, err := hasAccountStorageMap(s.Ledger, address)
+	if s.AccountStorage.isAccountStorageMapRemoved(address) {
+		return false
+	}
+
+	accountStorageMapExists, err := hasAccountStorageMap(s.storageLedger, address)
 	if err != nil {
 		panic(err)
 	}
@@ -298,7 +1009,7 @@ func (s *Storage) isV1Account(address common.Address) (isV1 bool) {
 	// Check if a storage map register exists for any of the domains.
 	// Check the most frequently used domains first, such as storage, public, private.
 	for _, domain := range common.AllStorageDomains {
-		domainExists, err := hasDomainRegister(s.Ledger, address, domain)
+		domainExists, err := s.hasDomainRegister(address, domain)
 		if err != nil {
 			panic(err)
 		}
@@ -311,11 +1022,62 @@ func (s *Storage) isV1Account(address common.Address) (isV1 bool) {
 	return false
 }
 
+// readDomainSlabIndex returns the slab index of the given domain register of the given account.
+// Domain registers of removed accounts do not exist, even though their removal is not committed yet.
+func (s *Storage) readDomainSlabIndex(
+	address common.Address,
+	domain common.StorageDomain,
+) (
+	atree.SlabIndex,
+	bool,
+	error,
+) {
+	if _, removed := s.removedV1DomainRegisters[address]; removed {
+		return atree.SlabIndex{}, false, nil
+	}
+
+	return readDomainSlabIndexFromRegister(s.storageLedger, address, domain)
+}
+
+// hasDomainRegister returns true if the given account has the given domain register,
+// see readDomainSlabIndex.
+func (s *Storage) hasDomainRegister(address common.Address, domain common.StorageDomain) (bool, error) {
+	_, exists, err := s.readDomainSlabIndex(address, domain)
+	return exists, err
+}
+
 func (s *Storage) cacheIsV1Account(address common.Address, isV1 bool) {
-	if s.cachedV1Accounts == nil {
-		s.cachedV1Accounts = map[common.Address]bool{}
+	if s.Config.DisableFormatCache {
+		return
+	}
+
+	s.cachedV1Accounts.Set(address, isV1)
+
+	s.evictCachedAccountFormats()
+}
+
+// evictCachedAccountFormats evicts the least recently used accounts from the account format cache,
+// until the cache no longer exceeds the limit, see StorageConfig.FormatCacheSize.
+//
+// Accounts with unsaved changes are never evicted, as their format cannot be determined from the ledger,
+// e.g. the account storage map of a new account is only written to the ledger on commit.
+func (s *Storage) evictCachedAccountFormats() {
+	limit := s.Config.FormatCacheSize
+	if limit <= 0 {
+		return
+	}
+
+	pair := s.cachedV1Accounts.Oldest()
+	for s.cachedV1Accounts.Len() > limit && pair != nil {
+		next := pair.Next()
+
+		address := pair.Key
+		if !s.PersistentSlabStorage.HasUnsavedChanges(atree.Address(address)) {
+			s.cachedV1Accounts.Delete(address)
+		}
+
+		pair = next
 	}
-	s.cachedV1Accounts[address] = isV1
 }
 
 func (s *Storage) cacheDomainStorageMap(
@@ -360,6 +1122,37 @@ type ContractUpdate struct {
 	Key           interpreter.StorageKey
 }
 
+// IsRemoval returns true if the contract update removes the contract,
+// i.e. if the contract value is nil.
+func (u ContractUpdate) IsRemoval() bool {
+	return u.ContractValue == nil
+}
+
+// PendingContractUpdates returns the recorded contract updates, without committing them.
+// The returned updates are a copy, sorted by key using SortContractUpdates.
+// Contract removals are represented by updates without a contract value, see ContractUpdate.IsRemoval.
+func (s *Storage) PendingContractUpdates() []ContractUpdate {
+	if s.contractUpdates == nil {
+		return nil
+	}
+
+	updates := make([]ContractUpdate, 0, s.contractUpdates.Len())
+
+	for pair := s.contractUpdates.Oldest(); pair != nil; pair = pair.Next() {
+		updates = append(
+			updates,
+			ContractUpdate{
+				Key:           pair.Key,
+				ContractValue: pair.Value,
+			},
+		)
+	}
+
+	SortContractUpdates(updates)
+
+	return updates
+}
+
 func SortContractUpdates(updates []ContractUpdate) {
 	sort.Slice(updates, func(i, j int) bool {
 		a := updates[i].Key
@@ -407,6 +1200,421 @@ func (s *Storage) NondeterministicCommit(inter *interpreter.Interpreter, commitC
 	return s.commit(inter, commitContractUpdates, false)
 }
 
+// CommitTo serializes and commits all values in the deltas storage in deterministic order,
+// like Commit, but writes the registers to the given target ledger instead of the ledger of the storage.
+// The ledger of the storage is not written to.
+//
+// Reads still go to the ledger of the storage, and committed slabs are no longer deltas afterwards,
+// so the storage should not be committed again to its own ledger after a call to CommitTo.
+func (s *Storage) CommitTo(
+	context interpreter.ValueTransferContext,
+	targetLedger atree.Ledger,
+	commitContractUpdates bool,
+) error {
+	s.storageLedger.targetLedger = targetLedger
+	defer func() {
+		s.storageLedger.targetLedger = nil
+	}()
+
+	return s.commit(context, commitContractUpdates, true)
+}
+
+// CommitWithChangeSet commits all values in the deltas storage in deterministic order, like Commit,
+// and returns the storage keys which were added, updated, or removed by the commit.
+//
+// The change set is determined before committing. The accounts with unsaved changes are determined
+// from the unsaved slabs and the removed accounts, and their modified domains are determined
+// from their account storage maps, see interpreter.AccountStorageMap.ModifiedDomains.
+// Only the modified domains are compared against their committed state, see interpreter.DiffDomainStorageMaps.
+// Values which are not equatable, e.g. resources, are always reported as updated.
+func (s *Storage) CommitWithChangeSet(
+	inter *interpreter.Interpreter,
+	commitContractUpdates bool,
+) (
+	ChangeSet,
+	error,
+) {
+	// Contract updates are written to the contract domains,
+	// so write them before determining the change set
+
+	if commitContractUpdates {
+		s.commitContractUpdates(inter)
+	}
+
+	changeSet, err := s.changeSet(inter)
+	if err != nil {
+		return ChangeSet{}, err
+	}
+
+	// Contract updates were already written above
+	err = s.commit(inter, false, true)
+	if err != nil {
+		return ChangeSet{}, err
+	}
+
+	return changeSet, nil
+}
+
+func (s *Storage) changeSet(inter *interpreter.Interpreter) (ChangeSet, error) {
+
+	// Determine the accounts with unsaved changes, in deterministic order:
+	// the accounts of the unsaved slabs, and the removed accounts
+
+	addressSet := map[common.Address]struct{}{}
+
+	// NOTE: map range is safe, as addresses are collected in a set and sorted below
+
+	for slabID := range s.unsavedSlabIDs { //nolint:maprange
+		addressSet[common.Address(slabID.Address())] = struct{}{}
+	}
+	for address := range s.AccountStorage.removedAccountStorageMapAddresses { //nolint:maprange
+		addressSet[address] = struct{}{}
+	}
+	for address := range s.removedV1DomainRegisters { //nolint:maprange
+		addressSet[address] = struct{}{}
+	}
+
+	addresses := make([]common.Address, 0, len(addressSet))
+	for address := range addressSet { //nolint:maprange
+		addresses = append(addresses, address)
+	}
+	slices.SortFunc(addresses, common.Address.Compare)
+
+	var changeSet ChangeSet
+
+	committedSlabStorage := s.CommittedSlabStorage()
+
+	for _, address := range addresses {
+		accountChangeSet, err := s.accountChangeSet(inter, committedSlabStorage, address)
+		if err != nil {
+			return ChangeSet{}, err
+		}
+
+		if accountChangeSet.IsEmpty() {
+			continue
+		}
+
+		changeSet.Accounts = append(changeSet.Accounts, accountChangeSet)
+	}
+
+	return changeSet, nil
+}
+
+// accountChangeSet returns the storage keys of the given account which were added, updated, or removed
+// since the last commit, by comparing the modified domains of the account with their committed state.
+func (s *Storage) accountChangeSet(
+	inter *interpreter.Interpreter,
+	committedSlabStorage atree.SlabStorage,
+	address common.Address,
+) (
+	AccountChangeSet,
+	error,
+) {
+	accountChangeSet := AccountChangeSet{
+		Address: address,
+	}
+
+	// The account storage register and the domain registers are only written on commit,
+	// so the registers still refer to the committed state
+
+	committedAccountStorageMap, err := getAccountStorageMapFromRegister(
+		s.storageLedger,
+		committedSlabStorage,
+		address,
+	)
+	if err != nil {
+		return AccountChangeSet{}, err
+	}
+
+	accountStorageMap := s.AccountStorage.getAccountStorageMap(address)
+
+	// Determine the modified domains of the account (account storage format v2).
+	// If the account storage map was created or replaced, all domains are compared
+
+	var modifiedDomains map[common.StorageDomain]struct{}
+
+	if accountStorageMap != nil &&
+		committedAccountStorageMap != nil &&
+		accountStorageMap.SlabID() == committedAccountStorageMap.SlabID() {
+
+		modifiedDomains = accountStorageMap.ModifiedDomains(inter)
+	} else {
+		modifiedDomains = map[common.StorageDomain]struct{}{}
+
+		if accountStorageMap != nil {
+			maps.Copy(modifiedDomains, accountStorageMap.Domains())
+		}
+		if committedAccountStorageMap != nil {
+			maps.Copy(modifiedDomains, committedAccountStorageMap.Domains())
+		}
+	}
+
+	addDiff := func(domain common.StorageDomain, diff interpreter.DomainDiff) {
+		for _, key := range diff.Added {
+			accountChangeSet.Added = append(accountChangeSet.Added, ChangedStorageKey{Domain: domain, Key: key})
+		}
+		for _, change := range diff.Changed {
+			accountChangeSet.Updated = append(accountChangeSet.Updated, ChangedStorageKey{Domain: domain, Key: change.Key})
+		}
+		for _, key := range diff.Removed {
+			accountChangeSet.Removed = append(accountChangeSet.Removed, ChangedStorageKey{Domain: domain, Key: key})
+		}
+	}
+
+	for _, domain := range common.AllStorageDomains {
+		if _, ok := modifiedDomains[domain]; !ok {
+			continue
+		}
+
+		var committedDomainStorageMap *interpreter.DomainStorageMap
+		if committedAccountStorageMap != nil {
+			committedDomainStorageMap = committedAccountStorageMap.GetDomain(inter, inter, domain, false)
+		}
+
+		var domainStorageMap *interpreter.DomainStorageMap
+		if accountStorageMap != nil {
+			domainStorageMap = accountStorageMap.GetDomain(inter, inter, domain, false)
+		}
+
+		addDiff(
+			domain,
+			interpreter.DiffDomainStorageMaps(inter, committedDomainStorageMap, domainStorageMap),
+		)
+	}
+
+	// Removed domains of removed accounts in account storage format v1
+
+	for _, domain := range s.removedV1DomainRegisters[address] {
+		slabIndex, exists, err := readDomainSlabIndexFromRegister(s.storageLedger, address, domain)
+		if err != nil {
+			return AccountChangeSet{}, err
+		}
+		if !exists {
+			continue
+		}
+
+		committedDomainStorageMap := interpreter.NewDomainStorageMapWithRootID(
+			committedSlabStorage,
+			atree.NewSlabID(atree.Address(address), slabIndex),
+		)
+
+		addDiff(
+			domain,
+			interpreter.DiffDomainStorageMaps(inter, committedDomainStorageMap, nil),
+		)
+	}
+
+	// The keys of a diff are in the iteration order of the domain storage maps,
+	// which depends on the seeds of the maps, so sort them
+
+	slices.SortFunc(accountChangeSet.Added, ChangedStorageKey.Compare)
+	slices.SortFunc(accountChangeSet.Updated, ChangedStorageKey.Compare)
+	slices.SortFunc(accountChangeSet.Removed, ChangedStorageKey.Compare)
+
+	return accountChangeSet, nil
+}
+
+// ChangeSet is the set of storage keys which were changed by a commit, see Storage.CommitWithChangeSet.
+type ChangeSet struct {
+	// Accounts are the changes of each account, sorted by address.
+	// Accounts without changes are omitted.
+	Accounts []AccountChangeSet
+}
+
+// AccountChangeSet is the set of storage keys of an account which were changed by a commit.
+// The keys are sorted by domain, then by key.
+type AccountChangeSet struct {
+	Address common.Address
+	Added   []ChangedStorageKey
+	Updated []ChangedStorageKey
+	Removed []ChangedStorageKey
+}
+
+func (s AccountChangeSet) IsEmpty() bool {
+	return len(s.Added) == 0 &&
+		len(s.Updated) == 0 &&
+		len(s.Removed) == 0
+}
+
+// ChangedStorageKey is a key of a domain storage map which was changed by a commit.
+type ChangedStorageKey struct {
+	Domain common.StorageDomain
+	Key    interpreter.StorageMapKey
+}
+
+// Compare orders keys by domain, then string keys before integer keys,
+// then by the key itself.
+func (k ChangedStorageKey) Compare(o ChangedStorageKey) int {
+	if c := cmp.Compare(k.Domain, o.Domain); c != 0 {
+		return c
+	}
+
+	switch key := k.Key.(type) {
+	case interpreter.StringStorageMapKey:
+		switch otherKey := o.Key.(type) {
+		case interpreter.StringStorageMapKey:
+			return strings.Compare(string(key), string(otherKey))
+		case interpreter.Uint64StorageMapKey:
+			return -1
+		}
+
+	case interpreter.Uint64StorageMapKey:
+		switch otherKey := o.Key.(type) {
+		case interpreter.StringStorageMapKey:
+			return 1
+		case interpreter.Uint64StorageMapKey:
+			return cmp.Compare(key, otherKey)
+		}
+	}
+
+	panic(errors.NewUnreachableError())
+}
+
+// FlushDomain eagerly writes the slabs of the given domain storage map to the ledger,
+// without committing the other unsaved changes of the storage, e.g. to stream writes to a large domain.
+//
+// Only the unsaved slabs of the domain storage map's subtree are written,
+// not the slabs which reference the domain storage map, e.g. the account storage map.
+// So only domain storage maps which are stored in their own slabs, and whose root slab was already committed,
+// can be flushed. Otherwise, a DomainNotFlushableError is returned, and a full commit is required.
+//
+// The flushed slabs remain unsaved changes, and are written again on the next commit,
+// but their encoding is only metered again if they are modified again.
+// Slabs which were removed from the domain storage map are only removed from the ledger on the next commit.
+func (s *Storage) FlushDomain(
+	context interpreter.ValueTransferContext,
+	address common.Address,
+	domain common.StorageDomain,
+) error {
+
+	const createIfNotExists = false
+	domainStorageMap := s.GetDomainStorageMap(context, address, domain, createIfNotExists)
+	if domainStorageMap == nil {
+		return nil
+	}
+
+	if domainStorageMap.Inlined() {
+		return DomainNotFlushableError{
+			Address: address,
+			Domain:  domain,
+		}
+	}
+
+	rootSlabID := domainStorageMap.SlabID()
+	rootSlabIndex := rootSlabID.Index()
+	committed, err := s.storageLedger.ValueExists(address[:], atree.SlabIndexToLedgerKey(rootSlabIndex))
+	if err != nil {
+		return errors.NewExternalError(err)
+	}
+	if !committed {
+		return DomainNotFlushableError{
+			Address: address,
+			Domain:  domain,
+		}
+	}
+
+	// Count the unsaved slabs of the account which were not flushed yet,
+	// so the traversal below can stop once all of them were found.
+	// If there are none, there is nothing to flush.
+
+	remaining := 0
+	// NOTE: map range is safe, as only the count is computed
+	for slabID := range s.unsavedSlabIDs { //nolint:maprange
+		if slabID.Address() != atree.Address(address) || !s.isFlushableSlab(slabID) {
+			continue
+		}
+		// Removed slabs are not reachable
+		if s.PersistentSlabStorage.RetrieveIfLoaded(slabID) == nil {
+			continue
+		}
+		remaining++
+	}
+	if remaining == 0 {
+		return nil
+	}
+
+	// Collect the unsaved slabs of the domain storage map's subtree.
+	// Slabs which are not loaded are unchanged, so their subtrees can be skipped,
+	// as unsaved slabs were loaded through their parents,
+	// and the slab cache is never dropped while there are unsaved slabs, see dropSlabCache.
+
+	var slabIDs []atree.SlabID
+	var slabs []atree.Slab
+
+	queue := []atree.SlabID{rootSlabID}
+	for len(queue) > 0 && remaining > 0 {
+		slabID := queue[0]
+		queue = queue[1:]
+
+		slab := s.PersistentSlabStorage.RetrieveIfLoaded(slabID)
+		if slab == nil {
+			continue
+		}
+
+		if s.isFlushableSlab(slabID) {
+			slabIDs = append(slabIDs, slabID)
+			slabs = append(slabs, slab)
+			remaining--
+		}
+
+		childStorables := slab.ChildStorables()
+		for len(childStorables) > 0 {
+			var next []atree.Storable
+
+			for _, childStorable := range childStorables {
+				if slabIDStorable, ok := childStorable.(atree.SlabIDStorable); ok {
+					queue = append(queue, atree.SlabID(slabIDStorable))
+					continue
+				}
+
+				// Handle inlined slabs, which may contain slab ID storables
+				next = append(next, childStorable.ChildStorables()...)
+			}
+
+			childStorables = next
+		}
+	}
+
+	if len(slabs) == 0 {
+		return nil
+	}
+
+	// Encode and write the slabs.
+	// Their encoding is metered like on commit, and not metered again on commit, see commit
+
+	var size uint64
+	for _, slab := range slabs {
+		size += uint64(slab.ByteSize())
+	}
+	if size > 0 {
+		context.ReportComputation(common.ComputationKindEncodeValue, uint(size))
+		common.UseMemory(context, common.NewBytesMemoryUsage(int(size)))
+	}
+	common.UseMemory(context, common.NewAtreeEncodedSlabMemoryUsage(uint(len(slabs))))
+
+	for i, slab := range slabs {
+		slabID := slabIDs[i]
+
+		data, err := atree.EncodeSlab(slab, interpreter.CBOREncMode)
+		if err != nil {
+			return err
+		}
+
+		slabIndex := slabID.Index()
+		err = s.storageLedger.SetValue(address[:], atree.SlabIndexToLedgerKey(slabIndex), data)
+		if err != nil {
+			return errors.NewExternalError(err)
+		}
+
+		if s.flushedSlabIDs == nil {
+			s.flushedSlabIDs = map[atree.SlabID]struct{}{}
+		}
+		s.flushedSlabIDs[slabID] = struct{}{}
+	}
+
+	return nil
+}
+
 func (s *Storage) commit(context interpreter.ValueTransferContext, commitContractUpdates bool, deterministic bool) error {
 
 	if commitContractUpdates {
@@ -418,11 +1626,20 @@ func (s *Storage) commit(context interpreter.ValueTransferContext, commitContrac
 		return err
 	}
 
+	err = s.commitRemovedV1DomainRegisters()
+	if err != nil {
+		return err
+	}
+
 	// Commit the underlying slab storage's writes
 
 	slabStorage := s.PersistentSlabStorage
 
-	size := slabStorage.DeltasSizeWithoutTempAddresses()
+	// The encoding of flushed slabs was already metered by FlushDomain
+
+	flushedCount, flushedSize := s.flushedSlabsSize()
+
+	size := slabStorage.DeltasSizeWithoutTempAddresses() - flushedSize
 	if size > 0 {
 		context.ReportComputation(common.ComputationKindEncodeValue, uint(size))
 		usage := common.NewBytesMemoryUsage(int(size))
@@ -430,14 +1647,57 @@ func (s *Storage) commit(context interpreter.ValueTransferContext, commitContrac
 	}
 
 	deltas := slabStorage.DeltasWithoutTempAddresses()
-	common.UseMemory(context, common.NewAtreeEncodedSlabMemoryUsage(deltas))
+	common.UseMemory(context, common.NewAtreeEncodedSlabMemoryUsage(deltas-flushedCount))
+
+	commitParallelism := s.Config.commitParallelism()
+
+	onSlabEncoded := s.Config.OnSlabEncoded
+	if onSlabEncoded != nil {
+		s.storageLedger.recordWrittenSlabIDs = true
+		defer func() {
+			s.storageLedger.recordWrittenSlabIDs = false
+			s.storageLedger.writtenSlabIDs = nil
+		}()
+	}
 
 	// TODO: report encoding metric for all encoded slabs
 	if deterministic {
-		return slabStorage.FastCommit(runtime.NumCPU())
+		err = slabStorage.FastCommit(commitParallelism)
 	} else {
-		return slabStorage.NondeterministicFastCommit(runtime.NumCPU())
+		err = slabStorage.NondeterministicFastCommit(commitParallelism)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.clearUnsavedSlabs()
+
+	// Committed slabs are moved from the deltas to the read cache.
+	// Flush the read cache when the threshold is reached, now that no unsaved changes are left.
+	// NOTE: Slabs committed to a different ledger using CommitTo are only in the read cache,
+	// so they must not be dropped.
+	if s.Config.SlabCacheFlushThreshold > 0 {
+		s.cachedSlabCount += int(deltas)
+		if s.cachedSlabCount >= s.Config.SlabCacheFlushThreshold &&
+			s.storageLedger.targetLedger == nil {
+
+			defer s.dropSlabCache()
+		}
+	}
+
+	if onSlabEncoded != nil {
+		// The committed slabs are moved from the deltas to the cache,
+		// so their sizes are the same as the sizes reported above
+		for _, slabID := range s.storageLedger.writtenSlabIDs {
+			slab := slabStorage.RetrieveIfLoaded(slabID)
+			if slab == nil {
+				continue
+			}
+			onSlabEncoded(slabID, int(slab.ByteSize()))
+		}
 	}
+
+	return nil
 }
 
 func (s *Storage) CheckHealth() error {
@@ -518,19 +1778,338 @@ func (s *Storage) CheckHealth() error {
 			return a.Compare(b) < 0
 		})
 
+		unreferencedRootSlabs := make([]UnreferencedRootSlab, 0, len(unreferencedRootSlabIDs))
+		for _, slabID := range unreferencedRootSlabIDs {
+			unreferencedRootSlabs = append(
+				unreferencedRootSlabs,
+				UnreferencedRootSlab{
+					Address: common.Address(slabID.Address()),
+					SlabID:  slabID,
+				},
+			)
+		}
+
 		return UnreferencedRootSlabsError{
 			UnreferencedRootSlabIDs: unreferencedRootSlabIDs,
+			UnreferencedRootSlabs:   unreferencedRootSlabs,
 		}
 	}
 
 	return nil
 }
 
-// AccountStorageFormat returns either StorageFormatV1 or StorageFormatV2 for existing accounts,
-// and StorageFormatUnknown for non-existing accounts.
-func (s *Storage) AccountStorageFormat(address common.Address) (format StorageFormat) {
-	cachedFormat, known := s.getCachedAccountFormat(address)
-	if known {
+// CheckHealthSampled is like CheckHealth, but only checks a random sample of the accounts
+// which are loaded in storage or have unsaved slabs,
+// and reports the unreferenced root slabs of the sampled accounts.
+//
+// The given fraction, in the range [0, 1], determines the size of the sample,
+// which is rounded up to the next whole account.
+// The sample is chosen deterministically from the given seed,
+// i.e. the same seed samples the same accounts of the same storage.
+//
+// Unlike CheckHealth, only the slabs stored since the last commit are checked,
+// and only the loaded slabs of the sampled accounts are traversed,
+// so the cost of the check is proportional to the sample, not to the whole storage.
+// Slabs are never loaded from the ledger.
+func (s *Storage) CheckHealthSampled(fraction float64, seed int64) error {
+
+	if !(fraction >= 0 && fraction <= 1) {
+		return InvalidHealthCheckSampleFractionError{
+			Fraction: fraction,
+		}
+	}
+
+	// Group the unsaved slabs by account.
+	// Removed slabs cannot be unreferenced, so they are skipped
+
+	accountUnsavedSlabIDs := map[common.Address]map[atree.SlabID]struct{}{}
+
+	// NOTE: map range is safe, as it creates a set
+	for slabID := range s.unsavedSlabIDs { //nolint:maprange
+		if s.PersistentSlabStorage.RetrieveIfLoaded(slabID) == nil {
+			continue
+		}
+
+		address := common.Address(slabID.Address())
+
+		slabIDs, ok := accountUnsavedSlabIDs[address]
+		if !ok {
+			slabIDs = map[atree.SlabID]struct{}{}
+			accountUnsavedSlabIDs[address] = slabIDs
+		}
+		slabIDs[slabID] = struct{}{}
+	}
+
+	// Sample the accounts, which are the cached accounts and the accounts with unsaved slabs.
+	// NOTE: addresses are sorted, so the sample only depends on the seed
+
+	addresses := s.Addresses()
+
+	// NOTE: map range is safe, as addresses are sorted below
+	for address := range accountUnsavedSlabIDs { //nolint:maprange
+		_, cached := slices.BinarySearchFunc(addresses, address, common.Address.Compare)
+		if !cached {
+			addresses = append(addresses, address)
+		}
+	}
+
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].Compare(addresses[j]) < 0
+	})
+
+	sampleSize := int(math.Ceil(fraction * float64(len(addresses))))
+
+	random := rand.New(rand.NewSource(seed))
+
+	sampledAddresses := make([]common.Address, 0, sampleSize)
+	for _, index := range random.Perm(len(addresses))[:sampleSize] {
+		sampledAddresses = append(sampledAddresses, addresses[index])
+	}
+
+	sort.Slice(sampledAddresses, func(i, j int) bool {
+		return sampledAddresses[i].Compare(sampledAddresses[j]) < 0
+	})
+
+	// Check the sampled accounts:
+	// Every unsaved slab of an account must be reachable from the account storage map.
+
+	var unreferencedRootSlabIDs []atree.SlabID
+
+	for _, address := range sampledAddresses {
+		unreferencedSlabIDs := accountUnsavedSlabIDs[address]
+		if len(unreferencedSlabIDs) == 0 {
+			continue
+		}
+
+		var queue []atree.SlabID
+		if accountStorageMap, ok := s.AccountStorage.cachedAccountStorageMaps[address]; ok {
+			queue = append(queue, accountStorageMap.SlabID())
+		}
+
+		// Traverse the loaded slabs of the account storage map's subtree,
+		// until all unsaved slabs were found.
+		// Slabs which are not loaded are unchanged, so their subtrees can be skipped,
+		// as unsaved slabs were loaded through their parents,
+		// and the slab cache is never dropped while there are unsaved slabs, see dropSlabCache.
+
+		for len(queue) > 0 && len(unreferencedSlabIDs) > 0 {
+			slabID := queue[0]
+			queue = queue[1:]
+
+			slab := s.PersistentSlabStorage.RetrieveIfLoaded(slabID)
+			if slab == nil {
+				continue
+			}
+
+			delete(unreferencedSlabIDs, slabID)
+
+			childStorables := slab.ChildStorables()
+			for len(childStorables) > 0 {
+				var next []atree.Storable
+
+				for _, childStorable := range childStorables {
+					if slabIDStorable, ok := childStorable.(atree.SlabIDStorable); ok {
+						queue = append(queue, atree.SlabID(slabIDStorable))
+						continue
+					}
+
+					// Handle inlined slabs, which may contain slab ID storables
+					next = append(next, childStorable.ChildStorables()...)
+				}
+
+				childStorables = next
+			}
+		}
+
+		for slabID := range unreferencedSlabIDs { //nolint:maprange
+			unreferencedRootSlabIDs = append(unreferencedRootSlabIDs, slabID)
+		}
+	}
+
+	if len(unreferencedRootSlabIDs) == 0 {
+		return nil
+	}
+
+	sort.Slice(unreferencedRootSlabIDs, func(i, j int) bool {
+		a := unreferencedRootSlabIDs[i]
+		b := unreferencedRootSlabIDs[j]
+		return a.Compare(b) < 0
+	})
+
+	unreferencedRootSlabs := make([]UnreferencedRootSlab, 0, len(unreferencedRootSlabIDs))
+	for _, slabID := range unreferencedRootSlabIDs {
+		unreferencedRootSlabs = append(
+			unreferencedRootSlabs,
+			UnreferencedRootSlab{
+				Address: common.Address(slabID.Address()),
+				SlabID:  slabID,
+			},
+		)
+	}
+
+	return UnreferencedRootSlabsError{
+		UnreferencedRootSlabIDs: unreferencedRootSlabIDs,
+		UnreferencedRootSlabs:   unreferencedRootSlabs,
+	}
+}
+
+// AccountSlabIDs returns the sorted IDs of all slabs of the given account,
+// i.e. the root slabs and the child slabs stored under the account's address.
+//
+// The root slabs of the account are loaded first, so the result includes all slabs
+// reachable from the account's storage, even if they were not accessed yet.
+// Unsaved slabs, e.g. slabs of newly stored values, are included, removed slabs are not.
+func (s *Storage) AccountSlabIDs(address common.Address) ([]atree.SlabID, error) {
+
+	// Load the root slabs of the account
+
+	switch s.AccountStorageFormat(address) {
+	case StorageFormatV1:
+		for _, domain := range common.AllStorageDomains {
+			slabIndex, exists, err := s.readDomainSlabIndex(
+				address,
+				domain,
+			)
+			if err != nil {
+				return nil, err
+			}
+			if !exists {
+				continue
+			}
+
+			slabID := atree.NewSlabID(atree.Address(address), slabIndex)
+			_ = interpreter.NewDomainStorageMapWithRootID(s, slabID)
+		}
+
+	case StorageFormatV2:
+		_ = s.AccountStorage.getAccountStorageMap(address)
+	}
+
+	// Enumerate the slabs, filtered by the account's address
+
+	slabIterator, err := s.SlabIterator()
+	if err != nil {
+		return nil, errors.NewExternalError(err)
+	}
+
+	atreeAddress := atree.Address(address)
+
+	var slabIDs []atree.SlabID
+
+	for {
+		slabID, _ := slabIterator()
+		if slabID == atree.SlabIDUndefined {
+			break
+		}
+
+		if slabID.Address() != atreeAddress {
+			continue
+		}
+
+		slabIDs = append(slabIDs, slabID)
+	}
+
+	sort.Slice(slabIDs, func(i, j int) bool {
+		return slabIDs[i].Compare(slabIDs[j]) < 0
+	})
+
+	return slabIDs, nil
+}
+
+// VerifyChecksums re-reads all slabs which were written to the ledger since the storage was created,
+// and checks that their data still matches the checksums recorded when they were written.
+// The slabs with mismatching data, including slabs which are missing from the ledger,
+// are reported in a SlabChecksumMismatchError.
+//
+// Checksums are only recorded if StorageConfig.SlabChecksums is enabled,
+// otherwise there is nothing to verify.
+func (s *Storage) VerifyChecksums() error {
+	slabChecksums := s.storageLedger.slabChecksums
+
+	var mismatchedSlabIDs []atree.SlabID
+
+	// NOTE: map range is safe, as mismatched slab IDs are sorted below
+	for slabID, checksum := range slabChecksums { //nolint:maprange
+		address := slabID.Address()
+		index := slabID.Index()
+
+		// NOTE: read from the ledger directly,
+		// as verification is not part of the regular register reads, see ReadCount
+		value, err := s.Ledger.GetValue(address[:], atree.SlabIndexToLedgerKey(index))
+		if err != nil {
+			return errors.NewExternalError(err)
+		}
+
+		if len(value) == 0 || crc32.ChecksumIEEE(value) != checksum {
+			mismatchedSlabIDs = append(mismatchedSlabIDs, slabID)
+		}
+	}
+
+	if len(mismatchedSlabIDs) == 0 {
+		return nil
+	}
+
+	sort.Slice(mismatchedSlabIDs, func(i, j int) bool {
+		return mismatchedSlabIDs[i].Compare(mismatchedSlabIDs[j]) < 0
+	})
+
+	return SlabChecksumMismatchError{
+		SlabIDs: mismatchedSlabIDs,
+	}
+}
+
+// Addresses returns the sorted addresses of all accounts whose storage was accessed,
+// i.e. the accounts in the account format cache, the domain storage map cache,
+// and the account storage map cache.
+func (s *Storage) Addresses() []common.Address {
+	addressSet := map[common.Address]struct{}{}
+
+	// NOTE: map range is safe, as addresses are collected in a set and sorted below
+
+	s.cachedV1Accounts.Foreach(func(address common.Address, _ bool) {
+		addressSet[address] = struct{}{}
+	})
+
+	for domainStorageKey := range s.cachedDomainStorageMaps { //nolint:maprange
+		addressSet[domainStorageKey.Address] = struct{}{}
+	}
+
+	for address := range s.AccountStorage.cachedAccountStorageMaps { //nolint:maprange
+		addressSet[address] = struct{}{}
+	}
+
+	addresses := make([]common.Address, 0, len(addressSet))
+	for address := range addressSet { //nolint:maprange
+		addresses = append(addresses, address)
+	}
+
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].Compare(addresses[j]) < 0
+	})
+
+	return addresses
+}
+
+// ModifiedAddresses returns the sorted addresses of all accounts whose storage was accessed,
+// and which have unsaved changes.
+func (s *Storage) ModifiedAddresses() []common.Address {
+	addresses := s.Addresses()
+
+	modifiedAddresses := addresses[:0]
+	for _, address := range addresses {
+		if s.PersistentSlabStorage.HasUnsavedChanges(atree.Address(address)) {
+			modifiedAddresses = append(modifiedAddresses, address)
+		}
+	}
+
+	return modifiedAddresses
+}
+
+// AccountStorageFormat returns either StorageFormatV1 or StorageFormatV2 for existing accounts,
+// and StorageFormatUnknown for non-existing accounts.
+func (s *Storage) AccountStorageFormat(address common.Address) (format StorageFormat) {
+	cachedFormat, known := s.getCachedAccountFormat(address)
+	if known {
 		return cachedFormat
 	}
 
@@ -555,8 +2134,227 @@ func (s *Storage) AccountStorageFormat(address common.Address) (format StorageFo
 	return StorageFormatUnknown
 }
 
+// PreloadAccountFormats determines and caches the account storage formats of the given accounts,
+// which would otherwise be determined lazily, e.g. when a domain storage map is first accessed.
+//
+// Register reads are grouped: first the account storage map registers of all given accounts are read,
+// then the domain registers of the accounts which are not in account storage format v2.
+// The cached formats are identical to the ones determined by AccountStorageFormat,
+// i.e. formats of non-existing accounts are not cached.
+func (s *Storage) PreloadAccountFormats(addresses []common.Address) {
+
+	seen := make(map[common.Address]struct{}, len(addresses))
+
+	var remaining []common.Address
+
+	// Check if accounts are v2 (by reading "stored" registers).
+
+	for _, address := range addresses {
+		if _, ok := seen[address]; ok {
+			continue
+		}
+		seen[address] = struct{}{}
+
+		if _, known := s.getCachedAccountFormat(address); known {
+			continue
+		}
+
+		if s.isV2Account(address) {
+			s.cacheIsV1Account(address, false)
+			continue
+		}
+
+		remaining = append(remaining, address)
+	}
+
+	// Check if remaining accounts are v1 (by reading domain registers).
+
+	for _, address := range remaining {
+		if s.isV1Account(address) {
+			s.cacheIsV1Account(address, true)
+		}
+	}
+}
+
+// RemoveAccount removes all storage of the given account, and removes all slabs reachable from it:
+// the account storage map and all its domains (account storage format v2),
+// or all domain registers and their domain storage maps (account storage format v1).
+// Cached account format, account storage map, and domain storage maps of the account are cleared.
+//
+// Like the removal of the slabs, the removal of the account storage register and domain registers
+// is only written to the ledger on commit, so the ledger is unchanged if the storage is not committed.
+func (s *Storage) RemoveAccount(inter *interpreter.Interpreter, address common.Address) error {
+
+	inter.RecordStorageMutation()
+
+	// Remove account storage map (account storage format v2)
+
+	err := s.AccountStorage.removeAccountStorageMap(inter, address)
+	if err != nil {
+		return err
+	}
+
+	// Remove domain storage maps (account storage format v1).
+	// The domain registers are removed on commit.
+
+	var removedDomains []common.StorageDomain
+
+	for _, domain := range common.AllStorageDomains {
+		slabIndex, exists, err := s.readDomainSlabIndex(address, domain)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+
+		slabID := atree.NewSlabID(atree.Address(address), slabIndex)
+
+		domainStorageMap := interpreter.NewDomainStorageMapWithRootID(s, slabID)
+
+		// NOTE: Don't validate atree storage, as domain storage maps of v1 accounts
+		// are not referenced by an account storage map.
+		domainStorageMap.DeepRemove(inter, false)
+
+		err = s.Remove(slabID)
+		if err != nil {
+			return errors.NewExternalError(err)
+		}
+
+		removedDomains = append(removedDomains, domain)
+	}
+
+	if len(removedDomains) > 0 {
+		if s.removedV1DomainRegisters == nil {
+			s.removedV1DomainRegisters = map[common.Address][]common.StorageDomain{}
+		}
+		s.removedV1DomainRegisters[address] = removedDomains
+	}
+
+	// Clear caches
+
+	for _, domain := range common.AllStorageDomains {
+		domainStorageKey := interpreter.NewStorageDomainKey(s.memoryGauge, address, domain)
+		delete(s.cachedDomainStorageMaps, domainStorageKey)
+	}
+
+	s.cachedV1Accounts.Delete(address)
+
+	return nil
+}
+
+// commitRemovedV1DomainRegisters removes the domain registers of removed accounts
+// in account storage format v1, in deterministic order, see RemoveAccount.
+func (s *Storage) commitRemovedV1DomainRegisters() error {
+	if len(s.removedV1DomainRegisters) == 0 {
+		return nil
+	}
+
+	addresses := make([]common.Address, 0, len(s.removedV1DomainRegisters))
+	for address := range s.removedV1DomainRegisters { //nolint:maprange
+		addresses = append(addresses, address)
+	}
+	slices.SortFunc(addresses, common.Address.Compare)
+
+	for _, address := range addresses {
+		for _, domain := range s.removedV1DomainRegisters[address] {
+			err := removeRegister(s.storageLedger, address, []byte(domain.Identifier()))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	s.removedV1DomainRegisters = nil
+
+	return nil
+}
+
+// IterableLedger is a ledger which can iterate over all of its registers.
+type IterableLedger interface {
+	atree.Ledger
+	// ForEach calls the given function for each register.
+	ForEach(f func(owner, key, value []byte) error) error
+}
+
+// IterateAccounts calls the given function for each account which has storage in the ledger,
+// in address order, until the function returns false or an error.
+// Accounts are discovered from their account storage map register (account storage format v2)
+// and their domain registers (account storage format v1).
+// The ledger must implement IterableLedger.
+func (s *Storage) IterateAccounts(f func(address common.Address) (bool, error)) error {
+
+	ledger, ok := s.Ledger.(IterableLedger)
+	if !ok {
+		return LedgerNotIterableError{}
+	}
+
+	accountKeys := make(map[string]struct{}, len(common.AllStorageDomains)+1)
+	accountKeys[AccountStorageKey] = struct{}{}
+	for _, domain := range common.AllStorageDomains {
+		accountKeys[domain.Identifier()] = struct{}{}
+	}
+
+	seen := map[common.Address]struct{}{}
+	var addresses []common.Address
+
+	err := ledger.ForEach(func(owner, key, value []byte) error {
+		// Skip removed registers
+		if len(value) == 0 {
+			return nil
+		}
+
+		if _, ok := accountKeys[string(key)]; !ok {
+			return nil
+		}
+
+		address, err := common.BytesToAddress(owner)
+		if err != nil {
+			return err
+		}
+
+		if _, ok := seen[address]; ok {
+			return nil
+		}
+		seen[address] = struct{}{}
+
+		addresses = append(addresses, address)
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	slices.SortFunc(addresses, common.Address.Compare)
+
+	for _, address := range addresses {
+		ok, err := f(address)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+	}
+
+	return nil
+}
+
+// UnreferencedRootSlab is a root slab which is not referenced by any account storage map,
+// and the account which owns it.
+type UnreferencedRootSlab struct {
+	Address common.Address
+	SlabID  atree.SlabID
+}
+
+func (s UnreferencedRootSlab) String() string {
+	return fmt.Sprintf("%s (account %s)", s.SlabID, s.Address.HexWithPrefix())
+}
+
 type UnreferencedRootSlabsError struct {
 	UnreferencedRootSlabIDs []atree.SlabID
+	UnreferencedRootSlabs   []UnreferencedRootSlab
 }
 
 var _ errors.InternalError = UnreferencedRootSlabsError{}
@@ -564,10 +2362,202 @@ var _ errors.InternalError = UnreferencedRootSlabsError{}
 func (UnreferencedRootSlabsError) IsInternalError() {}
 
 func (e UnreferencedRootSlabsError) Error() string {
+	if len(e.UnreferencedRootSlabs) == 0 {
+		return fmt.Sprintf(
+			"%s slabs not referenced: %s",
+			errors.InternalErrorMessagePrefix,
+			e.UnreferencedRootSlabIDs,
+		)
+	}
+
 	return fmt.Sprintf(
 		"%s slabs not referenced: %s",
 		errors.InternalErrorMessagePrefix,
-		e.UnreferencedRootSlabIDs,
+		e.UnreferencedRootSlabs,
+	)
+}
+
+type InvalidCommitParallelismError struct {
+	CommitParallelism int
+}
+
+var _ errors.InternalError = InvalidCommitParallelismError{}
+
+func (InvalidCommitParallelismError) IsInternalError() {}
+
+func (e InvalidCommitParallelismError) Error() string {
+	return fmt.Sprintf(
+		"%s invalid commit parallelism %d: must be 0 (number of CPUs) or greater",
+		errors.InternalErrorMessagePrefix,
+		e.CommitParallelism,
+	)
+}
+
+type SlabChecksumMismatchError struct {
+	SlabIDs []atree.SlabID
+}
+
+var _ errors.InternalError = SlabChecksumMismatchError{}
+
+func (SlabChecksumMismatchError) IsInternalError() {}
+
+func (e SlabChecksumMismatchError) Error() string {
+	return fmt.Sprintf(
+		"%s slab checksums mismatch: %s",
+		errors.InternalErrorMessagePrefix,
+		e.SlabIDs,
+	)
+}
+
+type DomainNotFlushableError struct {
+	Address common.Address
+	Domain  common.StorageDomain
+}
+
+var _ errors.InternalError = DomainNotFlushableError{}
+
+func (DomainNotFlushableError) IsInternalError() {}
+
+func (e DomainNotFlushableError) Error() string {
+	return fmt.Sprintf(
+		"%s cannot flush domain %s of account %s: domain storage map is not stored in committed, separate slabs",
+		errors.InternalErrorMessagePrefix,
+		e.Domain.Identifier(),
+		e.Address.HexWithPrefix(),
+	)
+}
+
+type InvalidPathDomainTypeError struct {
+	Type sema.Type
+}
+
+var _ errors.InternalError = InvalidPathDomainTypeError{}
+
+func (InvalidPathDomainTypeError) IsInternalError() {}
+
+func (e InvalidPathDomainTypeError) Error() string {
+	var typeID string
+	if e.Type != nil {
+		typeID = string(e.Type.ID())
+	}
+	return fmt.Sprintf(
+		"%s invalid path domain type %s: expected StoragePath, PublicPath, or PrivatePath",
+		errors.InternalErrorMessagePrefix,
+		typeID,
+	)
+}
+
+type InvalidSlabCacheFlushThresholdError struct {
+	SlabCacheFlushThreshold int
+}
+
+var _ errors.InternalError = InvalidSlabCacheFlushThresholdError{}
+
+func (InvalidSlabCacheFlushThresholdError) IsInternalError() {}
+
+func (e InvalidSlabCacheFlushThresholdError) Error() string {
+	return fmt.Sprintf(
+		"%s invalid slab cache flush threshold %d: must be 0 (no flushing) or greater",
+		errors.InternalErrorMessagePrefix,
+		e.SlabCacheFlushThreshold,
+	)
+}
+
+type InvalidFormatCacheSizeError struct {
+	FormatCacheSize int
+}
+
+var _ errors.InternalError = InvalidFormatCacheSizeError{}
+
+func (InvalidFormatCacheSizeError) IsInternalError() {}
+
+func (e InvalidFormatCacheSizeError) Error() string {
+	return fmt.Sprintf(
+		"%s invalid format cache size %d: must be 0 (unlimited) or greater",
+		errors.InternalErrorMessagePrefix,
+		e.FormatCacheSize,
+	)
+}
+
+type InvalidHealthCheckSampleFractionError struct {
+	Fraction float64
+}
+
+var _ errors.InternalError = InvalidHealthCheckSampleFractionError{}
+
+func (InvalidHealthCheckSampleFractionError) IsInternalError() {}
+
+func (e InvalidHealthCheckSampleFractionError) Error() string {
+	return fmt.Sprintf(
+		"%s invalid health check sample fraction %v: must be in the range [0, 1]",
+		errors.InternalErrorMessagePrefix,
+		e.Fraction,
+	)
+}
+
+type UnsavedChangesRegisterWriteError struct {
+	Address common.Address
+}
+
+var _ errors.InternalError = UnsavedChangesRegisterWriteError{}
+
+func (UnsavedChangesRegisterWriteError) IsInternalError() {}
+
+func (e UnsavedChangesRegisterWriteError) Error() string {
+	return fmt.Sprintf(
+		"%s cannot write register of account %s: account has unsaved changes",
+		errors.InternalErrorMessagePrefix,
+		e.Address.HexWithPrefix(),
+	)
+}
+
+type UnsavedChangesCacheInvalidationError struct{}
+
+var _ errors.InternalError = UnsavedChangesCacheInvalidationError{}
+
+func (UnsavedChangesCacheInvalidationError) IsInternalError() {}
+
+func (UnsavedChangesCacheInvalidationError) Error() string {
+	return fmt.Sprintf(
+		"%s cannot invalidate storage caches: storage has unsaved changes",
+		errors.InternalErrorMessagePrefix,
+	)
+}
+
+type LedgerNotIterableError struct{}
+
+var _ errors.InternalError = LedgerNotIterableError{}
+
+func (LedgerNotIterableError) IsInternalError() {}
+
+func (LedgerNotIterableError) Error() string {
+	return fmt.Sprintf(
+		"%s ledger does not support iterating over registers",
+		errors.InternalErrorMessagePrefix,
+	)
+}
+
+// MalformedDomainRegisterError is returned when the value of a domain register
+// (account storage format v1) is not a valid slab index.
+type MalformedDomainRegisterError struct {
+	Address common.Address
+	Domain  common.StorageDomain
+	Data    []byte
+}
+
+var _ errors.InternalError = MalformedDomainRegisterError{}
+
+func (MalformedDomainRegisterError) IsInternalError() {}
+
+func (e MalformedDomainRegisterError) Error() string {
+	return fmt.Sprintf(
+		"%s malformed %s domain register of account %s: expected slab index of length %d, got %d bytes: %x",
+		errors.InternalErrorMessagePrefix,
+		e.Domain.Identifier(),
+		e.Address.HexWithPrefix(),
+		storageIndexLength,
+		len(e.Data),
+		e.Data,
 	)
 }
 
@@ -22,8 +22,10 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"math"
 	"math/rand"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -39,6 +41,7 @@ import (
 	"github.com/onflow/cadence/encoding/json"
 	"github.com/onflow/cadence/interpreter"
 	. "github.com/onflow/cadence/runtime"
+	"github.com/onflow/cadence/sema"
 	. "github.com/onflow/cadence/test_utils/common_utils"
 	. "github.com/onflow/cadence/test_utils/interpreter_utils"
 	. "github.com/onflow/cadence/test_utils/runtime_utils"
@@ -1957,6 +1960,96 @@ func TestRuntimeSortContractUpdates(t *testing.T) {
 	)
 }
 
+func TestRuntimePendingContractUpdates(t *testing.T) {
+
+	t.Parallel()
+
+	address1 := common.MustBytesToAddress([]byte{0x1})
+	address2 := common.MustBytesToAddress([]byte{0x2})
+
+	ledger := NewTestLedger(nil, nil)
+	storage := NewStorage(ledger, nil, StorageConfig{})
+	inter := NewTestInterpreterWithStorage(t, storage)
+
+	environment := NewBaseInterpreterEnvironment(Config{})
+	environment.Configure(
+		&TestRuntimeInterface{},
+		NewCodesAndPrograms(),
+		storage,
+		nil,
+	)
+
+	require.Nil(t, storage.PendingContractUpdates())
+
+	newContractValue := func(location common.AddressLocation) *interpreter.CompositeValue {
+		return interpreter.NewCompositeValue(
+			inter,
+			interpreter.EmptyLocationRange,
+			location,
+			location.Name,
+			common.CompositeKindContract,
+			nil,
+			location.Address,
+		)
+	}
+
+	locationB := common.NewAddressLocation(nil, address2, "B")
+	locationA := common.NewAddressLocation(nil, address2, "A")
+	locationC := common.NewAddressLocation(nil, address1, "C")
+
+	contractValueB := newContractValue(locationB)
+	contractValueC := newContractValue(locationC)
+
+	environment.RecordContractUpdate(locationB, contractValueB)
+	environment.RecordContractRemoval(locationA)
+	environment.RecordContractUpdate(locationC, contractValueC)
+
+	updates := storage.PendingContractUpdates()
+	require.Equal(t,
+		[]ContractUpdate{
+			{
+				Key: interpreter.StorageKey{
+					Address: address1,
+					Key:     "C",
+				},
+				ContractValue: contractValueC,
+			},
+			{
+				Key: interpreter.StorageKey{
+					Address: address2,
+					Key:     "A",
+				},
+			},
+			{
+				Key: interpreter.StorageKey{
+					Address: address2,
+					Key:     "B",
+				},
+				ContractValue: contractValueB,
+			},
+		},
+		updates,
+	)
+
+	require.False(t, updates[0].IsRemoval())
+	require.True(t, updates[1].IsRemoval())
+	require.False(t, updates[2].IsRemoval())
+
+	// The returned updates are a copy
+
+	updates[0] = ContractUpdate{}
+
+	require.Len(t, storage.PendingContractUpdates(), 3)
+	require.Equal(t,
+		contractValueC,
+		storage.PendingContractUpdates()[0].ContractValue,
+	)
+
+	// Updates are not committed
+
+	require.Empty(t, ledger.StoredValues)
+}
+
 func TestRuntimeMissingSlab1173(t *testing.T) {
 
 	t.Parallel()
@@ -8367,141 +8460,3463 @@ func TestGetDomainStorageMapRegisterReadsForV2Account(t *testing.T) {
 	}
 }
 
-// createAndWriteAccountStorageMap creates account storage map with given domains and writes random values to domain storage map.
-func createAndWriteAccountStorageMap(
-	t testing.TB,
-	storage *Storage,
-	inter *interpreter.Interpreter,
-	address common.Address,
-	domains []common.StorageDomain,
-	count int,
-	random *rand.Rand,
-) accountStorageMapValues {
+func TestRuntimeStorageCommitParallelism(t *testing.T) {
+	t.Parallel()
 
-	accountValues := make(accountStorageMapValues)
+	address := common.MustBytesToAddress([]byte{0x1})
 
-	// Create domain storage map
-	for _, domain := range domains {
-		const createIfNotExists = true
-		domainStorageMap := storage.GetDomainStorageMap(inter, address, domain, createIfNotExists)
-		require.NotNil(t, domainStorageMap)
-		require.Equal(t, uint64(0), domainStorageMap.Count())
+	domains := []common.StorageDomain{
+		common.PathDomainStorage.StorageDomain(),
+		common.PathDomainPublic.StorageDomain(),
+		common.StorageDomainContract,
+	}
 
-		// Write to to domain storage map
-		accountValues[domain] = writeToDomainStorageMap(inter, domainStorageMap, count, random)
+	const domainStorageMapCount = 100
+
+	commit := func(t *testing.T, config StorageConfig) TestLedger {
+		ledger := NewTestLedger(nil, nil)
+		storage := NewStorage(ledger, nil, config)
+
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		random := rand.New(rand.NewSource(42))
+
+		createAndWriteAccountStorageMap(t, storage, inter, address, domains, domainStorageMapCount, random)
+
+		return ledger
 	}
 
-	// Commit changes
-	const commitContractUpdates = false
-	err := storage.Commit(inter, commitContractUpdates)
-	require.NoError(t, err)
+	t.Run("parallelism 1 equals default", func(t *testing.T) {
+		t.Parallel()
 
-	// Check storage health after commit
-	err = storage.CheckHealth()
-	require.NoError(t, err)
+		defaultLedger := commit(t, StorageConfig{})
+		sequentialLedger := commit(t, StorageConfig{CommitParallelism: 1})
 
-	return accountValues
+		require.Equal(t, defaultLedger.StoredValues, sequentialLedger.StoredValues)
+		require.Equal(t, defaultLedger.StorageIndices, sequentialLedger.StorageIndices)
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		t.Parallel()
+
+		require.PanicsWithValue(
+			t,
+			InvalidCommitParallelismError{CommitParallelism: -1},
+			func() {
+				NewStorage(NewTestLedger(nil, nil), nil, StorageConfig{CommitParallelism: -1})
+			},
+		)
+	})
 }
 
-func writeToDomainStorageMap(
-	inter *interpreter.Interpreter,
-	domainStorageMap *interpreter.DomainStorageMap,
-	count int,
-	random *rand.Rand,
-) domainStorageMapValues {
-	domainValues := make(domainStorageMapValues)
+func TestRuntimeStorageHasDomain(t *testing.T) {
+	t.Parallel()
 
-	for len(domainValues) < count {
-		n := random.Int()
+	address := common.MustBytesToAddress([]byte{0x1})
 
-		key := interpreter.StringStorageMapKey(strconv.Itoa(n))
+	storageDomain := common.PathDomainStorage.StorageDomain()
+	publicDomain := common.PathDomainPublic.StorageDomain()
+
+	// newStorageWithData creates storage with given data,
+	// and records reads of registers which are not slabs.
+	newStorageWithData := func(
+		storedValues map[string][]byte,
+		storageIndices map[string]uint64,
+		registerReads *[]string,
+	) *Storage {
+		ledger := NewTestLedgerWithData(
+			func(owner, key, _ []byte) {
+				if key[0] == '$' {
+					return
+				}
+				*registerReads = append(*registerReads, string(key))
+			},
+			nil,
+			storedValues,
+			storageIndices,
+		)
+		return NewStorage(ledger, nil, StorageConfig{})
+	}
 
-		value := interpreter.NewUnmeteredIntValueFromInt64(int64(n))
+	t.Run("new account", func(t *testing.T) {
+		t.Parallel()
 
-		domainStorageMap.WriteValue(inter, key, value)
+		var registerReads []string
 
-		domainValues[key] = value
-	}
+		storage := newStorageWithData(map[string][]byte{}, map[string]uint64{}, &registerReads)
 
-	return domainValues
-}
+		exists, err := storage.HasDomain(address, storageDomain)
+		require.NoError(t, err)
+		require.False(t, exists)
 
-// checkAccountStorageMapData creates new storage with given storedValues,
-// and compares account storage map values with given expectedAccountValues.
-func checkAccountStorageMapData(
-	tb testing.TB,
-	storedValues map[string][]byte,
-	storageIndices map[string]uint64,
-	address common.Address,
-	expectedAccountValues accountStorageMapValues,
-) {
-	// Create storage with given storedValues and storageIndices
-	ledger := NewTestLedgerWithData(nil, nil, storedValues, storageIndices)
-	storage := NewStorage(
-		ledger,
-		nil,
-		StorageConfig{},
-	)
+		require.Equal(t, []string{AccountStorageKey, storageDomain.Identifier()}, registerReads)
 
-	inter := NewTestInterpreterWithStorage(tb, storage)
+		// HasDomain doesn't create domain
+		require.Equal(t, StorageFormatUnknown, storage.AccountStorageFormat(address))
+	})
 
-	// Get account register
-	accountStorageMapSlabIndex, err := ledger.GetValue(address[:], []byte(AccountStorageKey))
-	require.NoError(tb, err)
-	require.Equal(tb, 8, len(accountStorageMapSlabIndex))
+	t.Run("v1 account", func(t *testing.T) {
+		t.Parallel()
 
-	// Load account storage map
-	accountSlabID := atree.NewSlabID(
-		atree.Address(address[:]),
-		atree.SlabIndex(accountStorageMapSlabIndex[:]),
-	)
-	accountStorageMap := interpreter.NewAccountStorageMapWithRootID(storage, accountSlabID)
-	require.NotNil(tb, accountStorageMap)
-	require.Equal(tb, uint64(len(expectedAccountValues)), accountStorageMap.Count())
+		ledger := NewTestLedger(nil, nil)
 
-	domainCount := 0
-	iter := accountStorageMap.Iterator()
-	for {
-		domain, domainStorageMap := iter.Next()
-		if domain == common.StorageDomainUnknown {
-			break
-		}
+		persistentSlabStorage := NewPersistentSlabStorage(ledger, nil)
 
-		domainCount++
+		orderedMap, err := atree.NewMap(
+			persistentSlabStorage,
+			atree.Address(address),
+			atree.NewDefaultDigesterBuilder(),
+			interpreter.EmptyTypeInfo{},
+		)
+		require.NoError(t, err)
 
-		expectedDomainValues, exist := expectedAccountValues[domain]
-		require.True(tb, exist)
-		require.Equal(tb, uint64(len(expectedDomainValues)), domainStorageMap.Count())
+		err = persistentSlabStorage.FastCommit(runtime.NumCPU())
+		require.NoError(t, err)
 
-		// Check values stored in domain storage map
-		for key, expectedValue := range expectedDomainValues {
-			value := domainStorageMap.ReadValue(nil, key)
+		// Create domain register
+		slabIndex := orderedMap.SlabID().Index()
+		err = ledger.SetValue(address[:], []byte(storageDomain.Identifier()), slabIndex[:])
+		require.NoError(t, err)
 
-			ev, ok := value.(interpreter.EquatableValue)
-			require.True(tb, ok)
-			require.True(tb, ev.Equal(inter, interpreter.EmptyLocationRange, expectedValue))
-		}
-	}
+		var registerReads []string
 
-	require.Equal(tb, len(expectedAccountValues), domainCount)
+		storage := newStorageWithData(ledger.StoredValues, ledger.StorageIndices, &registerReads)
 
-	// Check atree storage health
-	rootSlabIDs, err := atree.CheckStorageHealth(storage.PersistentSlabStorage, 1)
-	require.NoError(tb, err)
-	require.Equal(tb, 1, len(rootSlabIDs))
-	require.Contains(tb, rootSlabIDs, accountSlabID)
-}
+		exists, err := storage.HasDomain(address, storageDomain)
+		require.NoError(t, err)
+		require.True(t, exists)
 
-func concatRegisterAddressAndKey(
-	address common.Address,
-	key []byte,
-) string {
-	return string(address[:]) + "|" + string(key)
+		require.Equal(t, []string{AccountStorageKey, storageDomain.Identifier()}, registerReads)
+
+		// Account format is cached, so only the domain register is read
+
+		registerReads = nil
+
+		exists, err = storage.HasDomain(address, publicDomain)
+		require.NoError(t, err)
+		require.False(t, exists)
+
+		require.Equal(t, []string{publicDomain.Identifier()}, registerReads)
+
+		registerReads = nil
+
+		require.Equal(t, StorageFormatV1, storage.AccountStorageFormat(address))
+		require.Empty(t, registerReads)
+	})
+
+	t.Run("v2 account", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		ledger := NewTestLedger(nil, nil)
+		storage := NewStorage(ledger, nil, StorageConfig{})
+
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		const count = 10
+		createAndWriteAccountStorageMap(t, storage, inter, address, []common.StorageDomain{storageDomain}, count, random)
+
+		var registerReads []string
+
+		storage = newStorageWithData(ledger.StoredValues, ledger.StorageIndices, &registerReads)
+
+		exists, err := storage.HasDomain(address, storageDomain)
+		require.NoError(t, err)
+		require.True(t, exists)
+
+		require.Equal(t, []string{AccountStorageKey}, registerReads)
+
+		// Account format and account storage map are cached, so no register is read
+
+		registerReads = nil
+
+		exists, err = storage.HasDomain(address, publicDomain)
+		require.NoError(t, err)
+		require.False(t, exists)
+
+		require.Empty(t, registerReads)
+
+		require.Equal(t, StorageFormatV2, storage.AccountStorageFormat(address))
+		require.Empty(t, registerReads)
+	})
+
+	t.Run("v2 account, cached domain storage map", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := NewTestLedger(nil, nil)
+		storage := NewStorage(ledger, nil, StorageConfig{})
+
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		const createIfNotExists = true
+		domainStorageMap := storage.GetDomainStorageMap(inter, address, storageDomain, createIfNotExists)
+		require.NotNil(t, domainStorageMap)
+
+		exists, err := storage.HasDomain(address, storageDomain)
+		require.NoError(t, err)
+		require.True(t, exists)
+
+		exists, err = storage.HasDomain(address, publicDomain)
+		require.NoError(t, err)
+		require.False(t, exists)
+	})
 }
 
-func concatRegisterAddressAndDomain(
-	address common.Address,
-	domain common.StorageDomain,
-) string {
-	return string(address[:]) + "|" + domain.Identifier()
+func TestRuntimeStorageUnreferencedRootSlabsError(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+	otherAddress := common.MustBytesToAddress([]byte{0x2})
+
+	ledger := NewTestLedger(nil, nil)
+	storage := NewStorage(ledger, nil, StorageConfig{})
+
+	inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, true, false)
+
+	const createIfNotExists = true
+	domainStorageMap := storage.GetDomainStorageMap(
+		inter,
+		address,
+		common.PathDomainStorage.StorageDomain(),
+		createIfNotExists,
+	)
+	require.NotNil(t, domainStorageMap)
+
+	// Create a root slab in another account, which is not referenced by any account storage map
+
+	unreferencedMap, err := atree.NewMap(
+		storage,
+		atree.Address(otherAddress),
+		atree.NewDefaultDigesterBuilder(),
+		interpreter.EmptyTypeInfo{},
+	)
+	require.NoError(t, err)
+
+	unreferencedSlabID := unreferencedMap.SlabID()
+
+	err = storage.CheckHealth()
+	require.Error(t, err)
+
+	var unreferencedRootSlabsErr UnreferencedRootSlabsError
+	require.ErrorAs(t, err, &unreferencedRootSlabsErr)
+
+	require.Equal(
+		t,
+		[]atree.SlabID{unreferencedSlabID},
+		unreferencedRootSlabsErr.UnreferencedRootSlabIDs,
+	)
+	require.Equal(
+		t,
+		[]UnreferencedRootSlab{
+			{
+				Address: otherAddress,
+				SlabID:  unreferencedSlabID,
+			},
+		},
+		unreferencedRootSlabsErr.UnreferencedRootSlabs,
+	)
+	require.Contains(t, err.Error(), otherAddress.HexWithPrefix())
+}
+
+func TestRuntimeStorageCheckHealthSampled(t *testing.T) {
+	t.Parallel()
+
+	const accountCount = 10
+
+	addresses := make([]common.Address, 0, accountCount)
+	for i := range accountCount {
+		addresses = append(addresses, common.MustBytesToAddress([]byte{byte(i + 1)}))
+	}
+
+	// newStorage returns a storage with a domain storage map for each account,
+	// and an unreferenced root slab for each of the given leaking accounts
+	newStorage := func(t *testing.T, leakingAddresses []common.Address) *Storage {
+		ledger := NewTestLedger(nil, nil)
+		storage := NewStorage(ledger, nil, StorageConfig{})
+
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, true, false)
+
+		for _, address := range addresses {
+			const createIfNotExists = true
+			domainStorageMap := storage.GetDomainStorageMap(
+				inter,
+				address,
+				common.PathDomainStorage.StorageDomain(),
+				createIfNotExists,
+			)
+			require.NotNil(t, domainStorageMap)
+		}
+
+		for _, address := range leakingAddresses {
+			_, err := atree.NewMap(
+				storage,
+				atree.Address(address),
+				atree.NewDefaultDigesterBuilder(),
+				interpreter.EmptyTypeInfo{},
+			)
+			require.NoError(t, err)
+		}
+
+		return storage
+	}
+
+	unreferencedAddresses := func(t *testing.T, err error) []common.Address {
+		require.Error(t, err)
+
+		var unreferencedRootSlabsErr UnreferencedRootSlabsError
+		require.ErrorAs(t, err, &unreferencedRootSlabsErr)
+
+		var result []common.Address
+		for _, unreferencedRootSlab := range unreferencedRootSlabsErr.UnreferencedRootSlabs {
+			result = append(result, unreferencedRootSlab.Address)
+		}
+		return result
+	}
+
+	t.Run("healthy", func(t *testing.T) {
+		t.Parallel()
+
+		storage := newStorage(t, nil)
+
+		err := storage.CheckHealthSampled(1, 0)
+		require.NoError(t, err)
+	})
+
+	t.Run("same seed, same sample", func(t *testing.T) {
+		t.Parallel()
+
+		// Every account leaks, so the reported accounts are the sampled accounts
+
+		storage := newStorage(t, addresses)
+
+		const seed = 42
+
+		err := storage.CheckHealthSampled(0.3, seed)
+		sampledAddresses := unreferencedAddresses(t, err)
+		require.Len(t, sampledAddresses, 3)
+
+		for range 3 {
+			err = storage.CheckHealthSampled(0.3, seed)
+			require.Equal(t, sampledAddresses, unreferencedAddresses(t, err))
+		}
+
+		// Same seed, separate storage
+
+		err = newStorage(t, addresses).CheckHealthSampled(0.3, seed)
+		require.Equal(t, sampledAddresses, unreferencedAddresses(t, err))
+	})
+
+	t.Run("sample size", func(t *testing.T) {
+		t.Parallel()
+
+		storage := newStorage(t, addresses)
+
+		err := storage.CheckHealthSampled(0, 0)
+		require.NoError(t, err)
+
+		// Sample size is rounded up
+
+		err = storage.CheckHealthSampled(0.01, 0)
+		require.Len(t, unreferencedAddresses(t, err), 1)
+
+		err = storage.CheckHealthSampled(1, 0)
+		require.Equal(t, addresses, unreferencedAddresses(t, err))
+	})
+
+	t.Run("leak in sample", func(t *testing.T) {
+		t.Parallel()
+
+		leakingAddress := addresses[3]
+
+		storage := newStorage(t, []common.Address{leakingAddress})
+
+		err := storage.CheckHealthSampled(1, 0)
+		require.Equal(t,
+			[]common.Address{leakingAddress},
+			unreferencedAddresses(t, err),
+		)
+
+		// The full check reports the same leak
+
+		err = storage.CheckHealth()
+		require.Equal(t,
+			[]common.Address{leakingAddress},
+			unreferencedAddresses(t, err),
+		)
+	})
+
+	t.Run("only loaded slabs", func(t *testing.T) {
+		t.Parallel()
+
+		domain := common.PathDomainStorage.StorageDomain()
+
+		random := rand.New(rand.NewSource(42))
+
+		ledger := NewTestLedger(nil, nil)
+		storage := NewStorage(ledger, nil, StorageConfig{})
+
+		// NOTE: Disable atree validation, as it loads all slabs of a mutated container
+		inter := NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, false, false)
+
+		const count = 1000
+		for _, address := range addresses[:2] {
+			createAndWriteAccountStorageMap(t, storage, inter, address, []common.StorageDomain{domain}, count, random)
+		}
+
+		var reads int
+
+		storage = NewStorage(
+			NewTestLedgerWithData(
+				func(_, _, _ []byte) {
+					reads++
+				},
+				nil,
+				ledger.StoredValues,
+				ledger.StorageIndices,
+			),
+			nil,
+			StorageConfig{},
+		)
+
+		inter = NewTestInterpreterWithStorageAndAtreeValidationConfig(t, storage, false, false)
+
+		// Write to the first account, and leak a slab in the second account
+
+		domainStorageMap := storage.GetDomainStorageMap(inter, addresses[0], domain, false)
+		require.NotNil(t, domainStorageMap)
+		writeToDomainStorageMap(inter, domainStorageMap, 1, random)
+
+		leakingAddress := addresses[1]
+
+		_, err := atree.NewMap(
+			storage,
+			atree.Address(leakingAddress),
+			atree.NewDefaultDigesterBuilder(),
+			interpreter.EmptyTypeInfo{},
+		)
+		require.NoError(t, err)
+
+		// The check does not load the slabs of the accounts
+
+		reads = 0
+
+		err = storage.CheckHealthSampled(1, 0)
+		require.Equal(t,
+			[]common.Address{leakingAddress},
+			unreferencedAddresses(t, err),
+		)
+
+		require.Zero(t, reads)
+	})
+
+	t.Run("invalid fraction", func(t *testing.T) {
+		t.Parallel()
+
+		storage := newStorage(t, nil)
+
+		for _, fraction := range []float64{-0.1, 1.1, math.NaN()} {
+			err := storage.CheckHealthSampled(fraction, 0)
+			require.ErrorAs(t, err, &InvalidHealthCheckSampleFractionError{})
+		}
+	})
+}
+
+func TestRuntimeStorageAddresses(t *testing.T) {
+	t.Parallel()
+
+	address1 := common.MustBytesToAddress([]byte{0x1})
+	address2 := common.MustBytesToAddress([]byte{0x2})
+	address3 := common.MustBytesToAddress([]byte{0x3})
+	address4 := common.MustBytesToAddress([]byte{0x4})
+
+	domain := common.PathDomainStorage.StorageDomain()
+
+	random := rand.New(rand.NewSource(42))
+
+	// Create accounts 0x2 and 0x3
+
+	ledger := NewTestLedger(nil, nil)
+	storage := NewStorage(ledger, nil, StorageConfig{})
+
+	inter := NewTestInterpreterWithStorage(t, storage)
+
+	const count = 10
+	for _, address := range []common.Address{address2, address3} {
+		createAndWriteAccountStorageMap(t, storage, inter, address, []common.StorageDomain{domain}, count, random)
+	}
+
+	storage = NewStorage(
+		NewTestLedgerWithData(nil, nil, ledger.StoredValues, ledger.StorageIndices),
+		nil,
+		StorageConfig{},
+	)
+
+	inter = NewTestInterpreterWithStorage(t, storage)
+
+	require.Empty(t, storage.Addresses())
+	require.Empty(t, storage.ModifiedAddresses())
+
+	// Write to new account 0x4
+
+	const createIfNotExists = true
+	domainStorageMap := storage.GetDomainStorageMap(inter, address4, domain, createIfNotExists)
+	writeToDomainStorageMap(inter, domainStorageMap, count, random)
+
+	// Read from existing account 0x3
+
+	domainStorageMap = storage.GetDomainStorageMap(inter, address3, domain, false)
+	require.NotNil(t, domainStorageMap)
+	require.Equal(t, uint64(count), domainStorageMap.Count())
+
+	// Write to existing account 0x2
+
+	domainStorageMap = storage.GetDomainStorageMap(inter, address2, domain, false)
+	require.NotNil(t, domainStorageMap)
+	writeToDomainStorageMap(inter, domainStorageMap, 1, random)
+
+	// Check format of account 0x1, which does not exist
+
+	require.Equal(t, StorageFormatUnknown, storage.AccountStorageFormat(address1))
+
+	require.Equal(t, []common.Address{address2, address3, address4}, storage.Addresses())
+	require.Equal(t, []common.Address{address2, address4}, storage.ModifiedAddresses())
+
+	// Commit changes
+
+	err := storage.Commit(inter, false)
+	require.NoError(t, err)
+
+	require.Equal(t, []common.Address{address2, address3, address4}, storage.Addresses())
+	require.Empty(t, storage.ModifiedAddresses())
+}
+
+func TestRuntimeStoragePreloadAccountFormats(t *testing.T) {
+	t.Parallel()
+
+	v1Address := common.MustBytesToAddress([]byte{0x1})
+	v2Address1 := common.MustBytesToAddress([]byte{0x2})
+	v2Address2 := common.MustBytesToAddress([]byte{0x3})
+	newAddress := common.MustBytesToAddress([]byte{0x4})
+
+	domain := common.PathDomainStorage.StorageDomain()
+
+	random := rand.New(rand.NewSource(42))
+
+	// Create v2 accounts 0x2 and 0x3
+
+	ledger := NewTestLedger(nil, nil)
+	storage := NewStorage(ledger, nil, StorageConfig{})
+
+	inter := NewTestInterpreterWithStorage(t, storage)
+
+	const count = 10
+	for _, address := range []common.Address{v2Address1, v2Address2} {
+		createAndWriteAccountStorageMap(t, storage, inter, address, []common.StorageDomain{domain}, count, random)
+	}
+
+	// Create v1 account 0x1, which only has a domain register
+
+	persistentSlabStorage := NewPersistentSlabStorage(ledger, nil)
+
+	orderedMap, err := atree.NewMap(
+		persistentSlabStorage,
+		atree.Address(v1Address),
+		atree.NewDefaultDigesterBuilder(),
+		interpreter.EmptyTypeInfo{},
+	)
+	require.NoError(t, err)
+
+	err = persistentSlabStorage.FastCommit(runtime.NumCPU())
+	require.NoError(t, err)
+
+	slabIndex := orderedMap.SlabID().Index()
+	err = ledger.SetValue(v1Address[:], []byte(domain.Identifier()), slabIndex[:])
+	require.NoError(t, err)
+
+	addresses := []common.Address{newAddress, v2Address1, v1Address, v2Address2}
+
+	expectedFormats := map[common.Address]StorageFormat{
+		v1Address:  StorageFormatV1,
+		v2Address1: StorageFormatV2,
+		v2Address2: StorageFormatV2,
+		newAddress: StorageFormatUnknown,
+	}
+
+	type registerRead struct {
+		address common.Address
+		key     string
+	}
+
+	// newStorage creates storage with the data created above,
+	// and records reads of registers which are not slabs.
+	newStorage := func(registerReads *[]registerRead) *Storage {
+		ledger := NewTestLedgerWithData(
+			func(owner, key, _ []byte) {
+				if key[0] == '$' {
+					return
+				}
+				*registerReads = append(
+					*registerReads,
+					registerRead{
+						address: common.MustBytesToAddress(owner),
+						key:     string(key),
+					},
+				)
+			},
+			nil,
+			ledger.StoredValues,
+			ledger.StorageIndices,
+		)
+		return NewStorage(ledger, nil, StorageConfig{})
+	}
+
+	// Determine account formats lazily
+
+	var lazyRegisterReads []registerRead
+	lazyStorage := newStorage(&lazyRegisterReads)
+
+	for _, address := range addresses {
+		require.Equal(t, expectedFormats[address], lazyStorage.AccountStorageFormat(address))
+	}
+
+	// Preload account formats
+
+	var preloadRegisterReads []registerRead
+	preloadStorage := newStorage(&preloadRegisterReads)
+
+	// NOTE: duplicate addresses are only checked once
+	preloadStorage.PreloadAccountFormats(append(addresses, v1Address, newAddress))
+
+	// The same registers are read as when determining the account formats lazily,
+	// but grouped: first the account storage map registers, then the domain registers
+
+	require.ElementsMatch(t, lazyRegisterReads, preloadRegisterReads)
+
+	for i, read := range preloadRegisterReads {
+		if i < len(addresses) {
+			require.Equal(t, AccountStorageKey, read.key)
+		} else {
+			require.NotEqual(t, AccountStorageKey, read.key)
+		}
+	}
+
+	// The caches are identical
+
+	require.Equal(t, lazyStorage.Addresses(), preloadStorage.Addresses())
+	require.Equal(t,
+		[]common.Address{v1Address, v2Address1, v2Address2},
+		preloadStorage.Addresses(),
+	)
+
+	// Cached account formats are not determined again
+
+	preloadRegisterReads = nil
+
+	for _, address := range addresses {
+		require.Equal(t, expectedFormats[address], preloadStorage.AccountStorageFormat(address))
+		require.Equal(t, lazyStorage.AccountStorageFormat(address), preloadStorage.AccountStorageFormat(address))
+	}
+
+	for _, read := range preloadRegisterReads {
+		// Only the format of the non-existing account is not cached
+		require.Equal(t, newAddress, read.address)
+	}
+
+	// Cached v2 account can be accessed as usual
+
+	preloadInter := NewTestInterpreterWithStorage(t, preloadStorage)
+
+	domainStorageMap := preloadStorage.GetDomainStorageMap(preloadInter, v2Address1, domain, false)
+	require.NotNil(t, domainStorageMap)
+	require.Equal(t, uint64(count), domainStorageMap.Count())
+}
+
+func TestRuntimeStorageRemoveAccount(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+	otherAddress := common.MustBytesToAddress([]byte{0x2})
+
+	domains := []common.StorageDomain{
+		common.PathDomainStorage.StorageDomain(),
+		common.PathDomainPublic.StorageDomain(),
+		common.StorageDomainContract,
+	}
+
+	// accountRegisterCount returns the number of non-empty registers of the given account.
+	accountRegisterCount := func(ledger TestLedger, address common.Address) int {
+		var count int
+		err := ledger.ForEach(func(owner, _, value []byte) error {
+			if len(value) > 0 && string(owner) == string(address[:]) {
+				count++
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		return count
+	}
+
+	checkRemoved := func(t *testing.T, ledger TestLedger, storage *Storage, inter *interpreter.Interpreter) {
+		// Account is neither v1 nor v2
+		require.Equal(t, StorageFormatUnknown, storage.AccountStorageFormat(address))
+
+		for _, domain := range domains {
+			hasDomain, err := storage.HasDomain(address, domain)
+			require.NoError(t, err)
+			require.False(t, hasDomain)
+		}
+
+		require.NotContains(t, storage.Addresses(), address)
+
+		err := storage.CheckHealth()
+		require.NoError(t, err)
+
+		const commitContractUpdates = false
+		err = storage.Commit(inter, commitContractUpdates)
+		require.NoError(t, err)
+
+		err = storage.CheckHealth()
+		require.NoError(t, err)
+
+		require.Equal(t, 0, accountRegisterCount(ledger, address))
+		require.Equal(t, StorageFormatUnknown, storage.AccountStorageFormat(address))
+	}
+
+	t.Run("v2 account", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		ledger := NewTestLedger(nil, nil)
+		storage := NewStorage(ledger, nil, StorageConfig{})
+
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		const count = 10
+		createAndWriteAccountStorageMap(t, storage, inter, address, domains, count, random)
+		otherAccountValues := createAndWriteAccountStorageMap(t, storage, inter, otherAddress, domains, count, random)
+
+		require.Equal(t, StorageFormatV2, storage.AccountStorageFormat(address))
+		require.NotZero(t, accountRegisterCount(ledger, address))
+
+		err := storage.RemoveAccount(inter, address)
+		require.NoError(t, err)
+
+		checkRemoved(t, ledger, storage, inter)
+
+		// Other account is unchanged
+
+		checkAccountStorageMapData(t, ledger.StoredValues, ledger.StorageIndices, otherAddress, otherAccountValues)
+	})
+
+	t.Run("new v2 account", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		ledger := NewTestLedger(nil, nil)
+		storage := NewStorage(ledger, nil, StorageConfig{})
+
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		// Write to account without committing
+
+		for _, domain := range domains {
+			const createIfNotExists = true
+			domainStorageMap := storage.GetDomainStorageMap(inter, address, domain, createIfNotExists)
+			writeToDomainStorageMap(inter, domainStorageMap, 10, random)
+		}
+
+		err := storage.RemoveAccount(inter, address)
+		require.NoError(t, err)
+
+		checkRemoved(t, ledger, storage, inter)
+
+		// Account storage register is never written
+
+		_, exists := ledger.StoredValues[concatRegisterAddressAndKey(address, []byte(AccountStorageKey))]
+		require.False(t, exists)
+	})
+
+	t.Run("v1 account", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		ledger := NewTestLedger(nil, nil)
+		storage := NewStorage(ledger, nil, StorageConfig{})
+
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		const count = 10
+		otherAccountValues := createAndWriteAccountStorageMap(
+			t,
+			storage,
+			inter,
+			otherAddress,
+			domains,
+			count,
+			random,
+		)
+
+		// Create v1 account, which has domain registers.
+		// Turn off AtreeStorageValidationEnabled, because domain storage maps of v1 accounts
+		// are not referenced by an account storage map.
+
+		const atreeValueValidationEnabled = true
+		const atreeStorageValidationEnabled = false
+		inter = NewTestInterpreterWithStorageAndAtreeValidationConfig(
+			t,
+			storage,
+			atreeValueValidationEnabled,
+			atreeStorageValidationEnabled,
+		)
+
+		for _, domain := range domains {
+			domainStorageMap := interpreter.NewDomainStorageMap(nil, storage, atree.Address(address))
+
+			domainStorageMapValueID := domainStorageMap.ValueID()
+			err := ledger.SetValue(address[:], []byte(domain.Identifier()), domainStorageMapValueID[8:])
+			require.NoError(t, err)
+
+			writeToDomainStorageMap(inter, domainStorageMap, count, random)
+		}
+
+		const commitContractUpdates = false
+		err := storage.Commit(inter, commitContractUpdates)
+		require.NoError(t, err)
+
+		// Load v1 account with new storage
+
+		ledger = NewTestLedgerWithData(nil, nil, ledger.StoredValues, ledger.StorageIndices)
+		storage = NewStorage(ledger, nil, StorageConfig{})
+
+		inter = NewTestInterpreterWithStorage(t, storage)
+
+		require.Equal(t, StorageFormatV1, storage.AccountStorageFormat(address))
+		require.NotZero(t, accountRegisterCount(ledger, address))
+
+		err = storage.RemoveAccount(inter, address)
+		require.NoError(t, err)
+
+		checkRemoved(t, ledger, storage, inter)
+
+		// Other account is unchanged
+
+		checkAccountStorageMapData(t, ledger.StoredValues, ledger.StorageIndices, otherAddress, otherAccountValues)
+	})
+
+	t.Run("v2 account, not committed", func(t *testing.T) {
+		t.Parallel()
+
+		random := rand.New(rand.NewSource(42))
+
+		ledger := NewTestLedger(nil, nil)
+		storage := NewStorage(ledger, nil, StorageConfig{})
+
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		const count = 10
+		accountValues := createAndWriteAccountStorageMap(t, storage, inter, address, domains, count, random)
+
+		// Remove account with new storage, without committing
+
+		var writeCount int
+
+		ledger = NewTestLedgerWithData(nil, LedgerOnWriteCounter(&writeCount), ledger.StoredValues, ledger.StorageIndices)
+		storage = NewStorage(ledger, nil, StorageConfig{})
+
+		inter = NewTestInterpreterWithStorage(t, storage)
+
+		err := storage.RemoveAccount(inter, address)
+		require.NoError(t, err)
+
+		// Account is removed from the storage
+
+		require.Equal(t, StorageFormatUnknown, storage.AccountStorageFormat(address))
+
+		for _, domain := range domains {
+			domainStorageMap := storage.GetDomainStorageMap(inter, address, domain, false)
+			require.Nil(t, domainStorageMap)
+		}
+
+		// Ledger is unchanged
+
+		require.Equal(t, 0, writeCount)
+
+		checkAccountStorageMapData(t, ledger.StoredValues, ledger.StorageIndices, address, accountValues)
+	})
+
+	t.Run("non-existing account", func(t *testing.T) {
+		t.Parallel()
+
+		var writeCount int
+
+		ledger := NewTestLedger(nil, LedgerOnWriteCounter(&writeCount))
+		storage := NewStorage(ledger, nil, StorageConfig{})
+
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		err := storage.RemoveAccount(inter, address)
+		require.NoError(t, err)
+
+		checkRemoved(t, ledger, storage, inter)
+
+		require.Equal(t, 0, writeCount)
+	})
+}
+
+// createAndWriteAccountStorageMap creates account storage map with given domains and writes random values to domain storage map.
+func createAndWriteAccountStorageMap(
+	t testing.TB,
+	storage *Storage,
+	inter *interpreter.Interpreter,
+	address common.Address,
+	domains []common.StorageDomain,
+	count int,
+	random *rand.Rand,
+) accountStorageMapValues {
+
+	accountValues := make(accountStorageMapValues)
+
+	// Create domain storage map
+	for _, domain := range domains {
+		const createIfNotExists = true
+		domainStorageMap := storage.GetDomainStorageMap(inter, address, domain, createIfNotExists)
+		require.NotNil(t, domainStorageMap)
+		require.Equal(t, uint64(0), domainStorageMap.Count())
+
+		// Write to to domain storage map
+		accountValues[domain] = writeToDomainStorageMap(inter, domainStorageMap, count, random)
+	}
+
+	// Commit changes
+	const commitContractUpdates = false
+	err := storage.Commit(inter, commitContractUpdates)
+	require.NoError(t, err)
+
+	// Check storage health after commit
+	err = storage.CheckHealth()
+	require.NoError(t, err)
+
+	return accountValues
+}
+
+func writeToDomainStorageMap(
+	inter *interpreter.Interpreter,
+	domainStorageMap *interpreter.DomainStorageMap,
+	count int,
+	random *rand.Rand,
+) domainStorageMapValues {
+	domainValues := make(domainStorageMapValues)
+
+	for len(domainValues) < count {
+		n := random.Int()
+
+		key := interpreter.StringStorageMapKey(strconv.Itoa(n))
+
+		value := interpreter.NewUnmeteredIntValueFromInt64(int64(n))
+
+		domainStorageMap.WriteValue(inter, key, value)
+
+		domainValues[key] = value
+	}
+
+	return domainValues
+}
+
+// checkAccountStorageMapData creates new storage with given storedValues,
+// and compares account storage map values with given expectedAccountValues.
+func checkAccountStorageMapData(
+	tb testing.TB,
+	storedValues map[string][]byte,
+	storageIndices map[string]uint64,
+	address common.Address,
+	expectedAccountValues accountStorageMapValues,
+) {
+	// Create storage with given storedValues and storageIndices
+	ledger := NewTestLedgerWithData(nil, nil, storedValues, storageIndices)
+	storage := NewStorage(
+		ledger,
+		nil,
+		StorageConfig{},
+	)
+
+	inter := NewTestInterpreterWithStorage(tb, storage)
+
+	// Get account register
+	accountStorageMapSlabIndex, err := ledger.GetValue(address[:], []byte(AccountStorageKey))
+	require.NoError(tb, err)
+	require.Equal(tb, 8, len(accountStorageMapSlabIndex))
+
+	// Load account storage map
+	accountSlabID := atree.NewSlabID(
+		atree.Address(address[:]),
+		atree.SlabIndex(accountStorageMapSlabIndex[:]),
+	)
+	accountStorageMap := interpreter.NewAccountStorageMapWithRootID(storage, accountSlabID)
+	require.NotNil(tb, accountStorageMap)
+	require.Equal(tb, uint64(len(expectedAccountValues)), accountStorageMap.Count())
+
+	domainCount := 0
+	iter := accountStorageMap.Iterator()
+	for {
+		domain, domainStorageMap := iter.Next()
+		if domain == common.StorageDomainUnknown {
+			break
+		}
+
+		domainCount++
+
+		expectedDomainValues, exist := expectedAccountValues[domain]
+		require.True(tb, exist)
+		require.Equal(tb, uint64(len(expectedDomainValues)), domainStorageMap.Count())
+
+		// Check values stored in domain storage map
+		for key, expectedValue := range expectedDomainValues {
+			value := domainStorageMap.ReadValue(nil, key)
+
+			ev, ok := value.(interpreter.EquatableValue)
+			require.True(tb, ok)
+			require.True(tb, ev.Equal(inter, interpreter.EmptyLocationRange, expectedValue))
+		}
+	}
+
+	require.Equal(tb, len(expectedAccountValues), domainCount)
+
+	// Check atree storage health
+	rootSlabIDs, err := atree.CheckStorageHealth(storage.PersistentSlabStorage, 1)
+	require.NoError(tb, err)
+	require.Equal(tb, 1, len(rootSlabIDs))
+	require.Contains(tb, rootSlabIDs, accountSlabID)
+}
+
+func concatRegisterAddressAndKey(
+	address common.Address,
+	key []byte,
+) string {
+	return string(address[:]) + "|" + string(key)
+}
+
+func concatRegisterAddressAndDomain(
+	address common.Address,
+	domain common.StorageDomain,
+) string {
+	return string(address[:]) + "|" + domain.Identifier()
+}
+
+func TestRuntimeStorageIterateAccounts(t *testing.T) {
+	t.Parallel()
+
+	v2Address1 := common.MustBytesToAddress([]byte{0x1})
+	v1Address := common.MustBytesToAddress([]byte{0x2})
+	v2Address2 := common.MustBytesToAddress([]byte{0x1, 0x0})
+	removedAddress := common.MustBytesToAddress([]byte{0x3})
+	unrelatedAddress := common.MustBytesToAddress([]byte{0x4})
+
+	domains := []common.StorageDomain{
+		common.PathDomainStorage.StorageDomain(),
+		common.PathDomainPublic.StorageDomain(),
+	}
+
+	newLedger := func(t *testing.T) TestLedger {
+		random := rand.New(rand.NewSource(42))
+
+		ledger := NewTestLedger(nil, nil)
+		storage := NewStorage(ledger, nil, StorageConfig{})
+
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		// Create v2 accounts, in reverse address order
+
+		const count = 10
+		createAndWriteAccountStorageMap(t, storage, inter, v2Address2, domains, count, random)
+		createAndWriteAccountStorageMap(t, storage, inter, v2Address1, domains, count, random)
+
+		// Create v1 account, which only has domain registers.
+		// Register values are only inspected for presence.
+
+		for _, domain := range domains {
+			err := ledger.SetValue(v1Address[:], []byte(domain.Identifier()), []byte{0, 0, 0, 0, 0, 0, 0, 1})
+			require.NoError(t, err)
+		}
+
+		// Create removed account register
+
+		err := ledger.SetValue(removedAddress[:], []byte(AccountStorageKey), nil)
+		require.NoError(t, err)
+
+		// Create unrelated register
+
+		err = ledger.SetValue(unrelatedAddress[:], []byte("unrelated"), []byte{0x1})
+		require.NoError(t, err)
+
+		return ledger
+	}
+
+	t.Run("all accounts", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := newLedger(t)
+		storage := NewStorage(ledger, nil, StorageConfig{})
+
+		var addresses []common.Address
+		err := storage.IterateAccounts(func(address common.Address) (bool, error) {
+			addresses = append(addresses, address)
+			return true, nil
+		})
+		require.NoError(t, err)
+
+		require.Equal(t,
+			[]common.Address{
+				v2Address1,
+				v1Address,
+				v2Address2,
+			},
+			addresses,
+		)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := newLedger(t)
+		storage := NewStorage(ledger, nil, StorageConfig{})
+
+		var addresses []common.Address
+		err := storage.IterateAccounts(func(address common.Address) (bool, error) {
+			addresses = append(addresses, address)
+			return len(addresses) < 2, nil
+		})
+		require.NoError(t, err)
+
+		require.Equal(t,
+			[]common.Address{
+				v2Address1,
+				v1Address,
+			},
+			addresses,
+		)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := newLedger(t)
+		storage := NewStorage(ledger, nil, StorageConfig{})
+
+		expectedErr := fmt.Errorf("test")
+
+		var count int
+		err := storage.IterateAccounts(func(_ common.Address) (bool, error) {
+			count++
+			return true, expectedErr
+		})
+		require.ErrorIs(t, err, expectedErr)
+		require.Equal(t, 1, count)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := NewTestLedger(nil, nil)
+		storage := NewStorage(ledger, nil, StorageConfig{})
+
+		err := storage.IterateAccounts(func(_ common.Address) (bool, error) {
+			require.FailNow(t, "unexpected account")
+			return false, nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("ledger not iterable", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := struct {
+			atree.Ledger
+		}{
+			Ledger: NewTestLedger(nil, nil),
+		}
+		storage := NewStorage(ledger, nil, StorageConfig{})
+
+		err := storage.IterateAccounts(func(_ common.Address) (bool, error) {
+			return true, nil
+		})
+		require.ErrorAs(t, err, &LedgerNotIterableError{})
+	})
+}
+
+func TestRuntimeStorageSetMemoryGauge(t *testing.T) {
+	t.Parallel()
+
+	address1 := common.MustBytesToAddress([]byte{0x1})
+	address2 := common.MustBytesToAddress([]byte{0x2})
+
+	domain := common.PathDomainStorage.StorageDomain()
+
+	random := rand.New(rand.NewSource(42))
+
+	ledger := NewTestLedger(nil, nil)
+
+	// Create accounts
+
+	storage := NewStorage(ledger, nil, StorageConfig{})
+	inter := NewTestInterpreterWithStorage(t, storage)
+
+	const count = 10
+	accountValues1 := createAndWriteAccountStorageMap(
+		t,
+		storage,
+		inter,
+		address1,
+		[]common.StorageDomain{domain},
+		count,
+		random,
+	)
+	accountValues2 := createAndWriteAccountStorageMap(
+		t,
+		storage,
+		inter,
+		address2,
+		[]common.StorageDomain{domain},
+		count,
+		random,
+	)
+
+	totalMemory := func(gauge *testMemoryGauge) uint64 {
+		var total uint64
+		for _, amount := range gauge.meter { //nolint:maprange
+			total += amount
+		}
+		return total
+	}
+
+	checkDomainStorageMap := func(
+		domainStorageMap *interpreter.DomainStorageMap,
+		expectedValues domainStorageMapValues,
+	) {
+		require.NotNil(t, domainStorageMap)
+		require.Equal(t, uint64(len(expectedValues)), domainStorageMap.Count())
+
+		for key, expectedValue := range expectedValues { //nolint:maprange
+			value := domainStorageMap.ReadValue(nil, key)
+			ev, ok := value.(interpreter.EquatableValue)
+			require.True(t, ok)
+			require.True(t, ev.Equal(inter, interpreter.EmptyLocationRange, expectedValue))
+		}
+	}
+
+	// Load accounts from the ledger, with separate memory gauges
+
+	gauge1 := newTestMemoryGauge()
+	gauge2 := newTestMemoryGauge()
+
+	storage = NewStorage(
+		NewTestLedgerWithData(nil, nil, ledger.StoredValues, ledger.StorageIndices),
+		gauge1,
+		StorageConfig{},
+	)
+	inter = NewTestInterpreterWithStorage(t, storage)
+
+	const createIfNotExists = false
+
+	domainStorageMap1 := storage.GetDomainStorageMap(inter, address1, domain, createIfNotExists)
+	checkDomainStorageMap(domainStorageMap1, accountValues1[domain])
+
+	gauge1Memory := totalMemory(gauge1)
+	require.NotZero(t, gauge1Memory)
+	require.Zero(t, totalMemory(gauge2))
+
+	// Swap memory gauge, subsequent operations are metered against the new gauge
+
+	storage.SetMemoryGauge(gauge2)
+
+	domainStorageMap2 := storage.GetDomainStorageMap(inter, address2, domain, createIfNotExists)
+	checkDomainStorageMap(domainStorageMap2, accountValues2[domain])
+
+	require.Equal(t, gauge1Memory, totalMemory(gauge1))
+	require.NotZero(t, totalMemory(gauge2))
+
+	// Cached domain storage maps remain valid
+
+	require.Same(t,
+		domainStorageMap1,
+		storage.GetDomainStorageMap(inter, address1, domain, createIfNotExists),
+	)
+	checkDomainStorageMap(domainStorageMap1, accountValues1[domain])
+
+	// Remove memory gauge
+
+	storage.SetMemoryGauge(nil)
+
+	gauge2Memory := totalMemory(gauge2)
+
+	err := storage.Commit(inter, false)
+	require.NoError(t, err)
+
+	err = storage.CheckHealth()
+	require.NoError(t, err)
+
+	require.Equal(t, gauge1Memory, totalMemory(gauge1))
+	require.Equal(t, gauge2Memory, totalMemory(gauge2))
+}
+
+func TestRuntimeStorageMalformedDomainRegister(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	malformedDomain := common.PathDomainPublic.StorageDomain()
+
+	// Register value of wrong length (slab index has 8 bytes)
+	malformedData := []byte{0x1, 0x2, 0x3}
+
+	newStorage := func(t *testing.T) (*Storage, *interpreter.Interpreter) {
+		ledger := NewTestLedger(nil, nil)
+
+		err := ledger.SetValue(address[:], []byte(malformedDomain.Identifier()), malformedData)
+		require.NoError(t, err)
+
+		storage := NewStorage(ledger, nil, StorageConfig{})
+
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		return storage, inter
+	}
+
+	expectedErr := MalformedDomainRegisterError{
+		Address: address,
+		Domain:  malformedDomain,
+		Data:    malformedData,
+	}
+
+	t.Run("get malformed domain", func(t *testing.T) {
+		t.Parallel()
+
+		storage, inter := newStorage(t)
+
+		require.PanicsWithError(t,
+			expectedErr.Error(),
+			func() {
+				const createIfNotExists = false
+				storage.GetDomainStorageMap(inter, address, malformedDomain, createIfNotExists)
+			},
+		)
+	})
+
+	t.Run("create other domain", func(t *testing.T) {
+		t.Parallel()
+
+		storage, inter := newStorage(t)
+
+		// Determining the account format reads the malformed domain register
+
+		require.PanicsWithError(t,
+			expectedErr.Error(),
+			func() {
+				const createIfNotExists = true
+				storage.GetDomainStorageMap(inter, address, common.PathDomainStorage.StorageDomain(), createIfNotExists)
+			},
+		)
+	})
+
+	t.Run("has domain", func(t *testing.T) {
+		t.Parallel()
+
+		storage, _ := newStorage(t)
+
+		_, err := storage.HasDomain(address, malformedDomain)
+		require.ErrorAs(t, err, &MalformedDomainRegisterError{})
+
+		require.Equal(t,
+			"internal error: malformed public domain register of account 0x0000000000000001: "+
+				"expected slab index of length 8, got 3 bytes: 010203",
+			err.Error(),
+		)
+	})
+}
+
+func TestRuntimeStorageCommitTo(t *testing.T) {
+	t.Parallel()
+
+	address1 := common.MustBytesToAddress([]byte{0x1})
+	address2 := common.MustBytesToAddress([]byte{0x2})
+
+	domains := []common.StorageDomain{
+		common.PathDomainStorage.StorageDomain(),
+		common.PathDomainPublic.StorageDomain(),
+	}
+
+	random := rand.New(rand.NewSource(42))
+
+	var sourceWrites int
+	sourceLedger := NewTestLedger(
+		nil,
+		func(_, _, _ []byte) {
+			sourceWrites++
+		},
+	)
+
+	storage := NewStorage(sourceLedger, nil, StorageConfig{})
+	inter := NewTestInterpreterWithStorage(t, storage)
+
+	// Write values without committing them
+
+	const count = 10
+
+	accountValues := map[common.Address]accountStorageMapValues{}
+
+	for _, address := range []common.Address{address1, address2} {
+		values := make(accountStorageMapValues)
+		for _, domain := range domains {
+			const createIfNotExists = true
+			domainStorageMap := storage.GetDomainStorageMap(inter, address, domain, createIfNotExists)
+			require.NotNil(t, domainStorageMap)
+
+			values[domain] = writeToDomainStorageMap(inter, domainStorageMap, count, random)
+		}
+		accountValues[address] = values
+	}
+
+	require.Zero(t, sourceWrites)
+	require.Empty(t, sourceLedger.StoredValues)
+
+	// Commit to a fresh ledger
+
+	targetLedger := NewTestLedger(nil, nil)
+
+	const commitContractUpdates = false
+	err := storage.CommitTo(inter, targetLedger, commitContractUpdates)
+	require.NoError(t, err)
+
+	// Source ledger is untouched
+
+	require.Zero(t, sourceWrites)
+	require.Empty(t, sourceLedger.StoredValues)
+
+	require.NotEmpty(t, targetLedger.StoredValues)
+
+	// Load accounts independently from the target ledger.
+	// Slab indices are allocated by the source ledger.
+
+	for address, values := range accountValues { //nolint:maprange
+		checkAccountStorageMapData(
+			t,
+			targetLedger.StoredValues,
+			sourceLedger.StorageIndices,
+			address,
+			values,
+		)
+	}
+}
+
+func TestRuntimeStorageRegisterCounters(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	domain := common.PathDomainStorage.StorageDomain()
+
+	random := rand.New(rand.NewSource(42))
+
+	// Create account
+
+	ledger := NewTestLedger(nil, nil)
+	storage := NewStorage(ledger, nil, StorageConfig{})
+	inter := NewTestInterpreterWithStorage(t, storage)
+
+	const count = 10
+	createAndWriteAccountStorageMap(
+		t,
+		storage,
+		inter,
+		address,
+		[]common.StorageDomain{domain},
+		count,
+		random,
+	)
+
+	// Load account with a new storage, and count the operations of the underlying ledger
+
+	var ledgerReads, ledgerWrites uint64
+
+	ledger = NewTestLedgerWithData(
+		func(_, _, _ []byte) {
+			ledgerReads++
+		},
+		func(_, _, _ []byte) {
+			ledgerWrites++
+		},
+		ledger.StoredValues,
+		ledger.StorageIndices,
+	)
+	onValueExists := ledger.OnValueExists
+	ledger.OnValueExists = func(owner, key []byte) (bool, error) {
+		ledgerReads++
+		return onValueExists(owner, key)
+	}
+
+	storage = NewStorage(ledger, nil, StorageConfig{})
+	inter = NewTestInterpreterWithStorage(t, storage)
+
+	require.Zero(t, storage.ReadCount())
+	require.Zero(t, storage.WriteCount())
+
+	const createIfNotExists = false
+	domainStorageMap := storage.GetDomainStorageMap(inter, address, domain, createIfNotExists)
+	require.NotNil(t, domainStorageMap)
+	require.Equal(t, uint64(count), domainStorageMap.Count())
+
+	readCount := storage.ReadCount()
+	require.NotZero(t, readCount)
+	require.Equal(t, ledgerReads, readCount)
+	require.Zero(t, storage.WriteCount())
+
+	// Cache hits don't increment the read count
+
+	require.Same(t,
+		domainStorageMap,
+		storage.GetDomainStorageMap(inter, address, domain, createIfNotExists),
+	)
+
+	require.Equal(t, readCount, storage.ReadCount())
+
+	// Writes are counted on commit
+
+	domainStorageMap.WriteValue(
+		inter,
+		interpreter.StringStorageMapKey("new"),
+		interpreter.NewUnmeteredIntValueFromInt64(1),
+	)
+
+	require.Zero(t, storage.WriteCount())
+
+	err := storage.Commit(inter, false)
+	require.NoError(t, err)
+
+	require.NotZero(t, storage.WriteCount())
+	require.Equal(t, ledgerWrites, storage.WriteCount())
+	require.Equal(t, ledgerReads, storage.ReadCount())
+
+	// Reset counters
+
+	storage.ResetCounters()
+
+	require.Zero(t, storage.ReadCount())
+	require.Zero(t, storage.WriteCount())
+}
+
+func TestRuntimeStorageDisableFormatCache(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	domains := []common.StorageDomain{
+		common.PathDomainStorage.StorageDomain(),
+		common.PathDomainPublic.StorageDomain(),
+	}
+
+	random := rand.New(rand.NewSource(42))
+
+	// Create account
+
+	ledger := NewTestLedger(nil, nil)
+	storage := NewStorage(ledger, nil, StorageConfig{})
+	inter := NewTestInterpreterWithStorage(t, storage)
+
+	const count = 10
+	accountValues := createAndWriteAccountStorageMap(
+		t,
+		storage,
+		inter,
+		address,
+		domains,
+		count,
+		random,
+	)
+
+	test := func(disableFormatCache bool, expectedFormatRegisterReads int) {
+
+		var formatRegisterReads int
+
+		ledger := NewTestLedgerWithData(
+			func(_, key, _ []byte) {
+				if string(key) == AccountStorageKey {
+					formatRegisterReads++
+				}
+			},
+			nil,
+			ledger.StoredValues,
+			ledger.StorageIndices,
+		)
+
+		storage := NewStorage(
+			ledger,
+			nil,
+			StorageConfig{
+				DisableFormatCache: disableFormatCache,
+			},
+		)
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		for _, domain := range domains {
+			const createIfNotExists = false
+			domainStorageMap := storage.GetDomainStorageMap(inter, address, domain, createIfNotExists)
+			require.NotNil(t, domainStorageMap)
+			require.Equal(t, uint64(len(accountValues[domain])), domainStorageMap.Count())
+		}
+
+		require.Equal(t, expectedFormatRegisterReads, formatRegisterReads)
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Parallel()
+
+		// The "stored" register is read once to determine the account storage format,
+		// and once to load the account storage map.
+		// The account storage format is cached for the second domain.
+		test(false, 2)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		// The "stored" register is additionally read
+		// to determine the account storage format for the second domain.
+		test(true, 3)
+	})
+}
+
+func TestRuntimeStorageMaxInlineStringSize(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	const maxInlineStringSize = 100
+
+	// slabCount stores a string value under a string key with the given sizes,
+	// and returns the number of slabs allocated for the account.
+	slabCount := func(t *testing.T, config StorageConfig, keySize int, valueSize int) uint64 {
+		ledger := NewTestLedger(nil, nil)
+		storage := NewStorage(ledger, nil, config)
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		const createIfNotExists = true
+		domainStorageMap := storage.GetDomainStorageMap(
+			inter,
+			address,
+			common.PathDomainStorage.StorageDomain(),
+			createIfNotExists,
+		)
+		require.NotNil(t, domainStorageMap)
+
+		domainStorageMap.WriteValue(
+			inter,
+			interpreter.StringStorageMapKey(strings.Repeat("k", keySize)),
+			interpreter.NewUnmeteredStringValue(strings.Repeat("v", valueSize)),
+		)
+
+		const commitContractUpdates = false
+		err := storage.Commit(inter, commitContractUpdates)
+		require.NoError(t, err)
+
+		return ledger.StorageIndices[string(address[:])]
+	}
+
+	const small = maxInlineStringSize - 10
+	const large = maxInlineStringSize + 10
+
+	t.Run("value", func(t *testing.T) {
+		t.Parallel()
+
+		base := slabCount(t, StorageConfig{}, 1, small)
+
+		// By default, both strings are inlined
+		require.Equal(t, base, slabCount(t, StorageConfig{}, 1, large))
+
+		config := StorageConfig{
+			MaxInlineStringSize: maxInlineStringSize,
+		}
+
+		// The string below the limit is inlined, the string above the limit is not
+		require.Equal(t, base, slabCount(t, config, 1, small))
+		require.Equal(t, base+1, slabCount(t, config, 1, large))
+	})
+
+	t.Run("key", func(t *testing.T) {
+		t.Parallel()
+
+		base := slabCount(t, StorageConfig{}, small, 1)
+
+		// By default, both strings are inlined
+		require.Equal(t, base, slabCount(t, StorageConfig{}, large, 1))
+
+		config := StorageConfig{
+			MaxInlineStringSize: maxInlineStringSize,
+		}
+
+		// The string below the limit is inlined, the string above the limit is not
+		require.Equal(t, base, slabCount(t, config, small, 1))
+		require.Equal(t, base+1, slabCount(t, config, large, 1))
+	})
+
+	t.Run("limit above atree threshold", func(t *testing.T) {
+		t.Parallel()
+
+		config := StorageConfig{
+			MaxInlineStringSize: math.MaxUint64,
+		}
+
+		require.Equal(t,
+			slabCount(t, StorageConfig{}, 1, 10_000),
+			slabCount(t, config, 1, 10_000),
+		)
+	})
+}
+
+func TestRuntimeStorageCacheStats(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	domains := []common.StorageDomain{
+		common.PathDomainStorage.StorageDomain(),
+		common.PathDomainPublic.StorageDomain(),
+	}
+
+	random := rand.New(rand.NewSource(42))
+
+	// Create account
+
+	ledger := NewTestLedger(nil, nil)
+	storage := NewStorage(ledger, nil, StorageConfig{})
+	inter := NewTestInterpreterWithStorage(t, storage)
+
+	const count = 10
+	createAndWriteAccountStorageMap(
+		t,
+		storage,
+		inter,
+		address,
+		domains,
+		count,
+		random,
+	)
+
+	// Load account from a new storage
+
+	storage = NewStorage(
+		NewTestLedgerWithData(nil, nil, ledger.StoredValues, ledger.StorageIndices),
+		nil,
+		StorageConfig{},
+	)
+	inter = NewTestInterpreterWithStorage(t, storage)
+
+	requireCacheStats := func(expectedHits, expectedMisses uint64, expectedSize int) {
+		hits, misses, size := storage.CacheStats()
+		require.Equal(t, expectedHits, hits)
+		require.Equal(t, expectedMisses, misses)
+		require.Equal(t, expectedSize, size)
+	}
+
+	requireCacheStats(0, 0, 0)
+
+	const accesses = 10
+
+	for _, domain := range domains {
+		for i := 0; i < accesses; i++ {
+			const createIfNotExists = false
+			domainStorageMap := storage.GetDomainStorageMap(inter, address, domain, createIfNotExists)
+			require.NotNil(t, domainStorageMap)
+		}
+	}
+
+	// Only the first access of each domain misses the cache
+
+	requireCacheStats(
+		uint64(len(domains)*(accesses-1)),
+		uint64(len(domains)),
+		len(domains),
+	)
+
+	// Accessing a non-existent domain misses the cache every time,
+	// as non-existent domain storage maps are not cached
+
+	for i := 0; i < accesses; i++ {
+		const createIfNotExists = false
+		domainStorageMap := storage.GetDomainStorageMap(
+			inter,
+			address,
+			common.PathDomainPrivate.StorageDomain(),
+			createIfNotExists,
+		)
+		require.Nil(t, domainStorageMap)
+	}
+
+	requireCacheStats(
+		uint64(len(domains)*(accesses-1)),
+		uint64(len(domains)+accesses),
+		len(domains),
+	)
+}
+
+func TestRuntimeStorageInvalidateCache(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+	domain := common.PathDomainStorage.StorageDomain()
+	key := interpreter.StringStorageMapKey("a")
+
+	// writeValue writes the given value to the ledger,
+	// using a separate storage, i.e. externally to any other storage
+	writeValue := func(t *testing.T, ledger TestLedger, value int64) {
+		storage := NewStorage(ledger, nil, StorageConfig{})
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		const createIfNotExists = true
+		domainStorageMap := storage.GetDomainStorageMap(inter, address, domain, createIfNotExists)
+		domainStorageMap.WriteValue(inter, key, interpreter.NewUnmeteredIntValueFromInt64(value))
+
+		const commitContractUpdates = false
+		err := storage.Commit(inter, commitContractUpdates)
+		require.NoError(t, err)
+	}
+
+	readValue := func(t *testing.T, storage *Storage, inter *interpreter.Interpreter) interpreter.Value {
+		const createIfNotExists = false
+		domainStorageMap := storage.GetDomainStorageMap(inter, address, domain, createIfNotExists)
+		require.NotNil(t, domainStorageMap)
+		return domainStorageMap.ReadValue(nil, key)
+	}
+
+	test := func(t *testing.T, invalidate func(storage *Storage) error) {
+		t.Parallel()
+
+		ledger := NewTestLedger(nil, nil)
+
+		writeValue(t, ledger, 1)
+
+		storage := NewStorage(ledger, nil, StorageConfig{})
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		require.Equal(t,
+			interpreter.NewUnmeteredIntValueFromInt64(1),
+			readValue(t, storage, inter),
+		)
+
+		// Mutate the ledger externally. The cached value is stale
+
+		writeValue(t, ledger, 2)
+
+		require.Equal(t,
+			interpreter.NewUnmeteredIntValueFromInt64(1),
+			readValue(t, storage, inter),
+		)
+
+		// Invalidate the cache. The value is reloaded from the ledger
+
+		err := invalidate(storage)
+		require.NoError(t, err)
+
+		_, _, size := storage.CacheStats()
+		require.Equal(t, 0, size)
+
+		require.Equal(t,
+			interpreter.NewUnmeteredIntValueFromInt64(2),
+			readValue(t, storage, inter),
+		)
+	}
+
+	t.Run("domain", func(t *testing.T) {
+		test(t, func(storage *Storage) error {
+			return storage.InvalidateDomainCache(address, domain)
+		})
+	})
+
+	t.Run("account", func(t *testing.T) {
+		test(t, func(storage *Storage) error {
+			return storage.InvalidateAccountCache(address)
+		})
+	})
+
+	t.Run("nothing cached", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := NewTestLedger(nil, nil)
+
+		writeValue(t, ledger, 1)
+
+		storage := NewStorage(ledger, nil, StorageConfig{})
+
+		readCount := storage.ReadCount()
+
+		err := storage.InvalidateDomainCache(address, domain)
+		require.NoError(t, err)
+
+		err = storage.InvalidateAccountCache(address)
+		require.NoError(t, err)
+
+		err = storage.InvalidateAccountCache(common.MustBytesToAddress([]byte{0x2}))
+		require.NoError(t, err)
+
+		hits, misses, size := storage.CacheStats()
+		require.Equal(t, uint64(0), hits)
+		require.Equal(t, uint64(0), misses)
+		require.Equal(t, 0, size)
+
+		require.Equal(t, readCount, storage.ReadCount())
+	})
+
+	t.Run("unsaved changes", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := NewTestLedger(nil, nil)
+
+		writeValue(t, ledger, 1)
+
+		storage := NewStorage(ledger, nil, StorageConfig{})
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		const createIfNotExists = false
+		domainStorageMap := storage.GetDomainStorageMap(inter, address, domain, createIfNotExists)
+		require.NotNil(t, domainStorageMap)
+
+		// Modify a different account, so only the other account has unsaved changes
+
+		otherAddress := common.MustBytesToAddress([]byte{0x2})
+		otherDomainStorageMap := storage.GetDomainStorageMap(inter, otherAddress, domain, true)
+		otherDomainStorageMap.WriteValue(inter, key, interpreter.NewUnmeteredIntValueFromInt64(2))
+
+		// Invalidation is rejected, as the loaded values would reference dropped slabs
+
+		err := storage.InvalidateDomainCache(address, domain)
+		require.ErrorAs(t, err, &UnsavedChangesCacheInvalidationError{})
+
+		err = storage.InvalidateAccountCache(address)
+		require.ErrorAs(t, err, &UnsavedChangesCacheInvalidationError{})
+
+		// Nothing was invalidated
+
+		_, _, size := storage.CacheStats()
+		require.Equal(t, 2, size)
+
+		// After the commit, invalidation is possible again
+
+		const commitContractUpdates = false
+		err = storage.Commit(inter, commitContractUpdates)
+		require.NoError(t, err)
+
+		err = storage.InvalidateAccountCache(address)
+		require.NoError(t, err)
+
+		_, _, size = storage.CacheStats()
+		require.Equal(t, 0, size)
+	})
+}
+
+func TestRuntimeStorageReadWriteRegister(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+	domain := common.PathDomainStorage.StorageDomain()
+	key := interpreter.StringStorageMapKey("a")
+
+	// The account storage map, including the inlined domain storage map,
+	// is stored in the first slab of the account
+	registerKeys := [][]byte{
+		[]byte(AccountStorageKey),
+		atree.SlabIndexToLedgerKey(atree.SlabIndex{0, 0, 0, 0, 0, 0, 0, 1}),
+	}
+
+	// newLedger returns a ledger with the given value written to the account
+	newLedger := func(t *testing.T, value int64) TestLedger {
+		ledger := NewTestLedger(nil, nil)
+
+		storage := NewStorage(ledger, nil, StorageConfig{})
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		const createIfNotExists = true
+		domainStorageMap := storage.GetDomainStorageMap(inter, address, domain, createIfNotExists)
+		domainStorageMap.WriteValue(inter, key, interpreter.NewUnmeteredIntValueFromInt64(value))
+
+		const commitContractUpdates = false
+		err := storage.Commit(inter, commitContractUpdates)
+		require.NoError(t, err)
+
+		return ledger
+	}
+
+	readValue := func(t *testing.T, storage *Storage, inter *interpreter.Interpreter) interpreter.Value {
+		const createIfNotExists = false
+		domainStorageMap := storage.GetDomainStorageMap(inter, address, domain, createIfNotExists)
+		require.NotNil(t, domainStorageMap)
+		return domainStorageMap.ReadValue(nil, key)
+	}
+
+	t.Run("write", func(t *testing.T) {
+		t.Parallel()
+
+		storage := NewStorage(newLedger(t, 1), nil, StorageConfig{})
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		require.Equal(t,
+			interpreter.NewUnmeteredIntValueFromInt64(1),
+			readValue(t, storage, inter),
+		)
+
+		// Copy the registers of another ledger, which has a different value
+
+		otherStorage := NewStorage(newLedger(t, 2), nil, StorageConfig{})
+
+		for _, registerKey := range registerKeys {
+			value, err := otherStorage.ReadRegister(address, registerKey)
+			require.NoError(t, err)
+			require.NotEmpty(t, value)
+
+			err = storage.WriteRegister(address, registerKey, value)
+			require.NoError(t, err)
+
+			writtenValue, err := storage.ReadRegister(address, registerKey)
+			require.NoError(t, err)
+			require.Equal(t, value, writtenValue)
+		}
+
+		// The interpreter-level view reflects the written registers
+
+		require.Equal(t,
+			interpreter.NewUnmeteredIntValueFromInt64(2),
+			readValue(t, storage, inter),
+		)
+	})
+
+	t.Run("unsaved changes", func(t *testing.T) {
+		t.Parallel()
+
+		storage := NewStorage(newLedger(t, 1), nil, StorageConfig{})
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		const createIfNotExists = false
+		domainStorageMap := storage.GetDomainStorageMap(inter, address, domain, createIfNotExists)
+		domainStorageMap.WriteValue(inter, key, interpreter.NewUnmeteredIntValueFromInt64(3))
+
+		err := storage.WriteRegister(address, []byte(AccountStorageKey), nil)
+		require.ErrorAs(t, err, &UnsavedChangesRegisterWriteError{})
+
+		// The register is unchanged
+
+		value, err := storage.ReadRegister(address, []byte(AccountStorageKey))
+		require.NoError(t, err)
+		require.NotEmpty(t, value)
+	})
+
+	t.Run("unsaved changes of other account", func(t *testing.T) {
+		t.Parallel()
+
+		storage := NewStorage(newLedger(t, 1), nil, StorageConfig{})
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		otherAddress := common.MustBytesToAddress([]byte{0x2})
+
+		const createIfNotExists = true
+		domainStorageMap := storage.GetDomainStorageMap(inter, otherAddress, domain, createIfNotExists)
+		domainStorageMap.WriteValue(inter, key, interpreter.NewUnmeteredIntValueFromInt64(3))
+
+		err := storage.WriteRegister(address, []byte(AccountStorageKey), nil)
+		require.ErrorAs(t, err, &UnsavedChangesCacheInvalidationError{})
+
+		// The register is unchanged
+
+		value, err := storage.ReadRegister(address, []byte(AccountStorageKey))
+		require.NoError(t, err)
+		require.NotEmpty(t, value)
+	})
+}
+
+func TestRuntimeStorageGetContractNames(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	contractNames := []string{"C", "A", "B"}
+
+	t.Run("v2 account", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := NewTestLedger(nil, nil)
+
+		storage := NewStorage(ledger, nil, StorageConfig{})
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		const createIfNotExists = true
+		domainStorageMap := storage.GetDomainStorageMap(
+			inter,
+			address,
+			common.StorageDomainContract,
+			createIfNotExists,
+		)
+		for _, name := range contractNames {
+			domainStorageMap.WriteValue(
+				inter,
+				interpreter.StringStorageMapKey(name),
+				interpreter.NewUnmeteredStringValue(name),
+			)
+		}
+
+		const commitContractUpdates = false
+		err := storage.Commit(inter, commitContractUpdates)
+		require.NoError(t, err)
+
+		storage = NewStorage(ledger, nil, StorageConfig{})
+
+		require.Equal(t, StorageFormatV2, storage.AccountStorageFormat(address))
+
+		names, err := storage.GetContractNames(address)
+		require.NoError(t, err)
+		require.Equal(t, []string{"A", "B", "C"}, names)
+	})
+
+	t.Run("v1 account", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := NewTestLedger(nil, nil)
+
+		persistentSlabStorage := NewPersistentSlabStorage(ledger, nil)
+
+		orderedMap, err := atree.NewMap(
+			persistentSlabStorage,
+			atree.Address(address),
+			atree.NewDefaultDigesterBuilder(),
+			interpreter.EmptyTypeInfo{},
+		)
+		require.NoError(t, err)
+
+		for _, name := range contractNames {
+			key := interpreter.StringStorageMapKey(name)
+
+			existingStorable, err := orderedMap.Set(
+				key.AtreeValueCompare,
+				key.AtreeValueHashInput,
+				key.AtreeValue(),
+				interpreter.NewUnmeteredStringValue(name),
+			)
+			require.NoError(t, err)
+			require.Nil(t, existingStorable)
+		}
+
+		err = persistentSlabStorage.FastCommit(runtime.NumCPU())
+		require.NoError(t, err)
+
+		// Create contract domain register
+		slabIndex := orderedMap.SlabID().Index()
+		err = ledger.SetValue(address[:], []byte(common.StorageDomainContract.Identifier()), slabIndex[:])
+		require.NoError(t, err)
+
+		storage := NewStorage(ledger, nil, StorageConfig{})
+
+		require.Equal(t, StorageFormatV1, storage.AccountStorageFormat(address))
+
+		names, err := storage.GetContractNames(address)
+		require.NoError(t, err)
+		require.Equal(t, []string{"A", "B", "C"}, names)
+	})
+
+	t.Run("account without contracts", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := NewTestLedger(nil, nil)
+
+		storage := NewStorage(ledger, nil, StorageConfig{})
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		const createIfNotExists = true
+		domainStorageMap := storage.GetDomainStorageMap(
+			inter,
+			address,
+			common.PathDomainStorage.StorageDomain(),
+			createIfNotExists,
+		)
+		domainStorageMap.WriteValue(
+			inter,
+			interpreter.StringStorageMapKey("a"),
+			interpreter.NewUnmeteredIntValueFromInt64(1),
+		)
+
+		const commitContractUpdates = false
+		err := storage.Commit(inter, commitContractUpdates)
+		require.NoError(t, err)
+
+		storage = NewStorage(ledger, nil, StorageConfig{})
+
+		names, err := storage.GetContractNames(address)
+		require.NoError(t, err)
+		require.Empty(t, names)
+	})
+
+	t.Run("non-existing account", func(t *testing.T) {
+		t.Parallel()
+
+		storage := NewStorage(NewTestLedger(nil, nil), nil, StorageConfig{})
+
+		names, err := storage.GetContractNames(address)
+		require.NoError(t, err)
+		require.Empty(t, names)
+	})
+}
+
+func TestRuntimeStorageAccountSlabIDs(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+	otherAddress := common.MustBytesToAddress([]byte{0x2})
+
+	// traverseSlabIDs returns the sorted IDs of the slabs reachable from the given root slabs
+	traverseSlabIDs := func(t *testing.T, storage *Storage, rootSlabIDs ...atree.SlabID) []atree.SlabID {
+		var slabIDs []atree.SlabID
+
+		queue := rootSlabIDs
+		for len(queue) > 0 {
+			slabID := queue[0]
+			queue = queue[1:]
+
+			slabIDs = append(slabIDs, slabID)
+
+			slab, found, err := storage.Retrieve(slabID)
+			require.NoError(t, err)
+			require.True(t, found)
+
+			childStorables := slab.ChildStorables()
+			for len(childStorables) > 0 {
+				var next []atree.Storable
+
+				for _, childStorable := range childStorables {
+					if slabIDStorable, ok := childStorable.(atree.SlabIDStorable); ok {
+						queue = append(queue, atree.SlabID(slabIDStorable))
+						continue
+					}
+					next = append(next, childStorable.ChildStorables()...)
+				}
+
+				childStorables = next
+			}
+		}
+
+		sort.Slice(slabIDs, func(i, j int) bool {
+			return slabIDs[i].Compare(slabIDs[j]) < 0
+		})
+
+		return slabIDs
+	}
+
+	readSlabIndexRegister := func(t *testing.T, ledger TestLedger, key string) atree.SlabIndex {
+		value, err := ledger.GetValue(address[:], []byte(key))
+		require.NoError(t, err)
+		require.Len(t, value, len(atree.SlabIndex{}))
+		return atree.SlabIndex(value)
+	}
+
+	t.Run("v2 account", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := NewTestLedger(nil, nil)
+
+		storage := NewStorage(ledger, nil, StorageConfig{})
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		// Store a large array in both accounts, so the accounts have child slabs
+
+		for _, address := range []common.Address{address, otherAddress} {
+			elements := make([]interpreter.Value, 0, 200)
+			for i := 0; i < 200; i++ {
+				elements = append(elements, interpreter.NewUnmeteredIntValueFromInt64(int64(i)))
+			}
+
+			array := interpreter.NewArrayValue(
+				inter,
+				interpreter.EmptyLocationRange,
+				&interpreter.VariableSizedStaticType{
+					Type: interpreter.PrimitiveStaticTypeInt,
+				},
+				address,
+				elements...,
+			)
+
+			const createIfNotExists = true
+			domainStorageMap := storage.GetDomainStorageMap(
+				inter,
+				address,
+				common.PathDomainStorage.StorageDomain(),
+				createIfNotExists,
+			)
+			domainStorageMap.WriteValue(inter, interpreter.StringStorageMapKey("a"), array)
+		}
+
+		const commitContractUpdates = false
+		err := storage.Commit(inter, commitContractUpdates)
+		require.NoError(t, err)
+
+		storage = NewStorage(ledger, nil, StorageConfig{})
+
+		slabIDs, err := storage.AccountSlabIDs(address)
+		require.NoError(t, err)
+
+		rootSlabID := atree.NewSlabID(
+			atree.Address(address),
+			readSlabIndexRegister(t, ledger, AccountStorageKey),
+		)
+		expectedSlabIDs := traverseSlabIDs(t, storage, rootSlabID)
+
+		require.Greater(t, len(expectedSlabIDs), 1)
+		require.Equal(t, expectedSlabIDs, slabIDs)
+	})
+
+	t.Run("v1 account", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := NewTestLedger(nil, nil)
+
+		persistentSlabStorage := NewPersistentSlabStorage(ledger, nil)
+
+		orderedMap, err := atree.NewMap(
+			persistentSlabStorage,
+			atree.Address(address),
+			atree.NewDefaultDigesterBuilder(),
+			interpreter.EmptyTypeInfo{},
+		)
+		require.NoError(t, err)
+
+		// Store many values, so the domain storage map has child slabs
+
+		for i := 0; i < 200; i++ {
+			key := interpreter.StringStorageMapKey(strconv.Itoa(i))
+
+			existingStorable, err := orderedMap.Set(
+				key.AtreeValueCompare,
+				key.AtreeValueHashInput,
+				key.AtreeValue(),
+				interpreter.NewUnmeteredIntValueFromInt64(int64(i)),
+			)
+			require.NoError(t, err)
+			require.Nil(t, existingStorable)
+		}
+
+		err = persistentSlabStorage.FastCommit(runtime.NumCPU())
+		require.NoError(t, err)
+
+		// Create storage domain register
+		domain := common.PathDomainStorage.StorageDomain()
+		slabIndex := orderedMap.SlabID().Index()
+		err = ledger.SetValue(address[:], []byte(domain.Identifier()), slabIndex[:])
+		require.NoError(t, err)
+
+		storage := NewStorage(ledger, nil, StorageConfig{})
+
+		require.Equal(t, StorageFormatV1, storage.AccountStorageFormat(address))
+
+		slabIDs, err := storage.AccountSlabIDs(address)
+		require.NoError(t, err)
+
+		rootSlabID := atree.NewSlabID(
+			atree.Address(address),
+			readSlabIndexRegister(t, ledger, domain.Identifier()),
+		)
+		expectedSlabIDs := traverseSlabIDs(t, storage, rootSlabID)
+
+		require.Greater(t, len(expectedSlabIDs), 1)
+		require.Equal(t, expectedSlabIDs, slabIDs)
+	})
+
+	t.Run("non-existing account", func(t *testing.T) {
+		t.Parallel()
+
+		storage := NewStorage(NewTestLedger(nil, nil), nil, StorageConfig{})
+
+		slabIDs, err := storage.AccountSlabIDs(address)
+		require.NoError(t, err)
+		require.Empty(t, slabIDs)
+	})
+}
+
+func TestRuntimeStorageVerifyChecksums(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	// The account storage map, including the inlined domain storage map,
+	// is stored in the first slab of the account
+	slabID := atree.NewSlabID(
+		atree.Address(address),
+		atree.SlabIndex{0, 0, 0, 0, 0, 0, 0, 1},
+	)
+	slabKey := atree.SlabIndexToLedgerKey(slabID.Index())
+
+	newCommittedStorage := func(t *testing.T, config StorageConfig) (*Storage, TestLedger) {
+		ledger := NewTestLedger(nil, nil)
+
+		storage := NewStorage(ledger, nil, config)
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		const createIfNotExists = true
+		domainStorageMap := storage.GetDomainStorageMap(
+			inter,
+			address,
+			common.PathDomainStorage.StorageDomain(),
+			createIfNotExists,
+		)
+		domainStorageMap.WriteValue(
+			inter,
+			interpreter.StringStorageMapKey("a"),
+			interpreter.NewUnmeteredStringValue("test"),
+		)
+
+		const commitContractUpdates = false
+		err := storage.Commit(inter, commitContractUpdates)
+		require.NoError(t, err)
+
+		return storage, ledger
+	}
+
+	corruptSlab := func(t *testing.T, ledger TestLedger) {
+		value, err := ledger.GetValue(address[:], slabKey)
+		require.NoError(t, err)
+		require.NotEmpty(t, value)
+
+		corrupted := slices.Clone(value)
+		corrupted[len(corrupted)-1] ^= 0xff
+
+		err = ledger.SetValue(address[:], slabKey, corrupted)
+		require.NoError(t, err)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		storage, _ := newCommittedStorage(t, StorageConfig{SlabChecksums: true})
+
+		err := storage.VerifyChecksums()
+		require.NoError(t, err)
+	})
+
+	t.Run("corrupted slab", func(t *testing.T) {
+		t.Parallel()
+
+		storage, ledger := newCommittedStorage(t, StorageConfig{SlabChecksums: true})
+
+		corruptSlab(t, ledger)
+
+		err := storage.VerifyChecksums()
+
+		var mismatchErr SlabChecksumMismatchError
+		require.ErrorAs(t, err, &mismatchErr)
+		require.Equal(t, []atree.SlabID{slabID}, mismatchErr.SlabIDs)
+	})
+
+	t.Run("missing slab", func(t *testing.T) {
+		t.Parallel()
+
+		storage, ledger := newCommittedStorage(t, StorageConfig{SlabChecksums: true})
+
+		err := ledger.SetValue(address[:], slabKey, nil)
+		require.NoError(t, err)
+
+		err = storage.VerifyChecksums()
+
+		var mismatchErr SlabChecksumMismatchError
+		require.ErrorAs(t, err, &mismatchErr)
+		require.Equal(t, []atree.SlabID{slabID}, mismatchErr.SlabIDs)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		storage, ledger := newCommittedStorage(t, StorageConfig{})
+
+		corruptSlab(t, ledger)
+
+		err := storage.VerifyChecksums()
+		require.NoError(t, err)
+	})
+}
+
+func TestRuntimeStorageOnSlabEncoded(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	// commit stores a large array, so the account has several slabs,
+	// and returns the metered computation of the commit
+	commit := func(t *testing.T, config StorageConfig) map[common.ComputationKind]uint {
+		ledger := NewTestLedger(nil, nil)
+
+		storage := NewStorage(ledger, nil, config)
+
+		computation := map[common.ComputationKind]uint{}
+
+		inter, err := interpreter.NewInterpreter(
+			nil,
+			TestLocation,
+			&interpreter.Config{
+				Storage:                       storage,
+				AtreeValueValidationEnabled:   true,
+				AtreeStorageValidationEnabled: true,
+				OnMeterComputation: func(compKind common.ComputationKind, intensity uint) {
+					computation[compKind] += intensity
+				},
+			},
+		)
+		require.NoError(t, err)
+
+		elements := make([]interpreter.Value, 0, 200)
+		for i := 0; i < 200; i++ {
+			elements = append(elements, interpreter.NewUnmeteredIntValueFromInt64(int64(i)))
+		}
+
+		array := interpreter.NewArrayValue(
+			inter,
+			interpreter.EmptyLocationRange,
+			&interpreter.VariableSizedStaticType{
+				Type: interpreter.PrimitiveStaticTypeInt,
+			},
+			address,
+			elements...,
+		)
+
+		const createIfNotExists = true
+		domainStorageMap := storage.GetDomainStorageMap(
+			inter,
+			address,
+			common.PathDomainStorage.StorageDomain(),
+			createIfNotExists,
+		)
+		domainStorageMap.WriteValue(inter, interpreter.StringStorageMapKey("a"), array)
+
+		// Only meter the commit
+		clear(computation)
+
+		const commitContractUpdates = false
+		err = storage.Commit(inter, commitContractUpdates)
+		require.NoError(t, err)
+
+		return computation
+	}
+
+	slabSizes := map[atree.SlabID]int{}
+
+	computation := commit(t, StorageConfig{
+		OnSlabEncoded: func(slabID atree.SlabID, size int) {
+			require.NotContains(t, slabSizes, slabID)
+			require.Equal(t, atree.Address(address), slabID.Address())
+			slabSizes[slabID] = size
+		},
+	})
+
+	require.Greater(t, len(slabSizes), 1)
+
+	var totalSize uint
+	for _, size := range slabSizes { //nolint:maprange
+		require.Positive(t, size)
+		totalSize += uint(size)
+	}
+
+	require.Equal(t, computation[common.ComputationKindEncodeValue], totalSize)
+
+	// The hook does not change the metered computation
+
+	require.Equal(t, commit(t, StorageConfig{}), computation)
+}
+
+func TestRuntimeStorageSlabCacheFlushThreshold(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+	key := interpreter.StringStorageMapKey("a")
+
+	const elementCount = 500
+
+	// Store a large array, so the account has many slabs
+	newLedger := func(t *testing.T) TestLedger {
+		ledger := NewTestLedger(nil, nil)
+
+		storage := NewStorage(ledger, nil, StorageConfig{})
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		elements := make([]interpreter.Value, 0, elementCount)
+		for i := 0; i < elementCount; i++ {
+			elements = append(elements, interpreter.NewUnmeteredIntValueFromInt64(int64(i)))
+		}
+
+		array := interpreter.NewArrayValue(
+			inter,
+			interpreter.EmptyLocationRange,
+			&interpreter.VariableSizedStaticType{
+				Type: interpreter.PrimitiveStaticTypeInt,
+			},
+			address,
+			elements...,
+		)
+
+		const createIfNotExists = true
+		domainStorageMap := storage.GetDomainStorageMap(
+			inter,
+			address,
+			common.PathDomainStorage.StorageDomain(),
+			createIfNotExists,
+		)
+		domainStorageMap.WriteValue(inter, key, array)
+
+		const commitContractUpdates = false
+		err := storage.Commit(inter, commitContractUpdates)
+		require.NoError(t, err)
+
+		return ledger
+	}
+
+	// readArray reads all elements of the array in several transactions,
+	// and returns the number of register reads
+	readArray := func(t *testing.T, config StorageConfig) uint64 {
+		storage := NewStorage(newLedger(t), nil, config)
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		for i := 0; i < 3; i++ {
+			const createIfNotExists = false
+			domainStorageMap := storage.GetDomainStorageMap(
+				inter,
+				address,
+				common.PathDomainStorage.StorageDomain(),
+				createIfNotExists,
+			)
+			require.NotNil(t, domainStorageMap)
+
+			array, ok := domainStorageMap.ReadValue(nil, key).(*interpreter.ArrayValue)
+			require.True(t, ok)
+			require.Equal(t, elementCount, array.Count())
+
+			for index := 0; index < elementCount; index++ {
+				require.Equal(
+					t,
+					interpreter.NewUnmeteredIntValueFromInt64(int64(index)),
+					array.Get(nil, interpreter.EmptyLocationRange, index),
+				)
+			}
+
+			const commitContractUpdates = false
+			err := storage.Commit(inter, commitContractUpdates)
+			require.NoError(t, err)
+		}
+
+		return storage.ReadCount()
+	}
+
+	t.Run("no flushing", func(t *testing.T) {
+		t.Parallel()
+
+		readArray(t, StorageConfig{})
+	})
+
+	t.Run("flushing", func(t *testing.T) {
+		t.Parallel()
+
+		readCount := readArray(t, StorageConfig{})
+		flushedReadCount := readArray(t, StorageConfig{SlabCacheFlushThreshold: 2})
+
+		// Flushed slabs are read from the ledger again
+		require.Greater(t, flushedReadCount, readCount)
+	})
+
+	t.Run("flush", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := newLedger(t)
+
+		slabIDs, err := NewStorage(ledger, nil, StorageConfig{}).AccountSlabIDs(address)
+		require.NoError(t, err)
+		require.Greater(t, len(slabIDs), 1)
+
+		storage := NewStorage(ledger, nil, StorageConfig{SlabCacheFlushThreshold: 1})
+
+		_, found, err := storage.Retrieve(slabIDs[0])
+		require.NoError(t, err)
+		require.True(t, found)
+		require.NotNil(t, storage.RetrieveIfLoaded(slabIDs[0]))
+
+		_, found, err = storage.Retrieve(slabIDs[1])
+		require.NoError(t, err)
+		require.True(t, found)
+		require.NotNil(t, storage.RetrieveIfLoaded(slabIDs[1]))
+
+		// The slabs are not flushed during the transaction
+
+		require.NotNil(t, storage.RetrieveIfLoaded(slabIDs[0]))
+
+		// The slabs are flushed on commit
+
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		const commitContractUpdates = false
+		err = storage.Commit(inter, commitContractUpdates)
+		require.NoError(t, err)
+
+		require.Nil(t, storage.RetrieveIfLoaded(slabIDs[0]))
+		require.Nil(t, storage.RetrieveIfLoaded(slabIDs[1]))
+	})
+
+	t.Run("mutation through several values", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := newLedger(t)
+
+		otherKey := interpreter.StringStorageMapKey("b")
+
+		// Store another large array
+
+		storage := NewStorage(ledger, nil, StorageConfig{})
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		elements := make([]interpreter.Value, 0, elementCount)
+		for i := 0; i < elementCount; i++ {
+			elements = append(elements, interpreter.NewUnmeteredIntValueFromInt64(int64(i)))
+		}
+
+		otherArray := interpreter.NewArrayValue(
+			inter,
+			interpreter.EmptyLocationRange,
+			&interpreter.VariableSizedStaticType{
+				Type: interpreter.PrimitiveStaticTypeInt,
+			},
+			address,
+			elements...,
+		)
+
+		const createIfNotExists = false
+		domainStorageMap := storage.GetDomainStorageMap(
+			inter,
+			address,
+			common.PathDomainStorage.StorageDomain(),
+			createIfNotExists,
+		)
+		domainStorageMap.WriteValue(inter, otherKey, otherArray)
+
+		const commitContractUpdates = false
+		err := storage.Commit(inter, commitContractUpdates)
+		require.NoError(t, err)
+
+		// Load the same array twice, with other slabs loaded in between,
+		// and mutate it through both values
+
+		storage = NewStorage(ledger, nil, StorageConfig{SlabCacheFlushThreshold: 1})
+		inter = NewTestInterpreterWithStorage(t, storage)
+
+		readArray := func(key interpreter.StorageMapKey) *interpreter.ArrayValue {
+			domainStorageMap := storage.GetDomainStorageMap(
+				inter,
+				address,
+				common.PathDomainStorage.StorageDomain(),
+				createIfNotExists,
+			)
+			require.NotNil(t, domainStorageMap)
+
+			array, ok := domainStorageMap.ReadValue(inter, key).(*interpreter.ArrayValue)
+			require.True(t, ok)
+			return array
+		}
+
+		array1 := readArray(key)
+		_ = readArray(otherKey)
+		array2 := readArray(key)
+
+		array2.Append(inter, interpreter.EmptyLocationRange, interpreter.NewUnmeteredIntValueFromInt64(1))
+		array1.Append(inter, interpreter.EmptyLocationRange, interpreter.NewUnmeteredIntValueFromInt64(2))
+
+		require.Equal(t, elementCount+2, array1.Count())
+		require.Equal(t, elementCount+2, array2.Count())
+
+		err = storage.Commit(inter, commitContractUpdates)
+		require.NoError(t, err)
+
+		// Both mutations were committed
+
+		storage = NewStorage(ledger, nil, StorageConfig{})
+		inter = NewTestInterpreterWithStorage(t, storage)
+
+		require.Equal(t, elementCount+2, readArray(key).Count())
+
+		err = storage.CheckHealth()
+		require.NoError(t, err)
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		t.Parallel()
+
+		require.PanicsWithValue(
+			t,
+			InvalidSlabCacheFlushThresholdError{SlabCacheFlushThreshold: -1},
+			func() {
+				NewStorage(NewTestLedger(nil, nil), nil, StorageConfig{SlabCacheFlushThreshold: -1})
+			},
+		)
+	})
+}
+
+func TestRuntimeStorageFlushDomain(t *testing.T) {
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	storageDomain := common.PathDomainStorage.StorageDomain()
+	publicDomain := common.PathDomainPublic.StorageDomain()
+
+	const count = 100
+
+	// newLedger returns a ledger with an account which has a large storage domain,
+	// which is stored in separate slabs, and a small public domain,
+	// which is inlined into the account storage map
+	newLedger := func(t *testing.T) TestLedger {
+		ledger := NewTestLedger(nil, nil)
+
+		storage := NewStorage(ledger, nil, StorageConfig{})
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		const createIfNotExists = true
+
+		domainStorageMap := storage.GetDomainStorageMap(inter, address, storageDomain, createIfNotExists)
+		for i := 0; i < count; i++ {
+			domainStorageMap.WriteValue(
+				inter,
+				interpreter.StringStorageMapKey(strconv.Itoa(i)),
+				interpreter.NewUnmeteredIntValueFromInt64(int64(i)),
+			)
+		}
+
+		domainStorageMap = storage.GetDomainStorageMap(inter, address, publicDomain, createIfNotExists)
+		domainStorageMap.WriteValue(
+			inter,
+			interpreter.StringStorageMapKey("a"),
+			interpreter.NewUnmeteredIntValueFromInt64(1),
+		)
+
+		const commitContractUpdates = false
+		err := storage.Commit(inter, commitContractUpdates)
+		require.NoError(t, err)
+
+		return ledger
+	}
+
+	key := interpreter.StringStorageMapKey("new")
+
+	writeNewValue := func(
+		t *testing.T,
+		storage *Storage,
+		inter *interpreter.Interpreter,
+		domain common.StorageDomain,
+	) {
+		const createIfNotExists = false
+		domainStorageMap := storage.GetDomainStorageMap(inter, address, domain, createIfNotExists)
+		require.NotNil(t, domainStorageMap)
+
+		domainStorageMap.WriteValue(inter, key, interpreter.NewUnmeteredIntValueFromInt64(42))
+	}
+
+	readNewValue := func(
+		t *testing.T,
+		storage *Storage,
+		domain common.StorageDomain,
+	) interpreter.Value {
+		const createIfNotExists = false
+		domainStorageMap := storage.GetDomainStorageMap(nil, address, domain, createIfNotExists)
+		require.NotNil(t, domainStorageMap)
+
+		return domainStorageMap.ReadValue(nil, key)
+	}
+
+	t.Run("flush", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := newLedger(t)
+
+		storage := NewStorage(ledger, nil, StorageConfig{})
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		writeNewValue(t, storage, inter, storageDomain)
+		writeNewValue(t, storage, inter, publicDomain)
+
+		err := storage.FlushDomain(inter, address, storageDomain)
+		require.NoError(t, err)
+
+		// Crash, i.e. discard the unsaved changes, and reload the storage from the ledger
+
+		storage = NewStorage(ledger, nil, StorageConfig{})
+
+		// Only the flushed domain was persisted
+
+		require.Equal(t,
+			interpreter.NewUnmeteredIntValueFromInt64(42),
+			readNewValue(t, storage, storageDomain),
+		)
+		require.Nil(t, readNewValue(t, storage, publicDomain))
+
+		// The existing values of the flushed domain are intact
+
+		const createIfNotExists = false
+		domainStorageMap := storage.GetDomainStorageMap(nil, address, storageDomain, createIfNotExists)
+		require.Equal(t, uint64(count+1), domainStorageMap.Count())
+		for i := 0; i < count; i++ {
+			require.Equal(t,
+				interpreter.NewUnmeteredIntValueFromInt64(int64(i)),
+				domainStorageMap.ReadValue(nil, interpreter.StringStorageMapKey(strconv.Itoa(i))),
+			)
+		}
+
+		err = storage.CheckHealth()
+		require.NoError(t, err)
+	})
+
+	t.Run("flush, then commit", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := newLedger(t)
+
+		storage := NewStorage(ledger, nil, StorageConfig{})
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		writeNewValue(t, storage, inter, storageDomain)
+		writeNewValue(t, storage, inter, publicDomain)
+
+		err := storage.FlushDomain(inter, address, storageDomain)
+		require.NoError(t, err)
+
+		// The remaining unsaved changes are committed
+
+		const commitContractUpdates = false
+		err = storage.Commit(inter, commitContractUpdates)
+		require.NoError(t, err)
+
+		storage = NewStorage(ledger, nil, StorageConfig{})
+
+		require.Equal(t,
+			interpreter.NewUnmeteredIntValueFromInt64(42),
+			readNewValue(t, storage, storageDomain),
+		)
+		require.Equal(t,
+			interpreter.NewUnmeteredIntValueFromInt64(42),
+			readNewValue(t, storage, publicDomain),
+		)
+	})
+
+	t.Run("only unsaved slabs", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := newLedger(t)
+
+		slabIDs, err := NewStorage(ledger, nil, StorageConfig{}).AccountSlabIDs(address)
+		require.NoError(t, err)
+
+		storage := NewStorage(ledger, nil, StorageConfig{})
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		writeNewValue(t, storage, inter, storageDomain)
+
+		storage.ResetCounters()
+
+		err = storage.FlushDomain(inter, address, storageDomain)
+		require.NoError(t, err)
+
+		// Only the modified slabs of the domain were written
+
+		writeCount := storage.WriteCount()
+		require.Positive(t, writeCount)
+		require.Less(t, writeCount, uint64(len(slabIDs)))
+
+		// Flushing again without further changes writes nothing
+
+		storage.ResetCounters()
+
+		err = storage.FlushDomain(inter, address, storageDomain)
+		require.NoError(t, err)
+
+		require.Zero(t, storage.WriteCount())
+	})
+
+	t.Run("metering", func(t *testing.T) {
+		t.Parallel()
+
+		// encodingComputation returns the computation metered for encoding
+		// when writing a new value, optionally flushing the domain, and then committing
+		encodingComputation := func(t *testing.T, flush bool) uint {
+			storage := NewStorage(newLedger(t), nil, StorageConfig{})
+
+			var computation uint
+
+			inter, err := interpreter.NewInterpreter(
+				nil,
+				TestLocation,
+				&interpreter.Config{
+					Storage:                       storage,
+					AtreeValueValidationEnabled:   true,
+					AtreeStorageValidationEnabled: true,
+					OnMeterComputation: func(compKind common.ComputationKind, intensity uint) {
+						if compKind == common.ComputationKindEncodeValue {
+							computation += intensity
+						}
+					},
+				},
+			)
+			require.NoError(t, err)
+
+			writeNewValue(t, storage, inter, storageDomain)
+
+			if flush {
+				err = storage.FlushDomain(inter, address, storageDomain)
+				require.NoError(t, err)
+				require.Positive(t, computation)
+			}
+
+			const commitContractUpdates = false
+			err = storage.Commit(inter, commitContractUpdates)
+			require.NoError(t, err)
+
+			return computation
+		}
+
+		// The flushed slabs are not metered again on commit
+
+		require.Equal(t,
+			encodingComputation(t, false),
+			encodingComputation(t, true),
+		)
+	})
+
+	t.Run("inlined domain", func(t *testing.T) {
+		t.Parallel()
+
+		storage := NewStorage(newLedger(t), nil, StorageConfig{})
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		writeNewValue(t, storage, inter, publicDomain)
+
+		err := storage.FlushDomain(inter, address, publicDomain)
+		require.ErrorAs(t, err, &DomainNotFlushableError{})
+	})
+
+	t.Run("non-existing domain", func(t *testing.T) {
+		t.Parallel()
+
+		storage := NewStorage(newLedger(t), nil, StorageConfig{})
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		err := storage.FlushDomain(inter, address, common.StorageDomainInbox)
+		require.NoError(t, err)
+	})
+}
+
+func TestRuntimeStorageGetDomainStorageMapForType(t *testing.T) {
+
+	t.Parallel()
+
+	address := common.MustBytesToAddress([]byte{0x1})
+
+	type testCase struct {
+		pathType sema.Type
+		domain   common.StorageDomain
+	}
+
+	for _, testCase := range []testCase{
+		{pathType: sema.StoragePathType, domain: common.StorageDomainPathStorage},
+		{pathType: sema.PublicPathType, domain: common.StorageDomainPathPublic},
+		{pathType: sema.PrivatePathType, domain: common.StorageDomainPathPrivate},
+	} {
+		t.Run(testCase.pathType.String(), func(t *testing.T) {
+			t.Parallel()
+
+			storage := NewStorage(NewTestLedger(nil, nil), nil, StorageConfig{})
+			inter := NewTestInterpreterWithStorage(t, storage)
+
+			domainStorageMap, err := storage.GetDomainStorageMapForType(inter, address, testCase.pathType, false)
+			require.NoError(t, err)
+			require.Nil(t, domainStorageMap)
+
+			domainStorageMap, err = storage.GetDomainStorageMapForType(inter, address, testCase.pathType, true)
+			require.NoError(t, err)
+			require.NotNil(t, domainStorageMap)
+
+			require.Same(t,
+				storage.GetDomainStorageMap(inter, address, testCase.domain, false),
+				domainStorageMap,
+			)
+		})
+	}
+
+	t.Run("invalid type", func(t *testing.T) {
+		t.Parallel()
+
+		storage := NewStorage(NewTestLedger(nil, nil), nil, StorageConfig{})
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		for _, pathType := range []sema.Type{
+			sema.PathType,
+			sema.CapabilityPathType,
+			sema.IntType,
+		} {
+			domainStorageMap, err := storage.GetDomainStorageMapForType(inter, address, pathType, true)
+			require.ErrorAs(t, err, &InvalidPathDomainTypeError{})
+			require.Nil(t, domainStorageMap)
+		}
+	})
+}
+
+func TestRuntimeStorageCommitWithChangeSet(t *testing.T) {
+
+	t.Parallel()
+
+	address1 := common.MustBytesToAddress([]byte{0x1})
+	address2 := common.MustBytesToAddress([]byte{0x2})
+
+	ledger := NewTestLedger(nil, nil)
+
+	writeValue := func(
+		storage *Storage,
+		inter *interpreter.Interpreter,
+		address common.Address,
+		domain common.StorageDomain,
+		key interpreter.StorageMapKey,
+		value interpreter.Value,
+	) {
+		domainStorageMap := storage.GetDomainStorageMap(inter, address, domain, true)
+		domainStorageMap.WriteValue(inter, key, value)
+	}
+
+	loadAccountStorageMap := func(storage *Storage, address common.Address) *interpreter.AccountStorageMap {
+		slabIndex, err := ledger.GetValue(address[:], []byte(AccountStorageKey))
+		require.NoError(t, err)
+		require.Len(t, slabIndex, 8)
+
+		slabID := atree.NewSlabID(atree.Address(address), atree.SlabIndex(slabIndex))
+
+		return interpreter.NewAccountStorageMapWithRootID(storage, slabID)
+	}
+
+	// Write the initial values
+
+	storage := NewStorage(ledger, nil, StorageConfig{})
+	inter := NewTestInterpreterWithStorage(t, storage)
+
+	writeValue(storage, inter, address1, common.StorageDomainPathStorage, interpreter.StringStorageMapKey("a"), interpreter.NewUnmeteredIntValueFromInt64(1))
+	writeValue(storage, inter, address1, common.StorageDomainPathStorage, interpreter.StringStorageMapKey("b"), interpreter.NewUnmeteredIntValueFromInt64(2))
+	writeValue(storage, inter, address1, common.StorageDomainPathStorage, interpreter.StringStorageMapKey("c"), interpreter.NewUnmeteredIntValueFromInt64(3))
+	writeValue(storage, inter, address2, common.StorageDomainPathPublic, interpreter.StringStorageMapKey("x"), interpreter.NewUnmeteredIntValueFromInt64(4))
+
+	changeSet, err := storage.CommitWithChangeSet(inter, false)
+	require.NoError(t, err)
+
+	require.Equal(t,
+		ChangeSet{
+			Accounts: []AccountChangeSet{
+				{
+					Address: address1,
+					Added: []ChangedStorageKey{
+						{Domain: common.StorageDomainPathStorage, Key: interpreter.StringStorageMapKey("a")},
+						{Domain: common.StorageDomainPathStorage, Key: interpreter.StringStorageMapKey("b")},
+						{Domain: common.StorageDomainPathStorage, Key: interpreter.StringStorageMapKey("c")},
+					},
+				},
+				{
+					Address: address2,
+					Added: []ChangedStorageKey{
+						{Domain: common.StorageDomainPathPublic, Key: interpreter.StringStorageMapKey("x")},
+					},
+				},
+			},
+		},
+		changeSet,
+	)
+
+	// Mix of writes and removals
+
+	storage = NewStorage(ledger, nil, StorageConfig{})
+	inter = NewTestInterpreterWithStorage(t, storage)
+
+	// Unchanged value
+	writeValue(storage, inter, address1, common.StorageDomainPathStorage, interpreter.StringStorageMapKey("a"), interpreter.NewUnmeteredIntValueFromInt64(1))
+	// Updated value
+	writeValue(storage, inter, address1, common.StorageDomainPathStorage, interpreter.StringStorageMapKey("b"), interpreter.NewUnmeteredIntValueFromInt64(20))
+	// Removed value
+	writeValue(storage, inter, address1, common.StorageDomainPathStorage, interpreter.StringStorageMapKey("c"), nil)
+	// Added values
+	writeValue(storage, inter, address1, common.StorageDomainPathStorage, interpreter.StringStorageMapKey("d"), interpreter.NewUnmeteredIntValueFromInt64(5))
+	writeValue(storage, inter, address1, common.StorageDomainCapabilityController, interpreter.Uint64StorageMapKey(1), interpreter.NewUnmeteredIntValueFromInt64(6))
+	writeValue(storage, inter, address2, common.StorageDomainPathStorage, interpreter.StringStorageMapKey("z"), interpreter.NewUnmeteredIntValueFromInt64(7))
+	// Added and removed value
+	writeValue(storage, inter, address2, common.StorageDomainPathPublic, interpreter.StringStorageMapKey("y"), interpreter.NewUnmeteredIntValueFromInt64(8))
+	writeValue(storage, inter, address2, common.StorageDomainPathPublic, interpreter.StringStorageMapKey("y"), nil)
+
+	changeSet, err = storage.CommitWithChangeSet(inter, false)
+	require.NoError(t, err)
+
+	require.Equal(t,
+		ChangeSet{
+			Accounts: []AccountChangeSet{
+				{
+					Address: address1,
+					Added: []ChangedStorageKey{
+						{Domain: common.StorageDomainPathStorage, Key: interpreter.StringStorageMapKey("d")},
+						{Domain: common.StorageDomainCapabilityController, Key: interpreter.Uint64StorageMapKey(1)},
+					},
+					Updated: []ChangedStorageKey{
+						{Domain: common.StorageDomainPathStorage, Key: interpreter.StringStorageMapKey("b")},
+					},
+					Removed: []ChangedStorageKey{
+						{Domain: common.StorageDomainPathStorage, Key: interpreter.StringStorageMapKey("c")},
+					},
+				},
+				{
+					Address: address2,
+					Added: []ChangedStorageKey{
+						{Domain: common.StorageDomainPathStorage, Key: interpreter.StringStorageMapKey("z")},
+					},
+				},
+			},
+		},
+		changeSet,
+	)
+
+	// The changes were committed
+
+	storage = NewStorage(ledger, nil, StorageConfig{})
+	inter = NewTestInterpreterWithStorage(t, storage)
+
+	domainStorageMap := storage.GetDomainStorageMap(inter, address1, common.StorageDomainPathStorage, false)
+	require.NotNil(t, domainStorageMap)
+	require.Equal(t,
+		interpreter.NewUnmeteredIntValueFromInt64(20),
+		domainStorageMap.ReadValue(nil, interpreter.StringStorageMapKey("b")),
+	)
+	require.False(t, domainStorageMap.ValueExists(interpreter.StringStorageMapKey("c")))
+
+	// Committing again without changes results in an empty change set
+
+	changeSet, err = storage.CommitWithChangeSet(inter, false)
+	require.NoError(t, err)
+	require.Empty(t, changeSet.Accounts)
+
+	// Removal of a domain and of an account,
+	// without accessing the domain storage maps through the storage.
+	// Turn off AtreeStorageValidationEnabled, because the account storage map
+	// is loaded directly, and not through the storage.
+
+	const atreeValueValidationEnabled = true
+	const atreeStorageValidationEnabled = false
+
+	storage = NewStorage(ledger, nil, StorageConfig{})
+	inter = NewTestInterpreterWithStorageAndAtreeValidationConfig(
+		t,
+		storage,
+		atreeValueValidationEnabled,
+		atreeStorageValidationEnabled,
+	)
+
+	accountStorageMap := loadAccountStorageMap(storage, address1)
+
+	existed := accountStorageMap.WriteDomain(inter, common.StorageDomainCapabilityController, nil)
+	require.True(t, existed)
+
+	err = storage.RemoveAccount(inter, address2)
+	require.NoError(t, err)
+
+	changeSet, err = storage.CommitWithChangeSet(inter, false)
+	require.NoError(t, err)
+
+	require.Equal(t,
+		ChangeSet{
+			Accounts: []AccountChangeSet{
+				{
+					Address: address1,
+					Removed: []ChangedStorageKey{
+						{Domain: common.StorageDomainCapabilityController, Key: interpreter.Uint64StorageMapKey(1)},
+					},
+				},
+				{
+					Address: address2,
+					Removed: []ChangedStorageKey{
+						{Domain: common.StorageDomainPathStorage, Key: interpreter.StringStorageMapKey("z")},
+						{Domain: common.StorageDomainPathPublic, Key: interpreter.StringStorageMapKey("x")},
+					},
+				},
+			},
+		},
+		changeSet,
+	)
+
+	// Removal of all domains of an account
+
+	storage = NewStorage(ledger, nil, StorageConfig{})
+	inter = NewTestInterpreterWithStorageAndAtreeValidationConfig(
+		t,
+		storage,
+		atreeValueValidationEnabled,
+		atreeStorageValidationEnabled,
+	)
+
+	accountStorageMap = loadAccountStorageMap(storage, address1)
+
+	accountStorageMap.Clear(inter)
+
+	changeSet, err = storage.CommitWithChangeSet(inter, false)
+	require.NoError(t, err)
+
+	require.Equal(t,
+		ChangeSet{
+			Accounts: []AccountChangeSet{
+				{
+					Address: address1,
+					Removed: []ChangedStorageKey{
+						{Domain: common.StorageDomainPathStorage, Key: interpreter.StringStorageMapKey("a")},
+						{Domain: common.StorageDomainPathStorage, Key: interpreter.StringStorageMapKey("b")},
+						{Domain: common.StorageDomainPathStorage, Key: interpreter.StringStorageMapKey("d")},
+					},
+				},
+			},
+		},
+		changeSet,
+	)
+}
+
+func TestRuntimeStorageFormatCacheSize(t *testing.T) {
+
+	t.Parallel()
+
+	addresses := []common.Address{
+		common.MustBytesToAddress([]byte{0x1}),
+		common.MustBytesToAddress([]byte{0x2}),
+		common.MustBytesToAddress([]byte{0x3}),
+		common.MustBytesToAddress([]byte{0x4}),
+	}
+
+	const formatCacheSize = 2
+
+	// Create accounts
+
+	ledger := NewTestLedger(nil, nil)
+
+	storage := NewStorage(ledger, nil, StorageConfig{})
+	inter := NewTestInterpreterWithStorage(t, storage)
+
+	for _, address := range addresses {
+		domainStorageMap := storage.GetDomainStorageMap(inter, address, common.StorageDomainPathStorage, true)
+		domainStorageMap.WriteValue(inter, interpreter.StringStorageMapKey("a"), interpreter.NewUnmeteredIntValueFromInt64(1))
+	}
+
+	err := storage.Commit(inter, false)
+	require.NoError(t, err)
+
+	t.Run("eviction", func(t *testing.T) {
+		t.Parallel()
+
+		formatRegisterReads := map[string]int{}
+
+		ledger := NewTestLedgerWithData(
+			func(owner, key, _ []byte) {
+				if string(key) == AccountStorageKey {
+					formatRegisterReads[string(owner)]++
+				}
+			},
+			nil,
+			ledger.StoredValues,
+			ledger.StorageIndices,
+		)
+
+		storage := NewStorage(
+			ledger,
+			nil,
+			StorageConfig{
+				FormatCacheSize: formatCacheSize,
+			},
+		)
+
+		requireFormat := func(address common.Address, expectedReads int) {
+			require.Equal(t, StorageFormatV2, storage.AccountStorageFormat(address))
+			require.Equal(t, expectedReads, formatRegisterReads[string(address[:])])
+		}
+
+		// Exceed the cache size
+
+		for _, address := range addresses {
+			requireFormat(address, 1)
+		}
+
+		// The most recently used accounts are cached
+
+		requireFormat(addresses[2], 1)
+		requireFormat(addresses[3], 1)
+
+		// The least recently used accounts were evicted, and are detected again
+
+		requireFormat(addresses[0], 2)
+		requireFormat(addresses[1], 2)
+
+		// Detecting the evicted accounts again evicted the accounts used least recently
+
+		requireFormat(addresses[2], 2)
+	})
+
+	t.Run("unsaved changes", func(t *testing.T) {
+		t.Parallel()
+
+		ledger := NewTestLedgerWithData(nil, nil, ledger.StoredValues, ledger.StorageIndices)
+
+		storage := NewStorage(
+			ledger,
+			nil,
+			StorageConfig{
+				FormatCacheSize: formatCacheSize,
+			},
+		)
+		inter := NewTestInterpreterWithStorage(t, storage)
+
+		// Create a new account, which is only written to the ledger on commit
+
+		newAddress := common.MustBytesToAddress([]byte{0x5})
+
+		domainStorageMap := storage.GetDomainStorageMap(inter, newAddress, common.StorageDomainPathStorage, true)
+		domainStorageMap.WriteValue(inter, interpreter.StringStorageMapKey("a"), interpreter.NewUnmeteredIntValueFromInt64(1))
+
+		// Exceed the cache size
+
+		for _, address := range addresses {
+			require.Equal(t, StorageFormatV2, storage.AccountStorageFormat(address))
+		}
+
+		// The new account was not evicted,
+		// so the new domain is created in the account storage map of the new account
+
+		domainStorageMap = storage.GetDomainStorageMap(inter, newAddress, common.StorageDomainPathPublic, true)
+		domainStorageMap.WriteValue(inter, interpreter.StringStorageMapKey("b"), interpreter.NewUnmeteredIntValueFromInt64(2))
+
+		err := storage.Commit(inter, false)
+		require.NoError(t, err)
+
+		err = storage.CheckHealth()
+		require.NoError(t, err)
+
+		storage = NewStorage(ledger, nil, StorageConfig{})
+		inter = NewTestInterpreterWithStorage(t, storage)
+
+		for _, domain := range []common.StorageDomain{
+			common.StorageDomainPathStorage,
+			common.StorageDomainPathPublic,
+		} {
+			domainStorageMap := storage.GetDomainStorageMap(inter, newAddress, domain, false)
+			require.NotNil(t, domainStorageMap)
+			require.Equal(t, uint64(1), domainStorageMap.Count())
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		require.PanicsWithValue(t,
+			InvalidFormatCacheSizeError{FormatCacheSize: -1},
+			func() {
+				NewStorage(NewTestLedger(nil, nil), nil, StorageConfig{FormatCacheSize: -1})
+			},
+		)
+	})
 }
@@ -31,6 +31,46 @@ const CharacterTypeUtf8FieldDocString = `
 The byte array of the UTF-8 encoding.
 `
 
+const CharacterTypeValueFieldName = "value"
+
+var CharacterTypeValueFieldType = UInt32Type
+
+const CharacterTypeValueFieldDocString = `
+The Unicode scalar value of the character.
+Only the first Unicode scalar of the character is returned,
+e.g. the value of a letter followed by combining marks is the value of the letter.
+`
+
+const CharacterTypeIsLetterFieldName = "isLetter"
+
+var CharacterTypeIsLetterFieldType = BoolType
+
+const CharacterTypeIsLetterFieldDocString = `
+Is true if the character is a letter.
+Only the first Unicode scalar of the character is classified,
+e.g. a letter followed by combining marks is a letter.
+`
+
+const CharacterTypeIsDigitFieldName = "isDigit"
+
+var CharacterTypeIsDigitFieldType = BoolType
+
+const CharacterTypeIsDigitFieldDocString = `
+Is true if the character is a decimal digit.
+Only the first Unicode scalar of the character is classified,
+e.g. a digit followed by combining marks is a digit.
+`
+
+const CharacterTypeIsWhitespaceFieldName = "isWhitespace"
+
+var CharacterTypeIsWhitespaceFieldType = BoolType
+
+const CharacterTypeIsWhitespaceFieldDocString = `
+Is true if the character is whitespace.
+Only the first Unicode scalar of the character is classified,
+e.g. the grapheme cluster CR LF is whitespace.
+`
+
 const CharacterTypeToStringFunctionName = "toString"
 
 var CharacterTypeToStringFunctionType = &FunctionType{
@@ -44,6 +84,30 @@ const CharacterTypeToStringFunctionDocString = `
 Returns this character as a String.
 `
 
+const CharacterTypeCompareFunctionName = "compare"
+
+var CharacterTypeCompareFunctionType = &FunctionType{
+	Purity: FunctionPurityView,
+	Parameters: []Parameter{
+		{
+			Label:          ArgumentLabelNotRequired,
+			Identifier:     "other",
+			TypeAnnotation: NewTypeAnnotation(CharacterType),
+		},
+	},
+	ReturnTypeAnnotation: NewTypeAnnotation(
+		IntType,
+	),
+}
+
+const CharacterTypeCompareFunctionDocString = `
+Compares this character to the other character.
+Returns a negative integer if this character is less than the other character,
+zero if the characters are equal,
+and a positive integer if this character is greater than the other character.
+The order is the same as the order of the comparison operators.
+`
+
 const CharacterTypeName = "Character"
 
 var CharacterType = &SimpleType{
@@ -73,6 +137,38 @@ func init() {
 				CharacterTypeUtf8FieldType,
 				CharacterTypeUtf8FieldDocString,
 			),
+			NewUnmeteredFieldMember(
+				t,
+				PrimitiveAccess(ast.AccessAll),
+				ast.VariableKindConstant,
+				CharacterTypeValueFieldName,
+				CharacterTypeValueFieldType,
+				CharacterTypeValueFieldDocString,
+			),
+			NewUnmeteredFieldMember(
+				t,
+				PrimitiveAccess(ast.AccessAll),
+				ast.VariableKindConstant,
+				CharacterTypeIsLetterFieldName,
+				CharacterTypeIsLetterFieldType,
+				CharacterTypeIsLetterFieldDocString,
+			),
+			NewUnmeteredFieldMember(
+				t,
+				PrimitiveAccess(ast.AccessAll),
+				ast.VariableKindConstant,
+				CharacterTypeIsDigitFieldName,
+				CharacterTypeIsDigitFieldType,
+				CharacterTypeIsDigitFieldDocString,
+			),
+			NewUnmeteredFieldMember(
+				t,
+				PrimitiveAccess(ast.AccessAll),
+				ast.VariableKindConstant,
+				CharacterTypeIsWhitespaceFieldName,
+				CharacterTypeIsWhitespaceFieldType,
+				CharacterTypeIsWhitespaceFieldDocString,
+			),
 			NewUnmeteredFunctionMember(
 				t,
 				PrimitiveAccess(ast.AccessAll),
@@ -80,6 +176,13 @@ func init() {
 				CharacterTypeToStringFunctionType,
 				CharacterTypeToStringFunctionDocString,
 			),
+			NewUnmeteredFunctionMember(
+				t,
+				PrimitiveAccess(ast.AccessAll),
+				CharacterTypeCompareFunctionName,
+				CharacterTypeCompareFunctionType,
+				CharacterTypeCompareFunctionDocString,
+			),
 		})
 	}
 }
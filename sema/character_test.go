@@ -75,3 +75,78 @@ func TestCheckCharacterUtf8Field(t *testing.T) {
 		RequireGlobalValue(t, checker.Elaboration, "x"),
 	)
 }
+
+func TestCheckCharacterValue(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t, `
+		let a: Character = "a"
+        let x = a.value
+	`)
+
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		sema.UInt32Type,
+		RequireGlobalValue(t, checker.Elaboration, "x"),
+	)
+}
+
+func TestCheckCharacterClassificationFields(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t, `
+		let a: Character = "a"
+        let x = a.isLetter
+        let y = a.isDigit
+        let z = a.isWhitespace
+	`)
+
+	require.NoError(t, err)
+
+	for _, name := range []string{"x", "y", "z"} {
+		assert.Equal(t,
+			sema.BoolType,
+			RequireGlobalValue(t, checker.Elaboration, name),
+		)
+	}
+}
+
+func TestCheckCharacterCompare(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+		    let a: Character = "a"
+		    let b: Character = "b"
+            let x = a.compare(b)
+	    `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			sema.IntType,
+			RequireGlobalValue(t, checker.Elaboration, "x"),
+		)
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+		    let a: Character = "a"
+            let x = a.compare(1)
+	    `)
+
+		errs := RequireCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+}
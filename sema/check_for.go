@@ -165,6 +165,10 @@ func (checker *Checker) iterableElementType(valueType Type, hasPosition ast.HasP
 		return CharacterType
 	}
 
+	if valueType == StringSplitIteratorType {
+		return StringType
+	}
+
 	checker.report(
 		&TypeMismatchWithDescriptionError{
 			ExpectedTypeDescription: "array",
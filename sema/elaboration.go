@@ -840,6 +840,34 @@ func (e *Elaboration) SetInterfaceType(typeID TypeID, ty *InterfaceType) {
 	e.interfaceTypes[typeID] = ty
 }
 
+// MergeTypes adds the composite, interface, entitlement, and entitlement map types,
+// and the global values and types of the given elaboration to this elaboration.
+// Existing entries with the same type ID or name are overwritten.
+func (e *Elaboration) MergeTypes(other *Elaboration) {
+
+	// Iterating over the maps in a non-deterministic way is OK,
+	// we only copy the values over.
+
+	for typeID, ty := range other.compositeTypes { //nolint:maprange
+		e.SetCompositeType(typeID, ty)
+	}
+
+	for typeID, ty := range other.interfaceTypes { //nolint:maprange
+		e.SetInterfaceType(typeID, ty)
+	}
+
+	for typeID, ty := range other.entitlementTypes { //nolint:maprange
+		e.SetEntitlementType(typeID, ty)
+	}
+
+	for typeID, ty := range other.entitlementMapTypes { //nolint:maprange
+		e.SetEntitlementMapType(typeID, ty)
+	}
+
+	other.ForEachGlobalValue(e.SetGlobalValue)
+	other.ForEachGlobalType(e.SetGlobalType)
+}
+
 func (e *Elaboration) IdentifierInInvocationType(expression *ast.IdentifierExpression) Type {
 	if e.identifierInInvocationTypes == nil {
 		return nil
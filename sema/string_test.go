@@ -340,6 +340,271 @@ func TestCheckStringUtf8Field(t *testing.T) {
 	)
 }
 
+func TestCheckStringByteLengthAndScalarCount(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t, `
+      let x = "abc".byteLength
+      let y = "abc".scalarCount
+	`)
+
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		sema.IntType,
+		RequireGlobalValue(t, checker.Elaboration, "x"),
+	)
+	assert.Equal(t,
+		sema.IntType,
+		RequireGlobalValue(t, checker.Elaboration, "y"),
+	)
+}
+
+func TestCheckStringIsASCII(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t, `
+      let x = "abc".isASCII
+	`)
+
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		sema.BoolType,
+		RequireGlobalValue(t, checker.Elaboration, "x"),
+	)
+}
+
+func TestCheckStringMatches(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let x = "abc".matches(pattern: "a*")
+	    `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			sema.BoolType,
+			RequireGlobalValue(t, checker.Elaboration, "x"),
+		)
+	})
+
+	t.Run("missing argument label", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let x = "abc".matches("a*")
+	    `)
+
+		errs := RequireCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.MissingArgumentLabelError{}, errs[0])
+	})
+}
+
+func TestCheckStringToBytes(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t, `
+
+      let x = "abc".toBytes()
+	`)
+
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		sema.ByteArrayType,
+		RequireGlobalValue(t, checker.Elaboration, "x"),
+	)
+}
+
+func TestCheckStringRepeat(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t, `
+      let x = "abc".repeat(3)
+	`)
+
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		sema.StringType,
+		RequireGlobalValue(t, checker.Elaboration, "x"),
+	)
+}
+
+func TestCheckStringRepeatTypeMismatch(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t, `
+      let x = "abc".repeat("3")
+	`)
+
+	errs := RequireCheckerErrors(t, err, 1)
+
+	assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+}
+
+func TestCheckStringCenter(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let x = "abc".center(width: 7, with: "*")
+        `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			sema.StringType,
+			RequireGlobalValue(t, checker.Elaboration, "x"),
+		)
+	})
+
+	t.Run("missing label", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let x = "abc".center(width: 7, "*")
+        `)
+
+		errs := RequireCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.MissingArgumentLabelError{}, errs[0])
+	})
+
+	t.Run("invalid fill", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let x = "abc".center(width: 7, with: "**")
+        `)
+
+		errs := RequireCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InvalidCharacterLiteralError{}, errs[0])
+	})
+}
+
+func TestCheckStringChunked(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t, `
+      let x = "abcdef".chunked(size: 2)
+	`)
+
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		&sema.VariableSizedType{
+			Type: sema.StringType,
+		},
+		RequireGlobalValue(t, checker.Elaboration, "x"),
+	)
+}
+
+func TestCheckStringChunkedMissingLabel(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t, `
+      let x = "abcdef".chunked(2)
+	`)
+
+	errs := RequireCheckerErrors(t, err, 1)
+
+	assert.IsType(t, &sema.MissingArgumentLabelError{}, errs[0])
+}
+
+func TestCheckStringTokens(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+          let x = "a,b".tokens(separator: ",")
+          let y = x.next()
+        `)
+
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			sema.StringSplitIteratorType,
+			RequireGlobalValue(t, checker.Elaboration, "x"),
+		)
+		assert.Equal(t,
+			&sema.OptionalType{
+				Type: sema.StringType,
+			},
+			RequireGlobalValue(t, checker.Elaboration, "y"),
+		)
+	})
+
+	t.Run("for-in", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun test() {
+              let iterator: StringSplitIterator = "a,b".tokens(separator: ",")
+              for part in iterator {
+                  let s: String = part
+              }
+          }
+        `)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("missing argument label", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          let x = "a,b".tokens(",")
+        `)
+
+		errs := RequireCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.MissingArgumentLabelError{}, errs[0])
+	})
+
+	t.Run("not storable", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+          fun test(account: auth(Storage) &Account) {
+              account.storage.save("a,b".tokens(separator: ","), to: /storage/tokens)
+          }
+        `)
+
+		errs := RequireCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+}
+
 func TestCheckStringToLower(t *testing.T) {
 
 	t.Parallel()
@@ -356,6 +621,60 @@ func TestCheckStringToLower(t *testing.T) {
 	)
 }
 
+func TestCheckStringTrimStartAndTrimEnd(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t, `
+        let x = " abc ".trimStart()
+        let y = " abc ".trimEnd()
+	`)
+
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		sema.StringType,
+		RequireGlobalValue(t, checker.Elaboration, "x"),
+	)
+	assert.Equal(t,
+		sema.StringType,
+		RequireGlobalValue(t, checker.Elaboration, "y"),
+	)
+}
+
+func TestCheckStringCodePoints(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t, `
+		let codePoints = "abc".codePoints
+		let s = String.fromCodePoints(codePoints)
+	`)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		sema.UInt32ArrayType,
+		RequireGlobalValue(t, checker.Elaboration, "codePoints"),
+	)
+	assert.Equal(t,
+		sema.StringType,
+		RequireGlobalValue(t, checker.Elaboration, "s"),
+	)
+}
+
+func TestCheckStringFromCodePointsTypeMismatch(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t, `
+		let s = String.fromCodePoints([1 as UInt8])
+	`)
+
+	errs := RequireCheckerErrors(t, err, 1)
+
+	assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+}
+
 func TestCheckStringJoin(t *testing.T) {
 
 	t.Parallel()
@@ -371,6 +690,145 @@ func TestCheckStringJoin(t *testing.T) {
 	)
 }
 
+func TestCheckStringConcatAll(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+		    let s = String.concatAll(["👪", "❤️", "Abc"])
+	    `)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			sema.StringType,
+			RequireGlobalValue(t, checker.Elaboration, "s"),
+		)
+	})
+
+	t.Run("type mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+		    let s = String.concatAll([1])
+	    `)
+
+		errs := RequireCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+}
+
+func TestCheckStringFormat(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t, `
+		let s = String.format("{0} {1}", arguments: [1, true])
+	`)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		sema.StringType,
+		RequireGlobalValue(t, checker.Elaboration, "s"),
+	)
+}
+
+func TestCheckStringFormatMissingArgumentLabel(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t, `
+		let s = String.format("{0}", [1])
+	`)
+
+	errs := RequireCheckerErrors(t, err, 1)
+
+	assert.IsType(t, &sema.MissingArgumentLabelError{}, errs[0])
+}
+
+func TestCheckStringToInt(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+		  let a = String.toInt("-42")
+		  let b = String.toUInt("42")
+		`)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.OptionalType{Type: sema.IntType},
+			RequireGlobalValue(t, checker.Elaboration, "a"),
+		)
+		assert.Equal(t,
+			&sema.OptionalType{Type: sema.UIntType},
+			RequireGlobalValue(t, checker.Elaboration, "b"),
+		)
+	})
+
+	t.Run("wrong argument type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+		  let a = String.toInt(42)
+		  let b = String.toUInt(42)
+		`)
+
+		errs := RequireCheckerErrors(t, err, 2)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[1])
+	})
+}
+
+func TestCheckStringToFixedPoint(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+		  let a = String.toFix64("-1.5")
+		  let b = String.toUFix64("1.5")
+		`)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			&sema.OptionalType{Type: sema.Fix64Type},
+			RequireGlobalValue(t, checker.Elaboration, "a"),
+		)
+		assert.Equal(t,
+			&sema.OptionalType{Type: sema.UFix64Type},
+			RequireGlobalValue(t, checker.Elaboration, "b"),
+		)
+	})
+
+	t.Run("wrong argument type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+		  let a = String.toFix64(1.5)
+		  let b = String.toUFix64(1.5)
+		`)
+
+		errs := RequireCheckerErrors(t, err, 2)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[1])
+	})
+}
+
 func TestCheckStringJoinTypeMismatchStrs(t *testing.T) {
 
 	t.Parallel()
@@ -427,6 +885,23 @@ func TestCheckStringSplit(t *testing.T) {
 	)
 }
 
+func TestCheckStringSplitIndices(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t, `
+		let s = "👪.❤️.Abc".splitIndices(separator: ".")
+	`)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		&sema.VariableSizedType{
+			Type: sema.IntType,
+		},
+		RequireGlobalValue(t, checker.Elaboration, "s"),
+	)
+}
+
 func TestCheckStringSplitTypeMismatchSeparator(t *testing.T) {
 
 	t.Parallel()
@@ -453,6 +928,36 @@ func TestCheckStringSplitTypeMissingArgumentLabelSeparator(t *testing.T) {
 	assert.IsType(t, &sema.MissingArgumentLabelError{}, errs[0])
 }
 
+func TestCheckStringSplitWithLimit(t *testing.T) {
+
+	t.Parallel()
+
+	checker, err := ParseAndCheck(t, `
+		let s = "👪.❤️.Abc".split(separator: ".", limit: 2)
+	`)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		&sema.VariableSizedType{
+			Type: sema.StringType,
+		},
+		RequireGlobalValue(t, checker.Elaboration, "s"),
+	)
+}
+
+func TestCheckStringSplitTypeMismatchLimit(t *testing.T) {
+
+	t.Parallel()
+
+	_, err := ParseAndCheck(t, `
+		let s = "Abc:1".split(separator: ":", limit: "2")
+	`)
+
+	errs := RequireCheckerErrors(t, err, 1)
+
+	assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+}
+
 func TestCheckStringReplaceAll(t *testing.T) {
 
 	t.Parallel()
@@ -536,6 +1041,39 @@ func TestCheckStringReplaceAllTypeMissingArgumentLabelWith(t *testing.T) {
 	assert.IsType(t, &sema.MissingArgumentLabelError{}, errs[0])
 }
 
+func TestCheckStringReplaceAllCaseInsensitive(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		checker, err := ParseAndCheck(t, `
+		  let s = "xAbC".replaceAll(of: "abc", with: "-", caseInsensitive: true)
+		`)
+		require.NoError(t, err)
+
+		assert.Equal(t,
+			sema.StringType,
+			RequireGlobalValue(t, checker.Elaboration, "s"),
+		)
+	})
+
+	t.Run("wrong argument type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+		  let s = "xAbC".replaceAll(of: "abc", with: "-", caseInsensitive: "yes")
+		`)
+
+		errs := RequireCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+}
+
 func TestCheckStringContains(t *testing.T) {
 
 	t.Parallel()
@@ -652,6 +1190,91 @@ func TestCheckStringIndex(t *testing.T) {
 
 		require.NoError(t, err)
 	})
+
+	t.Run("valid, from", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+		  let a = "abcdef"
+		  let x: Int = a.index(of: "bc", from: 2)
+		`)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("wrong from argument type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+		  let a = "abcdef"
+		  let x: Int = a.index(of: "bc", from: "2")
+		`)
+
+		errs := RequireCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+}
+
+func TestCheckStringLastIndex(t *testing.T) {
+
+	t.Parallel()
+
+	t.Run("missing argument", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+		  let a = "abcdef"
+		  let x: Int = a.lastIndex()
+		`)
+
+		errs := RequireCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.InsufficientArgumentsError{}, errs[0])
+	})
+
+	t.Run("wrong argument type", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+		  let a = "abcdef"
+		  let x: Int = a.lastIndex(of: 1)
+		`)
+
+		errs := RequireCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.TypeMismatchError{}, errs[0])
+	})
+
+	t.Run("wrong argument label", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+		  let a = "abcdef"
+		  let x: Int = a.lastIndex(foo: "bc")
+		`)
+
+		errs := RequireCheckerErrors(t, err, 1)
+
+		assert.IsType(t, &sema.IncorrectArgumentLabelError{}, errs[0])
+	})
+
+	t.Run("valid", func(t *testing.T) {
+
+		t.Parallel()
+
+		_, err := ParseAndCheck(t, `
+		  let a = "abcdef"
+		  let x: Int = a.lastIndex(of: "bc")
+		`)
+
+		require.NoError(t, err)
+	})
 }
 
 func TestCheckStringCount(t *testing.T) {
@@ -79,6 +79,24 @@ const StringTypeFromCharactersFunctionDocString = `
 Returns a string from the given array of characters
 `
 
+var StringTypeFromCodePointsFunctionType = NewSimpleFunctionType(
+	FunctionPurityView,
+	[]Parameter{
+		{
+			Label:          ArgumentLabelNotRequired,
+			Identifier:     "codePoints",
+			TypeAnnotation: UInt32ArrayTypeAnnotation,
+		},
+	},
+	StringTypeAnnotation,
+)
+
+const StringTypeFromCodePointsFunctionName = "fromCodePoints"
+const StringTypeFromCodePointsFunctionDocString = `
+Returns a string from the given array of Unicode scalar values.
+Fails if any of the values is not a valid Unicode scalar value, e.g. a surrogate
+`
+
 var StringTypeJoinFunctionType = NewSimpleFunctionType(
 	FunctionPurityView,
 	[]Parameter{
@@ -102,24 +120,244 @@ const StringTypeJoinFunctionDocString = `
 Returns a string after joining the array of strings with the provided separator.
 `
 
-var StringTypeSplitFunctionType = NewSimpleFunctionType(
+var StringTypeConcatAllFunctionType = NewSimpleFunctionType(
 	FunctionPurityView,
 	[]Parameter{
 		{
-			Identifier:     "separator",
+			Label:      ArgumentLabelNotRequired,
+			Identifier: "strings",
+			TypeAnnotation: NewTypeAnnotation(&VariableSizedType{
+				Type: StringType,
+			}),
+		},
+	},
+	StringTypeAnnotation,
+)
+
+const StringTypeConcatAllFunctionName = "concatAll"
+const StringTypeConcatAllFunctionDocString = `
+Returns a string after concatenating the array of strings, without a separator.
+
+The result is the same as concatenating the strings one after another using ` + "`concat`" + `,
+but without creating intermediate strings
+`
+
+var StringTypeFormatFunctionType = NewSimpleFunctionType(
+	FunctionPurityView,
+	[]Parameter{
+		{
+			Label:          ArgumentLabelNotRequired,
+			Identifier:     "format",
+			TypeAnnotation: StringTypeAnnotation,
+		},
+		{
+			Identifier: "arguments",
+			TypeAnnotation: NewTypeAnnotation(&VariableSizedType{
+				Type: AnyStructType,
+			}),
+		},
+	},
+	StringTypeAnnotation,
+)
+
+const StringTypeFormatFunctionName = "format"
+const StringTypeFormatFunctionDocString = `
+Returns a string after replacing the positional placeholders {0}, {1}, ... in the format string
+with the string representations of the corresponding arguments. Literal braces are written as {{ and }}.
+`
+
+var StringTypeToIntFunctionType = NewSimpleFunctionType(
+	FunctionPurityView,
+	[]Parameter{
+		{
+			Label:          ArgumentLabelNotRequired,
+			Identifier:     "string",
 			TypeAnnotation: StringTypeAnnotation,
 		},
 	},
 	NewTypeAnnotation(
-		&VariableSizedType{
-			Type: StringType,
+		&OptionalType{
+			Type: IntType,
+		},
+	),
+)
+
+const StringTypeToIntFunctionName = "toInt"
+const StringTypeToIntFunctionDocString = `
+Attempts to parse the given string as a decimal integer, with an optional leading + or - sign.
+Returns nil if the string is malformed, e.g. if it is empty or contains leading or trailing spaces
+`
+
+var StringTypeToUIntFunctionType = NewSimpleFunctionType(
+	FunctionPurityView,
+	[]Parameter{
+		{
+			Label:          ArgumentLabelNotRequired,
+			Identifier:     "string",
+			TypeAnnotation: StringTypeAnnotation,
+		},
+	},
+	NewTypeAnnotation(
+		&OptionalType{
+			Type: UIntType,
+		},
+	),
+)
+
+const StringTypeToUIntFunctionName = "toUInt"
+const StringTypeToUIntFunctionDocString = `
+Attempts to parse the given string as a decimal unsigned integer, with an optional leading + sign.
+Returns nil if the string is malformed, e.g. if it is empty or contains leading or trailing spaces,
+or if the value is negative
+`
+
+var StringTypeToFix64FunctionType = NewSimpleFunctionType(
+	FunctionPurityView,
+	[]Parameter{
+		{
+			Label:          ArgumentLabelNotRequired,
+			Identifier:     "string",
+			TypeAnnotation: StringTypeAnnotation,
+		},
+	},
+	NewTypeAnnotation(
+		&OptionalType{
+			Type: Fix64Type,
+		},
+	),
+)
+
+const StringTypeToFix64FunctionName = "toFix64"
+const StringTypeToFix64FunctionDocString = `
+Attempts to parse the given string as a decimal fixed-point number, with an optional leading - sign, e.g. -1.5.
+The string must contain a decimal point, and at most 8 fractional digits, like fixed-point literals.
+Returns nil if the string is malformed or if the value is out of range
+`
+
+var StringTypeToUFix64FunctionType = NewSimpleFunctionType(
+	FunctionPurityView,
+	[]Parameter{
+		{
+			Label:          ArgumentLabelNotRequired,
+			Identifier:     "string",
+			TypeAnnotation: StringTypeAnnotation,
+		},
+	},
+	NewTypeAnnotation(
+		&OptionalType{
+			Type: UFix64Type,
 		},
 	),
 )
 
+const StringTypeToUFix64FunctionName = "toUFix64"
+const StringTypeToUFix64FunctionDocString = `
+Attempts to parse the given string as an unsigned decimal fixed-point number, e.g. 1.5.
+The string must contain a decimal point, and at most 8 fractional digits, like fixed-point literals.
+Returns nil if the string is malformed or if the value is out of range
+`
+
+var StringTypeSplitFunctionType = func() *FunctionType {
+	functionType := NewSimpleFunctionType(
+		FunctionPurityView,
+		[]Parameter{
+			{
+				Identifier:     "separator",
+				TypeAnnotation: StringTypeAnnotation,
+			},
+			{
+				Identifier:     "limit",
+				TypeAnnotation: IntTypeAnnotation,
+			},
+		},
+		NewTypeAnnotation(
+			&VariableSizedType{
+				Type: StringType,
+			},
+		),
+	)
+	// `limit` parameter is optional
+	functionType.Arity = &Arity{Min: 1, Max: 2}
+	return functionType
+}()
+
 const StringTypeSplitFunctionName = "split"
 const StringTypeSplitFunctionDocString = `
 Returns a variable-sized array of strings after splitting the string on the delimiter.
+
+If a positive limit is given, the result has at most limit elements:
+after limit - 1 splits, the remainder of the string is returned as the last element.
+A limit of zero or less splits the string fully, like no limit
+`
+
+var StringTypeSplitIndicesFunctionType = NewSimpleFunctionType(
+	FunctionPurityView,
+	[]Parameter{
+		{
+			Identifier:     "separator",
+			TypeAnnotation: StringTypeAnnotation,
+		},
+	},
+	NewTypeAnnotation(
+		&VariableSizedType{
+			Type: IntType,
+		},
+	),
+)
+
+const StringTypeSplitIndicesFunctionName = "splitIndices"
+
+const stringTypeSplitIndicesFunctionDocString = `
+Returns a variable-sized array of the character indices at which the substrings start,
+when splitting the string on the separator.
+
+The substrings are the same as the elements returned by split, and can be obtained using slice:
+each substring starts at its index, and ends before the separator which precedes the next index,
+or at the end of the string for the last substring
+`
+
+var StringTypeTokensFunctionType = NewSimpleFunctionType(
+	FunctionPurityView,
+	[]Parameter{
+		{
+			Identifier:     "separator",
+			TypeAnnotation: StringTypeAnnotation,
+		},
+	},
+	NewTypeAnnotation(StringSplitIteratorType),
+)
+
+const StringTypeTokensFunctionName = "tokens"
+
+const stringTypeTokensFunctionDocString = `
+Returns an iterator over the substrings of the string, split on the separator.
+
+The substrings are the same as the elements returned by split, but they are produced lazily, one at a time,
+either by calling next on the iterator, or by iterating over the iterator in a for-in loop
+`
+
+var StringTypeChunkedFunctionType = NewSimpleFunctionType(
+	FunctionPurityView,
+	[]Parameter{
+		{
+			Identifier:     "size",
+			TypeAnnotation: IntTypeAnnotation,
+		},
+	},
+	NewTypeAnnotation(
+		&VariableSizedType{
+			Type: StringType,
+		},
+	),
+)
+
+const StringTypeChunkedFunctionName = "chunked"
+
+const stringTypeChunkedFunctionDocString = `
+Returns a variable-sized array of strings, where each element contains the given number of characters of the string,
+except possibly the last element, which contains the remaining characters.
+
+The size must be positive
 `
 
 // StringType represents the string type
@@ -159,6 +397,18 @@ func init() {
 				StringTypeConcatFunctionType,
 				stringTypeConcatFunctionDocString,
 			),
+			NewUnmeteredPublicFunctionMember(
+				t,
+				StringTypeRepeatFunctionName,
+				StringTypeRepeatFunctionType,
+				stringTypeRepeatFunctionDocString,
+			),
+			NewUnmeteredPublicFunctionMember(
+				t,
+				StringTypeCenterFunctionName,
+				StringTypeCenterFunctionType,
+				stringTypeCenterFunctionDocString,
+			),
 			NewUnmeteredPublicFunctionMember(
 				t,
 				StringTypeSliceFunctionName,
@@ -177,24 +427,84 @@ func init() {
 				ByteArrayType,
 				stringTypeUtf8FieldDocString,
 			),
+			NewUnmeteredPublicFunctionMember(
+				t,
+				StringTypeToBytesFunctionName,
+				StringTypeToBytesFunctionType,
+				stringTypeToBytesFunctionDocString,
+			),
+			NewUnmeteredPublicConstantFieldMember(
+				t,
+				StringTypeCodePointsFieldName,
+				UInt32ArrayType,
+				stringTypeCodePointsFieldDocString,
+			),
 			NewUnmeteredPublicConstantFieldMember(
 				t,
 				StringTypeLengthFieldName,
 				IntType,
 				stringTypeLengthFieldDocString,
 			),
+			NewUnmeteredPublicConstantFieldMember(
+				t,
+				StringTypeByteLengthFieldName,
+				IntType,
+				stringTypeByteLengthFieldDocString,
+			),
+			NewUnmeteredPublicConstantFieldMember(
+				t,
+				StringTypeScalarCountFieldName,
+				IntType,
+				stringTypeScalarCountFieldDocString,
+			),
+			NewUnmeteredPublicConstantFieldMember(
+				t,
+				StringTypeIsASCIIFieldName,
+				BoolType,
+				stringTypeIsASCIIFieldDocString,
+			),
 			NewUnmeteredPublicFunctionMember(
 				t,
 				StringTypeToLowerFunctionName,
 				StringTypeToLowerFunctionType,
 				stringTypeToLowerFunctionDocString,
 			),
+			NewUnmeteredPublicFunctionMember(
+				t,
+				StringTypeTrimStartFunctionName,
+				StringTypeTrimStartFunctionType,
+				stringTypeTrimStartFunctionDocString,
+			),
+			NewUnmeteredPublicFunctionMember(
+				t,
+				StringTypeTrimEndFunctionName,
+				StringTypeTrimEndFunctionType,
+				stringTypeTrimEndFunctionDocString,
+			),
 			NewUnmeteredPublicFunctionMember(
 				t,
 				StringTypeSplitFunctionName,
 				StringTypeSplitFunctionType,
 				StringTypeSplitFunctionDocString,
 			),
+			NewUnmeteredPublicFunctionMember(
+				t,
+				StringTypeSplitIndicesFunctionName,
+				StringTypeSplitIndicesFunctionType,
+				stringTypeSplitIndicesFunctionDocString,
+			),
+			NewUnmeteredPublicFunctionMember(
+				t,
+				StringTypeTokensFunctionName,
+				StringTypeTokensFunctionType,
+				stringTypeTokensFunctionDocString,
+			),
+			NewUnmeteredPublicFunctionMember(
+				t,
+				StringTypeChunkedFunctionName,
+				StringTypeChunkedFunctionType,
+				stringTypeChunkedFunctionDocString,
+			),
 			NewUnmeteredPublicFunctionMember(
 				t,
 				StringTypeReplaceAllFunctionName,
@@ -207,12 +517,24 @@ func init() {
 				StringTypeContainsFunctionType,
 				stringTypeContainsFunctionDocString,
 			),
+			NewUnmeteredPublicFunctionMember(
+				t,
+				StringTypeMatchesFunctionName,
+				StringTypeMatchesFunctionType,
+				stringTypeMatchesFunctionDocString,
+			),
 			NewUnmeteredPublicFunctionMember(
 				t,
 				StringTypeIndexFunctionName,
 				StringTypeIndexFunctionType,
 				stringTypeIndexFunctionDocString,
 			),
+			NewUnmeteredPublicFunctionMember(
+				t,
+				StringTypeLastIndexFunctionName,
+				StringTypeLastIndexFunctionType,
+				stringTypeLastIndexFunctionDocString,
+			),
 			NewUnmeteredPublicFunctionMember(
 				t,
 				StringTypeCountFunctionName,
@@ -241,6 +563,52 @@ const stringTypeConcatFunctionDocString = `
 Returns a new string which contains the given string concatenated to the end of the original string, but does not modify the original string
 `
 
+var StringTypeRepeatFunctionType = NewSimpleFunctionType(
+	FunctionPurityView,
+	[]Parameter{
+		{
+			Label:          ArgumentLabelNotRequired,
+			Identifier:     "count",
+			TypeAnnotation: IntTypeAnnotation,
+		},
+	},
+	StringTypeAnnotation,
+)
+
+const StringTypeRepeatFunctionName = "repeat"
+
+const stringTypeRepeatFunctionDocString = `
+Returns a new string which contains the original string repeated the given number of times, but does not modify the original string.
+
+The count must not be negative
+`
+
+var StringTypeCenterFunctionType = NewSimpleFunctionType(
+	FunctionPurityView,
+	[]Parameter{
+		{
+			Identifier:     "width",
+			TypeAnnotation: IntTypeAnnotation,
+		},
+		{
+			Label:          "with",
+			Identifier:     "character",
+			TypeAnnotation: NewTypeAnnotation(CharacterType),
+		},
+	},
+	StringTypeAnnotation,
+)
+
+const StringTypeCenterFunctionName = "center"
+
+const stringTypeCenterFunctionDocString = `
+Returns a new string which contains the original string centered in a string of the given width,
+i.e. padded on both sides with the given character, so the result has at least the given number of characters.
+If the padding can't be distributed evenly, the extra character is added on the right.
+If the original string already has at least the given number of characters, it is returned unchanged.
+It does not modify the original string
+`
+
 var StringTypeSliceFunctionType = NewSimpleFunctionType(
 	FunctionPurityView,
 	[]Parameter{
@@ -261,7 +629,9 @@ const StringTypeSliceFunctionName = "slice"
 const stringTypeSliceFunctionDocString = `
 Returns a new string containing the slice of the characters in the given string from start index ` + "`from`" + ` up to, but not including, the end index ` + "`upTo`" + `.
 
-This function creates a new string whose length is ` + "`upTo - from`" + `.
+Negative indices are offsets from the end of the string, e.g. ` + "`-1`" + ` is the index of the last character.
+
+This function creates a new string whose length is ` + "`upTo - from`" + `, after negative indices are normalized.
 It does not modify the original string.
 If either of the parameters are out of the bounds of the string, or the indices are invalid (` + "`from > upTo`" + `), then the function will fail
 `
@@ -284,31 +654,65 @@ const stringTypeContainsFunctionDocString = `
 Returns true if this string contains the given other string as a substring.
 `
 
-var StringTypeIndexFunctionType = NewSimpleFunctionType(
+var StringTypeMatchesFunctionType = NewSimpleFunctionType(
 	FunctionPurityView,
 	[]Parameter{
 		{
-			Label:          "of",
-			Identifier:     "other",
+			Identifier:     "pattern",
 			TypeAnnotation: StringTypeAnnotation,
 		},
 	},
-	IntTypeAnnotation,
+	BoolTypeAnnotation,
 )
 
+const StringTypeMatchesFunctionName = "matches"
+
+const stringTypeMatchesFunctionDocString = `
+Returns true if this whole string matches the given wildcard pattern.
+
+The wildcard ` + "`*`" + ` matches any sequence of characters, including the empty sequence,
+and the wildcard ` + "`?`" + ` matches exactly one character.
+All other characters match themselves.
+A wildcard can be matched literally by escaping it with a backslash, i.e. ` + "`\\*`" + ` and ` + "`\\?`" + `
+`
+
+var StringTypeIndexFunctionType = func() *FunctionType {
+	functionType := NewSimpleFunctionType(
+		FunctionPurityView,
+		[]Parameter{
+			{
+				Label:          "of",
+				Identifier:     "other",
+				TypeAnnotation: StringTypeAnnotation,
+			},
+			{
+				Identifier:     "from",
+				TypeAnnotation: IntTypeAnnotation,
+			},
+		},
+		IntTypeAnnotation,
+	)
+	// `from` parameter is optional
+	functionType.Arity = &Arity{Min: 1, Max: 2}
+	return functionType
+}()
+
 const StringTypeIndexFunctionName = "index"
 
 const stringTypeIndexFunctionDocString = `
 Returns the index within this string of the first occurrence of the given substring.
 
+If a start index is given, the search starts at that index.
+The start index must not be negative, and must not be greater than the length of the string.
+
 If the substring is not found, the function returns -1.
 `
 
-var StringTypeCountFunctionType = NewSimpleFunctionType(
+var StringTypeLastIndexFunctionType = NewSimpleFunctionType(
 	FunctionPurityView,
 	[]Parameter{
 		{
-			Label:          ArgumentLabelNotRequired,
+			Label:          "of",
 			Identifier:     "other",
 			TypeAnnotation: StringTypeAnnotation,
 		},
@@ -316,36 +720,70 @@ var StringTypeCountFunctionType = NewSimpleFunctionType(
 	IntTypeAnnotation,
 )
 
-const StringTypeCountFunctionName = "count"
+const StringTypeLastIndexFunctionName = "lastIndex"
 
-const stringTypeCountFunctionDocString = `
-Returns the number of non-overlapping instances of the given substring in this string.
+const stringTypeLastIndexFunctionDocString = `
+Returns the index within this string of the last occurrence of the given substring.
 
-If the given substring is an empty string, the function returns 1 + the number of characters in this string.
+If the given substring is an empty string, the function returns the length of this string.
+
+If the substring is not found, the function returns -1.
 `
 
-var StringTypeReplaceAllFunctionType = NewSimpleFunctionType(
+var StringTypeCountFunctionType = NewSimpleFunctionType(
 	FunctionPurityView,
 	[]Parameter{
 		{
-			Label:          "of",
-			Identifier:     "old",
-			TypeAnnotation: StringTypeAnnotation,
-		},
-		{
-			Label:          "with",
-			Identifier:     "replacement",
+			Label:          ArgumentLabelNotRequired,
+			Identifier:     "other",
 			TypeAnnotation: StringTypeAnnotation,
 		},
 	},
-	StringTypeAnnotation,
+	IntTypeAnnotation,
 )
 
+const StringTypeCountFunctionName = "count"
+
+const stringTypeCountFunctionDocString = `
+Returns the number of non-overlapping instances of the given substring in this string.
+
+If the given substring is an empty string, the function returns 1 + the number of characters in this string.
+`
+
+var StringTypeReplaceAllFunctionType = func() *FunctionType {
+	functionType := NewSimpleFunctionType(
+		FunctionPurityView,
+		[]Parameter{
+			{
+				Label:          "of",
+				Identifier:     "old",
+				TypeAnnotation: StringTypeAnnotation,
+			},
+			{
+				Label:          "with",
+				Identifier:     "replacement",
+				TypeAnnotation: StringTypeAnnotation,
+			},
+			{
+				Identifier:     "caseInsensitive",
+				TypeAnnotation: BoolTypeAnnotation,
+			},
+		},
+		StringTypeAnnotation,
+	)
+	// `caseInsensitive` parameter is optional
+	functionType.Arity = &Arity{Min: 2, Max: 3}
+	return functionType
+}()
+
 const StringTypeReplaceAllFunctionName = "replaceAll"
 const StringTypeReplaceAllFunctionDocString = `
 Returns a new string after replacing all the occurrences of parameter ` + "`of` with the parameter `with`" + `.
 
 If ` + "`with`" + ` is empty, it matches at the beginning of the string and after each UTF-8 sequence, yielding k+1 replacements for a string of length k.
+
+If ` + "`caseInsensitive`" + ` is true, occurrences are matched ignoring case, using the same rules as ` + "`toLower`" + `.
+The replacement is inserted verbatim.
 `
 
 // ByteArrayType represents the type [UInt8]
@@ -362,6 +800,13 @@ var ByteArrayArrayType = &VariableSizedType{
 
 var ByteArrayArrayTypeAnnotation = NewTypeAnnotation(ByteArrayArrayType)
 
+// UInt32ArrayType represents the type [UInt32]
+var UInt32ArrayType = &VariableSizedType{
+	Type: UInt32Type,
+}
+
+var UInt32ArrayTypeAnnotation = NewTypeAnnotation(UInt32ArrayType)
+
 var StringTypeDecodeHexFunctionType = NewSimpleFunctionType(
 	FunctionPurityView,
 	nil,
@@ -380,7 +825,35 @@ If the string is malformed, the program aborts
 const StringTypeLengthFieldName = "length"
 
 const stringTypeLengthFieldDocString = `
-The number of characters in the string
+The number of characters in the string.
+
+Characters are grapheme clusters, so a character may consist of multiple Unicode scalar values,
+e.g. a letter followed by a combining mark.
+See the ` + "`byteLength`" + ` and ` + "`scalarCount`" + ` fields for the number of bytes and Unicode scalar values
+`
+
+const StringTypeByteLengthFieldName = "byteLength"
+
+const stringTypeByteLengthFieldDocString = `
+The number of bytes of the UTF-8 encoding of the string.
+
+The result is the same as the length of the ` + "`utf8`" + ` field
+`
+
+const StringTypeScalarCountFieldName = "scalarCount"
+
+const stringTypeScalarCountFieldDocString = `
+The number of Unicode scalar values in the string.
+
+The result is the same as the length of the ` + "`codePoints`" + ` field
+`
+
+const StringTypeIsASCIIFieldName = "isASCII"
+
+const stringTypeIsASCIIFieldDocString = `
+Is true if the string only consists of ASCII characters, false otherwise.
+
+The empty string is considered to be ASCII
 `
 
 const StringTypeUtf8FieldName = "utf8"
@@ -389,6 +862,26 @@ const stringTypeUtf8FieldDocString = `
 The byte array of the UTF-8 encoding
 `
 
+var StringTypeToBytesFunctionType = NewSimpleFunctionType(
+	FunctionPurityView,
+	nil,
+	ByteArrayTypeAnnotation,
+)
+
+const StringTypeToBytesFunctionName = "toBytes"
+
+const stringTypeToBytesFunctionDocString = `
+Returns the byte array of the UTF-8 encoding of the string.
+
+The result is the same as the ` + "`utf8`" + ` field
+`
+
+const StringTypeCodePointsFieldName = "codePoints"
+
+const stringTypeCodePointsFieldDocString = `
+The Unicode scalar values of the string
+`
+
 var StringTypeToLowerFunctionType = NewSimpleFunctionType(
 	FunctionPurityView,
 	nil,
@@ -401,6 +894,34 @@ const stringTypeToLowerFunctionDocString = `
 Returns the string with upper case letters replaced with lowercase
 `
 
+var StringTypeTrimStartFunctionType = NewSimpleFunctionType(
+	FunctionPurityView,
+	nil,
+	StringTypeAnnotation,
+)
+
+const StringTypeTrimStartFunctionName = "trimStart"
+
+const stringTypeTrimStartFunctionDocString = `
+Returns the string with leading whitespace removed.
+
+Characters are only removed if they consist of Unicode whitespace only
+`
+
+var StringTypeTrimEndFunctionType = NewSimpleFunctionType(
+	FunctionPurityView,
+	nil,
+	StringTypeAnnotation,
+)
+
+const StringTypeTrimEndFunctionName = "trimEnd"
+
+const stringTypeTrimEndFunctionDocString = `
+Returns the string with trailing whitespace removed.
+
+Characters are only removed if they consist of Unicode whitespace only
+`
+
 const stringFunctionDocString = "Creates an empty string"
 
 var StringFunctionType = func() *FunctionType {
@@ -453,6 +974,13 @@ var StringFunctionType = func() *FunctionType {
 		StringTypeFromCharactersFunctionDocString,
 	))
 
+	addMember(NewUnmeteredPublicFunctionMember(
+		functionType,
+		StringTypeFromCodePointsFunctionName,
+		StringTypeFromCodePointsFunctionType,
+		StringTypeFromCodePointsFunctionDocString,
+	))
+
 	addMember(NewUnmeteredPublicFunctionMember(
 		functionType,
 		StringTypeJoinFunctionName,
@@ -460,6 +988,48 @@ var StringFunctionType = func() *FunctionType {
 		StringTypeJoinFunctionDocString,
 	))
 
+	addMember(NewUnmeteredPublicFunctionMember(
+		functionType,
+		StringTypeConcatAllFunctionName,
+		StringTypeConcatAllFunctionType,
+		StringTypeConcatAllFunctionDocString,
+	))
+
+	addMember(NewUnmeteredPublicFunctionMember(
+		functionType,
+		StringTypeFormatFunctionName,
+		StringTypeFormatFunctionType,
+		StringTypeFormatFunctionDocString,
+	))
+
+	addMember(NewUnmeteredPublicFunctionMember(
+		functionType,
+		StringTypeToIntFunctionName,
+		StringTypeToIntFunctionType,
+		StringTypeToIntFunctionDocString,
+	))
+
+	addMember(NewUnmeteredPublicFunctionMember(
+		functionType,
+		StringTypeToUIntFunctionName,
+		StringTypeToUIntFunctionType,
+		StringTypeToUIntFunctionDocString,
+	))
+
+	addMember(NewUnmeteredPublicFunctionMember(
+		functionType,
+		StringTypeToFix64FunctionName,
+		StringTypeToFix64FunctionType,
+		StringTypeToFix64FunctionDocString,
+	))
+
+	addMember(NewUnmeteredPublicFunctionMember(
+		functionType,
+		StringTypeToUFix64FunctionName,
+		StringTypeToUFix64FunctionType,
+		StringTypeToUFix64FunctionDocString,
+	))
+
 	BaseValueActivation.Set(
 		typeName,
 		baseFunctionVariable(
@@ -4170,6 +4170,7 @@ func init() {
 			StorageCapabilityControllerType,
 			AccountCapabilityControllerType,
 			DeploymentResultType,
+			StringSplitIteratorType,
 			HashableStructType,
 			&InclusiveRangeType{},
 			StructStringerType,
@@ -9469,6 +9470,7 @@ func init() {
 		SignatureAlgorithmType,
 		AccountType,
 		DeploymentResultType,
+		StringSplitIteratorType,
 	}
 
 	extractNativeTypes(compositeTypes)
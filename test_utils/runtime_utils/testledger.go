@@ -193,3 +193,109 @@ func NewTestLedgerWithData(
 		},
 	}
 }
+
+type LedgerAccessKind uint8
+
+const (
+	LedgerAccessKindUnknown LedgerAccessKind = iota
+	LedgerAccessKindValueExists
+	LedgerAccessKindGetValue
+	LedgerAccessKindSetValue
+)
+
+func (k LedgerAccessKind) String() string {
+	switch k {
+	case LedgerAccessKindValueExists:
+		return "ValueExists"
+	case LedgerAccessKindGetValue:
+		return "GetValue"
+	case LedgerAccessKindSetValue:
+		return "SetValue"
+	}
+	return "Unknown"
+}
+
+type LedgerAccess struct {
+	Kind       LedgerAccessKind
+	Owner, Key []byte
+	// Value is the value read or written.
+	// It is nil for ValueExists accesses.
+	Value []byte
+}
+
+// RecordingTestLedger is a test ledger which records
+// the register accesses (reads and writes) in the order they occurred.
+type RecordingTestLedger struct {
+	TestLedger
+	accesses []LedgerAccess
+}
+
+func NewRecordingTestLedger() *RecordingTestLedger {
+
+	ledger := &RecordingTestLedger{}
+
+	ledger.TestLedger = NewTestLedger(
+		func(owner, key, value []byte) {
+			ledger.record(LedgerAccessKindGetValue, owner, key, value)
+		},
+		func(owner, key, value []byte) {
+			ledger.record(LedgerAccessKindSetValue, owner, key, value)
+		},
+	)
+
+	onValueExists := ledger.OnValueExists
+	ledger.OnValueExists = func(owner, key []byte) (bool, error) {
+		ledger.record(LedgerAccessKindValueExists, owner, key, nil)
+		return onValueExists(owner, key)
+	}
+
+	return ledger
+}
+
+func (l *RecordingTestLedger) record(kind LedgerAccessKind, owner, key, value []byte) {
+	l.accesses = append(
+		l.accesses,
+		LedgerAccess{
+			Kind:  kind,
+			Owner: owner,
+			Key:   key,
+			Value: value,
+		},
+	)
+}
+
+// Accesses returns the recorded register accesses, in order.
+func (l *RecordingTestLedger) Accesses() []LedgerAccess {
+	return l.accesses
+}
+
+// Reads returns the recorded register reads (GetValue and ValueExists), in order.
+func (l *RecordingTestLedger) Reads() []LedgerAccess {
+	return l.filter(func(kind LedgerAccessKind) bool {
+		return kind == LedgerAccessKindGetValue ||
+			kind == LedgerAccessKindValueExists
+	})
+}
+
+// Writes returns the recorded register writes, in order.
+func (l *RecordingTestLedger) Writes() []LedgerAccess {
+	return l.filter(func(kind LedgerAccessKind) bool {
+		return kind == LedgerAccessKindSetValue
+	})
+}
+
+func (l *RecordingTestLedger) filter(f func(kind LedgerAccessKind) bool) []LedgerAccess {
+	var result []LedgerAccess
+	for _, access := range l.accesses {
+		if f(access.Kind) {
+			result = append(result, access)
+		}
+	}
+	return result
+}
+
+// ResetAccesses clears the recorded register accesses,
+// e.g. to only record the accesses of a later phase of a test.
+func (l *RecordingTestLedger) ResetAccesses() {
+	l.accesses = nil
+}
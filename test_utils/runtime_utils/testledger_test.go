@@ -0,0 +1,99 @@
+/*
+ * Cadence - The resource-oriented smart contract programming language
+ *
+ * Copyright Flow Foundation
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package runtime_utils
+
+import (
+	"testing"
+
+	"github.com/onflow/atree"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordingTestLedger(t *testing.T) {
+
+	t.Parallel()
+
+	ledger := NewRecordingTestLedger()
+
+	var _ atree.Ledger = ledger
+
+	owner := []byte{0x1}
+
+	exists, err := ledger.ValueExists(owner, []byte("a"))
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	err = ledger.SetValue(owner, []byte("a"), []byte{0x2})
+	require.NoError(t, err)
+
+	value, err := ledger.GetValue(owner, []byte("a"))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x2}, value)
+
+	err = ledger.SetValue(owner, []byte("b"), []byte{0x3})
+	require.NoError(t, err)
+
+	value, err = ledger.GetValue(owner, []byte("c"))
+	require.NoError(t, err)
+	require.Nil(t, value)
+
+	require.Equal(t,
+		[]LedgerAccess{
+			{Kind: LedgerAccessKindValueExists, Owner: owner, Key: []byte("a")},
+			{Kind: LedgerAccessKindSetValue, Owner: owner, Key: []byte("a"), Value: []byte{0x2}},
+			{Kind: LedgerAccessKindGetValue, Owner: owner, Key: []byte("a"), Value: []byte{0x2}},
+			{Kind: LedgerAccessKindSetValue, Owner: owner, Key: []byte("b"), Value: []byte{0x3}},
+			{Kind: LedgerAccessKindGetValue, Owner: owner, Key: []byte("c")},
+		},
+		ledger.Accesses(),
+	)
+
+	require.Equal(t,
+		[]LedgerAccess{
+			{Kind: LedgerAccessKindValueExists, Owner: owner, Key: []byte("a")},
+			{Kind: LedgerAccessKindGetValue, Owner: owner, Key: []byte("a"), Value: []byte{0x2}},
+			{Kind: LedgerAccessKindGetValue, Owner: owner, Key: []byte("c")},
+		},
+		ledger.Reads(),
+	)
+
+	require.Equal(t,
+		[]LedgerAccess{
+			{Kind: LedgerAccessKindSetValue, Owner: owner, Key: []byte("a"), Value: []byte{0x2}},
+			{Kind: LedgerAccessKindSetValue, Owner: owner, Key: []byte("b"), Value: []byte{0x3}},
+		},
+		ledger.Writes(),
+	)
+
+	ledger.ResetAccesses()
+	require.Empty(t, ledger.Accesses())
+
+	// Stored values are not affected by resetting the log
+
+	value, err = ledger.GetValue(owner, []byte("b"))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x3}, value)
+
+	require.Equal(t,
+		[]LedgerAccess{
+			{Kind: LedgerAccessKindGetValue, Owner: owner, Key: []byte("b"), Value: []byte{0x3}},
+		},
+		ledger.Accesses(),
+	)
+}